@@ -1,13 +1,17 @@
 package client
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type AliBlobstore struct {
@@ -18,32 +22,92 @@ func New(storageClient StorageClient) (AliBlobstore, error) {
 	return AliBlobstore{storageClient: storageClient}, nil
 }
 
-func (client *AliBlobstore) Put(sourceFilePath string, destinationObject string) error {
+func (client *AliBlobstore) Put(ctx context.Context, sourceFilePath string, destinationObject string) error {
+	return client.PutWithOptions(ctx, sourceFilePath, destinationObject, common.PutOptions{})
+}
+
+func (client *AliBlobstore) PutWithOptions(ctx context.Context, sourceFilePath string, destinationObject string, opts common.PutOptions) error {
+	if err := checkIfMatch(opts.IfMatch); err != nil {
+		return err
+	}
+
 	sourceFileMD5, err := client.getMD5(sourceFilePath)
 	if err != nil {
 		return err
 	}
 
-	err = client.storageClient.Upload(sourceFilePath, sourceFileMD5, destinationObject)
+	err = client.storageClient.Upload(ctx, sourceFilePath, sourceFileMD5, destinationObject, opts)
+	if err != nil {
+		return fmt.Errorf("upload failure: %w", classifyError(err))
+	}
+	return nil
+}
+
+func (client *AliBlobstore) Get(ctx context.Context, sourceObject string, dest string) error {
+	return client.GetWithOptions(ctx, sourceObject, dest, common.GetOptions{})
+}
+
+func (client *AliBlobstore) GetWithOptions(ctx context.Context, sourceObject string, dest string, opts common.GetOptions) error {
+	return classifyError(client.storageClient.Download(ctx, sourceObject, dest, opts))
+}
+
+func (client *AliBlobstore) GetStream(ctx context.Context, sourceObject string) (io.ReadCloser, error) {
+	return client.GetStreamWithOptions(ctx, sourceObject, common.GetOptions{})
+}
+
+func (client *AliBlobstore) GetStreamWithOptions(ctx context.Context, sourceObject string, opts common.GetOptions) (io.ReadCloser, error) {
+	reader, err := client.storageClient.DownloadStream(ctx, sourceObject, opts)
+	return reader, classifyError(err)
+}
+
+func (client *AliBlobstore) PutStream(ctx context.Context, source io.Reader, destinationObject string) error {
+	return client.PutStreamWithOptions(ctx, source, destinationObject, common.PutOptions{})
+}
+
+func (client *AliBlobstore) PutStreamWithOptions(ctx context.Context, source io.Reader, destinationObject string, opts common.PutOptions) error {
+	if err := checkIfMatch(opts.IfMatch); err != nil {
+		return err
+	}
+
+	err := client.storageClient.UploadStream(ctx, source, destinationObject, opts)
 	if err != nil {
-		return fmt.Errorf("upload failure: %w", err)
+		return fmt.Errorf("upload failure: %w", classifyError(err))
 	}
 	return nil
 }
 
-func (client *AliBlobstore) Get(sourceObject string, dest string) error {
-	return client.storageClient.Download(sourceObject, dest)
+func (client *AliBlobstore) Stat(ctx context.Context, object string) (common.ObjectInfo, bool, error) {
+	info, exists, err := client.storageClient.Stat(ctx, object)
+	return info, exists, classifyError(err)
+}
+
+func (client *AliBlobstore) Delete(ctx context.Context, object string) error {
+	return client.DeleteWithOptions(ctx, object, common.DeleteOptions{})
+}
+
+func (client *AliBlobstore) DeleteWithOptions(ctx context.Context, object string, opts common.DeleteOptions) error {
+	if err := checkIfMatch(opts.IfMatch); err != nil {
+		return err
+	}
+	return classifyError(client.storageClient.Delete(ctx, object))
 }
 
-func (client *AliBlobstore) Delete(object string) error {
-	return client.storageClient.Delete(object)
+// checkIfMatch rejects ifMatch for uploads and deletes, which OSS cannot
+// make conditional on the ETag. Comparing the ETag beforehand would race
+// with concurrent writes.
+func checkIfMatch(ifMatch string) error {
+	if ifMatch == "" {
+		return nil
+	}
+	return common.NewError(common.ErrInvalidConfig, errors.New("alibaba oss does not support if-match preconditions for uploads and deletes"))
 }
 
-func (client *AliBlobstore) Exists(object string) (bool, error) {
-	return client.storageClient.Exists(object)
+func (client *AliBlobstore) Exists(ctx context.Context, object string) (bool, error) {
+	exists, err := client.storageClient.Exists(ctx, object)
+	return exists, classifyError(err)
 }
 
-func (client *AliBlobstore) Sign(object string, action string, expiration time.Duration) (string, error) {
+func (client *AliBlobstore) Sign(ctx context.Context, object string, action string, expiration time.Duration) (string, error) {
 	action = strings.ToUpper(action)
 	expiredInSec := int64(expiration.Seconds())
 	switch action {
@@ -75,22 +139,46 @@ func (client *AliBlobstore) getMD5(filePath string) (string, error) {
 	return md5, nil
 }
 
-func (client *AliBlobstore) List(prefix string) ([]string, error) {
-	return client.storageClient.List(prefix)
+func (client *AliBlobstore) List(ctx context.Context, prefix string) ([]string, error) {
+	names, err := client.storageClient.List(ctx, prefix)
+	return names, classifyError(err)
+}
+
+func (client *AliBlobstore) ListObjects(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	objects, err := client.storageClient.ListObjects(ctx, prefix)
+	return objects, classifyError(err)
+}
+
+func (client *AliBlobstore) ListWithOptions(ctx context.Context, opts common.ListOptions) (common.ListResult, error) {
+	result, err := client.storageClient.ListWithOptions(ctx, opts)
+	return result, classifyError(err)
 }
 
-func (client *AliBlobstore) Copy(srcBlob string, dstBlob string) error {
-	return client.storageClient.Copy(srcBlob, dstBlob)
+func (client *AliBlobstore) Copy(ctx context.Context, srcBlob string, dstBlob string) error {
+	return classifyError(client.storageClient.Copy(ctx, srcBlob, dstBlob))
 }
 
-func (client *AliBlobstore) Properties(dest string) error {
-	return client.storageClient.Properties(dest)
+func (client *AliBlobstore) Move(ctx context.Context, srcBlob string, dstBlob string) error {
+	return common.MoveByCopy(ctx, client, srcBlob, dstBlob)
 }
 
-func (client *AliBlobstore) EnsureStorageExists() error {
-	return client.storageClient.EnsureBucketExists()
+func (client *AliBlobstore) Properties(ctx context.Context, dest string) (common.Properties, error) {
+	props, err := client.storageClient.Properties(ctx, dest)
+	return props, classifyError(err)
+}
+
+func (client *AliBlobstore) EnsureStorageExists(ctx context.Context) error {
+	return classifyError(client.storageClient.EnsureBucketExists(ctx))
+}
+
+func (client *AliBlobstore) DeleteMany(ctx context.Context, objects []string) []common.DeleteFailure {
+	failures := client.storageClient.DeleteMany(ctx, objects)
+	for i := range failures {
+		failures[i].Err = classifyError(failures[i].Err)
+	}
+	return failures
 }
 
-func (client *AliBlobstore) DeleteRecursive(prefix string) error {
-	return client.storageClient.DeleteRecursive(prefix)
+func (client *AliBlobstore) DeleteRecursive(ctx context.Context, prefix string) error {
+	return classifyError(client.storageClient.DeleteRecursive(ctx, prefix))
 }
@@ -1,12 +1,20 @@
 package client_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+
 	"github.com/cloudfoundry/storage-cli/alioss/client"
 	"github.com/cloudfoundry/storage-cli/alioss/client/clientfakes"
+	"github.com/cloudfoundry/storage-cli/common"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -22,10 +30,10 @@ var _ = Describe("Client", func() {
 
 			tmpFile, _ := os.CreateTemp("", "azure-storage-cli-test") //nolint:errcheck
 
-			aliBlobstore.Put(tmpFile.Name(), "destination_object") //nolint:errcheck
+			aliBlobstore.Put(context.Background(), tmpFile.Name(), "destination_object") //nolint:errcheck
 
 			Expect(storageClient.UploadCallCount()).To(Equal(1))
-			sourceFilePath, sourceFileMD5, destination := storageClient.UploadArgsForCall(0)
+			_, sourceFilePath, sourceFileMD5, destination, _ := storageClient.UploadArgsForCall(0)
 
 			Expect(sourceFilePath).To(BeAssignableToTypeOf("source/file/path"))
 			Expect(sourceFileMD5).To(Equal("1B2M2Y8AsgTpgAmY7PhCfg=="))
@@ -40,16 +48,49 @@ var _ = Describe("Client", func() {
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).ToNot(HaveOccurred())
 
-			aliBlobstore.Get("source_object", "destination/file/path") //nolint:errcheck
+			aliBlobstore.Get(context.Background(), "source_object", "destination/file/path") //nolint:errcheck
 
 			Expect(storageClient.DownloadCallCount()).To(Equal(1))
-			sourceObject, destinationFilePath := storageClient.DownloadArgsForCall(0)
+			_, sourceObject, destinationFilePath, _ := storageClient.DownloadArgsForCall(0)
 
 			Expect(sourceObject).To(Equal("source_object"))
 			Expect(destinationFilePath).To(Equal("destination/file/path"))
 		})
 	})
 
+	Context("Streams", func() {
+		It("get stream opens the object for reading", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.DownloadStreamReturns(io.NopCloser(strings.NewReader("content")), nil)
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			reader, err := aliBlobstore.GetStream(context.Background(), "source_object")
+			Expect(err).ToNot(HaveOccurred())
+
+			content, err := io.ReadAll(reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("content"))
+			_, sourceObject, _ := storageClient.DownloadStreamArgsForCall(0)
+			Expect(sourceObject).To(Equal("source_object"))
+		})
+
+		It("put stream uploads with UploadStream", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.UploadStreamReturns(errors.New("boom"))
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = aliBlobstore.PutStream(context.Background(), strings.NewReader("content"), "destination_object")
+			Expect(err).To(MatchError("upload failure: boom"))
+
+			_, _, destination, _ := storageClient.UploadStreamArgsForCall(0)
+			Expect(destination).To(Equal("destination_object"))
+		})
+	})
+
 	Context("Delete", func() {
 		It("delete blob deletes the blob", func() {
 			storageClient := clientfakes.FakeStorageClient{}
@@ -57,10 +98,10 @@ var _ = Describe("Client", func() {
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).ToNot(HaveOccurred())
 
-			aliBlobstore.Delete("blob") //nolint:errcheck
+			aliBlobstore.Delete(context.Background(), "blob") //nolint:errcheck
 
 			Expect(storageClient.DeleteCallCount()).To(Equal(1))
-			object := storageClient.DeleteArgsForCall(0)
+			_, object := storageClient.DeleteArgsForCall(0)
 
 			Expect(object).To(Equal("blob"))
 		})
@@ -73,11 +114,11 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			existsState, err := aliBlobstore.Exists("blob")
+			existsState, err := aliBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == true).To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
-			object := storageClient.ExistsArgsForCall(0)
+			_, object := storageClient.ExistsArgsForCall(0)
 			Expect(object).To(Equal("blob"))
 		})
 
@@ -87,11 +128,11 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			existsState, err := aliBlobstore.Exists("blob")
+			existsState, err := aliBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == false).To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
-			object := storageClient.ExistsArgsForCall(0)
+			_, object := storageClient.ExistsArgsForCall(0)
 			Expect(object).To(Equal("blob"))
 		})
 
@@ -101,15 +142,87 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			existsState, err := aliBlobstore.Exists("blob")
+			existsState, err := aliBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == false).To(BeTrue())
 			Expect(err).To(HaveOccurred())
 
-			object := storageClient.ExistsArgsForCall(0)
+			_, object := storageClient.ExistsArgsForCall(0)
 			Expect(object).To(Equal("blob"))
 		})
 	})
 
+	Context("errors", func() {
+		It("marks OSS responses with the kind of failure", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.DownloadReturns(oss.ServiceError{StatusCode: http.StatusNotFound, Code: "NoSuchKey"})
+			storageClient.CopyReturns(fmt.Errorf("failed to copy object: %w", oss.ServiceError{StatusCode: http.StatusForbidden, Code: "AccessDenied"}))
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = aliBlobstore.Get(context.Background(), "source_object", "destination/file/path")
+			Expect(err).To(MatchError(common.ErrNotFound))
+
+			err = aliBlobstore.Copy(context.Background(), "source_object", "destination_object")
+			Expect(err).To(MatchError(common.ErrPermissionDenied))
+		})
+
+		It("marks uploads forbidden to overwrite an object as failed preconditions", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.UploadStreamReturns(oss.ServiceError{StatusCode: http.StatusConflict, Code: "FileAlreadyExists"})
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = aliBlobstore.PutStreamWithOptions(context.Background(), strings.NewReader("content"), "destination_object", common.PutOptions{IfNoneMatch: "*"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+		})
+	})
+
+	Context("preconditions", func() {
+		It("rejects if-match for uploads and deletes", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = aliBlobstore.DeleteWithOptions(context.Background(), "object", common.DeleteOptions{IfMatch: "abc"})
+			Expect(err).To(MatchError(common.ErrInvalidConfig))
+			Expect(storageClient.DeleteCallCount()).To(Equal(0))
+
+			err = aliBlobstore.PutStreamWithOptions(context.Background(), strings.NewReader("content"), "object", common.PutOptions{IfMatch: "abc"})
+			Expect(err).To(MatchError(common.ErrInvalidConfig))
+			Expect(storageClient.UploadStreamCallCount()).To(Equal(0))
+			Expect(storageClient.StatCallCount()).To(Equal(0))
+		})
+
+		It("passes if-match to downloads", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = aliBlobstore.GetWithOptions(context.Background(), "object", "destination/file/path", common.GetOptions{IfMatch: "abc"})
+			Expect(err).ToNot(HaveOccurred())
+			_, _, _, opts := storageClient.DownloadArgsForCall(0)
+			Expect(opts).To(Equal(common.GetOptions{IfMatch: "abc"}))
+		})
+
+		It("passes byte ranges to streamed downloads", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.DownloadStreamReturns(io.NopCloser(strings.NewReader("content")), nil)
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			getOpts := common.GetOptions{Range: &common.ByteRange{Start: 0, End: 511}}
+			_, err = aliBlobstore.GetStreamWithOptions(context.Background(), "object", getOpts)
+			Expect(err).ToNot(HaveOccurred())
+			_, _, opts := storageClient.DownloadStreamArgsForCall(0)
+			Expect(opts).To(Equal(getOpts))
+		})
+	})
+
 	Context("signed url", func() {
 		var expiry time.Duration
 
@@ -123,7 +236,7 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			url, err := aliBlobstore.Sign("blob", "get", expiry)
+			url, err := aliBlobstore.Sign(context.Background(), "blob", "get", expiry)
 			Expect(url == "https://the-signed-url").To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
@@ -138,7 +251,7 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			url, err := aliBlobstore.Sign("blob", "put", expiry)
+			url, err := aliBlobstore.Sign(context.Background(), "blob", "put", expiry)
 			Expect(url == "https://the-signed-url").To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
@@ -153,7 +266,7 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			url, err := aliBlobstore.Sign("blob", "unknown", expiry)
+			url, err := aliBlobstore.Sign(context.Background(), "blob", "unknown", expiry)
 			Expect(url).To(Equal(""))
 			Expect(err).To(HaveOccurred())
 
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// classifyError marks err with the kind of failure reported by OSS.
+func classifyError(err error) error {
+	if err == nil || common.ErrorKind(err) != nil {
+		return err
+	}
+
+	var ossErr oss.ServiceError
+	if errors.As(err, &ossErr) {
+		// Returned with 409 when an upload forbidding overwrites finds the
+		// object in place.
+		if ossErr.Code == "FileAlreadyExists" {
+			return common.NewError(common.ErrPreconditionFailed, err)
+		}
+		return common.ErrorFromHTTPStatus(ossErr.StatusCode, err)
+	}
+	return err
+}
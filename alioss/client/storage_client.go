@@ -1,9 +1,13 @@
 package client
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"strconv"
@@ -18,33 +22,64 @@ import (
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . StorageClient
 type StorageClient interface {
 	Upload(
+		ctx context.Context,
 		sourceFilePath string,
 		sourceFileMD5 string,
 		destinationObject string,
+		opts common.PutOptions,
+	) error
+
+	UploadStream(
+		ctx context.Context,
+		source io.Reader,
+		destinationObject string,
+		opts common.PutOptions,
 	) error
 
 	Download(
+		ctx context.Context,
 		sourceObject string,
 		destinationFilePath string,
+		opts common.GetOptions,
 	) error
 
+	DownloadStream(
+		ctx context.Context,
+		sourceObject string,
+		opts common.GetOptions,
+	) (io.ReadCloser, error)
+
 	Copy(
+		ctx context.Context,
 		srcBlob string,
 		destBlob string,
 	) error
 
 	Delete(
+		ctx context.Context,
 		object string,
 	) error
 
 	DeleteRecursive(
+		ctx context.Context,
 		objects string,
 	) error
 
+	DeleteMany(
+		ctx context.Context,
+		objects []string,
+	) []common.DeleteFailure
+
 	Exists(
+		ctx context.Context,
 		object string,
 	) (bool, error)
 
+	Stat(
+		ctx context.Context,
+		object string,
+	) (common.ObjectInfo, bool, error)
+
 	SignedUrlPut(
 		object string,
 		expiredInSec int64,
@@ -56,14 +91,26 @@ type StorageClient interface {
 	) (string, error)
 
 	List(
+		ctx context.Context,
 		prefix string,
 	) ([]string, error)
 
+	ListObjects(
+		ctx context.Context,
+		prefix string,
+	) ([]common.ObjectInfo, error)
+
+	ListWithOptions(
+		ctx context.Context,
+		opts common.ListOptions,
+	) (common.ListResult, error)
+
 	Properties(
+		ctx context.Context,
 		object string,
-	) error
+	) (common.Properties, error)
 
-	EnsureBucketExists() error
+	EnsureBucketExists(ctx context.Context) error
 }
 
 // 4 MB of part size
@@ -75,6 +122,12 @@ const maxConcurrency = 5
 // Single blob put threshold is 32MB
 const singleBlobPutThreshold = int64(32 * 1024 * 1024)
 
+// maximum number of objects returned by a page of ListObjects
+const maxListKeys = 1000
+
+// maximum number of objects deleted by a DeleteObjects request
+const maxDeleteObjects = 1000
+
 func getFileSize(fileName string) (int64, error) {
 	fileInfo, err := os.Stat(fileName)
 	if err != nil {
@@ -104,7 +157,7 @@ func newOSSClient(endpoint, accesKeyID, accessKeySecret string) (*oss.Client, er
 	}
 }
 
-func (dsc DefaultStorageClient) Upload(sourceFilePath string, sourceFileMD5 string, destinationObject string) error {
+func (dsc DefaultStorageClient) Upload(ctx context.Context, sourceFilePath string, sourceFileMD5 string, destinationObject string, opts common.PutOptions) error {
 	slog.Info("Uploading object to OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", destinationObject, "file_path", sourceFilePath)
 
 	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
@@ -122,14 +175,124 @@ func (dsc DefaultStorageClient) Upload(sourceFilePath string, sourceFileMD5 stri
 		return err
 	}
 	if fileSize <= singleBlobPutThreshold {
-		return bucket.PutObjectFromFile(destinationObject, sourceFilePath, oss.ContentMD5(sourceFileMD5))
+		return bucket.PutObjectFromFile(destinationObject, sourceFilePath, append(putOptions(opts), oss.ContentMD5(sourceFileMD5), oss.WithContext(ctx))...)
 
 	} else {
-		return bucket.UploadFile(destinationObject, sourceFilePath, partSize, oss.Routines(maxConcurrency))
+		options := append(putOptions(opts), oss.Routines(maxConcurrency), oss.WithContext(ctx))
+		if opts.Checkpoint != "" {
+			// The SDK records the upload ID and completed parts in its own
+			// checkpoint format, and removes the file once the upload completed.
+			options = append(options, oss.Checkpoint(true, opts.Checkpoint))
+		}
+		return bucket.UploadFile(destinationObject, sourceFilePath, partSize, options...)
+	}
+}
+
+// putOptions converts opts into the options of an upload.
+func putOptions(opts common.PutOptions) []oss.Option {
+	var options []oss.Option
+	if opts.ContentType != "" {
+		options = append(options, oss.ContentType(opts.ContentType))
+	}
+	if opts.ContentEncoding != "" {
+		options = append(options, oss.ContentEncoding(opts.ContentEncoding))
+	}
+	if opts.CacheControl != "" {
+		options = append(options, oss.CacheControl(opts.CacheControl))
+	}
+	if opts.ContentDisposition != "" {
+		options = append(options, oss.ContentDisposition(opts.ContentDisposition))
+	}
+	for key, value := range opts.Metadata {
+		options = append(options, oss.Meta(key, value))
+	}
+	// OSS has no If-None-Match on uploads, but forbidding overwrites creates
+	// the object only if it does not exist.
+	if opts.IfNoneMatch != "" {
+		options = append(options, oss.ForbidOverWrite(true))
+	}
+	return options
+}
+
+// getOptions converts opts into the options of a download.
+func getOptions(opts common.GetOptions) []oss.Option {
+	var options []oss.Option
+	if opts.IfMatch != "" {
+		options = append(options, oss.IfMatch(common.QuoteETag(opts.IfMatch)))
+	}
+	if opts.Range != nil {
+		options = append(options, oss.NormalizedRange(opts.Range.String()))
+	}
+	return options
+}
+
+// UploadStream uploads content of unknown length. Content that fits into a
+// single part is uploaded with one PutObject request; anything larger is
+// uploaded part by part, so at most one part is held in memory.
+func (dsc DefaultStorageClient) UploadStream(ctx context.Context, source io.Reader, destinationObject string, opts common.PutOptions) error {
+	slog.Info("Uploading stream to OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", destinationObject)
+
+	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
+	if err != nil {
+		return err
+	}
+
+	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, partSize)
+	n, err := io.ReadFull(source, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return bucket.PutObject(destinationObject, bytes.NewReader(buf[:n]), append(putOptions(opts), oss.WithContext(ctx))...)
+	}
+	if err != nil {
+		return fmt.Errorf("reading upload stream: %w", err)
+	}
+
+	imur, err := bucket.InitiateMultipartUpload(destinationObject, append(putOptions(opts), oss.WithContext(ctx))...)
+	if err != nil {
+		return fmt.Errorf("failed to initiate multipart upload: %w", err)
+	}
+
+	var parts []oss.UploadPart
+	for partNumber := 1; ; partNumber++ {
+		part, err := bucket.UploadPart(imur, bytes.NewReader(buf[:n]), int64(n), partNumber, oss.WithContext(ctx))
+		if err != nil {
+			dsc.abortMultipartUpload(ctx, bucket, imur)
+			return fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+		}
+		parts = append(parts, part)
+
+		n, err = io.ReadFull(source, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			dsc.abortMultipartUpload(ctx, bucket, imur)
+			return fmt.Errorf("reading upload stream: %w", err)
+		}
+	}
+
+	completeOptions := []oss.Option{oss.WithContext(ctx)}
+	if opts.IfNoneMatch != "" {
+		completeOptions = append(completeOptions, oss.ForbidOverWrite(true))
+	}
+	if _, err := bucket.CompleteMultipartUpload(imur, parts, completeOptions...); err != nil {
+		dsc.abortMultipartUpload(ctx, bucket, imur)
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+func (dsc DefaultStorageClient) abortMultipartUpload(ctx context.Context, bucket *oss.Bucket, imur oss.InitiateMultipartUploadResult) {
+	if err := bucket.AbortMultipartUpload(imur, oss.WithContext(context.WithoutCancel(ctx))); err != nil {
+		slog.Warn("Failed to abort multipart upload", "object_key", imur.Key, "upload_id", imur.UploadID, "error", err)
 	}
 }
 
-func (dsc DefaultStorageClient) Download(sourceObject string, destinationFilePath string) error {
+func (dsc DefaultStorageClient) Download(ctx context.Context, sourceObject string, destinationFilePath string, opts common.GetOptions) error {
 	slog.Info("Downloading object from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", sourceObject, "file_path", destinationFilePath)
 
 	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
@@ -142,10 +305,26 @@ func (dsc DefaultStorageClient) Download(sourceObject string, destinationFilePat
 		return err
 	}
 
-	return bucket.DownloadFile(sourceObject, destinationFilePath, partSize, oss.Routines(maxConcurrency))
+	return bucket.DownloadFile(sourceObject, destinationFilePath, partSize, append(getOptions(opts), oss.Routines(maxConcurrency), oss.WithContext(ctx))...)
+}
+
+func (dsc DefaultStorageClient) DownloadStream(ctx context.Context, sourceObject string, opts common.GetOptions) (io.ReadCloser, error) {
+	slog.Info("Streaming object from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", sourceObject)
+
+	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return bucket.GetObject(sourceObject, append(getOptions(opts), oss.WithContext(ctx))...)
 }
 
-func (dsc DefaultStorageClient) Copy(sourceObject string, destinationObject string) error {
+func (dsc DefaultStorageClient) Copy(ctx context.Context, sourceObject string, destinationObject string) error {
 	slog.Info("copying object within OSS bucket", "bucket", dsc.storageConfig.BucketName, "source_object", sourceObject, "destination_object", destinationObject)
 	srcOut := fmt.Sprintf("%s/%s", dsc.storageConfig.BucketName, sourceObject)
 	destOut := fmt.Sprintf("%s/%s", dsc.storageConfig.BucketName, destinationObject)
@@ -160,14 +339,14 @@ func (dsc DefaultStorageClient) Copy(sourceObject string, destinationObject stri
 		return err
 	}
 
-	if _, err := bucket.CopyObject(sourceObject, destinationObject); err != nil {
+	if _, err := bucket.CopyObject(sourceObject, destinationObject, oss.WithContext(ctx)); err != nil {
 		return fmt.Errorf("failed to copy object from %s to %s: %w", srcOut, destOut, err)
 	}
 
 	return nil
 }
 
-func (dsc DefaultStorageClient) Delete(object string) error {
+func (dsc DefaultStorageClient) Delete(ctx context.Context, object string) error {
 	slog.Info("Deleting object from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
 
 	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
@@ -180,10 +359,10 @@ func (dsc DefaultStorageClient) Delete(object string) error {
 		return err
 	}
 
-	return bucket.DeleteObject(object)
+	return bucket.DeleteObject(object, oss.WithContext(ctx))
 }
 
-func (dsc DefaultStorageClient) DeleteRecursive(prefix string) error {
+func (dsc DefaultStorageClient) DeleteRecursive(ctx context.Context, prefix string) error {
 	if prefix != "" {
 		slog.Info("Deleting all objects with prefix from OSS bucket", "bucket", dsc.storageConfig.BucketName, "prefix", prefix)
 	} else {
@@ -202,9 +381,11 @@ func (dsc DefaultStorageClient) DeleteRecursive(prefix string) error {
 
 	var marker string
 
+	var failures []common.DeleteFailure
 	for {
 		opts := []oss.Option{
-			oss.MaxKeys(1000),
+			oss.MaxKeys(maxDeleteObjects),
+			oss.WithContext(ctx),
 		}
 		if prefix != "" {
 			opts = append(opts, oss.Prefix(prefix))
@@ -215,11 +396,7 @@ func (dsc DefaultStorageClient) DeleteRecursive(prefix string) error {
 
 		resp, err := bucket.ListObjects(opts...)
 		if err != nil {
-			return fmt.Errorf("error listing objects for delete: %w", err)
-		}
-
-		if len(resp.Objects) == 0 && !resp.IsTruncated {
-			return nil
+			return errors.Join(fmt.Errorf("error listing objects for delete: %w", err), common.JoinDeleteFailures(failures))
 		}
 
 		keys := make([]string, 0, len(resp.Objects))
@@ -228,11 +405,7 @@ func (dsc DefaultStorageClient) DeleteRecursive(prefix string) error {
 		}
 
 		if len(keys) > 0 {
-			quiet := true
-			_, err := bucket.DeleteObjects(keys, oss.DeleteObjectsQuiet(quiet))
-			if err != nil {
-				return fmt.Errorf("failed to batch delete %d objects (prefix=%q): %w", len(keys), prefix, err)
-			}
+			failures = append(failures, deleteObjects(ctx, bucket, keys)...)
 		}
 
 		if !resp.IsTruncated {
@@ -241,10 +414,62 @@ func (dsc DefaultStorageClient) DeleteRecursive(prefix string) error {
 		marker = resp.NextMarker
 	}
 
-	return nil
+	return common.JoinDeleteFailures(failures)
 }
 
-func (dsc DefaultStorageClient) Exists(object string) (bool, error) {
+// DeleteMany deletes objects with DeleteObjects, maxDeleteObjects at a time.
+func (dsc DefaultStorageClient) DeleteMany(ctx context.Context, objects []string) []common.DeleteFailure {
+	slog.Info("Deleting objects from OSS bucket", "bucket", dsc.storageConfig.BucketName, "objects", len(objects))
+
+	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
+	if err != nil {
+		return deleteFailures(objects, err)
+	}
+
+	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
+	if err != nil {
+		return deleteFailures(objects, err)
+	}
+
+	var failures []common.DeleteFailure
+	for start := 0; start < len(objects); start += maxDeleteObjects {
+		batch := objects[start:min(start+maxDeleteObjects, len(objects))]
+		failures = append(failures, deleteObjects(ctx, bucket, batch)...)
+	}
+	return failures
+}
+
+// deleteObjects deletes at most maxDeleteObjects objects with a single
+// DeleteObjects request and returns the objects that were not deleted.
+func deleteObjects(ctx context.Context, bucket *oss.Bucket, objects []string) []common.DeleteFailure {
+	// OSS lists the deleted objects but not why others were not deleted.
+	result, err := bucket.DeleteObjects(objects, oss.WithContext(ctx))
+	if err != nil {
+		return deleteFailures(objects, fmt.Errorf("failed to batch delete %d objects: %w", len(objects), err))
+	}
+	deleted := make(map[string]bool, len(result.DeletedObjects))
+	for _, object := range result.DeletedObjects {
+		deleted[object] = true
+	}
+	var failures []common.DeleteFailure
+	for _, object := range objects {
+		if !deleted[object] {
+			failures = append(failures, common.DeleteFailure{Key: object, Err: errors.New("object was not reported as deleted")})
+		}
+	}
+	return failures
+}
+
+// deleteFailures reports every object as failed with err.
+func deleteFailures(objects []string, err error) []common.DeleteFailure {
+	failures := make([]common.DeleteFailure, len(objects))
+	for i, object := range objects {
+		failures[i] = common.DeleteFailure{Key: object, Err: err}
+	}
+	return failures
+}
+
+func (dsc DefaultStorageClient) Exists(ctx context.Context, object string) (bool, error) {
 	slog.Info("Checking if object exists in OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
 
 	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
@@ -257,7 +482,7 @@ func (dsc DefaultStorageClient) Exists(object string) (bool, error) {
 		return false, err
 	}
 
-	objectExists, err := bucket.IsObjectExist(object)
+	objectExists, err := bucket.IsObjectExist(object, oss.WithContext(ctx))
 	if err != nil {
 		return false, err
 	}
@@ -271,6 +496,46 @@ func (dsc DefaultStorageClient) Exists(object string) (bool, error) {
 	}
 }
 
+func (dsc DefaultStorageClient) Stat(ctx context.Context, object string) (common.ObjectInfo, bool, error) {
+	slog.Info("Getting object metadata from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
+
+	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
+	if err != nil {
+		return common.ObjectInfo{}, false, err
+	}
+
+	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
+	if err != nil {
+		return common.ObjectInfo{}, false, err
+	}
+
+	meta, err := bucket.GetObjectDetailedMeta(object, oss.WithContext(ctx))
+	if err != nil {
+		var ossErr oss.ServiceError
+		if errors.As(err, &ossErr) && ossErr.StatusCode == 404 {
+			return common.ObjectInfo{}, false, nil
+		}
+		return common.ObjectInfo{}, false, fmt.Errorf("failed to get metadata for object %s: %w", object, err)
+	}
+
+	info := common.ObjectInfo{
+		Name: object,
+		ETag: common.TrimETag(meta.Get("ETag")),
+	}
+	if size, err := strconv.ParseInt(meta.Get("Content-Length"), 10, 64); err == nil {
+		info.Size = size
+	}
+	if t, err := time.Parse(time.RFC1123, meta.Get("Last-Modified")); err == nil {
+		info.LastModified = t
+	}
+	if md5, err := base64.StdEncoding.DecodeString(meta.Get("Content-Md5")); err == nil && len(md5) > 0 {
+		info.ContentMD5 = hex.EncodeToString(md5)
+	} else {
+		info.ContentMD5 = common.MD5FromETag(info.ETag)
+	}
+	return info, true, nil
+}
+
 func (dsc DefaultStorageClient) SignedUrlPut(object string, expiredInSec int64) (string, error) {
 	slog.Info("Generating signed PUT URL for OSS object", "bucket", dsc.storageConfig.BucketName, "object_key", object, "expiration_seconds", expiredInSec)
 
@@ -303,124 +568,169 @@ func (dsc DefaultStorageClient) SignedUrlGet(object string, expiredInSec int64)
 	return bucket.SignURL(object, oss.HTTPGet, expiredInSec)
 }
 
-func (dsc DefaultStorageClient) List(prefix string) ([]string, error) {
-	if prefix != "" {
-		slog.Info("Listing all objects in OSS bucket with prefix", "bucket", dsc.storageConfig.BucketName, "prefix", prefix)
+func (dsc DefaultStorageClient) List(ctx context.Context, prefix string) ([]string, error) {
+	objects, err := dsc.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return common.ObjectNames(objects), nil
+}
+
+func (dsc DefaultStorageClient) ListObjects(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	result, err := dsc.ListWithOptions(ctx, common.ListOptions{Prefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+	return result.Objects, nil
+}
+
+func (dsc DefaultStorageClient) ListWithOptions(ctx context.Context, listOpts common.ListOptions) (common.ListResult, error) {
+	if listOpts.Prefix != "" {
+		slog.Info("Listing all objects in OSS bucket with prefix", "bucket", dsc.storageConfig.BucketName, "prefix", listOpts.Prefix, "delimiter", listOpts.Delimiter)
 	} else {
-		slog.Info("Listing all objects in OSS bucket", "bucket", dsc.storageConfig.BucketName)
+		slog.Info("Listing all objects in OSS bucket", "bucket", dsc.storageConfig.BucketName, "delimiter", listOpts.Delimiter)
 	}
 
-	var (
-		objects []string
-		marker  string
-	)
+	// OSS markers are the name listings start after, so they serve both as
+	// page tokens and for listOpts.StartAfter.
+	var result common.ListResult
+	marker := listOpts.StartAfter
+	if listOpts.PageToken != "" {
+		marker = listOpts.PageToken
+	}
 
 	for {
-		var opts []oss.Option
-		if prefix != "" {
-			opts = append(opts, oss.Prefix(prefix))
+		opts := []oss.Option{oss.WithContext(ctx)}
+		if listOpts.Prefix != "" {
+			opts = append(opts, oss.Prefix(listOpts.Prefix))
+		}
+		if listOpts.Delimiter != "" {
+			opts = append(opts, oss.Delimiter(listOpts.Delimiter))
 		}
 		if marker != "" {
 			opts = append(opts, oss.Marker(marker))
 		}
+		if listOpts.MaxKeys > 0 {
+			opts = append(opts, oss.MaxKeys(min(listOpts.MaxKeys, maxListKeys)))
+		}
 
 		client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
 		if err != nil {
-			return nil, err
+			return common.ListResult{}, err
 		}
 
 		bucket, err := client.Bucket(dsc.storageConfig.BucketName)
 		if err != nil {
-			return nil, err
+			return common.ListResult{}, err
 		}
 
 		resp, err := bucket.ListObjects(opts...)
 		if err != nil {
-			return nil, fmt.Errorf("error retrieving page of objects: %w", err)
+			return common.ListResult{}, fmt.Errorf("error retrieving page of objects: %w", err)
 		}
 
 		for _, obj := range resp.Objects {
-			objects = append(objects, obj.Key)
+			result.Objects = append(result.Objects, objectInfo(obj))
 		}
+		result.Prefixes = append(result.Prefixes, resp.CommonPrefixes...)
 
 		if !resp.IsTruncated {
 			break
 		}
+		if listOpts.MaxKeys > 0 {
+			result.NextPageToken = resp.NextMarker
+			break
+		}
 		marker = resp.NextMarker
 	}
 
-	return objects, nil
+	return result, nil
 }
 
-type BlobProperties struct {
-	ETag          string    `json:"etag,omitempty"`
-	LastModified  time.Time `json:"last_modified,omitempty"`
-	ContentLength int64     `json:"content_length,omitempty"`
+// objectInfo converts an entry of a ListObjects response.
+func objectInfo(obj oss.ObjectProperties) common.ObjectInfo {
+	info := common.ObjectInfo{
+		Name:         obj.Key,
+		Size:         obj.Size,
+		ETag:         common.TrimETag(obj.ETag),
+		LastModified: obj.LastModified,
+		StorageClass: obj.StorageClass,
+	}
+	info.ContentMD5 = common.MD5FromETag(info.ETag)
+	return info
 }
 
-func (dsc DefaultStorageClient) Properties(object string) error {
+func (dsc DefaultStorageClient) Properties(ctx context.Context, object string) (common.Properties, error) {
 	slog.Info("Getting object properties from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
 
 	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
 	if err != nil {
-		return err
+		return common.Properties{}, err
 	}
 
 	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
 	if err != nil {
-		return err
+		return common.Properties{}, err
 	}
 
-	meta, err := bucket.GetObjectDetailedMeta(object)
+	meta, err := bucket.GetObjectDetailedMeta(object, oss.WithContext(ctx))
 	if err != nil {
-		var ossErr oss.ServiceError
-		if errors.As(err, &ossErr) && ossErr.StatusCode == 404 {
-			fmt.Println(`{}`)
-			return nil
+		return common.Properties{}, fmt.Errorf("failed to get properties for object %s: %w", object, err)
+	}
+
+	props := common.Properties{
+		ETag:               common.TrimETag(meta.Get(oss.HTTPHeaderEtag)),
+		ContentType:        meta.Get(oss.HTTPHeaderContentType),
+		ContentEncoding:    meta.Get(oss.HTTPHeaderContentEncoding),
+		CacheControl:       meta.Get(oss.HTTPHeaderCacheControl),
+		ContentDisposition: meta.Get(oss.HTTPHeaderContentDisposition),
+		ContentMD5:         common.HexFromBase64(meta.Get(oss.HTTPHeaderContentMD5)),
+		CRC64:              crc64Hex(meta.Get(oss.HTTPHeaderOssCRC64)),
+		StorageClass:       meta.Get(oss.HTTPHeaderOssStorageClass),
+		Encryption: common.Encryption{
+			Algorithm: meta.Get(oss.HTTPHeaderOssServerSideEncryption),
+			KMSKeyID:  meta.Get(oss.HTTPHeaderOssServerSideEncryptionKeyID),
+		},
+	}
+	if props.ContentMD5 == "" {
+		props.ContentMD5 = common.MD5FromETag(props.ETag)
+	}
+	for key, values := range meta {
+		if name, ok := strings.CutPrefix(key, oss.HTTPHeaderOssMetaPrefix); ok && len(values) > 0 {
+			if props.Metadata == nil {
+				props.Metadata = map[string]string{}
+			}
+			props.Metadata[strings.ToLower(name)] = values[0]
 		}
-
-		return fmt.Errorf("failed to get properties for object %s: %w", object, err)
 	}
 
-	eTag := meta.Get("ETag")
-	lastModifiedStr := meta.Get("Last-Modified")
-	contentLengthStr := meta.Get("Content-Length")
-
-	var (
-		lastModified  time.Time
-		contentLength int64
-	)
-
-	if lastModifiedStr != "" {
-		t, parseErr := time.Parse(time.RFC1123, lastModifiedStr)
-		if parseErr == nil {
-			lastModified = t
+	if lastModified := meta.Get(oss.HTTPHeaderLastModified); lastModified != "" {
+		if t, err := time.Parse(time.RFC1123, lastModified); err == nil {
+			props.LastModified = t
 		}
 	}
 
-	if contentLengthStr != "" {
-		cl, convErr := strconv.ParseInt(contentLengthStr, 10, 64)
-		if convErr == nil {
-			contentLength = cl
+	if contentLength := meta.Get(oss.HTTPHeaderContentLength); contentLength != "" {
+		if n, err := strconv.ParseInt(contentLength, 10, 64); err == nil {
+			props.ContentLength = n
 		}
 	}
 
-	props := BlobProperties{
-		ETag:          strings.Trim(eTag, `"`),
-		LastModified:  lastModified,
-		ContentLength: contentLength,
-	}
+	return props, nil
+}
 
-	output, err := json.MarshalIndent(props, "", "  ")
+// crc64Hex re-encodes the decimal CRC-64 OSS reports in the
+// x-oss-hash-crc64ecma header as hex, or returns an empty string if it is
+// missing or invalid.
+func crc64Hex(crc string) string {
+	n, err := strconv.ParseUint(crc, 10, 64)
 	if err != nil {
-		return fmt.Errorf("failed to marshal object properties: %w", err)
+		return ""
 	}
-
-	fmt.Println(string(output))
-	return nil
+	return fmt.Sprintf("%016x", n)
 }
 
-func (dsc DefaultStorageClient) EnsureBucketExists() error {
+func (dsc DefaultStorageClient) EnsureBucketExists(ctx context.Context) error {
 	slog.Info("Ensuring OSS bucket exists", "bucket", dsc.storageConfig.BucketName)
 
 	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
@@ -438,7 +748,7 @@ func (dsc DefaultStorageClient) EnsureBucketExists() error {
 		return nil
 	}
 
-	if err := client.CreateBucket(dsc.storageConfig.BucketName); err != nil {
+	if err := client.CreateBucket(dsc.storageConfig.BucketName, oss.WithContext(ctx)); err != nil {
 		return fmt.Errorf("failed to create bucket '%s': %w", dsc.storageConfig.BucketName, err)
 	}
 
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"fmt"
 	"io"
@@ -9,6 +10,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type AzBlobstore struct {
@@ -30,7 +33,11 @@ func New(storageClient StorageClient) (AzBlobstore, error) {
 	return AzBlobstore{storageClient: storageClient}, nil
 }
 
-func (client *AzBlobstore) Put(sourceFilePath string, dest string) error {
+func (client *AzBlobstore) Put(ctx context.Context, sourceFilePath string, dest string) error {
+	return client.PutWithOptions(ctx, sourceFilePath, dest, common.PutOptions{})
+}
+
+func (client *AzBlobstore) PutWithOptions(ctx context.Context, sourceFilePath string, dest string, opts common.PutOptions) error {
 	sourceMD5, err := client.getMD5(sourceFilePath)
 	if err != nil {
 		return err
@@ -46,15 +53,15 @@ func (client *AzBlobstore) Put(sourceFilePath string, dest string) error {
 		return err
 	}
 	if fileSize <= singleBlobPutThreshold {
-		md5, err := client.storageClient.Upload(source, dest)
+		md5, err := client.storageClient.Upload(ctx, source, dest, opts)
 		if err != nil {
-			return fmt.Errorf("upload failure: %w", err)
+			return fmt.Errorf("upload failure: %w", classifyError(err))
 		}
 
 		if !bytes.Equal(sourceMD5, md5) {
 			slog.Error("Upload failed due to MD5 mismatch, deleting blob", "blob", dest, "expected_md5", fmt.Sprintf("%x", sourceMD5), "received_md5", fmt.Sprintf("%x", md5))
 
-			err := client.storageClient.Delete(dest)
+			err := client.storageClient.Delete(ctx, dest, common.DeleteOptions{})
 			if err != nil {
 				slog.Error("Failed to delete blob after MD5 mismatch", "blob", dest, "error", err)
 
@@ -64,42 +71,94 @@ func (client *AzBlobstore) Put(sourceFilePath string, dest string) error {
 
 		slog.Debug("MD5 verification passed", "blob", dest, "md5", fmt.Sprintf("%x", md5))
 
+	} else if opts.Checkpoint != "" {
+		checkpoint, err := common.LoadCheckpoint(opts.Checkpoint, source, dest)
+		if err != nil {
+			return err
+		}
+		if err := client.storageClient.UploadCheckpointed(ctx, source, fileSize, dest, opts, checkpoint); err != nil {
+			return classifyError(err)
+		}
 	} else {
-		err := client.storageClient.UploadStream(source, dest)
+		err := client.storageClient.UploadStream(ctx, source, dest, opts)
 		if err != nil {
-			return fmt.Errorf("upload failure: %w", err)
+			return fmt.Errorf("upload failure: %w", classifyError(err))
 		}
 	}
 
 	return nil
 }
 
-func (client *AzBlobstore) Get(source string, dest string) error {
+func (client *AzBlobstore) Get(ctx context.Context, source string, dest string) error {
+	return client.GetWithOptions(ctx, source, dest, common.GetOptions{})
+}
+
+func (client *AzBlobstore) GetWithOptions(ctx context.Context, source string, dest string, opts common.GetOptions) error {
 	dstFile, err := os.Create(dest)
 	if err != nil {
 		return fmt.Errorf("failed to create destination file: %w", err)
 	}
 	defer dstFile.Close() //nolint:errcheck
 
-	return client.storageClient.Download(source, dstFile)
+	return classifyError(client.storageClient.Download(ctx, source, dstFile, opts))
 }
 
-func (client *AzBlobstore) Delete(dest string) error {
+func (client *AzBlobstore) GetStream(ctx context.Context, source string) (io.ReadCloser, error) {
+	return client.GetStreamWithOptions(ctx, source, common.GetOptions{})
+}
+
+func (client *AzBlobstore) GetStreamWithOptions(ctx context.Context, source string, opts common.GetOptions) (io.ReadCloser, error) {
+	reader, err := client.storageClient.DownloadStream(ctx, source, opts)
+	return reader, classifyError(err)
+}
 
-	return client.storageClient.Delete(dest)
+// PutStream uploads content of unknown length in blocks. Unlike Put, it cannot
+// verify an MD5 as the content is only read once.
+func (client *AzBlobstore) PutStream(ctx context.Context, source io.Reader, dest string) error {
+	return client.PutStreamWithOptions(ctx, source, dest, common.PutOptions{})
 }
 
-func (client *AzBlobstore) DeleteRecursive(prefix string) error {
+func (client *AzBlobstore) PutStreamWithOptions(ctx context.Context, source io.Reader, dest string, opts common.PutOptions) error {
+	err := client.storageClient.UploadStream(ctx, source, dest, opts)
+	if err != nil {
+		return fmt.Errorf("upload failure: %w", classifyError(err))
+	}
+	return nil
+}
 
-	return client.storageClient.DeleteRecursive(prefix)
+func (client *AzBlobstore) Stat(ctx context.Context, dest string) (common.ObjectInfo, bool, error) {
+	info, exists, err := client.storageClient.Stat(ctx, dest)
+	return info, exists, classifyError(err)
 }
 
-func (client *AzBlobstore) Exists(dest string) (bool, error) {
+func (client *AzBlobstore) Delete(ctx context.Context, dest string) error {
+	return client.DeleteWithOptions(ctx, dest, common.DeleteOptions{})
+}
 
-	return client.storageClient.Exists(dest)
+func (client *AzBlobstore) DeleteWithOptions(ctx context.Context, dest string, opts common.DeleteOptions) error {
+	return classifyError(client.storageClient.Delete(ctx, dest, opts))
 }
 
-func (client *AzBlobstore) Sign(dest string, action string, expiration time.Duration) (string, error) {
+func (client *AzBlobstore) DeleteMany(ctx context.Context, dests []string) []common.DeleteFailure {
+	failures := client.storageClient.DeleteMany(ctx, dests)
+	for i := range failures {
+		failures[i].Err = classifyError(failures[i].Err)
+	}
+	return failures
+}
+
+func (client *AzBlobstore) DeleteRecursive(ctx context.Context, prefix string) error {
+
+	return classifyError(client.storageClient.DeleteRecursive(ctx, prefix))
+}
+
+func (client *AzBlobstore) Exists(ctx context.Context, dest string) (bool, error) {
+
+	exists, err := client.storageClient.Exists(ctx, dest)
+	return exists, classifyError(err)
+}
+
+func (client *AzBlobstore) Sign(ctx context.Context, dest string, action string, expiration time.Duration) (string, error) {
 	action = strings.ToUpper(action)
 	switch action {
 	case "GET", "PUT":
@@ -126,21 +185,37 @@ func (client *AzBlobstore) getMD5(filePath string) ([]byte, error) {
 	return hash.Sum(nil), nil
 }
 
-func (client *AzBlobstore) List(prefix string) ([]string, error) {
-	return client.storageClient.List(prefix)
+func (client *AzBlobstore) List(ctx context.Context, prefix string) ([]string, error) {
+	names, err := client.storageClient.List(ctx, prefix)
+	return names, classifyError(err)
+}
+
+func (client *AzBlobstore) ListObjects(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	objects, err := client.storageClient.ListObjects(ctx, prefix)
+	return objects, classifyError(err)
 }
 
-func (client *AzBlobstore) Copy(srcBlob string, dstBlob string) error {
+func (client *AzBlobstore) ListWithOptions(ctx context.Context, opts common.ListOptions) (common.ListResult, error) {
+	result, err := client.storageClient.ListWithOptions(ctx, opts)
+	return result, classifyError(err)
+}
+
+func (client *AzBlobstore) Copy(ctx context.Context, srcBlob string, dstBlob string) error {
+
+	return classifyError(client.storageClient.Copy(ctx, srcBlob, dstBlob))
+}
 
-	return client.storageClient.Copy(srcBlob, dstBlob)
+func (client *AzBlobstore) Move(ctx context.Context, srcBlob string, dstBlob string) error {
+	return common.MoveByCopy(ctx, client, srcBlob, dstBlob)
 }
 
-func (client *AzBlobstore) Properties(dest string) error {
+func (client *AzBlobstore) Properties(ctx context.Context, dest string) (common.Properties, error) {
 
-	return client.storageClient.Properties(dest)
+	props, err := client.storageClient.Properties(ctx, dest)
+	return props, classifyError(err)
 }
 
-func (client *AzBlobstore) EnsureStorageExists() error {
+func (client *AzBlobstore) EnsureStorageExists(ctx context.Context) error {
 
-	return client.storageClient.EnsureContainerExists()
+	return classifyError(client.storageClient.EnsureContainerExists(ctx))
 }
@@ -2,12 +2,20 @@ package client_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"io"
+	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 
 	"github.com/cloudfoundry/storage-cli/azurebs/client"
 	"github.com/cloudfoundry/storage-cli/azurebs/client/clientfakes"
+	"github.com/cloudfoundry/storage-cli/common"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -24,15 +32,32 @@ var _ = Describe("Client", func() {
 
 			file, _ := os.CreateTemp("", "tmpfile") //nolint:errcheck
 
-			azBlobstore.Put(file.Name(), "target/blob") //nolint:errcheck
+			azBlobstore.Put(context.Background(), file.Name(), "target/blob") //nolint:errcheck
 
 			Expect(storageClient.UploadCallCount()).To(Equal(1))
-			source, dest := storageClient.UploadArgsForCall(0)
+			_, source, dest, _ := storageClient.UploadArgsForCall(0)
 
 			Expect(source).To(BeAssignableToTypeOf((*os.File)(nil)))
 			Expect(dest).To(Equal("target/blob"))
 		})
 
+		It("passes the put options to the upload", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			azBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			file, _ := os.CreateTemp("", "tmpfile") //nolint:errcheck
+			defer os.Remove(file.Name())            //nolint:errcheck
+
+			opts := common.PutOptions{ContentType: "text/plain", Metadata: map[string]string{"owner": "cc"}}
+			azBlobstore.PutWithOptions(context.Background(), file.Name(), "target/blob", opts) //nolint:errcheck
+
+			Expect(storageClient.UploadCallCount()).To(Equal(1))
+			_, _, _, uploadOpts := storageClient.UploadArgsForCall(0)
+			Expect(uploadOpts).To(Equal(opts))
+		})
+
 		It("uploads a file with UploadStream", func() {
 			storageClient := clientfakes.FakeStorageClient{}
 
@@ -47,22 +72,47 @@ var _ = Describe("Client", func() {
 			content := bytes.Repeat([]byte("x"), contentSize)
 			_, _ = file.Write(content) //nolint:errcheck
 
-			azBlobstore.Put(file.Name(), "target/blob") //nolint:errcheck
+			azBlobstore.Put(context.Background(), file.Name(), "target/blob") //nolint:errcheck
 
 			Expect(storageClient.UploadStreamCallCount()).To(Equal(1))
-			source, dest := storageClient.UploadStreamArgsForCall(0)
+			_, source, dest, _ := storageClient.UploadStreamArgsForCall(0)
 
 			Expect(source).To(BeAssignableToTypeOf((*os.File)(nil)))
 			Expect(dest).To(Equal("target/blob"))
 		})
 
+		It("uploads a large file with a checkpoint as uncommitted blocks", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			azBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			dir := GinkgoT().TempDir()
+			source := filepath.Join(dir, "source")
+			file, err := os.Create(source)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file.Truncate(1024 * 1024 * 64)).To(Succeed())
+			Expect(file.Close()).To(Succeed())
+
+			opts := common.PutOptions{Checkpoint: filepath.Join(dir, "checkpoint")}
+			Expect(azBlobstore.PutWithOptions(context.Background(), source, "target/blob", opts)).To(Succeed())
+
+			Expect(storageClient.UploadStreamCallCount()).To(Equal(0))
+			Expect(storageClient.UploadCheckpointedCallCount()).To(Equal(1))
+			_, _, size, dest, uploadOpts, checkpoint := storageClient.UploadCheckpointedArgsForCall(0)
+			Expect(size).To(Equal(int64(1024 * 1024 * 64)))
+			Expect(dest).To(Equal("target/blob"))
+			Expect(uploadOpts).To(Equal(opts))
+			Expect(checkpoint).ToNot(BeNil())
+		})
+
 		It("skips the upload if the md5 cannot be calculated from the file", func() {
 			storageClient := clientfakes.FakeStorageClient{}
 
 			azBlobstore, err := client.New(&storageClient)
 			Expect(err).ToNot(HaveOccurred())
 
-			err = azBlobstore.Put("the/path", "target/blob")
+			err = azBlobstore.Put(context.Background(), "the/path", "target/blob")
 
 			Expect(storageClient.UploadCallCount()).To(Equal(0))
 			var expectedError string
@@ -83,16 +133,16 @@ var _ = Describe("Client", func() {
 
 			file, _ := os.CreateTemp("", "tmpfile") //nolint:errcheck
 
-			putError := azBlobstore.Put(file.Name(), "target/blob")
+			putError := azBlobstore.Put(context.Background(), file.Name(), "target/blob")
 			Expect(putError.Error()).To(Equal("MD5 mismatch: expected d41d8cd98f00b204e9800998ecf8427e, got 010203"))
 
 			Expect(storageClient.UploadCallCount()).To(Equal(1))
-			source, dest := storageClient.UploadArgsForCall(0)
+			_, source, dest, _ := storageClient.UploadArgsForCall(0)
 			Expect(source).To(BeAssignableToTypeOf((*os.File)(nil)))
 			Expect(dest).To(Equal("target/blob"))
 
 			Expect(storageClient.DeleteCallCount()).To(Equal(1))
-			dest = storageClient.DeleteArgsForCall(0)
+			_, dest, _ = storageClient.DeleteArgsForCall(0)
 			Expect(dest).To(Equal("target/blob"))
 		})
 	})
@@ -106,40 +156,106 @@ var _ = Describe("Client", func() {
 		dstFileName := "tmp-dest-azurebs-get"
 		defer os.Remove("tmp-dest-azurebs-get") //nolint:errcheck
 
-		azBlobstore.Get("source/blob", dstFileName) //nolint:errcheck
+		azBlobstore.Get(context.Background(), "source/blob", dstFileName) //nolint:errcheck
 
 		Expect(storageClient.DownloadCallCount()).To(Equal(1))
 
-		source, dest := storageClient.DownloadArgsForCall(0)
+		_, source, dest, _ := storageClient.DownloadArgsForCall(0)
 		Expect(source).To(Equal("source/blob"))
 		Expect(dest.Name()).To(Equal(dstFileName))
 	})
 
+	It("get stream opens the blob for reading", func() {
+		storageClient := clientfakes.FakeStorageClient{}
+		storageClient.DownloadStreamReturns(io.NopCloser(strings.NewReader("content")), nil)
+
+		azBlobstore, err := client.New(&storageClient)
+		Expect(err).ToNot(HaveOccurred())
+
+		reader, err := azBlobstore.GetStream(context.Background(), "source/blob")
+		Expect(err).ToNot(HaveOccurred())
+
+		content, err := io.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("content"))
+		_, source, _ := storageClient.DownloadStreamArgsForCall(0)
+		Expect(source).To(Equal("source/blob"))
+	})
+
+	It("put stream uploads with UploadStream", func() {
+		storageClient := clientfakes.FakeStorageClient{}
+
+		azBlobstore, err := client.New(&storageClient)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = azBlobstore.PutStream(context.Background(), strings.NewReader("content"), "target/blob")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(storageClient.UploadCallCount()).To(Equal(0))
+		Expect(storageClient.UploadStreamCallCount()).To(Equal(1))
+		_, _, dest, _ := storageClient.UploadStreamArgsForCall(0)
+		Expect(dest).To(Equal("target/blob"))
+	})
+
 	It("delete blob deletes the blob", func() {
 		storageClient := clientfakes.FakeStorageClient{}
 
 		azBlobstore, err := client.New(&storageClient)
 		Expect(err).ToNot(HaveOccurred())
 
-		azBlobstore.Delete("blob") //nolint:errcheck
+		azBlobstore.Delete(context.Background(), "blob") //nolint:errcheck
 
 		Expect(storageClient.DeleteCallCount()).To(Equal(1))
-		dest := storageClient.DeleteArgsForCall(0)
+		_, dest, _ := storageClient.DeleteArgsForCall(0)
 
 		Expect(dest).To(Equal("blob"))
 	})
 
+	It("passes preconditions to the storage client", func() {
+		storageClient := clientfakes.FakeStorageClient{}
+		storageClient.DownloadStreamReturns(io.NopCloser(strings.NewReader("content")), nil)
+
+		azBlobstore, err := client.New(&storageClient)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = azBlobstore.GetStreamWithOptions(context.Background(), "blob", common.GetOptions{IfMatch: "0x8DC"})
+		Expect(err).ToNot(HaveOccurred())
+		_, _, getOpts := storageClient.DownloadStreamArgsForCall(0)
+		Expect(getOpts).To(Equal(common.GetOptions{IfMatch: "0x8DC"}))
+
+		err = azBlobstore.DeleteWithOptions(context.Background(), "blob", common.DeleteOptions{IfMatch: "0x8DC"})
+		Expect(err).ToNot(HaveOccurred())
+		_, _, deleteOpts := storageClient.DeleteArgsForCall(0)
+		Expect(deleteOpts).To(Equal(common.DeleteOptions{IfMatch: "0x8DC"}))
+	})
+
+	It("passes byte ranges to the storage client", func() {
+		storageClient := clientfakes.FakeStorageClient{}
+
+		azBlobstore, err := client.New(&storageClient)
+		Expect(err).ToNot(HaveOccurred())
+
+		file, err := os.CreateTemp("", "tmpfile") //nolint:ineffassign,staticcheck
+		Expect(err).ToNot(HaveOccurred())
+		defer os.Remove(file.Name()) //nolint:errcheck
+
+		opts := common.GetOptions{Range: &common.ByteRange{Start: -22, End: -1}}
+		Expect(azBlobstore.GetWithOptions(context.Background(), "blob", file.Name(), opts)).To(Succeed())
+		_, _, _, getOpts := storageClient.DownloadArgsForCall(0)
+		Expect(getOpts).To(Equal(opts))
+	})
+
 	Context("if the blob existence is checked", func() {
 		It("returns blob.Existing on success", func() {
 			storageClient := clientfakes.FakeStorageClient{}
 			storageClient.ExistsReturns(true, nil)
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			existsState, err := azBlobstore.Exists("blob")
+			existsState, err := azBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == true).To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
-			dest := storageClient.ExistsArgsForCall(0)
+			_, dest := storageClient.ExistsArgsForCall(0)
 			Expect(dest).To(Equal("blob"))
 		})
 
@@ -148,11 +264,11 @@ var _ = Describe("Client", func() {
 			storageClient.ExistsReturns(false, nil)
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			existsState, err := azBlobstore.Exists("blob")
+			existsState, err := azBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == false).To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
-			dest := storageClient.ExistsArgsForCall(0)
+			_, dest := storageClient.ExistsArgsForCall(0)
 			Expect(dest).To(Equal("blob"))
 		})
 
@@ -161,22 +277,73 @@ var _ = Describe("Client", func() {
 			storageClient.ExistsReturns(false, errors.New("boom"))
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			existsState, err := azBlobstore.Exists("blob")
+			existsState, err := azBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == false).To(BeTrue())
 			Expect(err).To(HaveOccurred())
 
-			dest := storageClient.ExistsArgsForCall(0)
+			_, dest := storageClient.ExistsArgsForCall(0)
 			Expect(dest).To(Equal("blob"))
 		})
 	})
 
+	Context("errors", func() {
+		It("marks Azure responses with the kind of failure", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.DownloadStreamReturns(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound, ErrorCode: "BlobNotFound"})
+			storageClient.DeleteReturns(&azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "AuthorizationFailure"})
+			storageClient.ListReturns(nil, &azcore.ResponseError{StatusCode: http.StatusServiceUnavailable, ErrorCode: "ServerBusy"})
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+
+			_, err := azBlobstore.GetStream(context.Background(), "blob")
+			Expect(err).To(MatchError(common.ErrNotFound))
+
+			err = azBlobstore.Delete(context.Background(), "blob")
+			Expect(err).To(MatchError(common.ErrPermissionDenied))
+
+			_, err = azBlobstore.List(context.Background(), "")
+			Expect(err).To(MatchError(common.ErrThrottled))
+		})
+
+		It("marks failed preconditions", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.DeleteReturns(&azcore.ResponseError{StatusCode: http.StatusPreconditionFailed, ErrorCode: "ConditionNotMet"})
+			storageClient.UploadStreamReturns(&azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "BlobAlreadyExists"})
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+
+			err := azBlobstore.DeleteWithOptions(context.Background(), "blob", common.DeleteOptions{IfMatch: "0x8DC"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+
+			err = azBlobstore.PutStreamWithOptions(context.Background(), strings.NewReader("content"), "blob", common.PutOptions{IfNoneMatch: "*"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+		})
+
+		It("marks the failures of batch deletes with the kind of failure", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.DeleteManyReturns([]common.DeleteFailure{
+				{Key: "b", Err: &azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "AuthorizationFailure"}},
+			})
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+
+			failures := azBlobstore.DeleteMany(context.Background(), []string{"a", "b"})
+			Expect(failures).To(HaveLen(1))
+			Expect(failures[0].Key).To(Equal("b"))
+			Expect(failures[0].Err).To(MatchError(common.ErrPermissionDenied))
+
+			_, dests := storageClient.DeleteManyArgsForCall(0)
+			Expect(dests).To(Equal([]string{"a", "b"}))
+		})
+	})
+
 	Context("signed url", func() {
 		It("returns a signed url for action 'get'", func() {
 			storageClient := clientfakes.FakeStorageClient{}
 			storageClient.SignedUrlReturns("https://the-signed-url", nil)
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			url, err := azBlobstore.Sign("blob", "get", 100)
+			url, err := azBlobstore.Sign(context.Background(), "blob", "get", 100)
 			Expect(url == "https://the-signed-url").To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
@@ -191,7 +358,7 @@ var _ = Describe("Client", func() {
 			storageClient.SignedUrlReturns("", errors.New("boom"))
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			url, err := azBlobstore.Sign("blob", "unknown", 100)
+			url, err := azBlobstore.Sign(context.Background(), "blob", "unknown", 100)
 			Expect(url).To(Equal(""))
 			Expect(err).To(HaveOccurred())
 
@@ -205,11 +372,11 @@ var _ = Describe("Client", func() {
 			storageClient.ListReturns([]string{"blob1", "blob2"}, nil)
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			blobs, err := azBlobstore.List("")
+			blobs, err := azBlobstore.List(context.Background(), "")
 			Expect(blobs).To(Equal([]string{"blob1", "blob2"}))
 			Expect(err).ToNot(HaveOccurred())
 
-			containerName := storageClient.ListArgsForCall(0)
+			_, containerName := storageClient.ListArgsForCall(0)
 			Expect(containerName).To(Equal(""))
 		})
 
@@ -218,24 +385,50 @@ var _ = Describe("Client", func() {
 			storageClient.ListReturns([]string{"pre-blob1", "pre-blob2"}, nil)
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			blobs, err := azBlobstore.List("pre-")
+			blobs, err := azBlobstore.List(context.Background(), "pre-")
 			Expect(blobs).To(Equal([]string{"pre-blob1", "pre-blob2"}))
 			Expect(err).ToNot(HaveOccurred())
 
-			containerName := storageClient.ListArgsForCall(0)
+			_, containerName := storageClient.ListArgsForCall(0)
 			Expect(containerName).To(Equal("pre-"))
 		})
 
+		It("lists blobs with their metadata", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.ListObjectsReturns([]common.ObjectInfo{{Name: "pre-blob1", Size: 42, StorageClass: "Hot"}}, nil)
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+			objects, err := azBlobstore.ListObjects(context.Background(), "pre-")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objects).To(Equal([]common.ObjectInfo{{Name: "pre-blob1", Size: 42, StorageClass: "Hot"}}))
+
+			_, prefix := storageClient.ListObjectsArgsForCall(0)
+			Expect(prefix).To(Equal("pre-"))
+		})
+
+		It("lists blobs grouped by a delimiter", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.ListWithOptionsReturns(common.ListResult{Objects: []common.ObjectInfo{{Name: "dir/blob"}}, Prefixes: []string{"dir/sub/"}}, nil)
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+			result, err := azBlobstore.ListWithOptions(context.Background(), common.ListOptions{Prefix: "dir/", Delimiter: "/"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Prefixes).To(Equal([]string{"dir/sub/"}))
+
+			_, opts := storageClient.ListWithOptionsArgsForCall(0)
+			Expect(opts).To(Equal(common.ListOptions{Prefix: "dir/", Delimiter: "/"}))
+		})
+
 		It("returns an error if listing fails", func() {
 			storageClient := clientfakes.FakeStorageClient{}
 			storageClient.ListReturns(nil, errors.New("boom"))
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			blobs, err := azBlobstore.List("container")
+			blobs, err := azBlobstore.List(context.Background(), "container")
 			Expect(blobs).To(BeNil())
 			Expect(err).To(HaveOccurred())
 
-			containerName := storageClient.ListArgsForCall(0)
+			_, containerName := storageClient.ListArgsForCall(0)
 			Expect(containerName).To(Equal("container"))
 		})
 	})
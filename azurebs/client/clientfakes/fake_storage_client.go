@@ -2,20 +2,23 @@
 package clientfakes
 
 import (
+	"context"
 	"io"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/cloudfoundry/storage-cli/azurebs/client"
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type FakeStorageClient struct {
-	CopyStub        func(string, string) error
+	CopyStub        func(context.Context, string, string) error
 	copyMutex       sync.RWMutex
 	copyArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
 	}
 	copyReturns struct {
 		result1 error
@@ -23,10 +26,12 @@ type FakeStorageClient struct {
 	copyReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteStub        func(string) error
+	DeleteStub        func(context.Context, string, common.DeleteOptions) error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
+		arg3 common.DeleteOptions
 	}
 	deleteReturns struct {
 		result1 error
@@ -34,10 +39,23 @@ type FakeStorageClient struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteRecursiveStub        func(string) error
+	DeleteManyStub        func(context.Context, []string) []common.DeleteFailure
+	deleteManyMutex       sync.RWMutex
+	deleteManyArgsForCall []struct {
+		arg1 context.Context
+		arg2 []string
+	}
+	deleteManyReturns struct {
+		result1 []common.DeleteFailure
+	}
+	deleteManyReturnsOnCall map[int]struct {
+		result1 []common.DeleteFailure
+	}
+	DeleteRecursiveStub        func(context.Context, string) error
 	deleteRecursiveMutex       sync.RWMutex
 	deleteRecursiveArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	deleteRecursiveReturns struct {
 		result1 error
@@ -45,11 +63,13 @@ type FakeStorageClient struct {
 	deleteRecursiveReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DownloadStub        func(string, *os.File) error
+	DownloadStub        func(context.Context, string, *os.File, common.GetOptions) error
 	downloadMutex       sync.RWMutex
 	downloadArgsForCall []struct {
-		arg1 string
-		arg2 *os.File
+		arg1 context.Context
+		arg2 string
+		arg3 *os.File
+		arg4 common.GetOptions
 	}
 	downloadReturns struct {
 		result1 error
@@ -57,9 +77,25 @@ type FakeStorageClient struct {
 	downloadReturnsOnCall map[int]struct {
 		result1 error
 	}
-	EnsureContainerExistsStub        func() error
+	DownloadStreamStub        func(context.Context, string, common.GetOptions) (io.ReadCloser, error)
+	downloadStreamMutex       sync.RWMutex
+	downloadStreamArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 common.GetOptions
+	}
+	downloadStreamReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	downloadStreamReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	EnsureContainerExistsStub        func(context.Context) error
 	ensureContainerExistsMutex       sync.RWMutex
 	ensureContainerExistsArgsForCall []struct {
+		arg1 context.Context
 	}
 	ensureContainerExistsReturns struct {
 		result1 error
@@ -67,10 +103,11 @@ type FakeStorageClient struct {
 	ensureContainerExistsReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ExistsStub        func(string) (bool, error)
+	ExistsStub        func(context.Context, string) (bool, error)
 	existsMutex       sync.RWMutex
 	existsArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	existsReturns struct {
 		result1 bool
@@ -80,10 +117,11 @@ type FakeStorageClient struct {
 		result1 bool
 		result2 error
 	}
-	ListStub        func(string) ([]string, error)
+	ListStub        func(context.Context, string) ([]string, error)
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	listReturns struct {
 		result1 []string
@@ -93,16 +131,47 @@ type FakeStorageClient struct {
 		result1 []string
 		result2 error
 	}
-	PropertiesStub        func(string) error
+	ListObjectsStub        func(context.Context, string) ([]common.ObjectInfo, error)
+	listObjectsMutex       sync.RWMutex
+	listObjectsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	listObjectsReturns struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	listObjectsReturnsOnCall map[int]struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	ListWithOptionsStub        func(context.Context, common.ListOptions) (common.ListResult, error)
+	listWithOptionsMutex       sync.RWMutex
+	listWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 common.ListOptions
+	}
+	listWithOptionsReturns struct {
+		result1 common.ListResult
+		result2 error
+	}
+	listWithOptionsReturnsOnCall map[int]struct {
+		result1 common.ListResult
+		result2 error
+	}
+	PropertiesStub        func(context.Context, string) (common.Properties, error)
 	propertiesMutex       sync.RWMutex
 	propertiesArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	propertiesReturns struct {
-		result1 error
+		result1 common.Properties
+		result2 error
 	}
 	propertiesReturnsOnCall map[int]struct {
-		result1 error
+		result1 common.Properties
+		result2 error
 	}
 	SignedUrlStub        func(string, string, time.Duration) (string, error)
 	signedUrlMutex       sync.RWMutex
@@ -119,11 +188,29 @@ type FakeStorageClient struct {
 		result1 string
 		result2 error
 	}
-	UploadStub        func(io.ReadSeekCloser, string) ([]byte, error)
+	StatStub        func(context.Context, string) (common.ObjectInfo, bool, error)
+	statMutex       sync.RWMutex
+	statArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	statReturns struct {
+		result1 common.ObjectInfo
+		result2 bool
+		result3 error
+	}
+	statReturnsOnCall map[int]struct {
+		result1 common.ObjectInfo
+		result2 bool
+		result3 error
+	}
+	UploadStub        func(context.Context, io.ReadSeekCloser, string, common.PutOptions) ([]byte, error)
 	uploadMutex       sync.RWMutex
 	uploadArgsForCall []struct {
-		arg1 io.ReadSeekCloser
-		arg2 string
+		arg1 context.Context
+		arg2 io.ReadSeekCloser
+		arg3 string
+		arg4 common.PutOptions
 	}
 	uploadReturns struct {
 		result1 []byte
@@ -133,11 +220,29 @@ type FakeStorageClient struct {
 		result1 []byte
 		result2 error
 	}
-	UploadStreamStub        func(io.ReadSeekCloser, string) error
+	UploadCheckpointedStub        func(context.Context, io.ReaderAt, int64, string, common.PutOptions, *common.Checkpoint) error
+	uploadCheckpointedMutex       sync.RWMutex
+	uploadCheckpointedArgsForCall []struct {
+		arg1 context.Context
+		arg2 io.ReaderAt
+		arg3 int64
+		arg4 string
+		arg5 common.PutOptions
+		arg6 *common.Checkpoint
+	}
+	uploadCheckpointedReturns struct {
+		result1 error
+	}
+	uploadCheckpointedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UploadStreamStub        func(context.Context, io.Reader, string, common.PutOptions) error
 	uploadStreamMutex       sync.RWMutex
 	uploadStreamArgsForCall []struct {
-		arg1 io.ReadSeekCloser
-		arg2 string
+		arg1 context.Context
+		arg2 io.Reader
+		arg3 string
+		arg4 common.PutOptions
 	}
 	uploadStreamReturns struct {
 		result1 error
@@ -149,19 +254,20 @@ type FakeStorageClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStorageClient) Copy(arg1 string, arg2 string) error {
+func (fake *FakeStorageClient) Copy(arg1 context.Context, arg2 string, arg3 string) error {
 	fake.copyMutex.Lock()
 	ret, specificReturn := fake.copyReturnsOnCall[len(fake.copyArgsForCall)]
 	fake.copyArgsForCall = append(fake.copyArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+	}{arg1, arg2, arg3})
 	stub := fake.CopyStub
 	fakeReturns := fake.copyReturns
-	fake.recordInvocation("Copy", []interface{}{arg1, arg2})
+	fake.recordInvocation("Copy", []interface{}{arg1, arg2, arg3})
 	fake.copyMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -175,17 +281,17 @@ func (fake *FakeStorageClient) CopyCallCount() int {
 	return len(fake.copyArgsForCall)
 }
 
-func (fake *FakeStorageClient) CopyCalls(stub func(string, string) error) {
+func (fake *FakeStorageClient) CopyCalls(stub func(context.Context, string, string) error) {
 	fake.copyMutex.Lock()
 	defer fake.copyMutex.Unlock()
 	fake.CopyStub = stub
 }
 
-func (fake *FakeStorageClient) CopyArgsForCall(i int) (string, string) {
+func (fake *FakeStorageClient) CopyArgsForCall(i int) (context.Context, string, string) {
 	fake.copyMutex.RLock()
 	defer fake.copyMutex.RUnlock()
 	argsForCall := fake.copyArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorageClient) CopyReturns(result1 error) {
@@ -211,18 +317,20 @@ func (fake *FakeStorageClient) CopyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorageClient) Delete(arg1 string) error {
+func (fake *FakeStorageClient) Delete(arg1 context.Context, arg2 string, arg3 common.DeleteOptions) error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
 	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+		arg3 common.DeleteOptions
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteStub
 	fakeReturns := fake.deleteReturns
-	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2, arg3})
 	fake.deleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -236,17 +344,17 @@ func (fake *FakeStorageClient) DeleteCallCount() int {
 	return len(fake.deleteArgsForCall)
 }
 
-func (fake *FakeStorageClient) DeleteCalls(stub func(string) error) {
+func (fake *FakeStorageClient) DeleteCalls(stub func(context.Context, string, common.DeleteOptions) error) {
 	fake.deleteMutex.Lock()
 	defer fake.deleteMutex.Unlock()
 	fake.DeleteStub = stub
 }
 
-func (fake *FakeStorageClient) DeleteArgsForCall(i int) string {
+func (fake *FakeStorageClient) DeleteArgsForCall(i int) (context.Context, string, common.DeleteOptions) {
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
 	argsForCall := fake.deleteArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorageClient) DeleteReturns(result1 error) {
@@ -272,18 +380,86 @@ func (fake *FakeStorageClient) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorageClient) DeleteRecursive(arg1 string) error {
+func (fake *FakeStorageClient) DeleteMany(arg1 context.Context, arg2 []string) []common.DeleteFailure {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.deleteManyMutex.Lock()
+	ret, specificReturn := fake.deleteManyReturnsOnCall[len(fake.deleteManyArgsForCall)]
+	fake.deleteManyArgsForCall = append(fake.deleteManyArgsForCall, struct {
+		arg1 context.Context
+		arg2 []string
+	}{arg1, arg2Copy})
+	stub := fake.DeleteManyStub
+	fakeReturns := fake.deleteManyReturns
+	fake.recordInvocation("DeleteMany", []interface{}{arg1, arg2Copy})
+	fake.deleteManyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorageClient) DeleteManyCallCount() int {
+	fake.deleteManyMutex.RLock()
+	defer fake.deleteManyMutex.RUnlock()
+	return len(fake.deleteManyArgsForCall)
+}
+
+func (fake *FakeStorageClient) DeleteManyCalls(stub func(context.Context, []string) []common.DeleteFailure) {
+	fake.deleteManyMutex.Lock()
+	defer fake.deleteManyMutex.Unlock()
+	fake.DeleteManyStub = stub
+}
+
+func (fake *FakeStorageClient) DeleteManyArgsForCall(i int) (context.Context, []string) {
+	fake.deleteManyMutex.RLock()
+	defer fake.deleteManyMutex.RUnlock()
+	argsForCall := fake.deleteManyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorageClient) DeleteManyReturns(result1 []common.DeleteFailure) {
+	fake.deleteManyMutex.Lock()
+	defer fake.deleteManyMutex.Unlock()
+	fake.DeleteManyStub = nil
+	fake.deleteManyReturns = struct {
+		result1 []common.DeleteFailure
+	}{result1}
+}
+
+func (fake *FakeStorageClient) DeleteManyReturnsOnCall(i int, result1 []common.DeleteFailure) {
+	fake.deleteManyMutex.Lock()
+	defer fake.deleteManyMutex.Unlock()
+	fake.DeleteManyStub = nil
+	if fake.deleteManyReturnsOnCall == nil {
+		fake.deleteManyReturnsOnCall = make(map[int]struct {
+			result1 []common.DeleteFailure
+		})
+	}
+	fake.deleteManyReturnsOnCall[i] = struct {
+		result1 []common.DeleteFailure
+	}{result1}
+}
+
+func (fake *FakeStorageClient) DeleteRecursive(arg1 context.Context, arg2 string) error {
 	fake.deleteRecursiveMutex.Lock()
 	ret, specificReturn := fake.deleteRecursiveReturnsOnCall[len(fake.deleteRecursiveArgsForCall)]
 	fake.deleteRecursiveArgsForCall = append(fake.deleteRecursiveArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.DeleteRecursiveStub
 	fakeReturns := fake.deleteRecursiveReturns
-	fake.recordInvocation("DeleteRecursive", []interface{}{arg1})
+	fake.recordInvocation("DeleteRecursive", []interface{}{arg1, arg2})
 	fake.deleteRecursiveMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -297,17 +473,17 @@ func (fake *FakeStorageClient) DeleteRecursiveCallCount() int {
 	return len(fake.deleteRecursiveArgsForCall)
 }
 
-func (fake *FakeStorageClient) DeleteRecursiveCalls(stub func(string) error) {
+func (fake *FakeStorageClient) DeleteRecursiveCalls(stub func(context.Context, string) error) {
 	fake.deleteRecursiveMutex.Lock()
 	defer fake.deleteRecursiveMutex.Unlock()
 	fake.DeleteRecursiveStub = stub
 }
 
-func (fake *FakeStorageClient) DeleteRecursiveArgsForCall(i int) string {
+func (fake *FakeStorageClient) DeleteRecursiveArgsForCall(i int) (context.Context, string) {
 	fake.deleteRecursiveMutex.RLock()
 	defer fake.deleteRecursiveMutex.RUnlock()
 	argsForCall := fake.deleteRecursiveArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorageClient) DeleteRecursiveReturns(result1 error) {
@@ -333,19 +509,21 @@ func (fake *FakeStorageClient) DeleteRecursiveReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
-func (fake *FakeStorageClient) Download(arg1 string, arg2 *os.File) error {
+func (fake *FakeStorageClient) Download(arg1 context.Context, arg2 string, arg3 *os.File, arg4 common.GetOptions) error {
 	fake.downloadMutex.Lock()
 	ret, specificReturn := fake.downloadReturnsOnCall[len(fake.downloadArgsForCall)]
 	fake.downloadArgsForCall = append(fake.downloadArgsForCall, struct {
-		arg1 string
-		arg2 *os.File
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *os.File
+		arg4 common.GetOptions
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.DownloadStub
 	fakeReturns := fake.downloadReturns
-	fake.recordInvocation("Download", []interface{}{arg1, arg2})
+	fake.recordInvocation("Download", []interface{}{arg1, arg2, arg3, arg4})
 	fake.downloadMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -359,17 +537,17 @@ func (fake *FakeStorageClient) DownloadCallCount() int {
 	return len(fake.downloadArgsForCall)
 }
 
-func (fake *FakeStorageClient) DownloadCalls(stub func(string, *os.File) error) {
+func (fake *FakeStorageClient) DownloadCalls(stub func(context.Context, string, *os.File, common.GetOptions) error) {
 	fake.downloadMutex.Lock()
 	defer fake.downloadMutex.Unlock()
 	fake.DownloadStub = stub
 }
 
-func (fake *FakeStorageClient) DownloadArgsForCall(i int) (string, *os.File) {
+func (fake *FakeStorageClient) DownloadArgsForCall(i int) (context.Context, string, *os.File, common.GetOptions) {
 	fake.downloadMutex.RLock()
 	defer fake.downloadMutex.RUnlock()
 	argsForCall := fake.downloadArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStorageClient) DownloadReturns(result1 error) {
@@ -395,17 +573,84 @@ func (fake *FakeStorageClient) DownloadReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorageClient) EnsureContainerExists() error {
+func (fake *FakeStorageClient) DownloadStream(arg1 context.Context, arg2 string, arg3 common.GetOptions) (io.ReadCloser, error) {
+	fake.downloadStreamMutex.Lock()
+	ret, specificReturn := fake.downloadStreamReturnsOnCall[len(fake.downloadStreamArgsForCall)]
+	fake.downloadStreamArgsForCall = append(fake.downloadStreamArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 common.GetOptions
+	}{arg1, arg2, arg3})
+	stub := fake.DownloadStreamStub
+	fakeReturns := fake.downloadStreamReturns
+	fake.recordInvocation("DownloadStream", []interface{}{arg1, arg2, arg3})
+	fake.downloadStreamMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) DownloadStreamCallCount() int {
+	fake.downloadStreamMutex.RLock()
+	defer fake.downloadStreamMutex.RUnlock()
+	return len(fake.downloadStreamArgsForCall)
+}
+
+func (fake *FakeStorageClient) DownloadStreamCalls(stub func(context.Context, string, common.GetOptions) (io.ReadCloser, error)) {
+	fake.downloadStreamMutex.Lock()
+	defer fake.downloadStreamMutex.Unlock()
+	fake.DownloadStreamStub = stub
+}
+
+func (fake *FakeStorageClient) DownloadStreamArgsForCall(i int) (context.Context, string, common.GetOptions) {
+	fake.downloadStreamMutex.RLock()
+	defer fake.downloadStreamMutex.RUnlock()
+	argsForCall := fake.downloadStreamArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorageClient) DownloadStreamReturns(result1 io.ReadCloser, result2 error) {
+	fake.downloadStreamMutex.Lock()
+	defer fake.downloadStreamMutex.Unlock()
+	fake.DownloadStreamStub = nil
+	fake.downloadStreamReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) DownloadStreamReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.downloadStreamMutex.Lock()
+	defer fake.downloadStreamMutex.Unlock()
+	fake.DownloadStreamStub = nil
+	if fake.downloadStreamReturnsOnCall == nil {
+		fake.downloadStreamReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.downloadStreamReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) EnsureContainerExists(arg1 context.Context) error {
 	fake.ensureContainerExistsMutex.Lock()
 	ret, specificReturn := fake.ensureContainerExistsReturnsOnCall[len(fake.ensureContainerExistsArgsForCall)]
 	fake.ensureContainerExistsArgsForCall = append(fake.ensureContainerExistsArgsForCall, struct {
-	}{})
+		arg1 context.Context
+	}{arg1})
 	stub := fake.EnsureContainerExistsStub
 	fakeReturns := fake.ensureContainerExistsReturns
-	fake.recordInvocation("EnsureContainerExists", []interface{}{})
+	fake.recordInvocation("EnsureContainerExists", []interface{}{arg1})
 	fake.ensureContainerExistsMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -419,12 +664,19 @@ func (fake *FakeStorageClient) EnsureContainerExistsCallCount() int {
 	return len(fake.ensureContainerExistsArgsForCall)
 }
 
-func (fake *FakeStorageClient) EnsureContainerExistsCalls(stub func() error) {
+func (fake *FakeStorageClient) EnsureContainerExistsCalls(stub func(context.Context) error) {
 	fake.ensureContainerExistsMutex.Lock()
 	defer fake.ensureContainerExistsMutex.Unlock()
 	fake.EnsureContainerExistsStub = stub
 }
 
+func (fake *FakeStorageClient) EnsureContainerExistsArgsForCall(i int) context.Context {
+	fake.ensureContainerExistsMutex.RLock()
+	defer fake.ensureContainerExistsMutex.RUnlock()
+	argsForCall := fake.ensureContainerExistsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeStorageClient) EnsureContainerExistsReturns(result1 error) {
 	fake.ensureContainerExistsMutex.Lock()
 	defer fake.ensureContainerExistsMutex.Unlock()
@@ -448,18 +700,19 @@ func (fake *FakeStorageClient) EnsureContainerExistsReturnsOnCall(i int, result1
 	}{result1}
 }
 
-func (fake *FakeStorageClient) Exists(arg1 string) (bool, error) {
+func (fake *FakeStorageClient) Exists(arg1 context.Context, arg2 string) (bool, error) {
 	fake.existsMutex.Lock()
 	ret, specificReturn := fake.existsReturnsOnCall[len(fake.existsArgsForCall)]
 	fake.existsArgsForCall = append(fake.existsArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.ExistsStub
 	fakeReturns := fake.existsReturns
-	fake.recordInvocation("Exists", []interface{}{arg1})
+	fake.recordInvocation("Exists", []interface{}{arg1, arg2})
 	fake.existsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -473,17 +726,17 @@ func (fake *FakeStorageClient) ExistsCallCount() int {
 	return len(fake.existsArgsForCall)
 }
 
-func (fake *FakeStorageClient) ExistsCalls(stub func(string) (bool, error)) {
+func (fake *FakeStorageClient) ExistsCalls(stub func(context.Context, string) (bool, error)) {
 	fake.existsMutex.Lock()
 	defer fake.existsMutex.Unlock()
 	fake.ExistsStub = stub
 }
 
-func (fake *FakeStorageClient) ExistsArgsForCall(i int) string {
+func (fake *FakeStorageClient) ExistsArgsForCall(i int) (context.Context, string) {
 	fake.existsMutex.RLock()
 	defer fake.existsMutex.RUnlock()
 	argsForCall := fake.existsArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorageClient) ExistsReturns(result1 bool, result2 error) {
@@ -512,18 +765,19 @@ func (fake *FakeStorageClient) ExistsReturnsOnCall(i int, result1 bool, result2
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) List(arg1 string) ([]string, error) {
+func (fake *FakeStorageClient) List(arg1 context.Context, arg2 string) ([]string, error) {
 	fake.listMutex.Lock()
 	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
 	fake.listArgsForCall = append(fake.listArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.ListStub
 	fakeReturns := fake.listReturns
-	fake.recordInvocation("List", []interface{}{arg1})
+	fake.recordInvocation("List", []interface{}{arg1, arg2})
 	fake.listMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -537,17 +791,17 @@ func (fake *FakeStorageClient) ListCallCount() int {
 	return len(fake.listArgsForCall)
 }
 
-func (fake *FakeStorageClient) ListCalls(stub func(string) ([]string, error)) {
+func (fake *FakeStorageClient) ListCalls(stub func(context.Context, string) ([]string, error)) {
 	fake.listMutex.Lock()
 	defer fake.listMutex.Unlock()
 	fake.ListStub = stub
 }
 
-func (fake *FakeStorageClient) ListArgsForCall(i int) string {
+func (fake *FakeStorageClient) ListArgsForCall(i int) (context.Context, string) {
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
 	argsForCall := fake.listArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorageClient) ListReturns(result1 []string, result2 error) {
@@ -576,23 +830,154 @@ func (fake *FakeStorageClient) ListReturnsOnCall(i int, result1 []string, result
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) Properties(arg1 string) error {
+func (fake *FakeStorageClient) ListObjects(arg1 context.Context, arg2 string) ([]common.ObjectInfo, error) {
+	fake.listObjectsMutex.Lock()
+	ret, specificReturn := fake.listObjectsReturnsOnCall[len(fake.listObjectsArgsForCall)]
+	fake.listObjectsArgsForCall = append(fake.listObjectsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListObjectsStub
+	fakeReturns := fake.listObjectsReturns
+	fake.recordInvocation("ListObjects", []interface{}{arg1, arg2})
+	fake.listObjectsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) ListObjectsCallCount() int {
+	fake.listObjectsMutex.RLock()
+	defer fake.listObjectsMutex.RUnlock()
+	return len(fake.listObjectsArgsForCall)
+}
+
+func (fake *FakeStorageClient) ListObjectsCalls(stub func(context.Context, string) ([]common.ObjectInfo, error)) {
+	fake.listObjectsMutex.Lock()
+	defer fake.listObjectsMutex.Unlock()
+	fake.ListObjectsStub = stub
+}
+
+func (fake *FakeStorageClient) ListObjectsArgsForCall(i int) (context.Context, string) {
+	fake.listObjectsMutex.RLock()
+	defer fake.listObjectsMutex.RUnlock()
+	argsForCall := fake.listObjectsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorageClient) ListObjectsReturns(result1 []common.ObjectInfo, result2 error) {
+	fake.listObjectsMutex.Lock()
+	defer fake.listObjectsMutex.Unlock()
+	fake.ListObjectsStub = nil
+	fake.listObjectsReturns = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) ListObjectsReturnsOnCall(i int, result1 []common.ObjectInfo, result2 error) {
+	fake.listObjectsMutex.Lock()
+	defer fake.listObjectsMutex.Unlock()
+	fake.ListObjectsStub = nil
+	if fake.listObjectsReturnsOnCall == nil {
+		fake.listObjectsReturnsOnCall = make(map[int]struct {
+			result1 []common.ObjectInfo
+			result2 error
+		})
+	}
+	fake.listObjectsReturnsOnCall[i] = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) ListWithOptions(arg1 context.Context, arg2 common.ListOptions) (common.ListResult, error) {
+	fake.listWithOptionsMutex.Lock()
+	ret, specificReturn := fake.listWithOptionsReturnsOnCall[len(fake.listWithOptionsArgsForCall)]
+	fake.listWithOptionsArgsForCall = append(fake.listWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 common.ListOptions
+	}{arg1, arg2})
+	stub := fake.ListWithOptionsStub
+	fakeReturns := fake.listWithOptionsReturns
+	fake.recordInvocation("ListWithOptions", []interface{}{arg1, arg2})
+	fake.listWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) ListWithOptionsCallCount() int {
+	fake.listWithOptionsMutex.RLock()
+	defer fake.listWithOptionsMutex.RUnlock()
+	return len(fake.listWithOptionsArgsForCall)
+}
+
+func (fake *FakeStorageClient) ListWithOptionsCalls(stub func(context.Context, common.ListOptions) (common.ListResult, error)) {
+	fake.listWithOptionsMutex.Lock()
+	defer fake.listWithOptionsMutex.Unlock()
+	fake.ListWithOptionsStub = stub
+}
+
+func (fake *FakeStorageClient) ListWithOptionsArgsForCall(i int) (context.Context, common.ListOptions) {
+	fake.listWithOptionsMutex.RLock()
+	defer fake.listWithOptionsMutex.RUnlock()
+	argsForCall := fake.listWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorageClient) ListWithOptionsReturns(result1 common.ListResult, result2 error) {
+	fake.listWithOptionsMutex.Lock()
+	defer fake.listWithOptionsMutex.Unlock()
+	fake.ListWithOptionsStub = nil
+	fake.listWithOptionsReturns = struct {
+		result1 common.ListResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) ListWithOptionsReturnsOnCall(i int, result1 common.ListResult, result2 error) {
+	fake.listWithOptionsMutex.Lock()
+	defer fake.listWithOptionsMutex.Unlock()
+	fake.ListWithOptionsStub = nil
+	if fake.listWithOptionsReturnsOnCall == nil {
+		fake.listWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 common.ListResult
+			result2 error
+		})
+	}
+	fake.listWithOptionsReturnsOnCall[i] = struct {
+		result1 common.ListResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) Properties(arg1 context.Context, arg2 string) (common.Properties, error) {
 	fake.propertiesMutex.Lock()
 	ret, specificReturn := fake.propertiesReturnsOnCall[len(fake.propertiesArgsForCall)]
 	fake.propertiesArgsForCall = append(fake.propertiesArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.PropertiesStub
 	fakeReturns := fake.propertiesReturns
-	fake.recordInvocation("Properties", []interface{}{arg1})
+	fake.recordInvocation("Properties", []interface{}{arg1, arg2})
 	fake.propertiesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeStorageClient) PropertiesCallCount() int {
@@ -601,40 +986,43 @@ func (fake *FakeStorageClient) PropertiesCallCount() int {
 	return len(fake.propertiesArgsForCall)
 }
 
-func (fake *FakeStorageClient) PropertiesCalls(stub func(string) error) {
+func (fake *FakeStorageClient) PropertiesCalls(stub func(context.Context, string) (common.Properties, error)) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = stub
 }
 
-func (fake *FakeStorageClient) PropertiesArgsForCall(i int) string {
+func (fake *FakeStorageClient) PropertiesArgsForCall(i int) (context.Context, string) {
 	fake.propertiesMutex.RLock()
 	defer fake.propertiesMutex.RUnlock()
 	argsForCall := fake.propertiesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeStorageClient) PropertiesReturns(result1 error) {
+func (fake *FakeStorageClient) PropertiesReturns(result1 common.Properties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	fake.propertiesReturns = struct {
-		result1 error
-	}{result1}
+		result1 common.Properties
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) PropertiesReturnsOnCall(i int, result1 error) {
+func (fake *FakeStorageClient) PropertiesReturnsOnCall(i int, result1 common.Properties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	if fake.propertiesReturnsOnCall == nil {
 		fake.propertiesReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 common.Properties
+			result2 error
 		})
 	}
 	fake.propertiesReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 common.Properties
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakeStorageClient) SignedUrl(arg1 string, arg2 string, arg3 time.Duration) (string, error) {
@@ -703,19 +1091,89 @@ func (fake *FakeStorageClient) SignedUrlReturnsOnCall(i int, result1 string, res
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) Upload(arg1 io.ReadSeekCloser, arg2 string) ([]byte, error) {
+func (fake *FakeStorageClient) Stat(arg1 context.Context, arg2 string) (common.ObjectInfo, bool, error) {
+	fake.statMutex.Lock()
+	ret, specificReturn := fake.statReturnsOnCall[len(fake.statArgsForCall)]
+	fake.statArgsForCall = append(fake.statArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.StatStub
+	fakeReturns := fake.statReturns
+	fake.recordInvocation("Stat", []interface{}{arg1, arg2})
+	fake.statMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeStorageClient) StatCallCount() int {
+	fake.statMutex.RLock()
+	defer fake.statMutex.RUnlock()
+	return len(fake.statArgsForCall)
+}
+
+func (fake *FakeStorageClient) StatCalls(stub func(context.Context, string) (common.ObjectInfo, bool, error)) {
+	fake.statMutex.Lock()
+	defer fake.statMutex.Unlock()
+	fake.StatStub = stub
+}
+
+func (fake *FakeStorageClient) StatArgsForCall(i int) (context.Context, string) {
+	fake.statMutex.RLock()
+	defer fake.statMutex.RUnlock()
+	argsForCall := fake.statArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorageClient) StatReturns(result1 common.ObjectInfo, result2 bool, result3 error) {
+	fake.statMutex.Lock()
+	defer fake.statMutex.Unlock()
+	fake.StatStub = nil
+	fake.statReturns = struct {
+		result1 common.ObjectInfo
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeStorageClient) StatReturnsOnCall(i int, result1 common.ObjectInfo, result2 bool, result3 error) {
+	fake.statMutex.Lock()
+	defer fake.statMutex.Unlock()
+	fake.StatStub = nil
+	if fake.statReturnsOnCall == nil {
+		fake.statReturnsOnCall = make(map[int]struct {
+			result1 common.ObjectInfo
+			result2 bool
+			result3 error
+		})
+	}
+	fake.statReturnsOnCall[i] = struct {
+		result1 common.ObjectInfo
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeStorageClient) Upload(arg1 context.Context, arg2 io.ReadSeekCloser, arg3 string, arg4 common.PutOptions) ([]byte, error) {
 	fake.uploadMutex.Lock()
 	ret, specificReturn := fake.uploadReturnsOnCall[len(fake.uploadArgsForCall)]
 	fake.uploadArgsForCall = append(fake.uploadArgsForCall, struct {
-		arg1 io.ReadSeekCloser
-		arg2 string
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 io.ReadSeekCloser
+		arg3 string
+		arg4 common.PutOptions
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.UploadStub
 	fakeReturns := fake.uploadReturns
-	fake.recordInvocation("Upload", []interface{}{arg1, arg2})
+	fake.recordInvocation("Upload", []interface{}{arg1, arg2, arg3, arg4})
 	fake.uploadMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -729,17 +1187,17 @@ func (fake *FakeStorageClient) UploadCallCount() int {
 	return len(fake.uploadArgsForCall)
 }
 
-func (fake *FakeStorageClient) UploadCalls(stub func(io.ReadSeekCloser, string) ([]byte, error)) {
+func (fake *FakeStorageClient) UploadCalls(stub func(context.Context, io.ReadSeekCloser, string, common.PutOptions) ([]byte, error)) {
 	fake.uploadMutex.Lock()
 	defer fake.uploadMutex.Unlock()
 	fake.UploadStub = stub
 }
 
-func (fake *FakeStorageClient) UploadArgsForCall(i int) (io.ReadSeekCloser, string) {
+func (fake *FakeStorageClient) UploadArgsForCall(i int) (context.Context, io.ReadSeekCloser, string, common.PutOptions) {
 	fake.uploadMutex.RLock()
 	defer fake.uploadMutex.RUnlock()
 	argsForCall := fake.uploadArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStorageClient) UploadReturns(result1 []byte, result2 error) {
@@ -768,19 +1226,87 @@ func (fake *FakeStorageClient) UploadReturnsOnCall(i int, result1 []byte, result
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) UploadStream(arg1 io.ReadSeekCloser, arg2 string) error {
+func (fake *FakeStorageClient) UploadCheckpointed(arg1 context.Context, arg2 io.ReaderAt, arg3 int64, arg4 string, arg5 common.PutOptions, arg6 *common.Checkpoint) error {
+	fake.uploadCheckpointedMutex.Lock()
+	ret, specificReturn := fake.uploadCheckpointedReturnsOnCall[len(fake.uploadCheckpointedArgsForCall)]
+	fake.uploadCheckpointedArgsForCall = append(fake.uploadCheckpointedArgsForCall, struct {
+		arg1 context.Context
+		arg2 io.ReaderAt
+		arg3 int64
+		arg4 string
+		arg5 common.PutOptions
+		arg6 *common.Checkpoint
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.UploadCheckpointedStub
+	fakeReturns := fake.uploadCheckpointedReturns
+	fake.recordInvocation("UploadCheckpointed", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.uploadCheckpointedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorageClient) UploadCheckpointedCallCount() int {
+	fake.uploadCheckpointedMutex.RLock()
+	defer fake.uploadCheckpointedMutex.RUnlock()
+	return len(fake.uploadCheckpointedArgsForCall)
+}
+
+func (fake *FakeStorageClient) UploadCheckpointedCalls(stub func(context.Context, io.ReaderAt, int64, string, common.PutOptions, *common.Checkpoint) error) {
+	fake.uploadCheckpointedMutex.Lock()
+	defer fake.uploadCheckpointedMutex.Unlock()
+	fake.UploadCheckpointedStub = stub
+}
+
+func (fake *FakeStorageClient) UploadCheckpointedArgsForCall(i int) (context.Context, io.ReaderAt, int64, string, common.PutOptions, *common.Checkpoint) {
+	fake.uploadCheckpointedMutex.RLock()
+	defer fake.uploadCheckpointedMutex.RUnlock()
+	argsForCall := fake.uploadCheckpointedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakeStorageClient) UploadCheckpointedReturns(result1 error) {
+	fake.uploadCheckpointedMutex.Lock()
+	defer fake.uploadCheckpointedMutex.Unlock()
+	fake.UploadCheckpointedStub = nil
+	fake.uploadCheckpointedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorageClient) UploadCheckpointedReturnsOnCall(i int, result1 error) {
+	fake.uploadCheckpointedMutex.Lock()
+	defer fake.uploadCheckpointedMutex.Unlock()
+	fake.UploadCheckpointedStub = nil
+	if fake.uploadCheckpointedReturnsOnCall == nil {
+		fake.uploadCheckpointedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.uploadCheckpointedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorageClient) UploadStream(arg1 context.Context, arg2 io.Reader, arg3 string, arg4 common.PutOptions) error {
 	fake.uploadStreamMutex.Lock()
 	ret, specificReturn := fake.uploadStreamReturnsOnCall[len(fake.uploadStreamArgsForCall)]
 	fake.uploadStreamArgsForCall = append(fake.uploadStreamArgsForCall, struct {
-		arg1 io.ReadSeekCloser
-		arg2 string
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 io.Reader
+		arg3 string
+		arg4 common.PutOptions
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.UploadStreamStub
 	fakeReturns := fake.uploadStreamReturns
-	fake.recordInvocation("UploadStream", []interface{}{arg1, arg2})
+	fake.recordInvocation("UploadStream", []interface{}{arg1, arg2, arg3, arg4})
 	fake.uploadStreamMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -794,17 +1320,17 @@ func (fake *FakeStorageClient) UploadStreamCallCount() int {
 	return len(fake.uploadStreamArgsForCall)
 }
 
-func (fake *FakeStorageClient) UploadStreamCalls(stub func(io.ReadSeekCloser, string) error) {
+func (fake *FakeStorageClient) UploadStreamCalls(stub func(context.Context, io.Reader, string, common.PutOptions) error) {
 	fake.uploadStreamMutex.Lock()
 	defer fake.uploadStreamMutex.Unlock()
 	fake.UploadStreamStub = stub
 }
 
-func (fake *FakeStorageClient) UploadStreamArgsForCall(i int) (io.ReadSeekCloser, string) {
+func (fake *FakeStorageClient) UploadStreamArgsForCall(i int) (context.Context, io.Reader, string, common.PutOptions) {
 	fake.uploadStreamMutex.RLock()
 	defer fake.uploadStreamMutex.RUnlock()
 	argsForCall := fake.uploadStreamArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStorageClient) UploadStreamReturns(result1 error) {
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// errorCodeKinds maps Azure error codes onto the shared kinds of failures,
+// for the codes whose HTTP status is ambiguous.
+var errorCodeKinds = map[bloberror.Code]error{
+	bloberror.ServerBusy:        common.ErrThrottled,
+	bloberror.OperationTimedOut: common.ErrTimeout,
+	// Returned with 409 instead of 412 when If-None-Match: * fails.
+	bloberror.BlobAlreadyExists: common.ErrPreconditionFailed,
+}
+
+// classifyError marks err with the kind of failure reported by Azure.
+func classifyError(err error) error {
+	if err == nil || common.ErrorKind(err) != nil {
+		return err
+	}
+
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		return err
+	}
+	if kind, ok := errorCodeKinds[bloberror.Code(respErr.ErrorCode)]; ok {
+		return common.NewError(kind, err)
+	}
+	return common.ErrorFromHTTPStatus(respErr.StatusCode, err)
+}
+
+func isNotFound(err error) bool {
+	var respErr *azcore.ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound
+}
@@ -2,17 +2,20 @@ package client
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
@@ -22,42 +25,79 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
 
 	"github.com/cloudfoundry/storage-cli/azurebs/config"
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . StorageClient
 type StorageClient interface {
 	Upload(
+		ctx context.Context,
 		source io.ReadSeekCloser,
 		dest string,
+		opts common.PutOptions,
 	) ([]byte, error)
 
 	UploadStream(
-		source io.ReadSeekCloser,
+		ctx context.Context,
+		source io.Reader,
+		dest string,
+		opts common.PutOptions,
+	) error
+
+	UploadCheckpointed(
+		ctx context.Context,
+		source io.ReaderAt,
+		size int64,
 		dest string,
+		opts common.PutOptions,
+		checkpoint *common.Checkpoint,
 	) error
 
 	Download(
+		ctx context.Context,
 		source string,
 		dest *os.File,
+		opts common.GetOptions,
 	) error
 
+	DownloadStream(
+		ctx context.Context,
+		source string,
+		opts common.GetOptions,
+	) (io.ReadCloser, error)
+
 	Copy(
+		ctx context.Context,
 		srcBlob string,
 		destBlob string,
 	) error
 
 	Delete(
+		ctx context.Context,
 		dest string,
+		opts common.DeleteOptions,
 	) error
 
 	DeleteRecursive(
+		ctx context.Context,
 		dest string,
 	) error
 
+	DeleteMany(
+		ctx context.Context,
+		dests []string,
+	) []common.DeleteFailure
+
 	Exists(
+		ctx context.Context,
 		dest string,
 	) (bool, error)
 
+	Stat(
+		ctx context.Context,
+		dest string,
+	) (common.ObjectInfo, bool, error)
+
 	SignedUrl(
 		requestType string,
 		dest string,
@@ -65,12 +105,25 @@ type StorageClient interface {
 	) (string, error)
 
 	List(
+		ctx context.Context,
 		prefix string,
 	) ([]string, error)
+
+	ListObjects(
+		ctx context.Context,
+		prefix string,
+	) ([]common.ObjectInfo, error)
+
+	ListWithOptions(
+		ctx context.Context,
+		opts common.ListOptions,
+	) (common.ListResult, error)
+
 	Properties(
+		ctx context.Context,
 		dest string,
-	) error
-	EnsureContainerExists() error
+	) (common.Properties, error)
+	EnsureContainerExists(ctx context.Context) error
 }
 
 // 4 MB of block size
@@ -79,8 +132,13 @@ const blockSize = int64(4 * 1024 * 1024)
 // number of go routines
 const maxConcurrency = 5
 
-func createContext(dsc DefaultStorageClient) (context.Context, context.CancelFunc, error) {
-	var ctx context.Context
+// maximum number of blobs returned by a page of a blob listing
+const maxListResults = 5000
+
+// maximum number of sub-requests in a blob batch request
+const maxBatchSubRequests = 256
+
+func createContext(ctx context.Context, dsc DefaultStorageClient) (context.Context, context.CancelFunc, error) {
 	var cancel context.CancelFunc
 
 	if dsc.storageConfig.Timeout != "" {
@@ -94,9 +152,9 @@ func createContext(dsc DefaultStorageClient) (context.Context, context.CancelFun
 			slog.Info("Invalid timeout format, need seconds as number e.g. 30", "timeout", dsc.storageConfig.Timeout)
 			return nil, nil, fmt.Errorf("invalid timeout format: %w", err)
 		}
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 	} else {
-		ctx, cancel = context.WithCancel(context.Background())
+		ctx, cancel = context.WithCancel(ctx)
 	}
 
 	return ctx, cancel, nil
@@ -121,8 +179,10 @@ func NewStorageClient(storageConfig config.AZStorageConfig) (StorageClient, erro
 }
 
 func (dsc DefaultStorageClient) Upload(
+	ctx context.Context,
 	source io.ReadSeekCloser,
 	dest string,
+	opts common.PutOptions,
 ) ([]byte, error) {
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
 
@@ -132,7 +192,7 @@ func (dsc DefaultStorageClient) Upload(
 		slog.Info("Uploading blob to container", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
 	}
 
-	ctx, cancel, err := createContext(dsc)
+	ctx, cancel, err := createContext(ctx, dsc)
 	if err != nil {
 		return nil, err
 	}
@@ -143,10 +203,15 @@ func (dsc DefaultStorageClient) Upload(
 		return nil, err
 	}
 
-	uploadResponse, err := client.Upload(ctx, source, nil)
+	headers, metadata := uploadHeaders(opts)
+	uploadResponse, err := client.Upload(ctx, source, &blockblob.UploadOptions{
+		HTTPHeaders:      headers,
+		Metadata:         metadata,
+		AccessConditions: accessConditions(opts.IfMatch, opts.IfNoneMatch),
+	})
 	if err != nil {
 		if dsc.storageConfig.Timeout != "" && errors.Is(err, context.DeadlineExceeded) {
-			return nil, fmt.Errorf("upload failed: timeout of %s reached while uploading %s", dsc.storageConfig.Timeout, dest)
+			return nil, common.NewError(common.ErrTimeout, fmt.Errorf("upload failed: timeout of %s reached while uploading %s", dsc.storageConfig.Timeout, dest))
 		}
 		return nil, fmt.Errorf("upload failure: %w", err)
 	}
@@ -155,9 +220,57 @@ func (dsc DefaultStorageClient) Upload(
 	return uploadResponse.ContentMD5, nil
 }
 
+// uploadHeaders converts opts into the headers and metadata of an upload. The
+// headers are nil if opts sets none, leaving them to the SDK.
+func uploadHeaders(opts common.PutOptions) (*azBlob.HTTPHeaders, map[string]*string) {
+	var headers *azBlob.HTTPHeaders
+	if opts.ContentType != "" || opts.ContentEncoding != "" || opts.CacheControl != "" || opts.ContentDisposition != "" {
+		headers = &azBlob.HTTPHeaders{}
+		if opts.ContentType != "" {
+			headers.BlobContentType = to.Ptr(opts.ContentType)
+		}
+		if opts.ContentEncoding != "" {
+			headers.BlobContentEncoding = to.Ptr(opts.ContentEncoding)
+		}
+		if opts.CacheControl != "" {
+			headers.BlobCacheControl = to.Ptr(opts.CacheControl)
+		}
+		if opts.ContentDisposition != "" {
+			headers.BlobContentDisposition = to.Ptr(opts.ContentDisposition)
+		}
+	}
+
+	var metadata map[string]*string
+	if len(opts.Metadata) > 0 {
+		metadata = make(map[string]*string, len(opts.Metadata))
+		for key, value := range opts.Metadata {
+			metadata[key] = to.Ptr(value)
+		}
+	}
+	return headers, metadata
+}
+
+// accessConditions converts the If-Match and If-None-Match preconditions of a
+// request into access conditions, or nil if neither is set.
+func accessConditions(ifMatch, ifNoneMatch string) *azBlob.AccessConditions {
+	if ifMatch == "" && ifNoneMatch == "" {
+		return nil
+	}
+	conditions := &azBlob.ModifiedAccessConditions{}
+	if ifMatch != "" {
+		conditions.IfMatch = to.Ptr(azcore.ETag(common.QuoteETag(ifMatch)))
+	}
+	if ifNoneMatch != "" {
+		conditions.IfNoneMatch = to.Ptr(azcore.ETag(ifNoneMatch))
+	}
+	return &azBlob.AccessConditions{ModifiedAccessConditions: conditions}
+}
+
 func (dsc DefaultStorageClient) UploadStream(
-	source io.ReadSeekCloser,
+	ctx context.Context,
+	source io.Reader,
 	dest string,
+	opts common.PutOptions,
 ) error {
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
 
@@ -167,7 +280,7 @@ func (dsc DefaultStorageClient) UploadStream(
 		slog.Info("UploadStreaming blob to container", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
 	}
 
-	ctx, cancel, err := createContext(dsc)
+	ctx, cancel, err := createContext(ctx, dsc)
 	if err != nil {
 		return err
 	}
@@ -178,10 +291,18 @@ func (dsc DefaultStorageClient) UploadStream(
 		return err
 	}
 
-	_, err = client.UploadStream(ctx, source, &azblob.UploadStreamOptions{BlockSize: blockSize, Concurrency: maxConcurrency})
+	headers, metadata := uploadHeaders(opts)
+	_, err = client.UploadStream(ctx, source, &azblob.UploadStreamOptions{
+		BlockSize:   blockSize,
+		Concurrency: maxConcurrency,
+		HTTPHeaders: headers,
+		Metadata:    metadata,
+		// Evaluated when the block list is committed.
+		AccessConditions: accessConditions(opts.IfMatch, opts.IfNoneMatch),
+	})
 	if err != nil {
 		if dsc.storageConfig.Timeout != "" && errors.Is(err, context.DeadlineExceeded) {
-			return fmt.Errorf("upload failed: timeout of %s reached while uploading %s", dsc.storageConfig.Timeout, dest)
+			return common.NewError(common.ErrTimeout, fmt.Errorf("upload failed: timeout of %s reached while uploading %s", dsc.storageConfig.Timeout, dest))
 		}
 		return fmt.Errorf("upload failure: %w", err)
 	}
@@ -190,9 +311,132 @@ func (dsc DefaultStorageClient) UploadStream(
 	return nil
 }
 
+// maxBlocks is the Azure limit of blocks in a block blob.
+const maxBlocks = 50000
+
+// UploadCheckpointed uploads size bytes of source as uncommitted blocks,
+// recording the upload in checkpoint, and commits them once all are staged.
+// Blocks staged by an earlier call with the same checkpoint are kept, as
+// Azure holds uncommitted blocks for a week. The block IDs start with a
+// random ID of the upload, so blocks staged by other uploads to the same
+// blob are never mistaken for its own.
+func (dsc DefaultStorageClient) UploadCheckpointed(
+	ctx context.Context,
+	source io.ReaderAt,
+	size int64,
+	dest string,
+	opts common.PutOptions,
+	checkpoint *common.Checkpoint,
+) error {
+	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
+	slog.Info("Uploading blob to container with checkpoint", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
+
+	ctx, cancel, err := createContext(ctx, dsc)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	client, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
+	if err != nil {
+		return err
+	}
+
+	uploadID, partSize := checkpoint.Upload()
+	if uploadID != "" {
+		parts, err := stagedBlocks(ctx, client, uploadID, size, partSize)
+		if err != nil {
+			return fmt.Errorf("listing uncommitted blocks: %w", err)
+		}
+		if err := checkpoint.SetParts(parts); err != nil {
+			return err
+		}
+		slog.Info("Resuming checkpointed upload", "blob", dest, "upload_id", uploadID, "staged_blocks", len(parts))
+	} else {
+		random := make([]byte, 8)
+		if _, err := rand.Read(random); err != nil {
+			return err
+		}
+		uploadID = hex.EncodeToString(random)
+		if err := checkpoint.Start(uploadID, max(blockSize, (size+maxBlocks-1)/maxBlocks)); err != nil {
+			return err
+		}
+	}
+
+	err = checkpoint.UploadParts(ctx, maxConcurrency, func(ctx context.Context, number int, offset, length int64) (common.CheckpointPart, error) {
+		body := streaming.NopCloser(io.NewSectionReader(source, offset, length))
+		_, err := client.StageBlock(ctx, blockID(uploadID, number), body, nil)
+		return common.CheckpointPart{}, err
+	})
+	if err != nil {
+		return fmt.Errorf("upload failure, rerun to resume upload %s: %w", uploadID, err)
+	}
+
+	blockIDs := make([]string, 0, checkpoint.PartCount())
+	for number := 1; number <= checkpoint.PartCount(); number++ {
+		blockIDs = append(blockIDs, blockID(uploadID, number))
+	}
+	headers, metadata := uploadHeaders(opts)
+	_, err = client.CommitBlockList(ctx, blockIDs, &blockblob.CommitBlockListOptions{
+		HTTPHeaders:      headers,
+		Metadata:         metadata,
+		AccessConditions: accessConditions(opts.IfMatch, opts.IfNoneMatch),
+	})
+	if errors.Is(classifyError(err), common.ErrPreconditionFailed) {
+		// Resuming cannot succeed either; Azure discards the blocks.
+		checkpoint.Remove() //nolint:errcheck
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("committing blocks, rerun to resume upload %s: %w", uploadID, err)
+	}
+
+	slog.Info("Successfully uploaded blob", "container", dsc.storageConfig.ContainerName, "blob", dest, "upload_id", uploadID)
+	return checkpoint.Remove()
+}
+
+// blockID returns the base64-encoded ID of a block of an upload. IDs of a
+// blob must all have the same length.
+func blockID(uploadID string, number int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s-%06d", uploadID, number)))
+}
+
+// stagedBlocks returns the uncommitted blocks of the upload with the size
+// of a part of partSize bytes of a size-byte upload, by part number.
+func stagedBlocks(ctx context.Context, client *blockblob.Client, uploadID string, size int64, partSize int64) (map[int]common.CheckpointPart, error) {
+	parts := map[int]common.CheckpointPart{}
+	resp, err := client.GetBlockList(ctx, blockblob.BlockListTypeUncommitted, nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return parts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range resp.UncommittedBlocks {
+		if block.Name == nil || block.Size == nil {
+			continue
+		}
+		name, err := base64.StdEncoding.DecodeString(*block.Name)
+		if err != nil {
+			continue
+		}
+		var number int
+		if _, err := fmt.Sscanf(string(name), uploadID+"-%06d", &number); err != nil || number < 1 {
+			continue
+		}
+		offset := int64(number-1) * partSize
+		if offset < size && *block.Size == min(partSize, size-offset) {
+			parts[number] = common.CheckpointPart{}
+		}
+	}
+	return parts, nil
+}
+
 func (dsc DefaultStorageClient) Download(
+	ctx context.Context,
 	source string,
 	dest *os.File,
+	opts common.GetOptions,
 ) error {
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, source)
 	slog.Info("Downloading blob from container", "container", dsc.storageConfig.ContainerName, "blob", source, "local_file", dest.Name())
@@ -201,7 +445,15 @@ func (dsc DefaultStorageClient) Download(
 		return err
 	}
 
-	blobSize, err := client.DownloadFile(context.Background(), dest, nil) //nolint:ineffassign,staticcheck
+	blobRange, err := httpRange(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+
+	blobSize, err := client.DownloadFile(ctx, dest, &azBlob.DownloadFileOptions{ //nolint:ineffassign,staticcheck
+		Range:            blobRange,
+		AccessConditions: accessConditions(opts.IfMatch, ""),
+	})
 	if err != nil {
 		return err
 	}
@@ -217,7 +469,58 @@ func (dsc DefaultStorageClient) Download(
 	return nil
 }
 
+func (dsc DefaultStorageClient) DownloadStream(
+	ctx context.Context,
+	source string,
+	opts common.GetOptions,
+) (io.ReadCloser, error) {
+	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, source)
+	slog.Info("Streaming blob from container", "container", dsc.storageConfig.ContainerName, "blob", source)
+	client, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	blobRange, err := httpRange(ctx, client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.DownloadStream(ctx, &azBlob.DownloadStreamOptions{
+		Range:            blobRange,
+		AccessConditions: accessConditions(opts.IfMatch, ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The retry reader re-issues the ranged request if the connection drops
+	// mid-stream, so long transfers survive transient network errors.
+	return resp.NewRetryReader(ctx, nil), nil
+}
+
+// httpRange resolves the range of opts against the size of the blob, as
+// Azure addresses ranges by offset and count and can neither read a suffix
+// nor clamp an end past the blob. It returns the whole blob without a range.
+func httpRange(ctx context.Context, client *blockblob.Client, opts common.GetOptions) (azBlob.HTTPRange, error) {
+	if opts.Range == nil {
+		return azBlob.HTTPRange{}, nil
+	}
+	props, err := client.GetProperties(ctx, &azBlob.GetPropertiesOptions{
+		AccessConditions: accessConditions(opts.IfMatch, ""),
+	})
+	if err != nil {
+		return azBlob.HTTPRange{}, err
+	}
+	offset, count, err := opts.Range.Resolve(*props.ContentLength)
+	if err != nil {
+		return azBlob.HTTPRange{}, err
+	}
+	return azBlob.HTTPRange{Offset: offset, Count: count}, nil
+}
+
 func (dsc DefaultStorageClient) Copy(
+	ctx context.Context,
 	srcBlob string,
 	destBlob string,
 ) error {
@@ -231,7 +534,7 @@ func (dsc DefaultStorageClient) Copy(
 		return fmt.Errorf("failed to create destination client: %w", err)
 	}
 
-	resp, err := destClient.StartCopyFromURL(context.Background(), srcURL, nil)
+	resp, err := destClient.StartCopyFromURL(ctx, srcURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to start copy: %w", err)
 	}
@@ -241,7 +544,7 @@ func (dsc DefaultStorageClient) Copy(
 
 	// Wait for completion
 	for {
-		props, err := destClient.GetProperties(context.Background(), nil)
+		props, err := destClient.GetProperties(ctx, nil)
 		if err != nil {
 			return fmt.Errorf("failed to get properties: %w", err)
 		}
@@ -254,7 +557,11 @@ func (dsc DefaultStorageClient) Copy(
 			slog.Info("Copy completed successfully", "container", dsc.storageConfig.ContainerName, "source_blob", srcBlob, "dest_blob", destBlob)
 			return nil
 		case "pending":
-			time.Sleep(200 * time.Millisecond)
+			select {
+			case <-time.After(200 * time.Millisecond):
+			case <-ctx.Done():
+				return fmt.Errorf("waiting for copy %s: %w", copyID, ctx.Err())
+			}
 		default:
 			return fmt.Errorf("copy failed or aborted with status: %s", copyStatus)
 		}
@@ -262,7 +569,9 @@ func (dsc DefaultStorageClient) Copy(
 }
 
 func (dsc DefaultStorageClient) Delete(
+	ctx context.Context,
 	dest string,
+	opts common.DeleteOptions,
 ) error {
 
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
@@ -273,20 +582,93 @@ func (dsc DefaultStorageClient) Delete(
 		return err
 	}
 
-	_, err = client.Delete(context.Background(), nil)
+	_, err = client.Delete(ctx, &azBlob.DeleteOptions{
+		AccessConditions: accessConditions(opts.IfMatch, ""),
+	})
 
 	if err == nil {
 		return nil
 	}
 
-	if strings.Contains(err.Error(), "RESPONSE 404") {
+	if isNotFound(err) {
+		if opts.IfMatch != "" {
+			return common.NewError(common.ErrPreconditionFailed, fmt.Errorf("blob %q does not exist", dest))
+		}
 		return nil
 	}
 
 	return err
 }
 
+// DeleteMany deletes dests with blob batch requests of at most
+// maxBatchSubRequests deletes.
+func (dsc DefaultStorageClient) DeleteMany(
+	ctx context.Context,
+	dests []string,
+) []common.DeleteFailure {
+	slog.Info("Deleting blobs from container", "container", dsc.storageConfig.ContainerName, "blobs", len(dests))
+
+	containerClient, err := azContainer.NewClientWithSharedKeyCredential(dsc.serviceURL, dsc.credential, nil)
+	if err != nil {
+		return deleteFailures(dests, fmt.Errorf("failed to create container client: %w", err))
+	}
+
+	var failures []common.DeleteFailure
+	for start := 0; start < len(dests); start += maxBatchSubRequests {
+		batch := dests[start:min(start+maxBatchSubRequests, len(dests))]
+		failures = append(failures, dsc.deleteBatch(ctx, containerClient, batch)...)
+	}
+	return failures
+}
+
+func (dsc DefaultStorageClient) deleteBatch(
+	ctx context.Context,
+	containerClient *azContainer.Client,
+	dests []string,
+) []common.DeleteFailure {
+	builder, err := containerClient.NewBatchBuilder()
+	if err != nil {
+		return deleteFailures(dests, fmt.Errorf("failed to create batch: %w", err))
+	}
+	for _, dest := range dests {
+		if err := builder.Delete(dest, nil); err != nil {
+			return deleteFailures(dests, fmt.Errorf("failed to add %q to batch: %w", dest, err))
+		}
+	}
+
+	resp, err := containerClient.SubmitBatch(ctx, builder, nil)
+	if err != nil {
+		return deleteFailures(dests, fmt.Errorf("failed to submit batch delete: %w", err))
+	}
+
+	var failures []common.DeleteFailure
+	for _, item := range resp.Responses {
+		if item.Error == nil || isNotFound(item.Error) {
+			continue
+		}
+		// Sub-responses are matched to the requests by their content ID.
+		var dest string
+		if item.ContentID != nil && *item.ContentID < len(dests) {
+			dest = dests[*item.ContentID]
+		} else if item.BlobName != nil {
+			dest = *item.BlobName
+		}
+		failures = append(failures, common.DeleteFailure{Key: dest, Err: item.Error})
+	}
+	return failures
+}
+
+// deleteFailures reports every blob as failed with err.
+func deleteFailures(dests []string, err error) []common.DeleteFailure {
+	failures := make([]common.DeleteFailure, len(dests))
+	for i, dest := range dests {
+		failures[i] = common.DeleteFailure{Key: dest, Err: err}
+	}
+	return failures
+}
+
 func (dsc DefaultStorageClient) DeleteRecursive(
+	ctx context.Context,
 	prefix string,
 ) error {
 	if prefix != "" {
@@ -307,10 +689,11 @@ func (dsc DefaultStorageClient) DeleteRecursive(
 
 	pager := containerClient.NewListBlobsFlatPager(options)
 
+	var failures []common.DeleteFailure
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx)
 		if err != nil {
-			return fmt.Errorf("error retrieving page of blobs: %w", err)
+			return errors.Join(fmt.Errorf("error retrieving page of blobs: %w", err), common.JoinDeleteFailures(failures))
 		}
 
 		for _, blob := range resp.Segment.BlobItems {
@@ -318,20 +701,23 @@ func (dsc DefaultStorageClient) DeleteRecursive(
 			blobClient, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
 			if err != nil {
 				slog.Error("Failed to create blob client", "blob", *blob.Name, "error", err)
+				failures = append(failures, common.DeleteFailure{Key: *blob.Name, Err: err})
 				continue
 			}
 
-			_, err = blobClient.BlobClient().Delete(context.Background(), nil)
-			if err != nil && !strings.Contains(err.Error(), "RESPONSE 404") {
+			_, err = blobClient.BlobClient().Delete(ctx, nil)
+			if err != nil && !isNotFound(err) {
 				slog.Error("Failed to delete blob", "blob", *blob.Name, "error", err)
+				failures = append(failures, common.DeleteFailure{Key: *blob.Name, Err: err})
 			}
 		}
 	}
 
-	return nil
+	return common.JoinDeleteFailures(failures)
 }
 
 func (dsc DefaultStorageClient) Exists(
+	ctx context.Context,
 	dest string,
 ) (bool, error) {
 
@@ -343,12 +729,12 @@ func (dsc DefaultStorageClient) Exists(
 		return false, err
 	}
 
-	_, err = client.BlobClient().GetProperties(context.Background(), nil)
+	_, err = client.BlobClient().GetProperties(ctx, nil)
 	if err == nil {
 		slog.Info("Blob exists in container", "container", dsc.storageConfig.ContainerName, "blob", dest)
 		return true, nil
 	}
-	if strings.Contains(err.Error(), "RESPONSE 404") {
+	if isNotFound(err) {
 		slog.Info("Blob does not exist in container", "container", dsc.storageConfig.ContainerName, "blob", dest)
 		return false, nil
 	}
@@ -356,6 +742,42 @@ func (dsc DefaultStorageClient) Exists(
 	return false, err
 }
 
+func (dsc DefaultStorageClient) Stat(
+	ctx context.Context,
+	dest string,
+) (common.ObjectInfo, bool, error) {
+	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
+
+	slog.Info("Getting metadata for blob", "container", dsc.storageConfig.ContainerName, "blob", dest)
+	client, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
+	if err != nil {
+		return common.ObjectInfo{}, false, err
+	}
+
+	resp, err := client.GetProperties(ctx, nil)
+	if err != nil {
+		if isNotFound(err) {
+			return common.ObjectInfo{}, false, nil
+		}
+		return common.ObjectInfo{}, false, fmt.Errorf("failed to get properties for blob %s: %w", dest, err)
+	}
+
+	info := common.ObjectInfo{Name: dest}
+	if resp.ContentLength != nil {
+		info.Size = *resp.ContentLength
+	}
+	if resp.ETag != nil {
+		info.ETag = common.TrimETag(string(*resp.ETag))
+	}
+	if resp.LastModified != nil {
+		info.LastModified = *resp.LastModified
+	}
+	if len(resp.ContentMD5) > 0 {
+		info.ContentMD5 = hex.EncodeToString(resp.ContentMD5)
+	}
+	return info, true, nil
+}
+
 func (dsc DefaultStorageClient) SignedUrl(
 	requestType string,
 	dest string,
@@ -389,84 +811,192 @@ func (dsc DefaultStorageClient) SignedUrl(
 }
 
 func (dsc DefaultStorageClient) List(
+	ctx context.Context,
 	prefix string,
 ) ([]string, error) {
+	objects, err := dsc.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return common.ObjectNames(objects), nil
+}
 
-	if prefix != "" {
-		slog.Info("Listing blobs in container", "container", dsc.storageConfig.ContainerName, "prefix", prefix)
+func (dsc DefaultStorageClient) ListObjects(
+	ctx context.Context,
+	prefix string,
+) ([]common.ObjectInfo, error) {
+	result, err := dsc.ListWithOptions(ctx, common.ListOptions{Prefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+	return result.Objects, nil
+}
+
+func (dsc DefaultStorageClient) ListWithOptions(
+	ctx context.Context,
+	opts common.ListOptions,
+) (common.ListResult, error) {
+
+	if opts.Prefix != "" {
+		slog.Info("Listing blobs in container", "container", dsc.storageConfig.ContainerName, "prefix", opts.Prefix, "delimiter", opts.Delimiter)
 	} else {
-		slog.Info("Listing blobs in container", "container", dsc.storageConfig.ContainerName)
+		slog.Info("Listing blobs in container", "container", dsc.storageConfig.ContainerName, "delimiter", opts.Delimiter)
 	}
 
 	client, err := azContainer.NewClientWithSharedKeyCredential(dsc.serviceURL, dsc.credential, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create container client: %w", err)
+		return common.ListResult{}, fmt.Errorf("failed to create container client: %w", err)
 	}
 
-	options := &azContainer.ListBlobsFlatOptions{}
-	if prefix != "" {
-		options.Prefix = &prefix
+	var prefix, marker *string
+	if opts.Prefix != "" {
+		prefix = &opts.Prefix
+	}
+	if opts.PageToken != "" {
+		marker = &opts.PageToken
+	}
+	var maxResults *int32
+	if opts.MaxKeys > 0 {
+		maxResults = to.Ptr(int32(min(opts.MaxKeys, maxListResults)))
 	}
 
-	pager := client.NewListBlobsFlatPager(options)
-	var blobs []string
+	// Blob listings cannot start after a name, so the names up to
+	// opts.StartAfter are listed and skipped.
+	var result common.ListResult
+	addBlobs := func(blobs []*azContainer.BlobItem) {
+		for _, blob := range blobs {
+			if *blob.Name > opts.StartAfter {
+				result.Objects = append(result.Objects, blobInfo(blob))
+			}
+		}
+	}
+
+	if opts.Delimiter == "" {
+		pager := client.NewListBlobsFlatPager(&azContainer.ListBlobsFlatOptions{Prefix: prefix, Marker: marker, MaxResults: maxResults})
+		for pager.More() {
+			resp, err := pager.NextPage(ctx)
+			if err != nil {
+				return common.ListResult{}, fmt.Errorf("error retrieving page of blobs: %w", err)
+			}
 
+			addBlobs(resp.Segment.BlobItems)
+			if opts.MaxKeys > 0 {
+				if resp.NextMarker != nil {
+					result.NextPageToken = *resp.NextMarker
+				}
+				break
+			}
+		}
+		return result, nil
+	}
+
+	pager := client.NewListBlobsHierarchyPager(opts.Delimiter, &azContainer.ListBlobsHierarchyOptions{Prefix: prefix, Marker: marker, MaxResults: maxResults})
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("error retrieving page of blobs: %w", err)
+			return common.ListResult{}, fmt.Errorf("error retrieving page of blobs: %w", err)
 		}
 
-		for _, blob := range resp.Segment.BlobItems {
-			blobs = append(blobs, *blob.Name)
+		addBlobs(resp.Segment.BlobItems)
+		for _, blobPrefix := range resp.Segment.BlobPrefixes {
+			if *blobPrefix.Name > opts.StartAfter {
+				result.Prefixes = append(result.Prefixes, *blobPrefix.Name)
+			}
+		}
+		if opts.MaxKeys > 0 {
+			if resp.NextMarker != nil {
+				result.NextPageToken = *resp.NextMarker
+			}
+			break
 		}
 	}
-
-	return blobs, nil
+	return result, nil
 }
 
-type BlobProperties struct {
-	ETag          string    `json:"etag,omitempty"`
-	LastModified  time.Time `json:"last_modified,omitempty"`
-	ContentLength int64     `json:"content_length,omitempty"`
+// blobInfo converts an entry of a blob listing.
+func blobInfo(blob *azContainer.BlobItem) common.ObjectInfo {
+	info := common.ObjectInfo{Name: *blob.Name}
+	props := blob.Properties
+	if props == nil {
+		return info
+	}
+	if props.ContentLength != nil {
+		info.Size = *props.ContentLength
+	}
+	if props.ETag != nil {
+		info.ETag = common.TrimETag(string(*props.ETag))
+	}
+	if props.LastModified != nil {
+		info.LastModified = *props.LastModified
+	}
+	if len(props.ContentMD5) > 0 {
+		info.ContentMD5 = hex.EncodeToString(props.ContentMD5)
+	}
+	if props.AccessTier != nil {
+		info.StorageClass = string(*props.AccessTier)
+	}
+	return info
 }
 
 func (dsc DefaultStorageClient) Properties(
+	ctx context.Context,
 	dest string,
-) error {
+) (common.Properties, error) {
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
 
 	slog.Info("Getting properties for blob", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
 	client, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
 	if err != nil {
-		return err
+		return common.Properties{}, err
 	}
 
-	resp, err := client.GetProperties(context.Background(), nil)
+	resp, err := client.GetProperties(ctx, nil)
 	if err != nil {
-		if strings.Contains(err.Error(), "RESPONSE 404") {
-			fmt.Println(`{}`)
-			return nil
+		return common.Properties{}, fmt.Errorf("failed to get properties for blob %s: %w", dest, err)
+	}
+
+	props := common.Properties{
+		ContentType:        deref(resp.ContentType),
+		ContentEncoding:    deref(resp.ContentEncoding),
+		CacheControl:       deref(resp.CacheControl),
+		ContentDisposition: deref(resp.ContentDisposition),
+		ContentMD5:         hex.EncodeToString(resp.ContentMD5),
+		StorageClass:       deref(resp.AccessTier),
+		Encryption: common.Encryption{
+			KMSKeyID:          deref(resp.EncryptionScope),
+			CustomerKeySHA256: deref(resp.EncryptionKeySHA256),
+		},
+	}
+	if resp.ETag != nil {
+		props.ETag = common.TrimETag(string(*resp.ETag))
+	}
+	if resp.LastModified != nil {
+		props.LastModified = *resp.LastModified
+	}
+	if resp.ContentLength != nil {
+		props.ContentLength = *resp.ContentLength
+	}
+	if resp.IsServerEncrypted != nil && *resp.IsServerEncrypted {
+		// Azure Storage encrypts with 256-bit AES.
+		props.Encryption.Algorithm = "AES256"
+	}
+	if len(resp.Metadata) > 0 {
+		props.Metadata = make(map[string]string, len(resp.Metadata))
+		for key, value := range resp.Metadata {
+			props.Metadata[key] = deref(value)
 		}
-		return fmt.Errorf("failed to get properties for blob %s: %w", dest, err)
-	}
-
-	props := BlobProperties{
-		ETag:          strings.Trim(string(*resp.ETag), `"`),
-		LastModified:  *resp.LastModified,
-		ContentLength: *resp.ContentLength,
 	}
+	return props, nil
+}
 
-	output, err := json.MarshalIndent(props, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal blob properties: %w", err)
+func deref(s *string) string {
+	if s == nil {
+		return ""
 	}
-
-	fmt.Println(string(output))
-	return nil
+	return *s
 }
 
-func (dsc DefaultStorageClient) EnsureContainerExists() error {
+func (dsc DefaultStorageClient) EnsureContainerExists(ctx context.Context) error {
 	slog.Info("Ensuring container exists", "container", dsc.storageConfig.ContainerName)
 
 	containerClient, err := azContainer.NewClientWithSharedKeyCredential(dsc.serviceURL, dsc.credential, nil)
@@ -474,7 +1004,7 @@ func (dsc DefaultStorageClient) EnsureContainerExists() error {
 		return fmt.Errorf("failed to create container client: %w", err)
 	}
 
-	_, err = containerClient.Create(context.Background(), nil)
+	_, err = containerClient.Create(ctx, nil)
 	if err != nil {
 		var respErr *azcore.ResponseError
 		if errors.As(err, &respErr) && respErr.ErrorCode == string(bloberror.ContainerAlreadyExists) {
@@ -294,7 +294,7 @@ func AssertOnListDeleteLifecyle(cliPath string, cfg *config.AZStorageConfig) {
 	configPath := MakeConfigFile(cfg)
 	defer os.Remove(configPath) //nolint:errcheck
 
-	cli, err := RunCli(cliPath, configPath, storageType, "delete-recursive", "")
+	cli, err := RunCli(cliPath, configPath, storageType, "delete-recursive", "--all")
 	Expect(err).ToNot(HaveOccurred())
 	Expect(cli.ExitCode()).To(BeZero())
 	cliSession, err := RunCli(cliPath, configPath, storageType, "list")
@@ -341,7 +341,7 @@ func AssertOnListDeleteLifecyle(cliPath string, cfg *config.AZStorageConfig) {
 	Expect(len(bytes.FieldsFunc(cliSession.Out.Contents(), func(r rune) bool { return r == '\n' || r == '\r' }))).To(BeNumerically("==", 2))
 
 	// Delete all other blobs
-	cliSession, err = RunCli(cliPath, configPath, storageType, "delete-recursive", "")
+	cliSession, err = RunCli(cliPath, configPath, storageType, "delete-recursive", "--all")
 	Expect(err).ToNot(HaveOccurred())
 	Expect(cliSession.ExitCode()).To(BeZero())
 
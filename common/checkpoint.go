@@ -0,0 +1,220 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpoint records the progress of a multipart upload in a local file, so
+// that an upload interrupted by a crash can be resumed by a later process
+// instead of starting over. It is safe for concurrent use.
+type Checkpoint struct {
+	path  string
+	mu    sync.Mutex
+	state checkpointState
+}
+
+type checkpointState struct {
+	Source  string    `json:"source"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Dest    string    `json:"dest"`
+	// UploadID is the backend's handle on the upload, such as the S3
+	// upload ID or the GCS session URI.
+	UploadID string                 `json:"upload_id,omitempty"`
+	PartSize int64                  `json:"part_size,omitempty"`
+	Parts    map[int]CheckpointPart `json:"parts,omitempty"`
+}
+
+// CheckpointPart is an uploaded part recorded in a Checkpoint.
+type CheckpointPart struct {
+	ETag     string `json:"etag,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// LoadCheckpoint reads the checkpoint at path for uploading source to dest.
+// A missing checkpoint, or one recorded for another destination or another
+// version of source, as told by its size and modification time, is replaced
+// by an empty one, so that the upload starts over.
+func LoadCheckpoint(path string, source *os.File, dest string) (*Checkpoint, error) {
+	info, err := source.Stat()
+	if err != nil {
+		return nil, err
+	}
+	sourcePath, err := filepath.Abs(source.Name())
+	if err != nil {
+		return nil, err
+	}
+	fresh := checkpointState{Source: sourcePath, Size: info.Size(), ModTime: info.ModTime().UTC(), Dest: dest}
+	checkpoint := &Checkpoint{path: path, state: fresh}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	var recorded checkpointState
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return nil, fmt.Errorf("reading checkpoint %s: %w", path, err)
+	}
+	if recorded.Source != fresh.Source || recorded.Size != fresh.Size || !recorded.ModTime.Equal(fresh.ModTime) || recorded.Dest != fresh.Dest {
+		slog.Warn("Discarding checkpoint of another upload", "checkpoint", path, "source", recorded.Source, "dest", recorded.Dest, "upload_id", recorded.UploadID)
+		return checkpoint, nil
+	}
+	checkpoint.state = recorded
+	return checkpoint, nil
+}
+
+// Upload returns the upload ID and part size of the recorded upload, or an
+// empty ID if no upload was started.
+func (c *Checkpoint) Upload() (uploadID string, partSize int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.UploadID, c.state.PartSize
+}
+
+// Parts returns the recorded parts by part number.
+func (c *Checkpoint) Parts() map[int]CheckpointPart {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.state.Parts)
+}
+
+// Start records a new upload, forgetting the parts of any previous one.
+func (c *Checkpoint) Start(uploadID string, partSize int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.UploadID = uploadID
+	c.state.PartSize = partSize
+	c.state.Parts = nil
+	return c.save()
+}
+
+// SetParts replaces the recorded parts with the ones the backend reports
+// for the upload.
+func (c *Checkpoint) SetParts(parts map[int]CheckpointPart) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.Parts = parts
+	return c.save()
+}
+
+// AddPart records an uploaded part.
+func (c *Checkpoint) AddPart(number int, part CheckpointPart) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state.Parts == nil {
+		c.state.Parts = map[int]CheckpointPart{}
+	}
+	c.state.Parts[number] = part
+	return c.save()
+}
+
+// Remove deletes the checkpoint once the upload completed.
+func (c *Checkpoint) Remove() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// save replaces the checkpoint file atomically, so a crash while saving
+// leaves the previous state behind.
+func (c *Checkpoint) save() error {
+	data, err := json.Marshal(c.state)
+	if err != nil {
+		return err
+	}
+	tmpPath := c.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// UploadParts calls upload for every part of the upload that is not
+// recorded yet, at most concurrency at a time, and records the part each
+// call returns. Parts of partSize bytes are numbered from 1 and the last one
+// holds the rest. It returns the first error once the calls in flight have
+// finished, leaving the completed parts recorded for a later attempt.
+func (c *Checkpoint) UploadParts(ctx context.Context, concurrency int, upload func(ctx context.Context, number int, offset, length int64) (CheckpointPart, error)) error {
+	_, partSize := c.Upload()
+	if partSize <= 0 {
+		return errors.New("checkpoint has no part size")
+	}
+	done := c.Parts()
+	size := c.state.Size
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	work := make(chan int)
+	errs := make(chan error, 1)
+	wg := &sync.WaitGroup{}
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range work {
+				offset := int64(number-1) * partSize
+				part, err := upload(ctx, number, offset, min(partSize, size-offset))
+				if err == nil {
+					err = c.AddPart(number, part)
+				}
+				if err != nil {
+					select {
+					case errs <- fmt.Errorf("uploading part %d: %w", number, err):
+					default:
+					}
+					cancel()
+				}
+			}
+		}()
+	}
+
+	for number := 1; int64(number-1)*partSize < size; number++ {
+		if _, ok := done[number]; ok {
+			continue
+		}
+		select {
+		case work <- number:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+// PartCount returns the number of parts of the upload.
+func (c *Checkpoint) PartCount() int {
+	_, partSize := c.Upload()
+	if partSize <= 0 {
+		return 0
+	}
+	return int((c.state.Size + partSize - 1) / partSize)
+}
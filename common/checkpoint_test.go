@@ -0,0 +1,104 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checkpoint", func() {
+	var (
+		source         *os.File
+		checkpointPath string
+	)
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		checkpointPath = filepath.Join(dir, "upload.checkpoint")
+		var err error
+		source, err = os.Create(filepath.Join(dir, "droplet.tgz"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(source.Close)
+		_, err = source.WriteString("0123456789")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	load := func(dest string) *Checkpoint {
+		checkpoint, err := LoadCheckpoint(checkpointPath, source, dest)
+		Expect(err).NotTo(HaveOccurred())
+		return checkpoint
+	}
+
+	It("resumes the upload recorded for the same source and destination", func() {
+		checkpoint := load("droplet")
+		Expect(checkpoint.Upload()).To(BeEmpty())
+		Expect(checkpoint.Start("upload-1", 4)).To(Succeed())
+		Expect(checkpoint.AddPart(2, CheckpointPart{ETag: "b"})).To(Succeed())
+
+		checkpoint = load("droplet")
+		uploadID, partSize := checkpoint.Upload()
+		Expect(uploadID).To(Equal("upload-1"))
+		Expect(partSize).To(Equal(int64(4)))
+		Expect(checkpoint.Parts()).To(Equal(map[int]CheckpointPart{2: {ETag: "b"}}))
+		Expect(checkpoint.PartCount()).To(Equal(3))
+	})
+
+	It("discards the upload recorded for another destination or source version", func() {
+		Expect(load("droplet").Start("upload-1", 4)).To(Succeed())
+
+		uploadID, _ := load("other").Upload()
+		Expect(uploadID).To(BeEmpty())
+
+		later := time.Now().Add(time.Hour)
+		Expect(os.Chtimes(source.Name(), later, later)).To(Succeed())
+		uploadID, _ = load("droplet").Upload()
+		Expect(uploadID).To(BeEmpty())
+	})
+
+	It("removes its file", func() {
+		checkpoint := load("droplet")
+		Expect(checkpoint.Start("upload-1", 4)).To(Succeed())
+		Expect(checkpointPath).To(BeAnExistingFile())
+		Expect(checkpoint.Remove()).To(Succeed())
+		Expect(checkpointPath).NotTo(BeAnExistingFile())
+		Expect(checkpoint.Remove()).To(Succeed())
+	})
+
+	It("uploads the parts that are not recorded yet", func() {
+		checkpoint := load("droplet")
+		Expect(checkpoint.Start("upload-1", 4)).To(Succeed())
+		Expect(checkpoint.AddPart(1, CheckpointPart{ETag: "a"})).To(Succeed())
+
+		mu := sync.Mutex{}
+		uploaded := map[int][2]int64{}
+		err := checkpoint.UploadParts(context.Background(), 2, func(_ context.Context, number int, offset, length int64) (CheckpointPart, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			uploaded[number] = [2]int64{offset, length}
+			return CheckpointPart{ETag: string(rune('a' + number - 1))}, nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(uploaded).To(Equal(map[int][2]int64{2: {4, 4}, 3: {8, 2}}))
+		Expect(load("droplet").Parts()).To(HaveLen(3))
+	})
+
+	It("keeps the completed parts when a part fails", func() {
+		checkpoint := load("droplet")
+		Expect(checkpoint.Start("upload-1", 4)).To(Succeed())
+
+		err := checkpoint.UploadParts(context.Background(), 1, func(_ context.Context, number int, _, _ int64) (CheckpointPart, error) {
+			if number == 2 {
+				return CheckpointPart{}, errors.New("connection reset")
+			}
+			return CheckpointPart{ETag: "a"}, nil
+		})
+		Expect(err).To(MatchError("uploading part 2: connection reset"))
+		Expect(load("droplet").Parts()).To(HaveKey(1))
+	})
+})
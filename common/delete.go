@@ -0,0 +1,92 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultDeleteParallelism is the number of concurrent deletes used by
+// backends without a native batch delete.
+const DefaultDeleteParallelism = 16
+
+// DeleteOptions sets the preconditions of a delete.
+type DeleteOptions struct {
+	// IfMatch only deletes the object if its ETag matches. A missing object
+	// then fails the precondition instead of counting as deleted.
+	IfMatch string
+}
+
+// DeleteFailure reports a key that could not be deleted.
+type DeleteFailure struct {
+	Key string
+	Err error
+}
+
+// DeleteConcurrently deletes every key with del, running at most parallel
+// calls at a time, and returns the failures in the order of keys. Like del,
+// it reports missing keys as deleted.
+func DeleteConcurrently(ctx context.Context, keys []string, parallel int, del func(ctx context.Context, key string) error) []DeleteFailure {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	type failure struct {
+		index int
+		DeleteFailure
+	}
+
+	var (
+		failures []failure
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		work     = make(chan int)
+	)
+	for range parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				err := ctx.Err()
+				if err == nil {
+					err = del(ctx, keys[i])
+				}
+				if err != nil {
+					mu.Lock()
+					failures = append(failures, failure{i, DeleteFailure{Key: keys[i], Err: err}})
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range keys {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	if len(failures) == 0 {
+		return nil
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].index < failures[j].index })
+	result := make([]DeleteFailure, len(failures))
+	for i, f := range failures {
+		result[i] = f.DeleteFailure
+	}
+	return result
+}
+
+// JoinDeleteFailures returns an error naming every failed key and its
+// reason, or nil if there are no failures.
+func JoinDeleteFailures(failures []DeleteFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	errs := make([]error, len(failures))
+	for i, failure := range failures {
+		errs[i] = fmt.Errorf("deleting %q: %w", failure.Key, failure.Err)
+	}
+	return fmt.Errorf("failed to delete %d objects: %w", len(failures), errors.Join(errs...))
+}
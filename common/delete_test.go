@@ -0,0 +1,47 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteConcurrently", func() {
+	It("returns the failures in the order of the keys", func() {
+		keys := []string{"a", "bad-1", "b", "bad-2", "c", "bad-3"}
+		failures := DeleteConcurrently(context.Background(), keys, 4, func(_ context.Context, key string) error {
+			if strings.HasPrefix(key, "bad") {
+				return errors.New("cannot delete " + key)
+			}
+			return nil
+		})
+
+		Expect(failures).To(HaveLen(3))
+		for i, failure := range failures {
+			Expect(failure.Key).To(Equal(keys[2*i+1]))
+			Expect(failure.Err).To(MatchError("cannot delete " + failure.Key))
+		}
+	})
+
+	It("returns no failures when every key is deleted", func() {
+		failures := DeleteConcurrently(context.Background(), []string{"a", "b"}, 2, func(context.Context, string) error { return nil })
+		Expect(failures).To(BeNil())
+	})
+
+	It("fails the remaining keys once the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		calls := 0
+		failures := DeleteConcurrently(ctx, []string{"a", "b"}, 1, func(context.Context, string) error {
+			calls++
+			return nil
+		})
+		Expect(calls).To(Equal(0))
+		Expect(failures).To(HaveLen(2))
+		Expect(failures[0].Err).To(MatchError(context.Canceled))
+	})
+})
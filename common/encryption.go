@@ -0,0 +1,439 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Objects written with client-side encryption start with envelopeMagic, the
+// length of the header as a big-endian uint16 and the JSON header, followed
+// by the content sealed in chunks of encryptionChunkSize bytes with
+// AES-256-GCM under a random data key. The data key is wrapped with the key
+// named in the header. Chunk nonces are the nonce prefix of the header, the
+// chunk number and a flag set on the last chunk, so chunks cannot be
+// reordered or dropped, and every chunk authenticates the header.
+const (
+	envelopeMagic       = "SCLIENC\x01"
+	encryptionChunkSize = 64 * 1024
+	noncePrefixSize     = 7
+	// EnvelopePrefixMax is the largest size of the magic, length and header
+	// preceding the content of an encrypted object.
+	EnvelopePrefixMax = len(envelopeMagic) + 2 + maxEnvelopeHeader
+	maxEnvelopeHeader = 1024
+)
+
+// ErrNotEncrypted is returned when reading an object that was not written
+// with client-side encryption.
+var ErrNotEncrypted = errors.New("object is not encrypted with client-side encryption")
+
+// EncryptionConfig is the client_side_encryption section of a configuration
+// file, which applies to every storage type.
+type EncryptionConfig struct {
+	// KeyID names the key encrypting new objects. The other keys only
+	// decrypt objects written before a rotation.
+	KeyID string          `json:"key_id"`
+	Keys  []EncryptionKey `json:"keys"`
+	// AllowUnencrypted passes objects written without client-side
+	// encryption through unchanged instead of failing to read them.
+	AllowUnencrypted bool `json:"allow_unencrypted"`
+}
+
+// EncryptionKey is a base64-encoded 256-bit key given inline, in a file or
+// in an environment variable. Exactly one of Key, File and Env is set.
+type EncryptionKey struct {
+	ID   string `json:"id"`
+	Key  string `json:"key,omitempty"`
+	File string `json:"file,omitempty"`
+	Env  string `json:"env,omitempty"`
+}
+
+type envelopeHeader struct {
+	KeyID       string `json:"key_id"`
+	WrappedKey  []byte `json:"wrapped_key"`
+	NoncePrefix []byte `json:"nonce_prefix"`
+	ChunkSize   int    `json:"chunk_size"`
+}
+
+// Keyring encrypts and decrypts objects with the keys of an
+// EncryptionConfig.
+type Keyring struct {
+	keyID            string
+	keys             map[string]cipher.AEAD
+	allowUnencrypted bool
+	// prefixSize is the size of the envelope prefix written with every
+	// key, or 0 if it differs between keys.
+	prefixSize int
+}
+
+// NewKeyring loads the keys of cfg.
+func NewKeyring(cfg EncryptionConfig) (*Keyring, error) {
+	k := &Keyring{keyID: cfg.KeyID, keys: map[string]cipher.AEAD{}, allowUnencrypted: cfg.AllowUnencrypted}
+	for _, key := range cfg.Keys {
+		if key.ID == "" {
+			return nil, errors.New("client_side_encryption: key without id")
+		}
+		if _, ok := k.keys[key.ID]; ok {
+			return nil, fmt.Errorf("client_side_encryption: duplicate key %q", key.ID)
+		}
+		aead, err := loadKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("client_side_encryption: key %q: %w", key.ID, err)
+		}
+		k.keys[key.ID] = aead
+	}
+	if _, ok := k.keys[cfg.KeyID]; !ok {
+		return nil, fmt.Errorf("client_side_encryption: key_id %q names none of the keys", cfg.KeyID)
+	}
+	for id, kek := range k.keys {
+		size, err := envelopePrefixSize(id, kek)
+		if err != nil {
+			return nil, err
+		}
+		if k.prefixSize != 0 && k.prefixSize != size {
+			k.prefixSize = 0
+			break
+		}
+		k.prefixSize = size
+	}
+	return k, nil
+}
+
+// envelopePrefixSize returns the size of the magic, length and header
+// preceding the content of objects encrypted with kek, which only depends
+// on the ID of the key.
+func envelopePrefixSize(keyID string, kek cipher.AEAD) (int, error) {
+	header, err := json.Marshal(envelopeHeader{
+		KeyID:       keyID,
+		WrappedKey:  make([]byte, kek.NonceSize()+32+kek.Overhead()),
+		NoncePrefix: make([]byte, noncePrefixSize),
+		ChunkSize:   encryptionChunkSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(envelopeMagic) + 2 + len(header), nil
+}
+
+func loadKey(key EncryptionKey) (cipher.AEAD, error) {
+	var encoded string
+	switch {
+	case key.Key != "" && key.File == "" && key.Env == "":
+		encoded = key.Key
+	case key.File != "" && key.Key == "" && key.Env == "":
+		data, err := os.ReadFile(key.File)
+		if err != nil {
+			return nil, err
+		}
+		encoded = string(data)
+	case key.Env != "" && key.Key == "" && key.File == "":
+		var ok bool
+		if encoded, ok = os.LookupEnv(key.Env); !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", key.Env)
+		}
+	default:
+		return nil, errors.New("exactly one of key, file and env must be set")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decoding key: %w", err)
+	}
+	if len(raw) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(raw))
+	}
+	return newGCM(raw)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptReader returns a reader of src encrypted with a new data key,
+// wrapped with the current key.
+func (k *Keyring) EncryptReader(src io.Reader) (io.Reader, error) {
+	dataKey := make([]byte, 32)
+	noncePrefix := make([]byte, noncePrefixSize)
+	wrapNonce := make([]byte, k.keys[k.keyID].NonceSize())
+	for _, b := range [][]byte{dataKey, noncePrefix, wrapNonce} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := json.Marshal(envelopeHeader{
+		KeyID:       k.keyID,
+		WrappedKey:  k.keys[k.keyID].Seal(wrapNonce, wrapNonce, dataKey, []byte(k.keyID)),
+		NoncePrefix: noncePrefix,
+		ChunkSize:   encryptionChunkSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(header) > maxEnvelopeHeader {
+		return nil, errors.New("client_side_encryption: key_id too long")
+	}
+	prefix := binary.BigEndian.AppendUint16([]byte(envelopeMagic), uint16(len(header)))
+	prefix = append(prefix, header...)
+
+	return &encryptReader{
+		src:     src,
+		sealer:  chunkSealer{aead: aead, noncePrefix: noncePrefix, prefix: prefix},
+		pending: prefix,
+		buf:     make([]byte, 0, encryptionChunkSize+1),
+	}, nil
+}
+
+// DecryptReader returns a reader of the content of the encrypted object read
+// from src, with the key named in its header.
+func (k *Keyring) DecryptReader(src io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(src)
+	header, prefix, err := readEnvelopeHeader(buffered)
+	if errors.Is(err, ErrNotEncrypted) && k.allowUnencrypted {
+		return buffered, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	aead, err := k.dataKey(header)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{
+		src:       buffered,
+		sealer:    chunkSealer{aead: aead, noncePrefix: header.NoncePrefix, prefix: prefix},
+		chunkSize: header.ChunkSize,
+	}, nil
+}
+
+// PlaintextSize returns the size of the content of an encrypted object of
+// size bytes, whose first bytes are read from prefix.
+func (k *Keyring) PlaintextSize(prefix io.Reader, size int64) (int64, error) {
+	_, envelopePrefix, err := readEnvelopeHeader(bufio.NewReader(prefix))
+	if errors.Is(err, ErrNotEncrypted) && k.allowUnencrypted {
+		return size, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return contentSize(size - int64(len(envelopePrefix))), nil
+}
+
+// PlaintextSizeOf returns the size of the content of an encrypted object of
+// size bytes without reading its header. It returns false if the size
+// cannot be known that way: the header is longer for longer key IDs, so the
+// keys must have IDs of the same length, and objects written without
+// encryption must not be allowed.
+func (k *Keyring) PlaintextSizeOf(size int64) (int64, bool) {
+	if size == 0 {
+		// Only unencrypted objects are empty.
+		return 0, true
+	}
+	if k.allowUnencrypted || k.prefixSize == 0 || size < int64(k.prefixSize+chunkOverhead) {
+		return 0, false
+	}
+	return contentSize(size - int64(k.prefixSize)), true
+}
+
+// contentSize returns the size of the content sealed in body bytes of
+// chunks.
+func contentSize(body int64) int64 {
+	sealedChunk := int64(encryptionChunkSize + chunkOverhead)
+	chunks := max((body+sealedChunk-1)/sealedChunk, 1)
+	return body - chunks*chunkOverhead
+}
+
+func (k *Keyring) dataKey(header envelopeHeader) (cipher.AEAD, error) {
+	kek, ok := k.keys[header.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("object is encrypted with unknown key %q", header.KeyID)
+	}
+	nonceSize := kek.NonceSize()
+	if len(header.WrappedKey) < nonceSize {
+		return nil, errors.New("invalid wrapped data key")
+	}
+	dataKey, err := kek.Open(nil, header.WrappedKey[:nonceSize], header.WrappedKey[nonceSize:], []byte(header.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping data key with key %q: %w", header.KeyID, err)
+	}
+	return newGCM(dataKey)
+}
+
+// readEnvelopeHeader reads the header of an encrypted object and returns it
+// along with the bytes preceding the content.
+func readEnvelopeHeader(r *bufio.Reader) (envelopeHeader, []byte, error) {
+	var header envelopeHeader
+	start, err := r.Peek(len(envelopeMagic) + 2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return header, nil, err
+	}
+	if len(start) < len(envelopeMagic)+2 || !bytes.Equal(start[:len(envelopeMagic)], []byte(envelopeMagic)) {
+		return header, nil, ErrNotEncrypted
+	}
+	headerLen := int(binary.BigEndian.Uint16(start[len(envelopeMagic):]))
+	if headerLen > maxEnvelopeHeader {
+		return header, nil, errors.New("invalid encryption header")
+	}
+
+	prefix := make([]byte, len(start)+headerLen)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return header, nil, fmt.Errorf("reading encryption header: %w", err)
+	}
+	if err := json.Unmarshal(prefix[len(start):], &header); err != nil {
+		return header, nil, fmt.Errorf("reading encryption header: %w", err)
+	}
+	if len(header.NoncePrefix) != noncePrefixSize {
+		return header, nil, errors.New("invalid encryption header")
+	}
+	// The chunk size sizes the buffer of every chunk, so it must not be
+	// taken from the object as is.
+	if header.ChunkSize != encryptionChunkSize {
+		return header, nil, fmt.Errorf("unsupported encryption chunk size %d", header.ChunkSize)
+	}
+	return header, prefix, nil
+}
+
+// chunkOverhead is the size of the GCM tag added to every chunk.
+const chunkOverhead = 16
+
+type chunkSealer struct {
+	aead        cipher.AEAD
+	noncePrefix []byte
+	// prefix is authenticated with every chunk.
+	prefix []byte
+}
+
+func (s chunkSealer) nonce(number uint32, last bool) []byte {
+	nonce := make([]byte, 0, s.aead.NonceSize())
+	nonce = append(nonce, s.noncePrefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, number)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+type encryptReader struct {
+	src     io.Reader
+	sealer  chunkSealer
+	number  uint32
+	pending []byte
+	sealed  []byte
+	// buf holds the next chunk and one more byte, telling whether the
+	// chunk is the last one.
+	buf  []byte
+	done bool
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.sealNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *encryptReader) sealNext() error {
+	n, err := io.ReadFull(r.src, r.buf[len(r.buf):cap(r.buf)])
+	r.buf = r.buf[:len(r.buf)+n]
+	last := false
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	}
+
+	chunk := r.buf
+	if !last {
+		chunk = r.buf[:encryptionChunkSize]
+	}
+	r.sealed = r.sealer.aead.Seal(r.sealed[:0], r.sealer.nonce(r.number, last), chunk, r.sealer.prefix)
+	r.pending = r.sealed
+	r.number++
+	r.done = last
+	if !last {
+		// Keep the extra byte for the next chunk.
+		r.buf = append(r.buf[:0], r.buf[encryptionChunkSize])
+	}
+	return nil
+}
+
+type decryptReader struct {
+	src       *bufio.Reader
+	sealer    chunkSealer
+	chunkSize int
+	number    uint32
+	pending   []byte
+	buf       []byte
+	done      bool
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.openNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *decryptReader) openNext() error {
+	if r.buf == nil {
+		r.buf = make([]byte, r.chunkSize+chunkOverhead)
+	}
+	n, err := io.ReadFull(r.src, r.buf)
+	last := false
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case errors.Is(err, io.EOF):
+		return fmt.Errorf("decrypting: %w", NewError(ErrChecksumMismatch, errors.New("object is truncated")))
+	case err != nil:
+		return err
+	default:
+		if _, err := r.src.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	plain, err := r.sealer.aead.Open(r.buf[:0], r.sealer.nonce(r.number, last), r.buf[:n], r.sealer.prefix)
+	if err != nil {
+		return fmt.Errorf("decrypting chunk %d: %w", r.number, NewError(ErrChecksumMismatch, err))
+	}
+	r.pending = plain
+	r.number++
+	r.done = last
+	return nil
+}
@@ -0,0 +1,162 @@
+package common
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keyring", func() {
+	newKey := func() string {
+		key := make([]byte, 32)
+		_, err := rand.Read(key)
+		Expect(err).NotTo(HaveOccurred())
+		return base64.StdEncoding.EncodeToString(key)
+	}
+
+	var (
+		oldKey, currentKey string
+		keyring            *Keyring
+	)
+
+	BeforeEach(func() {
+		oldKey, currentKey = newKey(), newKey()
+		var err error
+		keyring, err = NewKeyring(EncryptionConfig{KeyID: "current", Keys: []EncryptionKey{{ID: "old", Key: oldKey}, {ID: "current", Key: currentKey}}})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	encrypt := func(k *Keyring, content []byte) []byte {
+		encrypted, err := k.EncryptReader(bytes.NewReader(content))
+		Expect(err).NotTo(HaveOccurred())
+		data, err := io.ReadAll(encrypted)
+		Expect(err).NotTo(HaveOccurred())
+		return data
+	}
+
+	decrypt := func(k *Keyring, data []byte) ([]byte, error) {
+		decrypted, err := k.DecryptReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(decrypted)
+	}
+
+	DescribeTable("round-trips content in chunks",
+		func(size int) {
+			content := make([]byte, size)
+			_, err := rand.Read(content)
+			Expect(err).NotTo(HaveOccurred())
+
+			data := encrypt(keyring, content)
+			Expect(bytes.Contains(data, content[:min(size, 64)])).To(Equal(size == 0))
+			Expect(decrypt(keyring, data)).To(Equal(content))
+
+			plaintextSize, err := keyring.PlaintextSize(bytes.NewReader(data), int64(len(data)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plaintextSize).To(Equal(int64(size)))
+		},
+		Entry("empty", 0),
+		Entry("smaller than a chunk", 100),
+		Entry("exactly one chunk", encryptionChunkSize),
+		Entry("several chunks", 3*encryptionChunkSize+1),
+	)
+
+	It("derives the content size from the object size when the headers have the same size", func() {
+		sameLength, err := NewKeyring(EncryptionConfig{KeyID: "new", Keys: []EncryptionKey{{ID: "old", Key: oldKey}, {ID: "new", Key: currentKey}}})
+		Expect(err).NotTo(HaveOccurred())
+		for _, size := range []int{0, 100, encryptionChunkSize, 3*encryptionChunkSize + 1} {
+			data := encrypt(sameLength, make([]byte, size))
+			plaintextSize, known := sameLength.PlaintextSizeOf(int64(len(data)))
+			Expect(known).To(BeTrue())
+			Expect(plaintextSize).To(Equal(int64(size)))
+		}
+
+		_, known := keyring.PlaintextSizeOf(int64(len(encrypt(keyring, []byte("content")))))
+		Expect(known).To(BeFalse())
+
+		lenient, err := NewKeyring(EncryptionConfig{KeyID: "current", Keys: []EncryptionKey{{ID: "current", Key: currentKey}}, AllowUnencrypted: true})
+		Expect(err).NotTo(HaveOccurred())
+		_, known = lenient.PlaintextSizeOf(int64(len(encrypt(lenient, []byte("content")))))
+		Expect(known).To(BeFalse())
+	})
+
+	It("decrypts objects written with a rotated key", func() {
+		rotated, err := NewKeyring(EncryptionConfig{KeyID: "old", Keys: []EncryptionKey{{ID: "old", Key: oldKey}}})
+		Expect(err).NotTo(HaveOccurred())
+		data := encrypt(rotated, []byte("content"))
+
+		Expect(decrypt(keyring, data)).To(Equal([]byte("content")))
+
+		_, err = decrypt(rotated, encrypt(keyring, []byte("content")))
+		Expect(err).To(MatchError(`object is encrypted with unknown key "current"`))
+	})
+
+	It("detects tampering and truncation", func() {
+		content := make([]byte, 2*encryptionChunkSize)
+		data := encrypt(keyring, content)
+
+		tampered := bytes.Clone(data)
+		tampered[len(tampered)-1] ^= 1
+		_, err := decrypt(keyring, tampered)
+		Expect(err).To(MatchError(ErrChecksumMismatch))
+
+		_, err = decrypt(keyring, data[:len(data)-encryptionChunkSize-chunkOverhead])
+		Expect(err).To(MatchError(ErrChecksumMismatch))
+	})
+
+	It("rejects headers with another chunk size", func() {
+		data := encrypt(keyring, []byte("content"))
+		headerLen := int(binary.BigEndian.Uint16(data[len(envelopeMagic):]))
+		headerEnd := len(envelopeMagic) + 2 + headerLen
+		var header envelopeHeader
+		Expect(json.Unmarshal(data[len(envelopeMagic)+2:headerEnd], &header)).To(Succeed())
+
+		header.ChunkSize = 1 << 40
+		tamperedHeader, err := json.Marshal(header)
+		Expect(err).NotTo(HaveOccurred())
+		tampered := append([]byte(envelopeMagic), binary.BigEndian.AppendUint16(nil, uint16(len(tamperedHeader)))...)
+		tampered = append(append(tampered, tamperedHeader...), data[headerEnd:]...)
+
+		_, err = decrypt(keyring, tampered)
+		Expect(err).To(MatchError(ContainSubstring("unsupported encryption chunk size 1099511627776")))
+	})
+
+	It("rejects unencrypted objects unless allowed", func() {
+		_, err := decrypt(keyring, []byte("plain content"))
+		Expect(err).To(MatchError(ErrNotEncrypted))
+
+		lenient, err := NewKeyring(EncryptionConfig{KeyID: "current", Keys: []EncryptionKey{{ID: "current", Key: currentKey}}, AllowUnencrypted: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decrypt(lenient, []byte("plain content"))).To(Equal([]byte("plain content")))
+	})
+
+	It("loads keys from files and environment variables", func() {
+		keyFile := filepath.Join(GinkgoT().TempDir(), "key")
+		Expect(os.WriteFile(keyFile, []byte(oldKey+"\n"), 0600)).To(Succeed())
+		GinkgoT().Setenv("STORAGE_CLI_TEST_KEY", currentKey)
+
+		fromSources, err := NewKeyring(EncryptionConfig{KeyID: "current", Keys: []EncryptionKey{{ID: "old", File: keyFile}, {ID: "current", Env: "STORAGE_CLI_TEST_KEY"}}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decrypt(fromSources, encrypt(keyring, []byte("content")))).To(Equal([]byte("content")))
+	})
+
+	It("rejects invalid configurations", func() {
+		_, err := NewKeyring(EncryptionConfig{KeyID: "missing", Keys: []EncryptionKey{{ID: "current", Key: currentKey}}})
+		Expect(err).To(MatchError(ContainSubstring("names none of the keys")))
+
+		_, err = NewKeyring(EncryptionConfig{KeyID: "current", Keys: []EncryptionKey{{ID: "current", Key: currentKey, Env: "KEY"}}})
+		Expect(err).To(MatchError(ContainSubstring("exactly one of key, file and env")))
+
+		_, err = NewKeyring(EncryptionConfig{KeyID: "current", Keys: []EncryptionKey{{ID: "current", Key: base64.StdEncoding.EncodeToString([]byte("short"))}}})
+		Expect(err).To(MatchError(ContainSubstring("key must be 32 bytes")))
+	})
+})
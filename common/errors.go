@@ -0,0 +1,98 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Kinds of failures shared by all backends. Backends map the errors of their
+// SDKs onto these, so callers can tell them apart with errors.Is instead of
+// matching provider specific messages:
+//
+//	if errors.Is(err, common.ErrNotFound) { ... }
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrThrottled          = errors.New("throttled")
+	ErrTimeout            = errors.New("timeout")
+	ErrInvalidConfig      = errors.New("invalid configuration")
+	ErrChecksumMismatch   = errors.New("checksum mismatch")
+)
+
+// ErrorKinds lists the kinds of failures, most specific first.
+var ErrorKinds = []error{
+	ErrNotFound,
+	ErrPermissionDenied,
+	ErrAlreadyExists,
+	ErrPreconditionFailed,
+	ErrThrottled,
+	ErrTimeout,
+	ErrInvalidConfig,
+	ErrChecksumMismatch,
+}
+
+// Error attaches a kind of failure to an error while keeping its message.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// NewError returns err marked as a failure of the given kind. A nil err
+// stays nil.
+func NewError(kind error, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// ErrorKind returns the kind of failure of err, or nil if it has none. An
+// expired context deadline is reported as ErrTimeout.
+func ErrorKind(err error) error {
+	for _, kind := range ErrorKinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return nil
+}
+
+// ErrorFromHTTPStatus marks err with the kind of failure matching an HTTP
+// response status. err is returned unchanged if it already has a kind or
+// the status has no matching kind.
+func ErrorFromHTTPStatus(status int, err error) error {
+	if err == nil || ErrorKind(err) != nil {
+		return err
+	}
+
+	switch status {
+	case http.StatusNotFound:
+		return NewError(ErrNotFound, err)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return NewError(ErrPermissionDenied, err)
+	case http.StatusConflict:
+		return NewError(ErrAlreadyExists, err)
+	case http.StatusPreconditionFailed, http.StatusNotModified:
+		return NewError(ErrPreconditionFailed, err)
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return NewError(ErrThrottled, err)
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return NewError(ErrTimeout, err)
+	default:
+		return err
+	}
+}
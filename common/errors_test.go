@@ -0,0 +1,70 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Errors", func() {
+	Context("NewError", func() {
+		It("keeps the message and both errors in the chain", func() {
+			cause := errors.New("RESPONSE 404")
+			err := NewError(ErrNotFound, cause)
+
+			Expect(err).To(MatchError("RESPONSE 404"))
+			Expect(errors.Is(err, ErrNotFound)).To(BeTrue())
+			Expect(errors.Is(err, cause)).To(BeTrue())
+		})
+
+		It("keeps nil errors nil", func() {
+			Expect(NewError(ErrNotFound, nil)).To(BeNil())
+		})
+	})
+
+	Context("ErrorKind", func() {
+		It("finds the kind of wrapped errors", func() {
+			err := fmt.Errorf("getting blob: %w", NewError(ErrThrottled, errors.New("slow down")))
+			Expect(ErrorKind(err)).To(Equal(ErrThrottled))
+		})
+
+		It("reports expired deadlines as timeouts", func() {
+			Expect(ErrorKind(fmt.Errorf("getting blob: %w", context.DeadlineExceeded))).To(Equal(ErrTimeout))
+		})
+
+		It("returns nil for other errors", func() {
+			Expect(ErrorKind(errors.New("boom"))).To(BeNil())
+		})
+	})
+
+	Context("ErrorFromHTTPStatus", func() {
+		DescribeTable("maps statuses onto kinds",
+			func(status int, kind error) {
+				err := ErrorFromHTTPStatus(status, errors.New("failed"))
+				if kind == nil {
+					Expect(ErrorKind(err)).To(BeNil())
+					return
+				}
+				Expect(ErrorKind(err)).To(Equal(kind))
+			},
+			Entry("404", http.StatusNotFound, ErrNotFound),
+			Entry("401", http.StatusUnauthorized, ErrPermissionDenied),
+			Entry("403", http.StatusForbidden, ErrPermissionDenied),
+			Entry("409", http.StatusConflict, ErrAlreadyExists),
+			Entry("412", http.StatusPreconditionFailed, ErrPreconditionFailed),
+			Entry("429", http.StatusTooManyRequests, ErrThrottled),
+			Entry("503", http.StatusServiceUnavailable, ErrThrottled),
+			Entry("504", http.StatusGatewayTimeout, ErrTimeout),
+			Entry("500", http.StatusInternalServerError, nil),
+		)
+
+		It("keeps the kind errors already have", func() {
+			err := NewError(ErrInvalidConfig, errors.New("failed"))
+			Expect(ErrorFromHTTPStatus(http.StatusNotFound, err)).To(BeIdenticalTo(err))
+		})
+	})
+})
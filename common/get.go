@@ -0,0 +1,91 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetOptions sets the preconditions and the byte range of a download.
+type GetOptions struct {
+	// IfMatch only downloads the object if its ETag matches.
+	IfMatch string
+	// Range only downloads part of the object, or all of it when nil.
+	Range *ByteRange
+}
+
+// ByteRange selects consecutive bytes of an object, like an HTTP Range
+// header with a single range.
+type ByteRange struct {
+	// Start is the offset of the first byte. A negative Start selects the
+	// last -Start bytes of the object and End is ignored.
+	Start int64
+	// End is the offset of the last byte, inclusive, or -1 to read to the
+	// end of the object.
+	End int64
+}
+
+// ParseByteRange parses a range in the forms "<start>-<end>", "<start>-"
+// (to the end of the object) and "-<length>" (the last length bytes).
+func ParseByteRange(s string) (ByteRange, error) {
+	first, last, ok := strings.Cut(s, "-")
+	if !ok || (first == "" && last == "") {
+		return ByteRange{}, fmt.Errorf("invalid range %q: expected <start>-<end>, <start>- or -<length>", s)
+	}
+	if first == "" {
+		length, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || length <= 0 {
+			return ByteRange{}, fmt.Errorf("invalid range %q: length must be a positive integer", s)
+		}
+		return ByteRange{Start: -length, End: -1}, nil
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return ByteRange{}, fmt.Errorf("invalid range %q: start must be a non-negative integer", s)
+	}
+	if last == "" {
+		return ByteRange{Start: start, End: -1}, nil
+	}
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || end < start {
+		return ByteRange{}, fmt.Errorf("invalid range %q: end must be an integer not before start", s)
+	}
+	return ByteRange{Start: start, End: end}, nil
+}
+
+// String formats r the way ParseByteRange parses it.
+func (r ByteRange) String() string {
+	switch {
+	case r.Start < 0:
+		return strconv.FormatInt(r.Start, 10)
+	case r.End < 0:
+		return strconv.FormatInt(r.Start, 10) + "-"
+	default:
+		return fmt.Sprintf("%d-%d", r.Start, r.End)
+	}
+}
+
+// HTTPHeader formats r as the value of an HTTP Range header.
+func (r ByteRange) HTTPHeader() string {
+	return "bytes=" + r.String()
+}
+
+// Resolve returns the offset and the length of r within an object of size
+// bytes, for backends that address ranges by offset and count. A suffix
+// longer than the object selects all of it, and an end past the object is
+// clamped to its last byte, as in HTTP. A range starting at or past the end
+// of the object cannot be satisfied.
+func (r ByteRange) Resolve(size int64) (offset, length int64, err error) {
+	if r.Start < 0 {
+		offset = max(size+r.Start, 0)
+		return offset, size - offset, nil
+	}
+	if r.Start >= size {
+		return 0, 0, fmt.Errorf("range %s starts beyond the end of the object of %d bytes", r, size)
+	}
+	end := size - 1
+	if r.End >= 0 && r.End < end {
+		end = r.End
+	}
+	return r.Start, end - r.Start + 1, nil
+}
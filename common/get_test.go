@@ -0,0 +1,55 @@
+package common
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ByteRange", func() {
+	DescribeTable("parses and formats ranges",
+		func(s string, expected ByteRange, header string) {
+			r, err := ParseByteRange(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r).To(Equal(expected))
+			Expect(r.String()).To(Equal(s))
+			Expect(r.HTTPHeader()).To(Equal(header))
+		},
+		Entry("bounded", "0-1023", ByteRange{Start: 0, End: 1023}, "bytes=0-1023"),
+		Entry("single byte", "7-7", ByteRange{Start: 7, End: 7}, "bytes=7-7"),
+		Entry("open ended", "512-", ByteRange{Start: 512, End: -1}, "bytes=512-"),
+		Entry("suffix", "-22", ByteRange{Start: -22, End: -1}, "bytes=-22"),
+	)
+
+	DescribeTable("rejects invalid ranges",
+		func(s string) {
+			_, err := ParseByteRange(s)
+			Expect(err).To(MatchError(ContainSubstring("invalid range")))
+		},
+		Entry("empty", ""),
+		Entry("dash only", "-"),
+		Entry("no dash", "12"),
+		Entry("end before start", "10-5"),
+		Entry("zero suffix", "-0"),
+		Entry("not a number", "a-b"),
+		Entry("two ranges", "0-1,5-6"),
+	)
+
+	DescribeTable("resolves ranges against the object size",
+		func(r ByteRange, size, offset, length int64) {
+			o, l, err := r.Resolve(size)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(o).To(Equal(offset))
+			Expect(l).To(Equal(length))
+		},
+		Entry("bounded", ByteRange{Start: 2, End: 5}, int64(10), int64(2), int64(4)),
+		Entry("end past the object", ByteRange{Start: 2, End: 50}, int64(10), int64(2), int64(8)),
+		Entry("open ended", ByteRange{Start: 3, End: -1}, int64(10), int64(3), int64(7)),
+		Entry("suffix", ByteRange{Start: -4, End: -1}, int64(10), int64(6), int64(4)),
+		Entry("suffix longer than the object", ByteRange{Start: -40, End: -1}, int64(10), int64(0), int64(10)),
+	)
+
+	It("cannot resolve a range starting past the object", func() {
+		_, _, err := ByteRange{Start: 10, End: -1}.Resolve(10)
+		Expect(err).To(MatchError(ContainSubstring("starts beyond the end of the object of 10 bytes")))
+	})
+})
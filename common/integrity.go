@@ -0,0 +1,137 @@
+package common
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrNoChecksum is returned by VerifyChecksums when the two sides have no
+// checksum in common, so the content could not be compared.
+var ErrNoChecksum = errors.New("no checksum to compare")
+
+var (
+	crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+	crc64Table  = crc64.MakeTable(crc64.ECMA)
+)
+
+// Checksums holds hex-encoded checksums of some content. Empty ones are
+// unknown.
+type Checksums struct {
+	MD5    string
+	SHA256 string
+	CRC32  string
+	CRC32C string
+	CRC64  string
+}
+
+// Checksums returns the checksums of the object computed by the backend.
+func (p Properties) Checksums() Checksums {
+	return Checksums{MD5: p.ContentMD5, SHA256: p.SHA256, CRC32: p.CRC32, CRC32C: p.CRC32C, CRC64: p.CRC64}
+}
+
+// named returns the checksums by algorithm name, strongest first.
+func (c Checksums) named() [][2]string {
+	return [][2]string{{"sha256", c.SHA256}, {"md5", c.MD5}, {"crc64", c.CRC64}, {"crc32c", c.CRC32C}, {"crc32", c.CRC32}}
+}
+
+// VerifyChecksums compares every checksum known in both expected and actual
+// and returns the names of the algorithms compared. A mismatch is reported
+// as ErrChecksumMismatch, and ErrNoChecksum is returned if no algorithm is
+// known to both.
+func VerifyChecksums(expected, actual Checksums) ([]string, error) {
+	var compared []string
+	actualSums := actual.named()
+	for i, sum := range expected.named() {
+		algorithm, want, got := sum[0], sum[1], actualSums[i][1]
+		if want == "" || got == "" {
+			continue
+		}
+		if !strings.EqualFold(want, got) {
+			return compared, NewError(ErrChecksumMismatch, fmt.Errorf("%s checksum mismatch: expected %s, got %s", algorithm, want, got))
+		}
+		compared = append(compared, algorithm)
+	}
+	if len(compared) == 0 {
+		return nil, ErrNoChecksum
+	}
+	return compared, nil
+}
+
+// Digest computes the checksums backends report for an object from the
+// content written to it, so they can be computed while streaming.
+type Digest struct {
+	md5, sha256   hash.Hash
+	crc32, crc32c hash.Hash32
+	crc64         hash.Hash64
+	w             io.Writer
+	size          int64
+}
+
+// NewDigest returns a Digest of no content.
+func NewDigest() *Digest {
+	d := &Digest{
+		md5:    md5.New(),
+		sha256: sha256.New(),
+		crc32:  crc32.NewIEEE(),
+		crc32c: crc32.New(crc32cTable),
+		crc64:  crc64.New(crc64Table),
+	}
+	d.w = io.MultiWriter(d.md5, d.sha256, d.crc32, d.crc32c, d.crc64)
+	return d
+}
+
+// DigestFile returns the Digest of the file at path.
+func DigestFile(path string) (*Digest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	d := NewDigest()
+	if _, err := io.Copy(d, file); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return d, nil
+}
+
+func (d *Digest) Write(p []byte) (int, error) {
+	n, err := d.w.Write(p)
+	d.size += int64(n)
+	return n, err
+}
+
+// Size returns the number of bytes written.
+func (d *Digest) Size() int64 {
+	return d.size
+}
+
+// Checksums returns the checksums of the content written so far.
+func (d *Digest) Checksums() Checksums {
+	return Checksums{
+		MD5:    hex.EncodeToString(d.md5.Sum(nil)),
+		SHA256: hex.EncodeToString(d.sha256.Sum(nil)),
+		CRC32:  hex.EncodeToString(d.crc32.Sum(nil)),
+		CRC32C: hex.EncodeToString(d.crc32c.Sum(nil)),
+		CRC64:  hex.EncodeToString(d.crc64.Sum(nil)),
+	}
+}
+
+// Verify compares the content written so far with the size and checksums
+// the backend reports in props, as VerifyChecksums does. A negative
+// ContentLength stands for an unknown size and is not compared.
+func (d *Digest) Verify(props Properties) ([]string, error) {
+	if props.ContentLength >= 0 && props.ContentLength != d.size {
+		return nil, NewError(ErrChecksumMismatch, fmt.Errorf("size mismatch: expected %d bytes, got %d", props.ContentLength, d.size))
+	}
+	return VerifyChecksums(props.Checksums(), d.Checksums())
+}
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Digest", func() {
+	const (
+		contentMD5    = "9a0364b9e99bb480dd25e1f0284c8555"
+		contentSHA256 = "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73"
+		contentCRC32  = "fec530a9"
+	)
+
+	var digest *Digest
+
+	BeforeEach(func() {
+		digest = NewDigest()
+		_, err := io.Copy(digest, strings.NewReader("content"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("computes the checksums of the content written to it", func() {
+		Expect(digest.Size()).To(Equal(int64(7)))
+		checksums := digest.Checksums()
+		Expect(checksums.MD5).To(Equal(contentMD5))
+		Expect(checksums.SHA256).To(Equal(contentSHA256))
+		Expect(checksums.CRC32).To(Equal(contentCRC32))
+		Expect(checksums.CRC32C).To(HaveLen(8))
+		Expect(checksums.CRC64).To(HaveLen(16))
+	})
+
+	It("digests files", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "droplet.tgz")
+		Expect(os.WriteFile(path, []byte("content"), 0644)).To(Succeed())
+		fileDigest, err := DigestFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fileDigest.Checksums()).To(Equal(digest.Checksums()))
+	})
+
+	It("verifies the checksums known to the backend", func() {
+		algorithms, err := digest.Verify(Properties{ContentLength: 7, ContentMD5: strings.ToUpper(contentMD5), CRC32: contentCRC32})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(algorithms).To(Equal([]string{"md5", "crc32"}))
+	})
+
+	It("reports a size or checksum mismatch", func() {
+		_, err := digest.Verify(Properties{ContentLength: 8, ContentMD5: contentMD5})
+		Expect(err).To(MatchError(ErrChecksumMismatch))
+		Expect(err).To(MatchError(ContainSubstring("expected 8 bytes, got 7")))
+
+		_, err = digest.Verify(Properties{ContentLength: 7, ContentMD5: contentMD5, SHA256: strings.Repeat("0", 64)})
+		Expect(err).To(MatchError(ErrChecksumMismatch))
+		Expect(err).To(MatchError(ContainSubstring("sha256 checksum mismatch")))
+	})
+
+	It("reports content without a checksum in common", func() {
+		_, err := digest.Verify(Properties{ContentLength: 7})
+		Expect(errors.Is(err, ErrNoChecksum)).To(BeTrue())
+		Expect(err).NotTo(MatchError(ErrChecksumMismatch))
+	})
+})
@@ -0,0 +1,100 @@
+package common
+
+import (
+	"sort"
+	"strings"
+)
+
+// ListOptions selects the objects returned by a listing.
+type ListOptions struct {
+	// Prefix restricts the listing to the objects whose name starts with it.
+	Prefix string
+	// Delimiter, when set, groups the objects whose name contains it after
+	// Prefix into ListResult.Prefixes, up to and including its first
+	// occurrence, like the directories of a file system.
+	Delimiter string
+	// MaxKeys, when positive, limits the listing to a single page of at
+	// most MaxKeys objects and prefixes. Backends may return fewer, even
+	// none, while more remain.
+	MaxKeys int
+	// StartAfter skips the names up to and including it.
+	StartAfter string
+	// PageToken continues a listing from the ListResult.NextPageToken of
+	// its previous page, listed with the same options.
+	PageToken string
+}
+
+// ListResult holds the objects and common prefixes of a listing.
+type ListResult struct {
+	Objects []ObjectInfo
+	// Prefixes are the distinct common prefixes of the objects grouped by
+	// ListOptions.Delimiter, in lexical order.
+	Prefixes []string
+	// NextPageToken is set when a listing limited by ListOptions.MaxKeys
+	// has more pages.
+	NextPageToken string
+}
+
+// GroupByDelimiter splits objects into the ones listed directly under prefix
+// and the common prefixes of the others, for backends without native support
+// for delimiters. Objects not starting with prefix are dropped.
+func GroupByDelimiter(objects []ObjectInfo, prefix, delimiter string) ListResult {
+	var result ListResult
+	seen := map[string]bool{}
+	for _, object := range objects {
+		if !strings.HasPrefix(object.Name, prefix) {
+			continue
+		}
+		rest := object.Name[len(prefix):]
+		i := strings.Index(rest, delimiter)
+		if delimiter == "" || i < 0 {
+			result.Objects = append(result.Objects, object)
+			continue
+		}
+		commonPrefix := prefix + rest[:i+len(delimiter)]
+		if !seen[commonPrefix] {
+			seen[commonPrefix] = true
+			result.Prefixes = append(result.Prefixes, commonPrefix)
+		}
+	}
+	sort.Strings(result.Prefixes)
+	return result
+}
+
+// Paginate returns the page of result selected by opts, for backends that
+// list every entry and page in memory. Their page tokens are the name of the
+// last entry of the page, so StartAfter and PageToken have the same meaning.
+func Paginate(result ListResult, opts ListOptions) ListResult {
+	after := max(opts.StartAfter, opts.PageToken)
+
+	type entry struct {
+		name   string
+		object *ObjectInfo
+	}
+	entries := make([]entry, 0, len(result.Objects)+len(result.Prefixes))
+	for i := range result.Objects {
+		if result.Objects[i].Name > after {
+			entries = append(entries, entry{name: result.Objects[i].Name, object: &result.Objects[i]})
+		}
+	}
+	for _, prefix := range result.Prefixes {
+		if prefix > after {
+			entries = append(entries, entry{name: prefix})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	var page ListResult
+	if opts.MaxKeys > 0 && len(entries) > opts.MaxKeys {
+		entries = entries[:opts.MaxKeys]
+		page.NextPageToken = entries[len(entries)-1].name
+	}
+	for _, e := range entries {
+		if e.object != nil {
+			page.Objects = append(page.Objects, *e.object)
+		} else {
+			page.Prefixes = append(page.Prefixes, e.name)
+		}
+	}
+	return page
+}
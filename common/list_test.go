@@ -0,0 +1,63 @@
+package common
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GroupByDelimiter", func() {
+	objects := []ObjectInfo{{Name: "a.txt"}, {Name: "a/b/c"}, {Name: "a/b/d"}, {Name: "a/e"}, {Name: "z/f"}}
+
+	It("groups the objects below the prefix into common prefixes", func() {
+		result := GroupByDelimiter(objects, "a/", "/")
+		Expect(result.Objects).To(Equal([]ObjectInfo{{Name: "a/e"}}))
+		Expect(result.Prefixes).To(Equal([]string{"a/b/"}))
+	})
+
+	It("groups from the start of the names without a prefix", func() {
+		result := GroupByDelimiter(objects, "", "/")
+		Expect(result.Objects).To(Equal([]ObjectInfo{{Name: "a.txt"}}))
+		Expect(result.Prefixes).To(Equal([]string{"a/", "z/"}))
+	})
+
+	It("keeps every object matching the prefix without a delimiter", func() {
+		result := GroupByDelimiter(objects, "a/b", "")
+		Expect(result.Objects).To(Equal([]ObjectInfo{{Name: "a/b/c"}, {Name: "a/b/d"}}))
+		Expect(result.Prefixes).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Paginate", func() {
+	result := ListResult{
+		Objects:  []ObjectInfo{{Name: "c"}, {Name: "a"}, {Name: "e"}},
+		Prefixes: []string{"b/", "d/"},
+	}
+
+	It("returns everything in lexical order without a limit", func() {
+		page := Paginate(result, ListOptions{})
+		Expect(ObjectNames(page.Objects)).To(Equal([]string{"a", "c", "e"}))
+		Expect(page.Prefixes).To(Equal([]string{"b/", "d/"}))
+		Expect(page.NextPageToken).To(BeEmpty())
+	})
+
+	It("returns pages of objects and prefixes continued by the page token", func() {
+		page := Paginate(result, ListOptions{MaxKeys: 2})
+		Expect(ObjectNames(page.Objects)).To(Equal([]string{"a"}))
+		Expect(page.Prefixes).To(Equal([]string{"b/"}))
+		Expect(page.NextPageToken).To(Equal("b/"))
+
+		page = Paginate(result, ListOptions{MaxKeys: 2, PageToken: page.NextPageToken})
+		Expect(ObjectNames(page.Objects)).To(Equal([]string{"c"}))
+		Expect(page.Prefixes).To(Equal([]string{"d/"}))
+
+		page = Paginate(result, ListOptions{MaxKeys: 2, PageToken: page.NextPageToken})
+		Expect(ObjectNames(page.Objects)).To(Equal([]string{"e"}))
+		Expect(page.NextPageToken).To(BeEmpty())
+	})
+
+	It("skips the names up to start-after", func() {
+		page := Paginate(result, ListOptions{StartAfter: "c"})
+		Expect(ObjectNames(page.Objects)).To(Equal([]string{"e"}))
+		Expect(page.Prefixes).To(Equal([]string{"d/"}))
+	})
+})
@@ -0,0 +1,57 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Copier is implemented by backends that move objects with a server-side
+// copy followed by a delete.
+type Copier interface {
+	Stat(ctx context.Context, dest string) (ObjectInfo, bool, error)
+	Copy(ctx context.Context, srcBlob string, dstBlob string) error
+	Delete(ctx context.Context, dest string) error
+}
+
+// MoveByCopy moves src to dst for backends without a native move. It copies
+// src server-side and deletes it only once dst is verified to have the size
+// and, when both are known, the MD5 of src. Retried after the delete of a
+// previous attempt, it finds src gone and dst in place and succeeds.
+func MoveByCopy(ctx context.Context, c Copier, src, dst string) error {
+	srcInfo, exists, err := c.Stat(ctx, src)
+	if err != nil {
+		return fmt.Errorf("checking source %q: %w", src, err)
+	}
+	if !exists {
+		_, moved, err := c.Stat(ctx, dst)
+		if err != nil {
+			return fmt.Errorf("checking destination %q: %w", dst, err)
+		}
+		if moved {
+			return nil
+		}
+		return NewError(ErrNotFound, fmt.Errorf("source %q does not exist", src))
+	}
+
+	if err := c.Copy(ctx, src, dst); err != nil {
+		return fmt.Errorf("copying %q to %q: %w", src, dst, err)
+	}
+
+	dstInfo, exists, err := c.Stat(ctx, dst)
+	if err != nil {
+		return fmt.Errorf("verifying destination %q: %w", dst, err)
+	}
+	if !exists {
+		return fmt.Errorf("verifying destination %q: it does not exist after the copy", dst)
+	}
+	if dstInfo.Size != srcInfo.Size ||
+		(srcInfo.ContentMD5 != "" && dstInfo.ContentMD5 != "" && !strings.EqualFold(srcInfo.ContentMD5, dstInfo.ContentMD5)) {
+		return fmt.Errorf("verifying destination %q: its content differs from %q, keeping the source", dst, src)
+	}
+
+	if err := c.Delete(ctx, src); err != nil {
+		return fmt.Errorf("deleting source %q: %w", src, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package common
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// memoryCopier holds objects in memory, copying them with copyObject.
+type memoryCopier struct {
+	objects    map[string]ObjectInfo
+	copyObject func(info ObjectInfo) ObjectInfo
+	deletes    []string
+}
+
+func (m *memoryCopier) Stat(_ context.Context, dest string) (ObjectInfo, bool, error) {
+	info, ok := m.objects[dest]
+	return info, ok, nil
+}
+
+func (m *memoryCopier) Copy(_ context.Context, srcBlob string, dstBlob string) error {
+	m.objects[dstBlob] = m.copyObject(m.objects[srcBlob])
+	return nil
+}
+
+func (m *memoryCopier) Delete(_ context.Context, dest string) error {
+	m.deletes = append(m.deletes, dest)
+	delete(m.objects, dest)
+	return nil
+}
+
+var _ = Describe("MoveByCopy", func() {
+	var copier *memoryCopier
+
+	BeforeEach(func() {
+		copier = &memoryCopier{
+			objects:    map[string]ObjectInfo{"src": {Size: 3, ContentMD5: "acbd18db4cc2f85cedef654fccc4a4d8"}},
+			copyObject: func(info ObjectInfo) ObjectInfo { return info },
+		}
+	})
+
+	It("copies the object and deletes the source", func() {
+		Expect(MoveByCopy(context.Background(), copier, "src", "dst")).To(Succeed())
+		Expect(copier.objects).To(HaveKey("dst"))
+		Expect(copier.objects).ToNot(HaveKey("src"))
+	})
+
+	It("succeeds when retried after the source was deleted", func() {
+		Expect(MoveByCopy(context.Background(), copier, "src", "dst")).To(Succeed())
+		Expect(MoveByCopy(context.Background(), copier, "src", "dst")).To(Succeed())
+		Expect(copier.deletes).To(Equal([]string{"src"}))
+	})
+
+	It("fails with a not found error when neither object exists", func() {
+		err := MoveByCopy(context.Background(), copier, "missing", "dst")
+		Expect(errors.Is(err, ErrNotFound)).To(BeTrue())
+	})
+
+	It("keeps the source when the copy differs", func() {
+		copier.copyObject = func(info ObjectInfo) ObjectInfo {
+			info.ContentMD5 = "37b51d194a7513e45b56f6524f2d51f2"
+			return info
+		}
+
+		err := MoveByCopy(context.Background(), copier, "src", "dst")
+		Expect(err).To(MatchError(ContainSubstring("keeping the source")))
+		Expect(copier.objects).To(HaveKey("src"))
+	})
+})
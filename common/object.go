@@ -0,0 +1,127 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// ObjectInfo describes an object as reported by a storage backend. It is
+// shared across backends so that callers can compare objects held in
+// different blobstores.
+type ObjectInfo struct {
+	Name string
+	Size int64
+	// ETag is the backend's entity tag with surrounding quotes removed.
+	ETag string
+	// ContentMD5 is the hex-encoded MD5 of the content, or empty when the
+	// backend does not know it.
+	ContentMD5   string
+	LastModified time.Time
+	// StorageClass is the backend's storage class or access tier, or empty
+	// when the backend has none.
+	StorageClass string
+}
+
+// Properties are the properties of a single object, as reported by a
+// backend's Properties. Values the backend does not report are left empty.
+type Properties struct {
+	// ETag is the backend's entity tag with surrounding quotes removed.
+	ETag               string
+	LastModified       time.Time
+	ContentLength      int64
+	ContentType        string
+	ContentEncoding    string
+	CacheControl       string
+	ContentDisposition string
+	// ContentMD5, CRC32, CRC32C, CRC64 and SHA256 are hex-encoded
+	// checksums of the content computed by the backend. CRC64 is the
+	// CRC-64/ECMA reported by Alibaba OSS.
+	ContentMD5 string
+	CRC32      string
+	CRC32C     string
+	CRC64      string
+	SHA256     string
+	// StorageClass is the backend's storage class or access tier.
+	StorageClass string
+	Encryption   Encryption
+	// Metadata holds the user-defined metadata of the object.
+	Metadata map[string]string
+}
+
+// Encryption describes the server-side encryption of an object.
+type Encryption struct {
+	// Algorithm is the encryption algorithm, such as AES256 or aws:kms.
+	Algorithm string
+	// KMSKeyID identifies the key management service key protecting the
+	// object, or its encryption scope on Azure.
+	KMSKeyID string
+	// CustomerKeySHA256 is the base64-encoded SHA256 of the key supplied by
+	// the customer to encrypt the object.
+	CustomerKeySHA256 string
+}
+
+// ObjectNames returns the names of objects, in the same order.
+func ObjectNames(objects []ObjectInfo) []string {
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		names = append(names, object.Name)
+	}
+	return names
+}
+
+// SameContent reports whether o and other are known to hold the same bytes.
+// Sizes must match, and either both MD5 digests or both ETags must be known
+// and equal. Objects that cannot be compared are reported as different.
+func (o ObjectInfo) SameContent(other ObjectInfo) bool {
+	if o.Size != other.Size {
+		return false
+	}
+	if o.ContentMD5 != "" && other.ContentMD5 != "" {
+		return strings.EqualFold(o.ContentMD5, other.ContentMD5)
+	}
+	if o.ETag != "" && other.ETag != "" {
+		return strings.EqualFold(o.ETag, other.ETag)
+	}
+	return false
+}
+
+// TrimETag removes the weak validator prefix and surrounding quotes from an
+// HTTP entity tag.
+func TrimETag(etag string) string {
+	return strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+}
+
+// QuoteETag returns etag as an HTTP entity tag for If-Match and If-None-Match
+// headers, quoting it unless it is already quoted or the wildcard "*".
+func QuoteETag(etag string) string {
+	if etag == "*" || strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// MD5FromETag returns the ETag lower-cased if it looks like a hex-encoded MD5
+// digest, as it does for objects uploaded in a single request to S3 and OSS.
+// Otherwise it returns an empty string.
+func MD5FromETag(etag string) string {
+	etag = TrimETag(etag)
+	if len(etag) != 32 {
+		return ""
+	}
+	if _, err := hex.DecodeString(etag); err != nil {
+		return ""
+	}
+	return strings.ToLower(etag)
+}
+
+// HexFromBase64 re-encodes a base64-encoded checksum, as sent in HTTP headers,
+// as hex. It returns an empty string if checksum is not valid base64.
+func HexFromBase64(checksum string) string {
+	raw, err := base64.StdEncoding.DecodeString(checksum)
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(raw)
+}
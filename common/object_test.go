@@ -0,0 +1,68 @@
+package common
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ObjectInfo", func() {
+	Context("SameContent", func() {
+		It("compares MD5 digests when both are known", func() {
+			a := ObjectInfo{Size: 3, ETag: "0x8DC", ContentMD5: "ACBD18DB4CC2F85CEDEF654FCCC4A4D8"}
+			b := ObjectInfo{Size: 3, ETag: "CJrR", ContentMD5: "acbd18db4cc2f85cedef654fccc4a4d8"}
+			Expect(a.SameContent(b)).To(BeTrue())
+
+			b.ContentMD5 = "37b51d194a7513e45b56f6524f2d51f2"
+			Expect(a.SameContent(b)).To(BeFalse())
+		})
+
+		It("falls back to ETags when a digest is missing", func() {
+			a := ObjectInfo{Size: 3, ETag: "abc-2"}
+			b := ObjectInfo{Size: 3, ETag: "abc-2", ContentMD5: "acbd18db4cc2f85cedef654fccc4a4d8"}
+			Expect(a.SameContent(b)).To(BeTrue())
+		})
+
+		It("reports objects of different size as different", func() {
+			a := ObjectInfo{Size: 3, ETag: "abc"}
+			b := ObjectInfo{Size: 4, ETag: "abc"}
+			Expect(a.SameContent(b)).To(BeFalse())
+		})
+
+		It("reports objects that cannot be compared as different", func() {
+			Expect(ObjectInfo{Size: 3}.SameContent(ObjectInfo{Size: 3})).To(BeFalse())
+		})
+	})
+
+	Context("MD5FromETag", func() {
+		It("returns single-part ETags", func() {
+			Expect(MD5FromETag(`"ACBD18DB4CC2F85CEDEF654FCCC4A4D8"`)).To(Equal("acbd18db4cc2f85cedef654fccc4a4d8"))
+		})
+
+		It("ignores multipart and opaque ETags", func() {
+			Expect(MD5FromETag(`"acbd18db4cc2f85cedef654fccc4a4d8-2"`)).To(BeEmpty())
+			Expect(MD5FromETag(`"0x8DC2A1B3C4D5E6F"`)).To(BeEmpty())
+		})
+	})
+
+	Context("QuoteETag", func() {
+		It("quotes bare ETags", func() {
+			Expect(QuoteETag("0x8DC2A1B3C4D5E6F")).To(Equal(`"0x8DC2A1B3C4D5E6F"`))
+		})
+
+		It("keeps quoted ETags and the wildcard", func() {
+			Expect(QuoteETag(`"abc"`)).To(Equal(`"abc"`))
+			Expect(QuoteETag(`W/"abc"`)).To(Equal(`W/"abc"`))
+			Expect(QuoteETag("*")).To(Equal("*"))
+		})
+	})
+
+	Context("HexFromBase64", func() {
+		It("re-encodes checksums as hex", func() {
+			Expect(HexFromBase64("rL0Y20zC+Fzt72VPzMSk2A==")).To(Equal("acbd18db4cc2f85cedef654fccc4a4d8"))
+		})
+
+		It("ignores invalid checksums", func() {
+			Expect(HexFromBase64("not base64!")).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CheckPreconditions evaluates ifMatch and ifNoneMatch, as set in PutOptions,
+// GetOptions and DeleteOptions, against an object with the given ETag, for
+// backends that cannot have the server evaluate them. exists reports whether
+// the object exists. A failed precondition returns an error matching
+// ErrPreconditionFailed.
+func CheckPreconditions(etag string, exists bool, ifMatch, ifNoneMatch string) error {
+	if ifNoneMatch != "" && exists {
+		return NewError(ErrPreconditionFailed, errors.New("object already exists"))
+	}
+	if ifMatch == "" {
+		return nil
+	}
+	if !exists {
+		return NewError(ErrPreconditionFailed, errors.New("object does not exist"))
+	}
+	if ifMatch != "*" && TrimETag(ifMatch) != TrimETag(etag) {
+		return NewError(ErrPreconditionFailed, fmt.Errorf("ETag %q does not match %q", TrimETag(etag), TrimETag(ifMatch)))
+	}
+	return nil
+}
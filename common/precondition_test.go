@@ -0,0 +1,35 @@
+package common
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckPreconditions", func() {
+	It("passes without preconditions", func() {
+		Expect(CheckPreconditions("", false, "", "")).To(Succeed())
+		Expect(CheckPreconditions("abc", true, "", "")).To(Succeed())
+	})
+
+	It("fails if-none-match when the object exists", func() {
+		Expect(CheckPreconditions("", false, "", "*")).To(Succeed())
+		err := CheckPreconditions("abc", true, "", "*")
+		Expect(errors.Is(err, ErrPreconditionFailed)).To(BeTrue())
+	})
+
+	It("compares if-match with the ETag, ignoring quotes", func() {
+		Expect(CheckPreconditions(`"abc"`, true, "abc", "")).To(Succeed())
+		Expect(CheckPreconditions("abc", true, "*", "")).To(Succeed())
+
+		err := CheckPreconditions("abc", true, "def", "")
+		Expect(errors.Is(err, ErrPreconditionFailed)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring(`ETag "abc" does not match "def"`)))
+	})
+
+	It("fails if-match when the object does not exist", func() {
+		err := CheckPreconditions("", false, "abc", "")
+		Expect(errors.Is(err, ErrPreconditionFailed)).To(BeTrue())
+	})
+})
@@ -0,0 +1,31 @@
+package common
+
+// PutOptions sets the headers and user-defined metadata of an uploaded
+// object. Empty values leave the backend's defaults in place.
+type PutOptions struct {
+	ContentType        string
+	ContentEncoding    string
+	CacheControl       string
+	ContentDisposition string
+	Metadata           map[string]string
+	// IfMatch only replaces the object if its ETag matches.
+	IfMatch string
+	// IfNoneMatch set to "*" only creates the object if it does not exist.
+	IfNoneMatch string
+	// Checkpoint is the path of a local file recording the progress of a
+	// multipart upload, see LoadCheckpoint, so that a later upload of the
+	// same file resumes it. Uploads of streams cannot be resumed and
+	// ignore it.
+	Checkpoint string
+	// Compression is the algorithm compressing the content before it is
+	// uploaded, CompressionGzip or CompressionZstd, which becomes its
+	// ContentEncoding. It is applied by the storage package's compressing
+	// Storager, backends ignore it.
+	Compression string
+}
+
+// Compression algorithms of PutOptions.Compression.
+const (
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -11,6 +13,8 @@ import (
 	"github.com/cloudfoundry/bosh-utils/httpclient"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 
+	"github.com/cloudfoundry/storage-cli/common"
+
 	davconf "github.com/cloudfoundry/storage-cli/dav/config"
 )
 
@@ -49,12 +53,19 @@ func NewWithStorageClient(storageClient StorageClient) *DavBlobstore {
 	return &DavBlobstore{storageClient: storageClient}
 }
 
-func (d *DavBlobstore) Put(sourceFilePath string, dest string) error {
+func (d *DavBlobstore) Put(ctx context.Context, sourceFilePath string, dest string) error {
+	return d.PutWithOptions(ctx, sourceFilePath, dest, common.PutOptions{})
+}
+
+func (d *DavBlobstore) PutWithOptions(ctx context.Context, sourceFilePath string, dest string, opts common.PutOptions) error {
 	slog.Info("uploading file to webdav", "source", sourceFilePath, "dest", dest)
 
 	if err := validateBlobID(dest); err != nil {
 		return err
 	}
+	if opts.Checkpoint != "" {
+		return errors.New("webdav does not support checkpointed uploads")
+	}
 
 	source, err := os.Open(sourceFilePath)
 	if err != nil {
@@ -67,7 +78,7 @@ func (d *DavBlobstore) Put(sourceFilePath string, dest string) error {
 		return fmt.Errorf("failed to stat source file: %w", err)
 	}
 
-	err = d.storageClient.Put(dest, source, fileInfo.Size())
+	err = d.storageClient.Put(ctx, dest, source, fileInfo.Size(), opts)
 	if err != nil {
 		return fmt.Errorf("upload failure: %w", err)
 	}
@@ -76,7 +87,11 @@ func (d *DavBlobstore) Put(sourceFilePath string, dest string) error {
 	return nil
 }
 
-func (d *DavBlobstore) Get(source string, dest string) error {
+func (d *DavBlobstore) Get(ctx context.Context, source string, dest string) error {
+	return d.GetWithOptions(ctx, source, dest, common.GetOptions{})
+}
+
+func (d *DavBlobstore) GetWithOptions(ctx context.Context, source string, dest string, opts common.GetOptions) error {
 	slog.Info("downloading file from webdav", "source", source, "dest", dest)
 
 	if err := validateBlobID(source); err != nil {
@@ -89,7 +104,7 @@ func (d *DavBlobstore) Get(source string, dest string) error {
 	}
 	defer destFile.Close() //nolint:errcheck
 
-	content, err := d.storageClient.Get(source)
+	content, err := d.storageClient.Get(ctx, source, opts)
 	if err != nil {
 		return fmt.Errorf("download failure: %w", err)
 	}
@@ -104,23 +119,85 @@ func (d *DavBlobstore) Get(source string, dest string) error {
 	return nil
 }
 
-func (d *DavBlobstore) Delete(dest string) error {
+// GetStream opens a blob for reading. The caller must close the returned reader.
+func (d *DavBlobstore) GetStream(ctx context.Context, source string) (io.ReadCloser, error) {
+	return d.GetStreamWithOptions(ctx, source, common.GetOptions{})
+}
+
+func (d *DavBlobstore) GetStreamWithOptions(ctx context.Context, source string, opts common.GetOptions) (io.ReadCloser, error) {
+	slog.Info("streaming file from webdav", "source", source)
+
+	if err := validateBlobID(source); err != nil {
+		return nil, err
+	}
+
+	content, err := d.storageClient.Get(ctx, source, opts)
+	if err != nil {
+		return nil, fmt.Errorf("download failure: %w", err)
+	}
+	return content, nil
+}
+
+// PutStream uploads content of unknown length using chunked transfer encoding.
+func (d *DavBlobstore) PutStream(ctx context.Context, source io.Reader, dest string) error {
+	return d.PutStreamWithOptions(ctx, source, dest, common.PutOptions{})
+}
+
+func (d *DavBlobstore) PutStreamWithOptions(ctx context.Context, source io.Reader, dest string, opts common.PutOptions) error {
+	slog.Info("streaming file to webdav", "dest", dest)
+
+	if err := validateBlobID(dest); err != nil {
+		return err
+	}
+
+	err := d.storageClient.Put(ctx, dest, io.NopCloser(source), -1, opts)
+	if err != nil {
+		return fmt.Errorf("upload failure: %w", err)
+	}
+
+	slog.Debug("successfully uploaded stream", "dest", dest)
+	return nil
+}
+
+func (d *DavBlobstore) Stat(ctx context.Context, dest string) (common.ObjectInfo, bool, error) {
+	slog.Info("fetching blob metadata from webdav", "dest", dest)
+	if err := validateBlobID(dest); err != nil {
+		return common.ObjectInfo{}, false, err
+	}
+	return d.storageClient.Stat(ctx, dest)
+}
+
+func (d *DavBlobstore) Delete(ctx context.Context, dest string) error {
+	return d.DeleteWithOptions(ctx, dest, common.DeleteOptions{})
+}
+
+func (d *DavBlobstore) DeleteWithOptions(ctx context.Context, dest string, opts common.DeleteOptions) error {
 	slog.Info("deleting file from webdav", "dest", dest)
 	if err := validateBlobID(dest); err != nil {
 		return err
 	}
-	return d.storageClient.Delete(dest)
+	return d.storageClient.Delete(ctx, dest, opts)
 }
 
-func (d *DavBlobstore) Exists(dest string) (bool, error) {
+func (d *DavBlobstore) DeleteMany(ctx context.Context, dests []string) []common.DeleteFailure {
+	slog.Info("deleting files from webdav", "count", len(dests))
+	return common.DeleteConcurrently(ctx, dests, common.DefaultDeleteParallelism, func(ctx context.Context, dest string) error {
+		if err := validateBlobID(dest); err != nil {
+			return err
+		}
+		return d.storageClient.Delete(ctx, dest, common.DeleteOptions{})
+	})
+}
+
+func (d *DavBlobstore) Exists(ctx context.Context, dest string) (bool, error) {
 	slog.Info("checking if file exists on webdav", "dest", dest)
 	if err := validateBlobID(dest); err != nil {
 		return false, err
 	}
-	return d.storageClient.Exists(dest)
+	return d.storageClient.Exists(ctx, dest)
 }
 
-func (d *DavBlobstore) Sign(dest string, action string, expiration time.Duration) (string, error) {
+func (d *DavBlobstore) Sign(ctx context.Context, dest string, action string, expiration time.Duration) (string, error) {
 	slog.Info("signing url for webdav", "dest", dest, "action", action, "expiration", expiration)
 	if err := validateBlobID(dest); err != nil {
 		return "", err
@@ -174,22 +251,42 @@ func (d *DavBlobstore) SignPublic(dest string, action string, expiration time.Du
 	}
 }
 
-func (d *DavBlobstore) DeleteRecursive(prefix string) error {
+func (d *DavBlobstore) DeleteRecursive(ctx context.Context, prefix string) error {
 	slog.Info("deleting blobs recursively from webdav", "prefix", prefix)
-	return d.storageClient.DeleteRecursive(prefix)
+	return d.storageClient.DeleteRecursive(ctx, prefix)
 }
 
-func (d *DavBlobstore) List(prefix string) ([]string, error) {
+func (d *DavBlobstore) List(ctx context.Context, prefix string) ([]string, error) {
 	slog.Info("listing blobs on webdav", "prefix", prefix)
 	if prefix != "" {
 		if err := validatePrefix(prefix); err != nil {
 			return nil, err
 		}
 	}
-	return d.storageClient.List(prefix)
+	return d.storageClient.List(ctx, prefix)
 }
 
-func (d *DavBlobstore) Copy(srcBlob string, dstBlob string) error {
+func (d *DavBlobstore) ListObjects(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	slog.Info("listing blobs with metadata on webdav", "prefix", prefix)
+	if prefix != "" {
+		if err := validatePrefix(prefix); err != nil {
+			return nil, err
+		}
+	}
+	return d.storageClient.ListObjects(ctx, prefix)
+}
+
+func (d *DavBlobstore) ListWithOptions(ctx context.Context, opts common.ListOptions) (common.ListResult, error) {
+	slog.Info("listing blobs on webdav", "prefix", opts.Prefix, "delimiter", opts.Delimiter)
+	if opts.Prefix != "" {
+		if err := validatePrefix(opts.Prefix); err != nil {
+			return common.ListResult{}, err
+		}
+	}
+	return d.storageClient.ListWithOptions(ctx, opts)
+}
+
+func (d *DavBlobstore) Copy(ctx context.Context, srcBlob string, dstBlob string) error {
 	slog.Info("copying blob on webdav", "src", srcBlob, "dst", dstBlob)
 	if err := validateBlobID(srcBlob); err != nil {
 		return fmt.Errorf("invalid source blob ID: %w", err)
@@ -197,18 +294,29 @@ func (d *DavBlobstore) Copy(srcBlob string, dstBlob string) error {
 	if err := validateBlobID(dstBlob); err != nil {
 		return fmt.Errorf("invalid destination blob ID: %w", err)
 	}
-	return d.storageClient.Copy(srcBlob, dstBlob)
+	return d.storageClient.Copy(ctx, srcBlob, dstBlob)
+}
+
+func (d *DavBlobstore) Move(ctx context.Context, srcBlob string, dstBlob string) error {
+	slog.Info("moving blob on webdav", "src", srcBlob, "dst", dstBlob)
+	if err := validateBlobID(srcBlob); err != nil {
+		return fmt.Errorf("invalid source blob ID: %w", err)
+	}
+	if err := validateBlobID(dstBlob); err != nil {
+		return fmt.Errorf("invalid destination blob ID: %w", err)
+	}
+	return d.storageClient.Move(ctx, srcBlob, dstBlob)
 }
 
-func (d *DavBlobstore) Properties(dest string) error {
+func (d *DavBlobstore) Properties(ctx context.Context, dest string) (common.Properties, error) {
 	slog.Info("fetching blob properties from webdav", "dest", dest)
 	if err := validateBlobID(dest); err != nil {
-		return err
+		return common.Properties{}, err
 	}
-	return d.storageClient.Properties(dest)
+	return d.storageClient.Properties(ctx, dest)
 }
 
-func (d *DavBlobstore) EnsureStorageExists() error {
+func (d *DavBlobstore) EnsureStorageExists(ctx context.Context) error {
 	slog.Info("ensuring webdav storage root exists")
-	return d.storageClient.EnsureStorageExists()
+	return d.storageClient.EnsureStorageExists(ctx)
 }
@@ -1,12 +1,14 @@
 package client_test
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/dav/client"
 	"github.com/cloudfoundry/storage-cli/dav/client/clientfakes"
 
@@ -31,11 +33,11 @@ var _ = Describe("Client", func() {
 			Expect(err).NotTo(HaveOccurred())
 			file.Close() //nolint:errcheck
 
-			err = davBlobstore.Put(file.Name(), "target/blob")
+			err = davBlobstore.Put(context.Background(), file.Name(), "target/blob")
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStorageClient.PutCallCount()).To(Equal(1))
-			path, _, _ := fakeStorageClient.PutArgsForCall(0)
+			_, path, _, _, _ := fakeStorageClient.PutArgsForCall(0)
 			Expect(path).To(Equal("target/blob"))
 		})
 
@@ -43,7 +45,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient := &clientfakes.FakeStorageClient{}
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			err := davBlobstore.Put("nonexistent/path", "target/blob")
+			err := davBlobstore.Put(context.Background(), "nonexistent/path", "target/blob")
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to open source file"))
@@ -64,7 +66,7 @@ var _ = Describe("Client", func() {
 			tmpFile.Close()                 //nolint:errcheck
 			defer os.Remove(tmpFile.Name()) //nolint:errcheck
 
-			err = davBlobstore.Get("source/blob", tmpFile.Name())
+			err = davBlobstore.Get(context.Background(), "source/blob", tmpFile.Name())
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStorageClient.GetCallCount()).To(Equal(1))
@@ -75,17 +77,59 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Context("GetStream", func() {
+		It("returns the blob content", func() {
+			fakeStorageClient := &clientfakes.FakeStorageClient{}
+			fakeStorageClient.GetReturns(io.NopCloser(strings.NewReader("test content")), nil)
+
+			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
+
+			reader, err := davBlobstore.GetStream(context.Background(), "source/blob")
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := io.ReadAll(reader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("test content"))
+		})
+
+		It("rejects invalid blob IDs", func() {
+			fakeStorageClient := &clientfakes.FakeStorageClient{}
+
+			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
+
+			_, err := davBlobstore.GetStream(context.Background(), "../escape")
+			Expect(err).To(HaveOccurred())
+			Expect(fakeStorageClient.GetCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("PutStream", func() {
+		It("uploads content of unknown length", func() {
+			fakeStorageClient := &clientfakes.FakeStorageClient{}
+
+			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
+
+			err := davBlobstore.PutStream(context.Background(), strings.NewReader("test content"), "target/blob")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, path, _, contentLength, _ := fakeStorageClient.PutArgsForCall(0)
+			Expect(path).To(Equal("target/blob"))
+			Expect(contentLength).To(Equal(int64(-1)))
+		})
+	})
+
 	Context("Delete", func() {
 		It("deletes a blob", func() {
 			fakeStorageClient := &clientfakes.FakeStorageClient{}
 			fakeStorageClient.DeleteReturns(nil)
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			err := davBlobstore.Delete("blob/path")
+			err := davBlobstore.Delete(context.Background(), "blob/path")
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStorageClient.DeleteCallCount()).To(Equal(1))
-			Expect(fakeStorageClient.DeleteArgsForCall(0)).To(Equal("blob/path"))
+			_, arg, _ := fakeStorageClient.DeleteArgsForCall(0)
+			Expect(arg).To(Equal("blob/path"))
 		})
 	})
 
@@ -95,7 +139,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.ExistsReturns(true, nil)
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			exists, err := davBlobstore.Exists("blob/path")
+			exists, err := davBlobstore.Exists(context.Background(), "blob/path")
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(exists).To(BeTrue())
@@ -107,7 +151,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.ExistsReturns(false, nil)
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			exists, err := davBlobstore.Exists("blob/path")
+			exists, err := davBlobstore.Exists(context.Background(), "blob/path")
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(exists).To(BeFalse())
@@ -118,7 +162,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.ExistsReturns(false, fmt.Errorf("server error"))
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			exists, err := davBlobstore.Exists("blob/path")
+			exists, err := davBlobstore.Exists(context.Background(), "blob/path")
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("server error"))
@@ -134,7 +178,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.SignReturns("https://the-signed-url", nil)
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			url, err := davBlobstore.Sign("blob/path", "get", expiry)
+			url, err := davBlobstore.Sign(context.Background(), "blob/path", "get", expiry)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(url).To(Equal("https://the-signed-url"))
@@ -151,7 +195,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.SignReturns("https://the-signed-url", nil)
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			url, err := davBlobstore.Sign("blob/path", "put", expiry)
+			url, err := davBlobstore.Sign(context.Background(), "blob/path", "put", expiry)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(url).To(Equal("https://the-signed-url"))
@@ -164,7 +208,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient := &clientfakes.FakeStorageClient{}
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			url, err := davBlobstore.Sign("blob/path", "unknown", expiry)
+			url, err := davBlobstore.Sign(context.Background(), "blob/path", "unknown", expiry)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("action not implemented"))
@@ -177,7 +221,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.SignReturns("", fmt.Errorf("boom"))
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			url, err := davBlobstore.Sign("blob/path", "get", expiry)
+			url, err := davBlobstore.Sign(context.Background(), "blob/path", "get", expiry)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("boom"))
@@ -327,12 +371,12 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.CopyReturns(nil)
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			err := davBlobstore.Copy("src/blob", "dst/blob")
+			err := davBlobstore.Copy(context.Background(), "src/blob", "dst/blob")
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStorageClient.CopyCallCount()).To(Equal(1))
 
-			src, dst := fakeStorageClient.CopyArgsForCall(0)
+			_, src, dst := fakeStorageClient.CopyArgsForCall(0)
 			Expect(src).To(Equal("src/blob"))
 			Expect(dst).To(Equal("dst/blob"))
 		})
@@ -342,7 +386,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.CopyReturns(fmt.Errorf("copy failed"))
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			err := davBlobstore.Copy("src/blob", "dst/blob")
+			err := davBlobstore.Copy(context.Background(), "src/blob", "dst/blob")
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("copy failed"))
@@ -355,13 +399,14 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.ListReturns([]string{"a/b/c", "a/b/d"}, nil)
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			blobs, err := davBlobstore.List("a/b")
+			blobs, err := davBlobstore.List(context.Background(), "a/b")
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(blobs).To(ConsistOf("a/b/c", "a/b/d"))
 
 			Expect(fakeStorageClient.ListCallCount()).To(Equal(1))
-			Expect(fakeStorageClient.ListArgsForCall(0)).To(Equal("a/b"))
+			_, arg := fakeStorageClient.ListArgsForCall(0)
+			Expect(arg).To(Equal("a/b"))
 		})
 
 		It("propagates errors from the storage client", func() {
@@ -369,7 +414,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.ListReturns(nil, fmt.Errorf("list failed"))
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			blobs, err := davBlobstore.List("any/prefix")
+			blobs, err := davBlobstore.List(context.Background(), "any/prefix")
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("list failed"))
@@ -383,11 +428,12 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.DeleteRecursiveReturns(nil)
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			err := davBlobstore.DeleteRecursive("some/prefix")
+			err := davBlobstore.DeleteRecursive(context.Background(), "some/prefix")
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStorageClient.DeleteRecursiveCallCount()).To(Equal(1))
-			Expect(fakeStorageClient.DeleteRecursiveArgsForCall(0)).To(Equal("some/prefix"))
+			_, arg := fakeStorageClient.DeleteRecursiveArgsForCall(0)
+			Expect(arg).To(Equal("some/prefix"))
 		})
 
 		It("propagates errors from the storage client", func() {
@@ -395,7 +441,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.DeleteRecursiveReturns(fmt.Errorf("recursive delete failed"))
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			err := davBlobstore.DeleteRecursive("some/prefix")
+			err := davBlobstore.DeleteRecursive(context.Background(), "some/prefix")
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("recursive delete failed"))
@@ -405,22 +451,24 @@ var _ = Describe("Client", func() {
 	Context("Properties", func() {
 		It("forwards the destination to the storage client", func() {
 			fakeStorageClient := &clientfakes.FakeStorageClient{}
-			fakeStorageClient.PropertiesReturns(nil)
+			fakeStorageClient.PropertiesReturns(common.Properties{ContentLength: 42}, nil)
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			err := davBlobstore.Properties("blob/path")
+			props, err := davBlobstore.Properties(context.Background(), "blob/path")
 
 			Expect(err).NotTo(HaveOccurred())
+			Expect(props).To(Equal(common.Properties{ContentLength: 42}))
 			Expect(fakeStorageClient.PropertiesCallCount()).To(Equal(1))
-			Expect(fakeStorageClient.PropertiesArgsForCall(0)).To(Equal("blob/path"))
+			_, arg := fakeStorageClient.PropertiesArgsForCall(0)
+			Expect(arg).To(Equal("blob/path"))
 		})
 
 		It("propagates errors from the storage client", func() {
 			fakeStorageClient := &clientfakes.FakeStorageClient{}
-			fakeStorageClient.PropertiesReturns(fmt.Errorf("properties failed"))
+			fakeStorageClient.PropertiesReturns(common.Properties{}, fmt.Errorf("properties failed"))
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			err := davBlobstore.Properties("blob/path")
+			_, err := davBlobstore.Properties(context.Background(), "blob/path")
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("properties failed"))
@@ -433,7 +481,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.EnsureStorageExistsReturns(nil)
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			err := davBlobstore.EnsureStorageExists()
+			err := davBlobstore.EnsureStorageExists(context.Background())
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeStorageClient.EnsureStorageExistsCallCount()).To(Equal(1))
@@ -444,7 +492,7 @@ var _ = Describe("Client", func() {
 			fakeStorageClient.EnsureStorageExistsReturns(fmt.Errorf("ensure failed"))
 
 			davBlobstore := client.NewWithStorageClient(fakeStorageClient)
-			err := davBlobstore.EnsureStorageExists()
+			err := davBlobstore.EnsureStorageExists(context.Background())
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("ensure failed"))
@@ -2,19 +2,22 @@
 package clientfakes
 
 import (
+	"context"
 	"io"
 	"sync"
 	"time"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/dav/client"
 )
 
 type FakeStorageClient struct {
-	CopyStub        func(string, string) error
+	CopyStub        func(context.Context, string, string) error
 	copyMutex       sync.RWMutex
 	copyArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
 	}
 	copyReturns struct {
 		result1 error
@@ -22,10 +25,12 @@ type FakeStorageClient struct {
 	copyReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteStub        func(string) error
+	DeleteStub        func(context.Context, string, common.DeleteOptions) error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
+		arg3 common.DeleteOptions
 	}
 	deleteReturns struct {
 		result1 error
@@ -33,10 +38,11 @@ type FakeStorageClient struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteRecursiveStub        func(string) error
+	DeleteRecursiveStub        func(context.Context, string) error
 	deleteRecursiveMutex       sync.RWMutex
 	deleteRecursiveArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	deleteRecursiveReturns struct {
 		result1 error
@@ -44,9 +50,10 @@ type FakeStorageClient struct {
 	deleteRecursiveReturnsOnCall map[int]struct {
 		result1 error
 	}
-	EnsureStorageExistsStub        func() error
+	EnsureStorageExistsStub        func(context.Context) error
 	ensureStorageExistsMutex       sync.RWMutex
 	ensureStorageExistsArgsForCall []struct {
+		arg1 context.Context
 	}
 	ensureStorageExistsReturns struct {
 		result1 error
@@ -54,10 +61,11 @@ type FakeStorageClient struct {
 	ensureStorageExistsReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ExistsStub        func(string) (bool, error)
+	ExistsStub        func(context.Context, string) (bool, error)
 	existsMutex       sync.RWMutex
 	existsArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	existsReturns struct {
 		result1 bool
@@ -67,10 +75,12 @@ type FakeStorageClient struct {
 		result1 bool
 		result2 error
 	}
-	GetStub        func(string) (io.ReadCloser, error)
+	GetStub        func(context.Context, string, common.GetOptions) (io.ReadCloser, error)
 	getMutex       sync.RWMutex
 	getArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
+		arg3 common.GetOptions
 	}
 	getReturns struct {
 		result1 io.ReadCloser
@@ -80,10 +90,11 @@ type FakeStorageClient struct {
 		result1 io.ReadCloser
 		result2 error
 	}
-	ListStub        func(string) ([]string, error)
+	ListStub        func(context.Context, string) ([]string, error)
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	listReturns struct {
 		result1 []string
@@ -93,23 +104,69 @@ type FakeStorageClient struct {
 		result1 []string
 		result2 error
 	}
-	PropertiesStub        func(string) error
+	ListObjectsStub        func(context.Context, string) ([]common.ObjectInfo, error)
+	listObjectsMutex       sync.RWMutex
+	listObjectsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	listObjectsReturns struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	listObjectsReturnsOnCall map[int]struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	ListWithOptionsStub        func(context.Context, common.ListOptions) (common.ListResult, error)
+	listWithOptionsMutex       sync.RWMutex
+	listWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 common.ListOptions
+	}
+	listWithOptionsReturns struct {
+		result1 common.ListResult
+		result2 error
+	}
+	listWithOptionsReturnsOnCall map[int]struct {
+		result1 common.ListResult
+		result2 error
+	}
+	MoveStub        func(context.Context, string, string) error
+	moveMutex       sync.RWMutex
+	moveArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	moveReturns struct {
+		result1 error
+	}
+	moveReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PropertiesStub        func(context.Context, string) (common.Properties, error)
 	propertiesMutex       sync.RWMutex
 	propertiesArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	propertiesReturns struct {
-		result1 error
+		result1 common.Properties
+		result2 error
 	}
 	propertiesReturnsOnCall map[int]struct {
-		result1 error
+		result1 common.Properties
+		result2 error
 	}
-	PutStub        func(string, io.ReadCloser, int64) error
+	PutStub        func(context.Context, string, io.ReadCloser, int64, common.PutOptions) error
 	putMutex       sync.RWMutex
 	putArgsForCall []struct {
-		arg1 string
-		arg2 io.ReadCloser
-		arg3 int64
+		arg1 context.Context
+		arg2 string
+		arg3 io.ReadCloser
+		arg4 int64
+		arg5 common.PutOptions
 	}
 	putReturns struct {
 		result1 error
@@ -162,23 +219,40 @@ type FakeStorageClient struct {
 		result1 string
 		result2 error
 	}
+	StatStub        func(context.Context, string) (common.ObjectInfo, bool, error)
+	statMutex       sync.RWMutex
+	statArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	statReturns struct {
+		result1 common.ObjectInfo
+		result2 bool
+		result3 error
+	}
+	statReturnsOnCall map[int]struct {
+		result1 common.ObjectInfo
+		result2 bool
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStorageClient) Copy(arg1 string, arg2 string) error {
+func (fake *FakeStorageClient) Copy(arg1 context.Context, arg2 string, arg3 string) error {
 	fake.copyMutex.Lock()
 	ret, specificReturn := fake.copyReturnsOnCall[len(fake.copyArgsForCall)]
 	fake.copyArgsForCall = append(fake.copyArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+	}{arg1, arg2, arg3})
 	stub := fake.CopyStub
 	fakeReturns := fake.copyReturns
-	fake.recordInvocation("Copy", []interface{}{arg1, arg2})
+	fake.recordInvocation("Copy", []interface{}{arg1, arg2, arg3})
 	fake.copyMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -192,17 +266,17 @@ func (fake *FakeStorageClient) CopyCallCount() int {
 	return len(fake.copyArgsForCall)
 }
 
-func (fake *FakeStorageClient) CopyCalls(stub func(string, string) error) {
+func (fake *FakeStorageClient) CopyCalls(stub func(context.Context, string, string) error) {
 	fake.copyMutex.Lock()
 	defer fake.copyMutex.Unlock()
 	fake.CopyStub = stub
 }
 
-func (fake *FakeStorageClient) CopyArgsForCall(i int) (string, string) {
+func (fake *FakeStorageClient) CopyArgsForCall(i int) (context.Context, string, string) {
 	fake.copyMutex.RLock()
 	defer fake.copyMutex.RUnlock()
 	argsForCall := fake.copyArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorageClient) CopyReturns(result1 error) {
@@ -228,18 +302,20 @@ func (fake *FakeStorageClient) CopyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorageClient) Delete(arg1 string) error {
+func (fake *FakeStorageClient) Delete(arg1 context.Context, arg2 string, arg3 common.DeleteOptions) error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
 	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+		arg3 common.DeleteOptions
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteStub
 	fakeReturns := fake.deleteReturns
-	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2, arg3})
 	fake.deleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -253,17 +329,17 @@ func (fake *FakeStorageClient) DeleteCallCount() int {
 	return len(fake.deleteArgsForCall)
 }
 
-func (fake *FakeStorageClient) DeleteCalls(stub func(string) error) {
+func (fake *FakeStorageClient) DeleteCalls(stub func(context.Context, string, common.DeleteOptions) error) {
 	fake.deleteMutex.Lock()
 	defer fake.deleteMutex.Unlock()
 	fake.DeleteStub = stub
 }
 
-func (fake *FakeStorageClient) DeleteArgsForCall(i int) string {
+func (fake *FakeStorageClient) DeleteArgsForCall(i int) (context.Context, string, common.DeleteOptions) {
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
 	argsForCall := fake.deleteArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorageClient) DeleteReturns(result1 error) {
@@ -289,18 +365,19 @@ func (fake *FakeStorageClient) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorageClient) DeleteRecursive(arg1 string) error {
+func (fake *FakeStorageClient) DeleteRecursive(arg1 context.Context, arg2 string) error {
 	fake.deleteRecursiveMutex.Lock()
 	ret, specificReturn := fake.deleteRecursiveReturnsOnCall[len(fake.deleteRecursiveArgsForCall)]
 	fake.deleteRecursiveArgsForCall = append(fake.deleteRecursiveArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.DeleteRecursiveStub
 	fakeReturns := fake.deleteRecursiveReturns
-	fake.recordInvocation("DeleteRecursive", []interface{}{arg1})
+	fake.recordInvocation("DeleteRecursive", []interface{}{arg1, arg2})
 	fake.deleteRecursiveMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -314,17 +391,17 @@ func (fake *FakeStorageClient) DeleteRecursiveCallCount() int {
 	return len(fake.deleteRecursiveArgsForCall)
 }
 
-func (fake *FakeStorageClient) DeleteRecursiveCalls(stub func(string) error) {
+func (fake *FakeStorageClient) DeleteRecursiveCalls(stub func(context.Context, string) error) {
 	fake.deleteRecursiveMutex.Lock()
 	defer fake.deleteRecursiveMutex.Unlock()
 	fake.DeleteRecursiveStub = stub
 }
 
-func (fake *FakeStorageClient) DeleteRecursiveArgsForCall(i int) string {
+func (fake *FakeStorageClient) DeleteRecursiveArgsForCall(i int) (context.Context, string) {
 	fake.deleteRecursiveMutex.RLock()
 	defer fake.deleteRecursiveMutex.RUnlock()
 	argsForCall := fake.deleteRecursiveArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorageClient) DeleteRecursiveReturns(result1 error) {
@@ -350,17 +427,18 @@ func (fake *FakeStorageClient) DeleteRecursiveReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
-func (fake *FakeStorageClient) EnsureStorageExists() error {
+func (fake *FakeStorageClient) EnsureStorageExists(arg1 context.Context) error {
 	fake.ensureStorageExistsMutex.Lock()
 	ret, specificReturn := fake.ensureStorageExistsReturnsOnCall[len(fake.ensureStorageExistsArgsForCall)]
 	fake.ensureStorageExistsArgsForCall = append(fake.ensureStorageExistsArgsForCall, struct {
-	}{})
+		arg1 context.Context
+	}{arg1})
 	stub := fake.EnsureStorageExistsStub
 	fakeReturns := fake.ensureStorageExistsReturns
-	fake.recordInvocation("EnsureStorageExists", []interface{}{})
+	fake.recordInvocation("EnsureStorageExists", []interface{}{arg1})
 	fake.ensureStorageExistsMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -374,12 +452,19 @@ func (fake *FakeStorageClient) EnsureStorageExistsCallCount() int {
 	return len(fake.ensureStorageExistsArgsForCall)
 }
 
-func (fake *FakeStorageClient) EnsureStorageExistsCalls(stub func() error) {
+func (fake *FakeStorageClient) EnsureStorageExistsCalls(stub func(context.Context) error) {
 	fake.ensureStorageExistsMutex.Lock()
 	defer fake.ensureStorageExistsMutex.Unlock()
 	fake.EnsureStorageExistsStub = stub
 }
 
+func (fake *FakeStorageClient) EnsureStorageExistsArgsForCall(i int) context.Context {
+	fake.ensureStorageExistsMutex.RLock()
+	defer fake.ensureStorageExistsMutex.RUnlock()
+	argsForCall := fake.ensureStorageExistsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeStorageClient) EnsureStorageExistsReturns(result1 error) {
 	fake.ensureStorageExistsMutex.Lock()
 	defer fake.ensureStorageExistsMutex.Unlock()
@@ -403,18 +488,19 @@ func (fake *FakeStorageClient) EnsureStorageExistsReturnsOnCall(i int, result1 e
 	}{result1}
 }
 
-func (fake *FakeStorageClient) Exists(arg1 string) (bool, error) {
+func (fake *FakeStorageClient) Exists(arg1 context.Context, arg2 string) (bool, error) {
 	fake.existsMutex.Lock()
 	ret, specificReturn := fake.existsReturnsOnCall[len(fake.existsArgsForCall)]
 	fake.existsArgsForCall = append(fake.existsArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.ExistsStub
 	fakeReturns := fake.existsReturns
-	fake.recordInvocation("Exists", []interface{}{arg1})
+	fake.recordInvocation("Exists", []interface{}{arg1, arg2})
 	fake.existsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -428,17 +514,17 @@ func (fake *FakeStorageClient) ExistsCallCount() int {
 	return len(fake.existsArgsForCall)
 }
 
-func (fake *FakeStorageClient) ExistsCalls(stub func(string) (bool, error)) {
+func (fake *FakeStorageClient) ExistsCalls(stub func(context.Context, string) (bool, error)) {
 	fake.existsMutex.Lock()
 	defer fake.existsMutex.Unlock()
 	fake.ExistsStub = stub
 }
 
-func (fake *FakeStorageClient) ExistsArgsForCall(i int) string {
+func (fake *FakeStorageClient) ExistsArgsForCall(i int) (context.Context, string) {
 	fake.existsMutex.RLock()
 	defer fake.existsMutex.RUnlock()
 	argsForCall := fake.existsArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorageClient) ExistsReturns(result1 bool, result2 error) {
@@ -467,18 +553,20 @@ func (fake *FakeStorageClient) ExistsReturnsOnCall(i int, result1 bool, result2
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) Get(arg1 string) (io.ReadCloser, error) {
+func (fake *FakeStorageClient) Get(arg1 context.Context, arg2 string, arg3 common.GetOptions) (io.ReadCloser, error) {
 	fake.getMutex.Lock()
 	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
 	fake.getArgsForCall = append(fake.getArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+		arg3 common.GetOptions
+	}{arg1, arg2, arg3})
 	stub := fake.GetStub
 	fakeReturns := fake.getReturns
-	fake.recordInvocation("Get", []interface{}{arg1})
+	fake.recordInvocation("Get", []interface{}{arg1, arg2, arg3})
 	fake.getMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -492,17 +580,17 @@ func (fake *FakeStorageClient) GetCallCount() int {
 	return len(fake.getArgsForCall)
 }
 
-func (fake *FakeStorageClient) GetCalls(stub func(string) (io.ReadCloser, error)) {
+func (fake *FakeStorageClient) GetCalls(stub func(context.Context, string, common.GetOptions) (io.ReadCloser, error)) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = stub
 }
 
-func (fake *FakeStorageClient) GetArgsForCall(i int) string {
+func (fake *FakeStorageClient) GetArgsForCall(i int) (context.Context, string, common.GetOptions) {
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
 	argsForCall := fake.getArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorageClient) GetReturns(result1 io.ReadCloser, result2 error) {
@@ -531,18 +619,19 @@ func (fake *FakeStorageClient) GetReturnsOnCall(i int, result1 io.ReadCloser, re
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) List(arg1 string) ([]string, error) {
+func (fake *FakeStorageClient) List(arg1 context.Context, arg2 string) ([]string, error) {
 	fake.listMutex.Lock()
 	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
 	fake.listArgsForCall = append(fake.listArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.ListStub
 	fakeReturns := fake.listReturns
-	fake.recordInvocation("List", []interface{}{arg1})
+	fake.recordInvocation("List", []interface{}{arg1, arg2})
 	fake.listMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -556,17 +645,17 @@ func (fake *FakeStorageClient) ListCallCount() int {
 	return len(fake.listArgsForCall)
 }
 
-func (fake *FakeStorageClient) ListCalls(stub func(string) ([]string, error)) {
+func (fake *FakeStorageClient) ListCalls(stub func(context.Context, string) ([]string, error)) {
 	fake.listMutex.Lock()
 	defer fake.listMutex.Unlock()
 	fake.ListStub = stub
 }
 
-func (fake *FakeStorageClient) ListArgsForCall(i int) string {
+func (fake *FakeStorageClient) ListArgsForCall(i int) (context.Context, string) {
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
 	argsForCall := fake.listArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorageClient) ListReturns(result1 []string, result2 error) {
@@ -595,23 +684,217 @@ func (fake *FakeStorageClient) ListReturnsOnCall(i int, result1 []string, result
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) Properties(arg1 string) error {
+func (fake *FakeStorageClient) ListObjects(arg1 context.Context, arg2 string) ([]common.ObjectInfo, error) {
+	fake.listObjectsMutex.Lock()
+	ret, specificReturn := fake.listObjectsReturnsOnCall[len(fake.listObjectsArgsForCall)]
+	fake.listObjectsArgsForCall = append(fake.listObjectsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListObjectsStub
+	fakeReturns := fake.listObjectsReturns
+	fake.recordInvocation("ListObjects", []interface{}{arg1, arg2})
+	fake.listObjectsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) ListObjectsCallCount() int {
+	fake.listObjectsMutex.RLock()
+	defer fake.listObjectsMutex.RUnlock()
+	return len(fake.listObjectsArgsForCall)
+}
+
+func (fake *FakeStorageClient) ListObjectsCalls(stub func(context.Context, string) ([]common.ObjectInfo, error)) {
+	fake.listObjectsMutex.Lock()
+	defer fake.listObjectsMutex.Unlock()
+	fake.ListObjectsStub = stub
+}
+
+func (fake *FakeStorageClient) ListObjectsArgsForCall(i int) (context.Context, string) {
+	fake.listObjectsMutex.RLock()
+	defer fake.listObjectsMutex.RUnlock()
+	argsForCall := fake.listObjectsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorageClient) ListObjectsReturns(result1 []common.ObjectInfo, result2 error) {
+	fake.listObjectsMutex.Lock()
+	defer fake.listObjectsMutex.Unlock()
+	fake.ListObjectsStub = nil
+	fake.listObjectsReturns = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) ListObjectsReturnsOnCall(i int, result1 []common.ObjectInfo, result2 error) {
+	fake.listObjectsMutex.Lock()
+	defer fake.listObjectsMutex.Unlock()
+	fake.ListObjectsStub = nil
+	if fake.listObjectsReturnsOnCall == nil {
+		fake.listObjectsReturnsOnCall = make(map[int]struct {
+			result1 []common.ObjectInfo
+			result2 error
+		})
+	}
+	fake.listObjectsReturnsOnCall[i] = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) ListWithOptions(arg1 context.Context, arg2 common.ListOptions) (common.ListResult, error) {
+	fake.listWithOptionsMutex.Lock()
+	ret, specificReturn := fake.listWithOptionsReturnsOnCall[len(fake.listWithOptionsArgsForCall)]
+	fake.listWithOptionsArgsForCall = append(fake.listWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 common.ListOptions
+	}{arg1, arg2})
+	stub := fake.ListWithOptionsStub
+	fakeReturns := fake.listWithOptionsReturns
+	fake.recordInvocation("ListWithOptions", []interface{}{arg1, arg2})
+	fake.listWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) ListWithOptionsCallCount() int {
+	fake.listWithOptionsMutex.RLock()
+	defer fake.listWithOptionsMutex.RUnlock()
+	return len(fake.listWithOptionsArgsForCall)
+}
+
+func (fake *FakeStorageClient) ListWithOptionsCalls(stub func(context.Context, common.ListOptions) (common.ListResult, error)) {
+	fake.listWithOptionsMutex.Lock()
+	defer fake.listWithOptionsMutex.Unlock()
+	fake.ListWithOptionsStub = stub
+}
+
+func (fake *FakeStorageClient) ListWithOptionsArgsForCall(i int) (context.Context, common.ListOptions) {
+	fake.listWithOptionsMutex.RLock()
+	defer fake.listWithOptionsMutex.RUnlock()
+	argsForCall := fake.listWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorageClient) ListWithOptionsReturns(result1 common.ListResult, result2 error) {
+	fake.listWithOptionsMutex.Lock()
+	defer fake.listWithOptionsMutex.Unlock()
+	fake.ListWithOptionsStub = nil
+	fake.listWithOptionsReturns = struct {
+		result1 common.ListResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) ListWithOptionsReturnsOnCall(i int, result1 common.ListResult, result2 error) {
+	fake.listWithOptionsMutex.Lock()
+	defer fake.listWithOptionsMutex.Unlock()
+	fake.ListWithOptionsStub = nil
+	if fake.listWithOptionsReturnsOnCall == nil {
+		fake.listWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 common.ListResult
+			result2 error
+		})
+	}
+	fake.listWithOptionsReturnsOnCall[i] = struct {
+		result1 common.ListResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) Move(arg1 context.Context, arg2 string, arg3 string) error {
+	fake.moveMutex.Lock()
+	ret, specificReturn := fake.moveReturnsOnCall[len(fake.moveArgsForCall)]
+	fake.moveArgsForCall = append(fake.moveArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.MoveStub
+	fakeReturns := fake.moveReturns
+	fake.recordInvocation("Move", []interface{}{arg1, arg2, arg3})
+	fake.moveMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorageClient) MoveCallCount() int {
+	fake.moveMutex.RLock()
+	defer fake.moveMutex.RUnlock()
+	return len(fake.moveArgsForCall)
+}
+
+func (fake *FakeStorageClient) MoveCalls(stub func(context.Context, string, string) error) {
+	fake.moveMutex.Lock()
+	defer fake.moveMutex.Unlock()
+	fake.MoveStub = stub
+}
+
+func (fake *FakeStorageClient) MoveArgsForCall(i int) (context.Context, string, string) {
+	fake.moveMutex.RLock()
+	defer fake.moveMutex.RUnlock()
+	argsForCall := fake.moveArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorageClient) MoveReturns(result1 error) {
+	fake.moveMutex.Lock()
+	defer fake.moveMutex.Unlock()
+	fake.MoveStub = nil
+	fake.moveReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorageClient) MoveReturnsOnCall(i int, result1 error) {
+	fake.moveMutex.Lock()
+	defer fake.moveMutex.Unlock()
+	fake.MoveStub = nil
+	if fake.moveReturnsOnCall == nil {
+		fake.moveReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.moveReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorageClient) Properties(arg1 context.Context, arg2 string) (common.Properties, error) {
 	fake.propertiesMutex.Lock()
 	ret, specificReturn := fake.propertiesReturnsOnCall[len(fake.propertiesArgsForCall)]
 	fake.propertiesArgsForCall = append(fake.propertiesArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.PropertiesStub
 	fakeReturns := fake.propertiesReturns
-	fake.recordInvocation("Properties", []interface{}{arg1})
+	fake.recordInvocation("Properties", []interface{}{arg1, arg2})
 	fake.propertiesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeStorageClient) PropertiesCallCount() int {
@@ -620,56 +903,61 @@ func (fake *FakeStorageClient) PropertiesCallCount() int {
 	return len(fake.propertiesArgsForCall)
 }
 
-func (fake *FakeStorageClient) PropertiesCalls(stub func(string) error) {
+func (fake *FakeStorageClient) PropertiesCalls(stub func(context.Context, string) (common.Properties, error)) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = stub
 }
 
-func (fake *FakeStorageClient) PropertiesArgsForCall(i int) string {
+func (fake *FakeStorageClient) PropertiesArgsForCall(i int) (context.Context, string) {
 	fake.propertiesMutex.RLock()
 	defer fake.propertiesMutex.RUnlock()
 	argsForCall := fake.propertiesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeStorageClient) PropertiesReturns(result1 error) {
+func (fake *FakeStorageClient) PropertiesReturns(result1 common.Properties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	fake.propertiesReturns = struct {
-		result1 error
-	}{result1}
+		result1 common.Properties
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) PropertiesReturnsOnCall(i int, result1 error) {
+func (fake *FakeStorageClient) PropertiesReturnsOnCall(i int, result1 common.Properties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	if fake.propertiesReturnsOnCall == nil {
 		fake.propertiesReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 common.Properties
+			result2 error
 		})
 	}
 	fake.propertiesReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 common.Properties
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) Put(arg1 string, arg2 io.ReadCloser, arg3 int64) error {
+func (fake *FakeStorageClient) Put(arg1 context.Context, arg2 string, arg3 io.ReadCloser, arg4 int64, arg5 common.PutOptions) error {
 	fake.putMutex.Lock()
 	ret, specificReturn := fake.putReturnsOnCall[len(fake.putArgsForCall)]
 	fake.putArgsForCall = append(fake.putArgsForCall, struct {
-		arg1 string
-		arg2 io.ReadCloser
-		arg3 int64
-	}{arg1, arg2, arg3})
+		arg1 context.Context
+		arg2 string
+		arg3 io.ReadCloser
+		arg4 int64
+		arg5 common.PutOptions
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.PutStub
 	fakeReturns := fake.putReturns
-	fake.recordInvocation("Put", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("Put", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.putMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -683,17 +971,17 @@ func (fake *FakeStorageClient) PutCallCount() int {
 	return len(fake.putArgsForCall)
 }
 
-func (fake *FakeStorageClient) PutCalls(stub func(string, io.ReadCloser, int64) error) {
+func (fake *FakeStorageClient) PutCalls(stub func(context.Context, string, io.ReadCloser, int64, common.PutOptions) error) {
 	fake.putMutex.Lock()
 	defer fake.putMutex.Unlock()
 	fake.PutStub = stub
 }
 
-func (fake *FakeStorageClient) PutArgsForCall(i int) (string, io.ReadCloser, int64) {
+func (fake *FakeStorageClient) PutArgsForCall(i int) (context.Context, string, io.ReadCloser, int64, common.PutOptions) {
 	fake.putMutex.RLock()
 	defer fake.putMutex.RUnlock()
 	argsForCall := fake.putArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeStorageClient) PutReturns(result1 error) {
@@ -917,6 +1205,74 @@ func (fake *FakeStorageClient) SignPublicReturnsOnCall(i int, result1 string, re
 	}{result1, result2}
 }
 
+func (fake *FakeStorageClient) Stat(arg1 context.Context, arg2 string) (common.ObjectInfo, bool, error) {
+	fake.statMutex.Lock()
+	ret, specificReturn := fake.statReturnsOnCall[len(fake.statArgsForCall)]
+	fake.statArgsForCall = append(fake.statArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.StatStub
+	fakeReturns := fake.statReturns
+	fake.recordInvocation("Stat", []interface{}{arg1, arg2})
+	fake.statMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeStorageClient) StatCallCount() int {
+	fake.statMutex.RLock()
+	defer fake.statMutex.RUnlock()
+	return len(fake.statArgsForCall)
+}
+
+func (fake *FakeStorageClient) StatCalls(stub func(context.Context, string) (common.ObjectInfo, bool, error)) {
+	fake.statMutex.Lock()
+	defer fake.statMutex.Unlock()
+	fake.StatStub = stub
+}
+
+func (fake *FakeStorageClient) StatArgsForCall(i int) (context.Context, string) {
+	fake.statMutex.RLock()
+	defer fake.statMutex.RUnlock()
+	argsForCall := fake.statArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorageClient) StatReturns(result1 common.ObjectInfo, result2 bool, result3 error) {
+	fake.statMutex.Lock()
+	defer fake.statMutex.Unlock()
+	fake.StatStub = nil
+	fake.statReturns = struct {
+		result1 common.ObjectInfo
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeStorageClient) StatReturnsOnCall(i int, result1 common.ObjectInfo, result2 bool, result3 error) {
+	fake.statMutex.Lock()
+	defer fake.statMutex.Unlock()
+	fake.StatStub = nil
+	if fake.statReturnsOnCall == nil {
+		fake.statReturnsOnCall = make(map[int]struct {
+			result1 common.ObjectInfo
+			result2 bool
+			result3 error
+		})
+	}
+	fake.statReturnsOnCall[i] = struct {
+		result1 common.ObjectInfo
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeStorageClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
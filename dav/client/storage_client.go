@@ -1,18 +1,22 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cloudfoundry/bosh-utils/httpclient"
+	"github.com/cloudfoundry/storage-cli/common"
 	davconf "github.com/cloudfoundry/storage-cli/dav/config"
 	URLsigner "github.com/cloudfoundry/storage-cli/dav/signer"
 )
@@ -20,28 +24,27 @@ import (
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . StorageClient
 
 type StorageClient interface {
-	Get(path string) (content io.ReadCloser, err error)
-	Put(path string, content io.ReadCloser, contentLength int64) (err error)
-	Exists(path string) (bool, error)
-	Delete(path string) (err error)
-	DeleteRecursive(prefix string) error
+	Get(ctx context.Context, path string, opts common.GetOptions) (content io.ReadCloser, err error)
+	Put(ctx context.Context, path string, content io.ReadCloser, contentLength int64, opts common.PutOptions) (err error)
+	Exists(ctx context.Context, path string) (bool, error)
+	Stat(ctx context.Context, path string) (common.ObjectInfo, bool, error)
+	Delete(ctx context.Context, path string, opts common.DeleteOptions) (err error)
+	DeleteRecursive(ctx context.Context, prefix string) error
 	Sign(objectID, action string, duration time.Duration) (string, error)
 	SignInternal(objectID, action string, duration time.Duration) (string, error)
 	SignPublic(objectID, action string, duration time.Duration) (string, error)
-	Copy(srcBlob, dstBlob string) error
-	List(prefix string) ([]string, error)
-	Properties(path string) error
-	EnsureStorageExists() error
-}
-
-type BlobProperties struct {
-	ETag          string    `json:"etag,omitempty"`
-	LastModified  time.Time `json:"last_modified,omitempty"`
-	ContentLength *int64    `json:"content_length,omitempty"`
+	Copy(ctx context.Context, srcBlob, dstBlob string) error
+	Move(ctx context.Context, srcBlob, dstBlob string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+	ListObjects(ctx context.Context, prefix string) ([]common.ObjectInfo, error)
+	ListWithOptions(ctx context.Context, opts common.ListOptions) (common.ListResult, error)
+	Properties(ctx context.Context, path string) (common.Properties, error)
+	EnsureStorageExists(ctx context.Context) error
 }
 
 // PROPFIND request body — sent as XML to ask the WebDAV server for the
-// resourcetype of every child entry of a collection.
+// resourcetype, size, modification time and ETag of every child entry of a
+// collection.
 type propfindRequest struct {
 	XMLName xml.Name        `xml:"D:propfind"`
 	DAVNS   string          `xml:"xmlns:D,attr"`
@@ -49,7 +52,10 @@ type propfindRequest struct {
 }
 
 type propfindReqProp struct {
-	ResourceType struct{} `xml:"D:resourcetype"`
+	ResourceType     struct{} `xml:"D:resourcetype"`
+	GetContentLength struct{} `xml:"D:getcontentlength"`
+	GetLastModified  struct{} `xml:"D:getlastmodified"`
+	GetETag          struct{} `xml:"D:getetag"`
 }
 
 func newPropfindBody() (io.Reader, error) {
@@ -76,7 +82,10 @@ type davPropStat struct {
 }
 
 type davProp struct {
-	ResourceType davResourceType `xml:"resourcetype"`
+	ResourceType     davResourceType `xml:"resourcetype"`
+	GetContentLength string          `xml:"getcontentlength"`
+	GetLastModified  string          `xml:"getlastmodified"`
+	GetETag          string          `xml:"getetag"`
 }
 
 type davResourceType struct {
@@ -92,6 +101,24 @@ func (r davResponse) isCollection() bool {
 	return false
 }
 
+// objectInfo collects the properties reported for a blob. Servers return
+// the properties they do not know in a separate propstat, with empty values.
+func (r davResponse) objectInfo(blobID string) common.ObjectInfo {
+	info := common.ObjectInfo{Name: blobID}
+	for _, ps := range r.PropStats {
+		if size, err := strconv.ParseInt(strings.TrimSpace(ps.Prop.GetContentLength), 10, 64); err == nil {
+			info.Size = size
+		}
+		if t, err := http.ParseTime(strings.TrimSpace(ps.Prop.GetLastModified)); err == nil {
+			info.LastModified = t
+		}
+		if etag := common.TrimETag(strings.TrimSpace(ps.Prop.GetETag)); etag != "" {
+			info.ETag = etag
+		}
+	}
+	return info
+}
+
 type storageClient struct {
 	config     davconf.Config
 	httpClient httpclient.Client
@@ -104,34 +131,90 @@ func NewStorageClient(config davconf.Config, httpClient httpclient.Client) Stora
 	}
 }
 
-func (c *storageClient) Get(path string) (io.ReadCloser, error) {
-	req, err := c.createReq("GET", path, nil)
+func (c *storageClient) Get(ctx context.Context, path string, opts common.GetOptions) (io.ReadCloser, error) {
+	req, err := c.createReq(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, err
 	}
+	setPreconditions(req, opts.IfMatch, "")
+	if opts.Range != nil {
+		req.Header.Set("Range", opts.Range.HTTPHeader())
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("getting dav blob %q: %w", path, err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && opts.Range != nil:
+		return resp.Body, nil
+	case resp.StatusCode == http.StatusOK && opts.Range != nil:
+		return rangeOfBody(path, resp, *opts.Range)
+	case resp.StatusCode == http.StatusOK:
+		return resp.Body, nil
+	default:
 		defer resp.Body.Close() //nolint:errcheck
-		return nil, fmt.Errorf("getting dav blob %q: wrong response code: %d; body: %s", path, resp.StatusCode, c.readAndTruncateBody(resp))
+		return nil, common.ErrorFromHTTPStatus(resp.StatusCode, fmt.Errorf("getting dav blob %q: wrong response code: %d; body: %s", path, resp.StatusCode, c.readAndTruncateBody(resp)))
 	}
+}
 
-	return resp.Body, nil
+// rangeOfBody cuts byteRange out of the whole blob, for servers that ignore
+// Range headers and answer with 200 OK.
+func rangeOfBody(path string, resp *http.Response, byteRange common.ByteRange) (io.ReadCloser, error) {
+	if resp.ContentLength < 0 {
+		resp.Body.Close() //nolint:errcheck
+		return nil, fmt.Errorf("getting range of dav blob %q: the server ignored the Range header and sent no Content-Length", path)
+	}
+	offset, length, err := byteRange.Resolve(resp.ContentLength)
+	if err != nil {
+		resp.Body.Close() //nolint:errcheck
+		return nil, fmt.Errorf("getting range of dav blob %q: %w", path, err)
+	}
+	if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+		resp.Body.Close() //nolint:errcheck
+		return nil, fmt.Errorf("getting range of dav blob %q: %w", path, err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(resp.Body, length), resp.Body}, nil
 }
 
-func (c *storageClient) Put(path string, content io.ReadCloser, contentLength int64) error {
+// Put uploads content with the headers of opts. WebDAV has no user-defined
+// metadata, so opts.Metadata must be empty.
+func (c *storageClient) Put(ctx context.Context, path string, content io.ReadCloser, contentLength int64, opts common.PutOptions) error {
 	defer content.Close() //nolint:errcheck
 
-	req, err := c.createReq("PUT", path, content)
+	if len(opts.Metadata) > 0 {
+		return errors.New("webdav does not support user-defined metadata")
+	}
+
+	req, err := c.createReq(ctx, "PUT", path, content)
 	if err != nil {
 		return err
 	}
+	for header, value := range map[string]string{
+		"Content-Type":        opts.ContentType,
+		"Content-Encoding":    opts.ContentEncoding,
+		"Cache-Control":       opts.CacheControl,
+		"Content-Disposition": opts.ContentDisposition,
+	} {
+		if value != "" {
+			req.Header.Set(header, value)
+		}
+	}
+	setPreconditions(req, opts.IfMatch, opts.IfNoneMatch)
 
 	req.ContentLength = contentLength
+	if contentLength < 0 {
+		// The retry client buffers bodies it cannot rewind in memory so that
+		// it can replay them. Content of unknown length is streamed instead
+		// and a failed upload is not retried.
+		req.GetBody = func() (io.ReadCloser, error) {
+			return nil, errors.New("streamed request body cannot be replayed")
+		}
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("putting dav blob %q: %w", path, err)
@@ -139,14 +222,14 @@ func (c *storageClient) Put(path string, content io.ReadCloser, contentLength in
 	defer resp.Body.Close() //nolint:errcheck
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("putting dav blob %q: wrong response code: %d; body: %s", path, resp.StatusCode, c.readAndTruncateBody(resp))
+		return common.ErrorFromHTTPStatus(resp.StatusCode, fmt.Errorf("putting dav blob %q: wrong response code: %d; body: %s", path, resp.StatusCode, c.readAndTruncateBody(resp)))
 	}
 
 	return nil
 }
 
-func (c *storageClient) Exists(path string) (bool, error) {
-	req, err := c.createReq("HEAD", path, nil)
+func (c *storageClient) Exists(ctx context.Context, path string) (bool, error) {
+	req, err := c.createReq(ctx, "HEAD", path, nil)
 	if err != nil {
 		return false, err
 	}
@@ -162,17 +245,61 @@ func (c *storageClient) Exists(path string) (bool, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("checking if dav blob %q exists: invalid status: %d", path, resp.StatusCode)
+		return false, common.ErrorFromHTTPStatus(resp.StatusCode, fmt.Errorf("checking if dav blob %q exists: invalid status: %d", path, resp.StatusCode))
 	}
 
 	return true, nil
 }
 
-func (c *storageClient) Delete(path string) error {
-	req, err := c.createReq("DELETE", path, nil)
+func (c *storageClient) Stat(ctx context.Context, path string) (common.ObjectInfo, bool, error) {
+	req, err := c.createReq(ctx, "HEAD", path, nil)
+	if err != nil {
+		return common.ObjectInfo{}, false, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return common.ObjectInfo{}, false, fmt.Errorf("fetching metadata of dav blob %q: %w", path, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode == http.StatusNotFound {
+		return common.ObjectInfo{}, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return common.ObjectInfo{}, false, common.ErrorFromHTTPStatus(resp.StatusCode, fmt.Errorf("fetching metadata of dav blob %q: invalid status: %d", path, resp.StatusCode))
+	}
+
+	info := common.ObjectInfo{
+		Name: path,
+		Size: resp.ContentLength,
+		ETag: common.TrimETag(resp.Header.Get("ETag")),
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.LastModified = t
+	}
+	return info, true, nil
+}
+
+// setPreconditions sets the If-Match and If-None-Match headers of req, if
+// given.
+func setPreconditions(req *http.Request, ifMatch, ifNoneMatch string) {
+	if ifMatch != "" {
+		req.Header.Set("If-Match", common.QuoteETag(ifMatch))
+	}
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+}
+
+// Delete removes path. A missing blob counts as deleted, unless opts.IfMatch
+// is set.
+func (c *storageClient) Delete(ctx context.Context, path string, opts common.DeleteOptions) error {
+	req, err := c.createReq(ctx, "DELETE", path, nil)
 	if err != nil {
 		return fmt.Errorf("creating delete request for blob %q: %w", path, err)
 	}
+	setPreconditions(req, opts.IfMatch, "")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -181,11 +308,14 @@ func (c *storageClient) Delete(path string) error {
 	defer resp.Body.Close() //nolint:errcheck
 
 	if resp.StatusCode == http.StatusNotFound {
+		if opts.IfMatch != "" {
+			return common.NewError(common.ErrPreconditionFailed, fmt.Errorf("deleting blob %q: blob does not exist", path))
+		}
 		return nil
 	}
 
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		return fmt.Errorf("deleting blob %q: invalid status %d", path, resp.StatusCode)
+		return common.ErrorFromHTTPStatus(resp.StatusCode, fmt.Errorf("deleting blob %q: invalid status %d", path, resp.StatusCode))
 	}
 
 	return nil
@@ -256,13 +386,13 @@ func (c *storageClient) buildBlobURL(blobID string) (string, error) {
 	return blobURL.String(), nil
 }
 
-func (c *storageClient) createReq(method, blobID string, body io.Reader) (*http.Request, error) {
+func (c *storageClient) createReq(ctx context.Context, method, blobID string, body io.Reader) (*http.Request, error) {
 	rawURL, err := c.buildBlobURL(blobID)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(method, rawURL, body)
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -281,7 +411,7 @@ func (c *storageClient) readAndTruncateBody(resp *http.Response) string {
 	return string(bodyBytes)
 }
 
-func (c *storageClient) Copy(srcBlob, dstBlob string) error {
+func (c *storageClient) Copy(ctx context.Context, srcBlob, dstBlob string) error {
 	dstURL, err := c.buildBlobURL(dstBlob)
 	if err != nil {
 		return fmt.Errorf("building destination URL: %w", err)
@@ -289,7 +419,84 @@ func (c *storageClient) Copy(srcBlob, dstBlob string) error {
 
 	// PUT an empty file first so nginx (create_full_put_path on) creates any
 	// missing parent directories before COPY overwrites the placeholder.
-	putReq, err := c.createReq("PUT", dstBlob, http.NoBody)
+	if err := c.putPlaceholder(ctx, dstBlob); err != nil {
+		return err
+	}
+
+	copyReq, err := c.createReq(ctx, "COPY", srcBlob, nil)
+	if err != nil {
+		return fmt.Errorf("creating COPY request: %w", err)
+	}
+	copyReq.Header.Set("Destination", dstURL)
+	copyReq.Header.Set("Overwrite", "T")
+
+	copyResp, err := c.httpClient.Do(copyReq)
+	if err != nil {
+		return fmt.Errorf("performing COPY %q -> %q: %w", srcBlob, dstBlob, err)
+	}
+	defer copyResp.Body.Close() //nolint:errcheck
+
+	// RFC 4918 §9.8: 201 Created (new) or 204 No Content (overwritten).
+	if copyResp.StatusCode == http.StatusCreated || copyResp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return common.ErrorFromHTTPStatus(copyResp.StatusCode, fmt.Errorf("COPY %q -> %q: status %d, body: %s",
+		srcBlob, dstBlob, copyResp.StatusCode, c.readAndTruncateBody(copyResp)))
+}
+
+// Move moves srcBlob with the WebDAV MOVE method.
+func (c *storageClient) Move(ctx context.Context, srcBlob, dstBlob string) error {
+	dstURL, err := c.buildBlobURL(dstBlob)
+	if err != nil {
+		return fmt.Errorf("building destination URL: %w", err)
+	}
+
+	srcExists, err := c.Exists(ctx, srcBlob)
+	if err != nil {
+		return fmt.Errorf("checking source %q: %w", srcBlob, err)
+	}
+	if !srcExists {
+		dstExists, err := c.Exists(ctx, dstBlob)
+		if err != nil {
+			return fmt.Errorf("checking destination %q: %w", dstBlob, err)
+		}
+		if dstExists {
+			return nil
+		}
+		return common.NewError(common.ErrNotFound, fmt.Errorf("source %q does not exist", srcBlob))
+	}
+
+	// As for COPY, the placeholder makes nginx create the parent directories.
+	if err := c.putPlaceholder(ctx, dstBlob); err != nil {
+		return err
+	}
+
+	moveReq, err := c.createReq(ctx, "MOVE", srcBlob, nil)
+	if err != nil {
+		return fmt.Errorf("creating MOVE request: %w", err)
+	}
+	moveReq.Header.Set("Destination", dstURL)
+	moveReq.Header.Set("Overwrite", "T")
+
+	moveResp, err := c.httpClient.Do(moveReq)
+	if err != nil {
+		return fmt.Errorf("performing MOVE %q -> %q: %w", srcBlob, dstBlob, err)
+	}
+	defer moveResp.Body.Close() //nolint:errcheck
+
+	// RFC 4918 §9.9: 201 Created (new) or 204 No Content (overwritten).
+	if moveResp.StatusCode == http.StatusCreated || moveResp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return common.ErrorFromHTTPStatus(moveResp.StatusCode, fmt.Errorf("MOVE %q -> %q: status %d, body: %s",
+		srcBlob, dstBlob, moveResp.StatusCode, c.readAndTruncateBody(moveResp)))
+}
+
+// putPlaceholder PUTs an empty blob at dstBlob.
+func (c *storageClient) putPlaceholder(ctx context.Context, dstBlob string) error {
+	putReq, err := c.createReq(ctx, "PUT", dstBlob, http.NoBody)
 	if err != nil {
 		return fmt.Errorf("creating destination PUT request: %w", err)
 	}
@@ -302,36 +509,71 @@ func (c *storageClient) Copy(srcBlob, dstBlob string) error {
 	defer putResp.Body.Close() //nolint:errcheck
 
 	if putResp.StatusCode != http.StatusCreated && putResp.StatusCode != http.StatusNoContent && putResp.StatusCode != http.StatusOK {
-		return fmt.Errorf("creating destination placeholder %q: status %d, body: %s",
-			dstBlob, putResp.StatusCode, c.readAndTruncateBody(putResp))
+		return common.ErrorFromHTTPStatus(putResp.StatusCode, fmt.Errorf("creating destination placeholder %q: status %d, body: %s",
+			dstBlob, putResp.StatusCode, c.readAndTruncateBody(putResp)))
 	}
+	return nil
+}
 
-	copyReq, err := c.createReq("COPY", srcBlob, nil)
+func (c *storageClient) List(ctx context.Context, prefix string) ([]string, error) {
+	objects, err := c.ListObjects(ctx, prefix)
 	if err != nil {
-		return fmt.Errorf("creating COPY request: %w", err)
+		return nil, err
 	}
-	copyReq.Header.Set("Destination", dstURL)
-	copyReq.Header.Set("Overwrite", "T")
+	return common.ObjectNames(objects), nil
+}
 
-	copyResp, err := c.httpClient.Do(copyReq)
+func (c *storageClient) ListObjects(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	result, err := c.ListWithOptions(ctx, common.ListOptions{Prefix: prefix})
 	if err != nil {
-		return fmt.Errorf("performing COPY %q -> %q: %w", srcBlob, dstBlob, err)
+		return nil, err
 	}
-	defer copyResp.Body.Close() //nolint:errcheck
+	return result.Objects, nil
+}
 
-	// RFC 4918 §9.8: 201 Created (new) or 204 No Content (overwritten).
-	if copyResp.StatusCode == http.StatusCreated || copyResp.StatusCode == http.StatusNoContent {
-		return nil
+// ListWithOptions lists a single directory with a Depth-1 PROPFIND when the
+// delimiter is "/". Other delimiters do not match the directory structure,
+// so the objects below the prefix are walked and grouped instead. Pages are
+// cut from the entries in lexical order, their tokens are the name of the
+// last entry of the page.
+func (c *storageClient) ListWithOptions(ctx context.Context, opts common.ListOptions) (common.ListResult, error) {
+	rootURL, endpointPath, err := c.listRoot(opts.Prefix)
+	if err != nil {
+		return common.ListResult{}, err
 	}
 
-	return fmt.Errorf("COPY %q -> %q: status %d, body: %s",
-		srcBlob, dstBlob, copyResp.StatusCode, c.readAndTruncateBody(copyResp))
+	switch opts.Delimiter {
+	case "":
+		walk := &davWalk{endpointPath: endpointPath, prefix: opts.Prefix, after: max(opts.StartAfter, opts.PageToken)}
+		if opts.MaxKeys > 0 {
+			// One more blob tells whether there is a next page.
+			walk.limit = opts.MaxKeys + 1
+		}
+		if err := c.listRecursive(ctx, rootURL, walk); err != nil {
+			return common.ListResult{}, err
+		}
+		return common.Paginate(common.ListResult{Objects: walk.blobs}, opts), nil
+	case "/":
+		result, err := c.listDir(ctx, rootURL, endpointPath, opts.Prefix)
+		if err != nil {
+			return common.ListResult{}, err
+		}
+		return common.Paginate(result, opts), nil
+	default:
+		walk := &davWalk{endpointPath: endpointPath, prefix: opts.Prefix}
+		if err := c.listRecursive(ctx, rootURL, walk); err != nil {
+			return common.ListResult{}, err
+		}
+		return common.Paginate(common.GroupByDelimiter(walk.blobs, opts.Prefix, opts.Delimiter), opts), nil
+	}
 }
 
-func (c *storageClient) List(prefix string) ([]string, error) {
+// listRoot returns the URL of the directory a listing of prefix starts at and
+// the path of the endpoint, which blob IDs are relative to.
+func (c *storageClient) listRoot(prefix string) (string, string, error) {
 	rootURL, err := url.Parse(c.config.Endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("parsing endpoint URL: %w", err)
+		return "", "", fmt.Errorf("parsing endpoint URL: %w", err)
 	}
 	if !strings.HasPrefix(rootURL.Path, "/") {
 		rootURL.Path = "/" + rootURL.Path
@@ -349,7 +591,7 @@ func (c *storageClient) List(prefix string) ([]string, error) {
 		rootURL.Path = path.Join(rootURL.Path, dir) + "/"
 	}
 
-	return c.listRecursive(rootURL.String(), endpointPath, prefix)
+	return rootURL.String(), endpointPath, nil
 }
 
 // prefixDir returns the deepest directory a blob-ID prefix fully names, or ""
@@ -364,15 +606,17 @@ func prefixDir(prefix string) string {
 	return dir
 }
 
-func (c *storageClient) listRecursive(dirURL, endpointPath, prefix string) ([]string, error) {
+// propfind returns the entries of the collection at dirURL, without the
+// collection itself. A missing collection has no entries.
+func (c *storageClient) propfind(ctx context.Context, dirURL string) ([]davResponse, *url.URL, error) {
 	body, err := newPropfindBody()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	req, err := http.NewRequest("PROPFIND", dirURL, body)
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", dirURL, body)
 	if err != nil {
-		return nil, fmt.Errorf("creating PROPFIND request: %w", err)
+		return nil, nil, fmt.Errorf("creating PROPFIND request: %w", err)
 	}
 	if c.config.User != "" {
 		req.SetBasicAuth(c.config.User, c.config.Password)
@@ -382,69 +626,166 @@ func (c *storageClient) listRecursive(dirURL, endpointPath, prefix string) ([]st
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("performing PROPFIND: %w", err)
+		return nil, nil, fmt.Errorf("performing PROPFIND: %w", err)
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
+	parsedDirURL, err := url.Parse(dirURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing dirURL: %w", err)
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
-		return []string{}, nil
+		return nil, parsedDirURL, nil
 	}
 	if resp.StatusCode != http.StatusMultiStatus && resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("PROPFIND %q: status %d, body: %s",
-			dirURL, resp.StatusCode, c.readAndTruncateBody(resp))
+		return nil, nil, common.ErrorFromHTTPStatus(resp.StatusCode, fmt.Errorf("PROPFIND %q: status %d, body: %s",
+			dirURL, resp.StatusCode, c.readAndTruncateBody(resp)))
 	}
 
 	var multi multistatusResponse
 	if err := xml.NewDecoder(resp.Body).Decode(&multi); err != nil {
-		return nil, fmt.Errorf("decoding PROPFIND response: %w", err)
+		return nil, nil, fmt.Errorf("decoding PROPFIND response: %w", err)
 	}
 
-	parsedDirURL, err := url.Parse(dirURL)
-	if err != nil {
-		return nil, fmt.Errorf("parsing dirURL: %w", err)
-	}
 	currentPath := strings.TrimSuffix(parsedDirURL.Path, "/")
 
-	var blobs []string
+	var entries []davResponse
 	for _, response := range multi.Responses {
 		hrefURL, err := url.Parse(response.Href)
 		if err != nil {
 			slog.Warn("skipping unparseable href in PROPFIND response", "href", response.Href, "error", err)
 			continue
 		}
-		hrefPath := strings.TrimSuffix(hrefURL.Path, "/")
-
-		if hrefPath == currentPath {
+		if strings.TrimSuffix(hrefURL.Path, "/") == currentPath {
 			continue
 		}
+		entries = append(entries, response)
+	}
+	return entries, parsedDirURL, nil
+}
+
+// davWalk holds the state of a recursive listing.
+type davWalk struct {
+	endpointPath string
+	prefix       string
+	// after skips the blob IDs up to and including it.
+	after string
+	// limit, when positive, stops the walk once it found that many blobs.
+	limit int
+	blobs []common.ObjectInfo
+}
+
+func (w *davWalk) full() bool {
+	return w.limit > 0 && len(w.blobs) >= w.limit
+}
+
+// skipsCollection reports whether no blob the walk lists can be below the
+// collection at href.
+func (w *davWalk) skipsCollection(href string) bool {
+	if !collectionMayContainPrefix(href, w.endpointPath, w.prefix) {
+		return true
+	}
+	rel, err := blobIDFromHref(href, w.endpointPath)
+	if err != nil || w.after == "" {
+		return false
+	}
+	// Every blob ID below the collection starts with rel, so they all sort
+	// up to after, unless after starts with rel too.
+	rel = strings.TrimSuffix(rel, "/") + "/"
+	return rel < w.after && !strings.HasPrefix(w.after, rel)
+}
+
+// listRecursive walks the collection at dirURL depth first, visiting the
+// entries in the lexical order of the blob IDs below them, so that a walk
+// stopped by its limit has found the first blobs.
+func (c *storageClient) listRecursive(ctx context.Context, dirURL string, walk *davWalk) error {
+	entries, parsedDirURL, err := c.propfind(ctx, dirURL)
+	if err != nil {
+		return err
+	}
+
+	// Collections sort as their ID with a trailing "/", which every blob
+	// below them starts with.
+	sortKey := func(response davResponse) string {
+		blobID, err := blobIDFromHref(response.Href, walk.endpointPath)
+		if err != nil {
+			return response.Href
+		}
+		if response.isCollection() {
+			return strings.TrimSuffix(blobID, "/") + "/"
+		}
+		return blobID
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return sortKey(entries[i]) < sortKey(entries[j]) })
+
+	for _, response := range entries {
+		if walk.full() {
+			return nil
+		}
 
 		if response.isCollection() {
-			if !collectionMayContainPrefix(response.Href, endpointPath, prefix) {
+			if walk.skipsCollection(response.Href) {
 				continue
 			}
+			hrefURL, err := url.Parse(response.Href)
+			if err != nil {
+				return fmt.Errorf("parsing href: %w", err)
+			}
 			subURL := hrefURL.String()
 			if !hrefURL.IsAbs() {
 				subURL = parsedDirURL.ResolveReference(hrefURL).String()
 			}
-			sub, err := c.listRecursive(subURL, endpointPath, prefix)
-			if err != nil {
-				return nil, err
+			if err := c.listRecursive(ctx, subURL, walk); err != nil {
+				return err
 			}
-			blobs = append(blobs, sub...)
 			continue
 		}
 
+		blobID, err := blobIDFromHref(response.Href, walk.endpointPath)
+		if err != nil {
+			slog.Warn("skipping href that could not be mapped to a blob ID", "href", response.Href, "error", err)
+			continue
+		}
+		if strings.HasPrefix(blobID, walk.prefix) && blobID > walk.after {
+			walk.blobs = append(walk.blobs, response.objectInfo(blobID))
+		}
+	}
+
+	return nil
+}
+
+// listDir lists the blobs and subdirectories of the collection at dirURL
+// matching prefix. Subdirectories are reported as common prefixes ending
+// with "/".
+func (c *storageClient) listDir(ctx context.Context, dirURL, endpointPath, prefix string) (common.ListResult, error) {
+	entries, _, err := c.propfind(ctx, dirURL)
+	if err != nil {
+		return common.ListResult{}, err
+	}
+
+	var result common.ListResult
+	for _, response := range entries {
 		blobID, err := blobIDFromHref(response.Href, endpointPath)
 		if err != nil {
 			slog.Warn("skipping href that could not be mapped to a blob ID", "href", response.Href, "error", err)
 			continue
 		}
-		if prefix == "" || strings.HasPrefix(blobID, prefix) {
-			blobs = append(blobs, blobID)
+
+		if response.isCollection() {
+			dir := strings.TrimSuffix(blobID, "/") + "/"
+			if strings.HasPrefix(dir, prefix) {
+				result.Prefixes = append(result.Prefixes, dir)
+			}
+			continue
+		}
+		if strings.HasPrefix(blobID, prefix) {
+			result.Objects = append(result.Objects, response.objectInfo(blobID))
 		}
 	}
 
-	return blobs, nil
+	sort.Strings(result.Prefixes)
+	return result, nil
 }
 
 // collectionMayContainPrefix reports whether a collection can hold blob IDs
@@ -489,8 +830,8 @@ func blobIDFromHref(href, endpointPath string) (string, error) {
 	return hrefPath, nil
 }
 
-func (c *storageClient) DeleteRecursive(prefix string) error {
-	blobs, err := c.List(prefix)
+func (c *storageClient) DeleteRecursive(ctx context.Context, prefix string) error {
+	blobs, err := c.List(ctx, prefix)
 	if err != nil {
 		return fmt.Errorf("listing blobs under %q: %w", prefix, err)
 	}
@@ -500,43 +841,45 @@ func (c *storageClient) DeleteRecursive(prefix string) error {
 		return nil
 	}
 
+	var failures []common.DeleteFailure
 	for _, blob := range blobs {
-		if err := c.Delete(blob); err != nil {
-			return fmt.Errorf("deleting %q: %w", blob, err)
+		if err := ctx.Err(); err != nil {
+			return errors.Join(err, common.JoinDeleteFailures(failures))
+		}
+		if err := c.Delete(ctx, blob, common.DeleteOptions{}); err != nil {
+			failures = append(failures, common.DeleteFailure{Key: blob, Err: err})
 		}
 	}
-	return nil
+	return common.JoinDeleteFailures(failures)
 }
 
-// Properties prints the blob's metadata (ETag, Last-Modified, Content-Length)
-// as JSON to stdout. Returns nil with `{}` on 404 to mirror the behaviour of
-// other backends (S3, Azure) for missing blobs.
-func (c *storageClient) Properties(blobPath string) error {
-	req, err := c.createReq("HEAD", blobPath, nil)
+// Properties returns the blob's metadata from the headers of a HEAD request.
+func (c *storageClient) Properties(ctx context.Context, blobPath string) (common.Properties, error) {
+	req, err := c.createReq(ctx, "HEAD", blobPath, nil)
 	if err != nil {
-		return fmt.Errorf("creating HEAD request for %q: %w", blobPath, err)
+		return common.Properties{}, fmt.Errorf("creating HEAD request for %q: %w", blobPath, err)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("fetching properties of %q: %w", blobPath, err)
+		return common.Properties{}, fmt.Errorf("fetching properties of %q: %w", blobPath, err)
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
-	if resp.StatusCode == http.StatusNotFound {
-		fmt.Println("{}")
-		return nil
-	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("fetching properties of %q: status %d", blobPath, resp.StatusCode)
+		return common.Properties{}, common.ErrorFromHTTPStatus(resp.StatusCode, fmt.Errorf("fetching properties of %q: status %d", blobPath, resp.StatusCode))
 	}
 
-	props := BlobProperties{}
-	if resp.ContentLength >= 0 {
-		props.ContentLength = &resp.ContentLength
+	props := common.Properties{
+		ETag:               common.TrimETag(resp.Header.Get("ETag")),
+		ContentType:        resp.Header.Get("Content-Type"),
+		ContentEncoding:    resp.Header.Get("Content-Encoding"),
+		CacheControl:       resp.Header.Get("Cache-Control"),
+		ContentDisposition: resp.Header.Get("Content-Disposition"),
+		ContentMD5:         common.HexFromBase64(resp.Header.Get("Content-MD5")),
 	}
-	if etag := resp.Header.Get("ETag"); etag != "" {
-		props.ETag = strings.Trim(etag, `"`)
+	if resp.ContentLength >= 0 {
+		props.ContentLength = resp.ContentLength
 	}
 	if lm := resp.Header.Get("Last-Modified"); lm != "" {
 		if t, err := time.Parse(time.RFC1123, lm); err == nil {
@@ -545,13 +888,7 @@ func (c *storageClient) Properties(blobPath string) error {
 			slog.Warn("could not parse Last-Modified header", "value", lm, "error", err)
 		}
 	}
-
-	out, err := json.MarshalIndent(props, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshaling properties: %w", err)
-	}
-	fmt.Println(string(out))
-	return nil
+	return props, nil
 }
 
 // EnsureStorageExists is a no-op for DAV. WebDAV has no "bucket" concept to
@@ -559,6 +896,6 @@ func (c *storageClient) Properties(blobPath string) error {
 // `create_full_put_path on`), so there is nothing to do here. Matches the
 // fog-based Ruby DavClient, whose ensure_bucket_exists is also empty. The
 // method exists only to satisfy the StorageClient interface.
-func (c *storageClient) EnsureStorageExists() error {
+func (c *storageClient) EnsureStorageExists(ctx context.Context) error {
 	return nil
 }
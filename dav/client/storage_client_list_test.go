@@ -1,14 +1,19 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"slices"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	davconf "github.com/cloudfoundry/storage-cli/dav/config"
 )
 
@@ -22,6 +27,9 @@ type fakeDavServer struct {
 	// dirs maps a directory path (relative to the endpoint, "" for the
 	// root) to its child entries.
 	dirs map[string][]fakeEntry
+
+	// forbidden lists the paths whose DELETE is refused.
+	forbidden map[string]bool
 }
 
 type fakeEntry struct {
@@ -82,6 +90,10 @@ func (s *fakeDavServer) handler(endpointPath string) http.HandlerFunc {
 			s.mu.Lock()
 			s.deletes = append(s.deletes, rel)
 			s.mu.Unlock()
+			if s.forbidden[rel] {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
 			w.WriteHeader(http.StatusNoContent)
 
 		default:
@@ -131,7 +143,7 @@ func TestListScopesWalkToPrefix(t *testing.T) {
 	c, cleanup := newTestStorageClient(t, store)
 	defer cleanup()
 
-	blobs, err := c.List("ab/cd/abcd-target-guid")
+	blobs, err := c.List(context.Background(), "ab/cd/abcd-target-guid")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -147,12 +159,107 @@ func TestListScopesWalkToPrefix(t *testing.T) {
 	}
 }
 
+func TestListWithSlashDelimiterReadsOneDirectory(t *testing.T) {
+	store := newPartitionedStore()
+	c, cleanup := newTestStorageClient(t, store)
+	defer cleanup()
+
+	result, err := c.ListWithOptions(context.Background(), common.ListOptions{Prefix: "ab/cd/abcd-t", Delimiter: "/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantBlobs := []string{"ab/cd/abcd-target-guid-file"}
+	if blobs := common.ObjectNames(result.Objects); !slices.Equal(blobs, wantBlobs) {
+		t.Fatalf("unexpected blobs: %v, want %v", blobs, wantBlobs)
+	}
+	wantPrefixes := []string{"ab/cd/abcd-target-guid/"}
+	if !slices.Equal(result.Prefixes, wantPrefixes) {
+		t.Fatalf("unexpected prefixes: %v, want %v", result.Prefixes, wantPrefixes)
+	}
+	if wantPropfinds := []string{"ab/cd"}; !slices.Equal(store.propfinds, wantPropfinds) {
+		t.Fatalf("PROPFINDs hit %v, want %v", store.propfinds, wantPropfinds)
+	}
+}
+
+func TestListWithOtherDelimiterGroupsWalkedBlobs(t *testing.T) {
+	store := newPartitionedStore()
+	c, cleanup := newTestStorageClient(t, store)
+	defer cleanup()
+
+	result, err := c.ListWithOptions(context.Background(), common.ListOptions{Prefix: "ab/cd/", Delimiter: "-"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Objects) != 0 {
+		t.Fatalf("unexpected blobs: %v", common.ObjectNames(result.Objects))
+	}
+	wantPrefixes := []string{"ab/cd/abcd-"}
+	if !slices.Equal(result.Prefixes, wantPrefixes) {
+		t.Fatalf("unexpected prefixes: %v, want %v", result.Prefixes, wantPrefixes)
+	}
+}
+
+func TestListWithMaxKeysPagesThroughWalk(t *testing.T) {
+	store := newPartitionedStore()
+	c, cleanup := newTestStorageClient(t, store)
+	defer cleanup()
+
+	var blobs []string
+	opts := common.ListOptions{MaxKeys: 2}
+	for page := 1; ; page++ {
+		result, err := c.ListWithOptions(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Objects) > 2 {
+			t.Fatalf("page %d has %d blobs, want at most 2", page, len(result.Objects))
+		}
+		blobs = append(blobs, common.ObjectNames(result.Objects)...)
+		if result.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = result.NextPageToken
+	}
+
+	wantBlobs := []string{
+		"aa/bb/aabb-other-guid",
+		"ab/cd/abcd-target-guid-file",
+		"ab/cd/abcd-target-guid/cflinuxfs4",
+		"ab/cd/abcd-unrelated",
+		"ab/zz/abzz-unrelated",
+		"zz/yy/zzyy-other",
+	}
+	if !slices.Equal(blobs, wantBlobs) {
+		t.Fatalf("unexpected blobs: %v, want %v", blobs, wantBlobs)
+	}
+}
+
+func TestListStartAfterSkipsEarlierCollections(t *testing.T) {
+	store := newPartitionedStore()
+	c, cleanup := newTestStorageClient(t, store)
+	defer cleanup()
+
+	result, err := c.ListWithOptions(context.Background(), common.ListOptions{StartAfter: "ab/zz/abzz-unrelated"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if blobs, want := common.ObjectNames(result.Objects), []string{"zz/yy/zzyy-other"}; !slices.Equal(blobs, want) {
+		t.Fatalf("unexpected blobs: %v, want %v", blobs, want)
+	}
+	if want := []string{"", "ab", "ab/zz", "zz", "zz/yy"}; !slices.Equal(sorted(store.propfinds), want) {
+		t.Fatalf("walk visited collections before start-after: PROPFINDs hit %v, want %v", sorted(store.propfinds), want)
+	}
+}
+
 func TestListEmptyPrefixWalksWholeStore(t *testing.T) {
 	store := newPartitionedStore()
 	c, cleanup := newTestStorageClient(t, store)
 	defer cleanup()
 
-	blobs, err := c.List("")
+	blobs, err := c.List(context.Background(), "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -175,7 +282,7 @@ func TestListMissingPrefixDirectoryReturnsEmpty(t *testing.T) {
 	c, cleanup := newTestStorageClient(t, store)
 	defer cleanup()
 
-	blobs, err := c.List("no/such/prefix")
+	blobs, err := c.List(context.Background(), "no/such/prefix")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -189,7 +296,7 @@ func TestListSingleSegmentPrefixPrunesSiblings(t *testing.T) {
 	c, cleanup := newTestStorageClient(t, store)
 	defer cleanup()
 
-	blobs, err := c.List("ab")
+	blobs, err := c.List(context.Background(), "ab")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -216,7 +323,7 @@ func TestDeleteRecursiveDeletesOnlyPrefixedBlobs(t *testing.T) {
 	c, cleanup := newTestStorageClient(t, store)
 	defer cleanup()
 
-	if err := c.DeleteRecursive("ab/cd/abcd-target-guid"); err != nil {
+	if err := c.DeleteRecursive(context.Background(), "ab/cd/abcd-target-guid"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -231,6 +338,23 @@ func TestDeleteRecursiveDeletesOnlyPrefixedBlobs(t *testing.T) {
 	}
 }
 
+func TestDeleteRecursiveContinuesAfterFailedDeletes(t *testing.T) {
+	store := newPartitionedStore()
+	store.forbidden = map[string]bool{"ab/cd/abcd-target-guid-file": true}
+	c, cleanup := newTestStorageClient(t, store)
+	defer cleanup()
+
+	err := c.DeleteRecursive(context.Background(), "ab/cd/abcd-target-guid")
+	if !errors.Is(err, common.ErrPermissionDenied) || !strings.Contains(err.Error(), "abcd-target-guid-file") {
+		t.Fatalf("expected a permission denied error naming the blob, got %v", err)
+	}
+
+	wantDeletes := []string{"ab/cd/abcd-target-guid-file", "ab/cd/abcd-target-guid/cflinuxfs4"}
+	if !slices.Equal(sorted(store.deletes), wantDeletes) {
+		t.Fatalf("unexpected deletes: %v, want %v", sorted(store.deletes), wantDeletes)
+	}
+}
+
 func TestListEscapingPrefixStaysInsideEndpoint(t *testing.T) {
 	store := newPartitionedStore()
 	c, cleanup := newTestStorageClient(t, store)
@@ -239,7 +363,7 @@ func TestListEscapingPrefixStaysInsideEndpoint(t *testing.T) {
 	for _, prefix := range []string{"../", "../../..", "../etc/passwd"} {
 		store.propfinds = nil
 
-		blobs, err := c.List(prefix)
+		blobs, err := c.List(context.Background(), prefix)
 		if err != nil {
 			t.Fatalf("List(%q): unexpected error: %v", prefix, err)
 		}
@@ -259,7 +383,7 @@ func TestDeleteRecursiveMissingPrefixIsNoop(t *testing.T) {
 	c, cleanup := newTestStorageClient(t, store)
 	defer cleanup()
 
-	if err := c.DeleteRecursive("no/such/prefix"); err != nil {
+	if err := c.DeleteRecursive(context.Background(), "no/such/prefix"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if len(store.deletes) != 0 {
@@ -313,3 +437,46 @@ func TestCollectionMayContainPrefix(t *testing.T) {
 		}
 	}
 }
+
+func TestListObjectsReadsPropfindProperties(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body) //nolint:errcheck
+		requested = string(body)
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`<?xml version="1.0"?><D:multistatus xmlns:D="DAV:">` + //nolint:errcheck
+			`<D:response><D:href>/dav/</D:href><D:propstat><D:prop><D:resourcetype><D:collection/></D:resourcetype></D:prop></D:propstat></D:response>` +
+			`<D:response><D:href>/dav/blob</D:href>` +
+			`<D:propstat><D:prop><D:resourcetype/><D:getcontentlength>42</D:getcontentlength>` +
+			`<D:getlastmodified>Tue, 02 Jan 2024 03:04:05 GMT</D:getlastmodified><D:getetag>"abc"</D:getetag></D:prop>` +
+			`<D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>` +
+			`<D:response><D:href>/dav/bare</D:href>` +
+			`<D:propstat><D:prop><D:resourcetype/></D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat>` +
+			`<D:propstat><D:prop><D:getcontentlength/><D:getetag/></D:prop><D:status>HTTP/1.1 404 Not Found</D:status></D:propstat></D:response>` +
+			`</D:multistatus>`))
+	}))
+	defer server.Close()
+	c := NewStorageClient(davconf.Config{Endpoint: server.URL + "/dav"}, http.DefaultClient)
+
+	objects, err := c.ListObjects(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, prop := range []string{"D:getcontentlength", "D:getlastmodified", "D:getetag"} {
+		if !strings.Contains(requested, prop) {
+			t.Errorf("PROPFIND body does not request %s: %s", prop, requested)
+		}
+	}
+	want := []common.ObjectInfo{
+		{Name: "bare"},
+		{Name: "blob", Size: 42, ETag: "abc", LastModified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	if !slices.EqualFunc(objects, want, func(a, b common.ObjectInfo) bool {
+		return a.Name == b.Name && a.Size == b.Size && a.ETag == b.ETag && a.LastModified.Equal(b.LastModified)
+	}) {
+		t.Fatalf("ListObjects() = %+v, want %+v", objects, want)
+	}
+}
@@ -0,0 +1,326 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/bosh-utils/httpclient"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	"github.com/cloudfoundry/storage-cli/common"
+	davconf "github.com/cloudfoundry/storage-cli/dav/config"
+)
+
+func TestPutUnknownLengthStreamsChunked(t *testing.T) {
+	var gotBody string
+	var gotTransferEncoding []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body) //nolint:errcheck
+		gotBody = string(body)
+		gotTransferEncoding = r.TransferEncoding
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	retryClient := httpclient.NewRetryClient(http.DefaultClient, 3, time.Duration(0), boshlog.NewLogger(boshlog.LevelNone))
+	c := NewStorageClient(davconf.Config{Endpoint: server.URL + "/dav"}, retryClient)
+
+	// A pipe is neither seekable nor of known length.
+	pr, pw := io.Pipe()
+	go func() {
+		pw.Write([]byte("streamed content")) //nolint:errcheck
+		pw.Close()                           //nolint:errcheck
+	}()
+
+	if err := c.Put(context.Background(), "some/blob", pr, -1, common.PutOptions{}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if gotBody != "streamed content" {
+		t.Errorf("body = %q, want %q", gotBody, "streamed content")
+	}
+	if len(gotTransferEncoding) != 1 || gotTransferEncoding[0] != "chunked" {
+		t.Errorf("transfer encoding = %v, want [chunked]", gotTransferEncoding)
+	}
+}
+
+func TestPutUnknownLengthIsNotRetried(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		io.Copy(io.Discard, r.Body) //nolint:errcheck
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	retryClient := httpclient.NewRetryClient(http.DefaultClient, 3, time.Duration(0), boshlog.NewLogger(boshlog.LevelNone))
+	c := NewStorageClient(davconf.Config{Endpoint: server.URL + "/dav"}, retryClient)
+
+	err := c.Put(context.Background(), "some/blob", io.NopCloser(strings.NewReader("content")), -1, common.PutOptions{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestStat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+		if r.URL.Path != "/dav/some/blob" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"65f1-5f2"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Header().Set("Content-Length", "1522")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := NewStorageClient(davconf.Config{Endpoint: server.URL + "/dav"}, http.DefaultClient)
+
+	info, exists, err := c.Stat(context.Background(), "some/blob")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if !exists {
+		t.Fatal("expected blob to exist")
+	}
+	if info.Size != 1522 || info.ETag != "65f1-5f2" || info.ContentMD5 != "" {
+		t.Errorf("info = %+v", info)
+	}
+	if want := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC); !info.LastModified.Equal(want) {
+		t.Errorf("last modified = %v, want %v", info.LastModified, want)
+	}
+
+	_, exists, err = c.Stat(context.Background(), "missing")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if exists {
+		t.Error("expected missing blob not to exist")
+	}
+}
+
+func TestProperties(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dav/empty" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"0-5f2"`)
+		w.Header().Set("Content-Length", "0")
+		w.Header().Set("Content-Type", "application/gzip")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := NewStorageClient(davconf.Config{Endpoint: server.URL + "/dav"}, http.DefaultClient)
+
+	props, err := c.Properties(context.Background(), "empty")
+	if err != nil {
+		t.Fatalf("Properties: %v", err)
+	}
+	if props.ETag != "0-5f2" || props.ContentLength != 0 || props.ContentType != "application/gzip" {
+		t.Errorf("props = %+v", props)
+	}
+
+	_, err = c.Properties(context.Background(), "missing")
+	if !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
+
+func TestPutSetsHeaders(t *testing.T) {
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c := NewStorageClient(davconf.Config{Endpoint: server.URL + "/dav"}, http.DefaultClient)
+
+	opts := common.PutOptions{ContentType: "text/plain", ContentDisposition: `attachment; filename="a.txt"`}
+	if err := c.Put(context.Background(), "some/blob", io.NopCloser(strings.NewReader("content")), 7, opts); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if got := gotHeader.Get("Content-Type"); got != "text/plain" {
+		t.Errorf("content type = %q", got)
+	}
+	if got := gotHeader.Get("Content-Disposition"); got != `attachment; filename="a.txt"` {
+		t.Errorf("content disposition = %q", got)
+	}
+
+	opts = common.PutOptions{Metadata: map[string]string{"owner": "cc"}}
+	if err := c.Put(context.Background(), "some/blob", io.NopCloser(strings.NewReader("content")), 7, opts); err == nil {
+		t.Error("expected metadata to be rejected")
+	}
+}
+
+func TestPreconditions(t *testing.T) {
+	const etag = `"abc"`
+	exists := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body) //nolint:errcheck
+		switch {
+		case !exists:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Header.Get("If-None-Match") == "*":
+			w.WriteHeader(http.StatusPreconditionFailed)
+		case r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag:
+			w.WriteHeader(http.StatusPreconditionFailed)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	c := NewStorageClient(davconf.Config{Endpoint: server.URL + "/dav"}, http.DefaultClient)
+	ctx := context.Background()
+
+	err := c.Put(ctx, "some/blob", io.NopCloser(strings.NewReader("content")), 7, common.PutOptions{IfNoneMatch: "*"})
+	if !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("put with If-None-Match: err = %v, want a precondition failure", err)
+	}
+	if err := c.Put(ctx, "some/blob", io.NopCloser(strings.NewReader("content")), 7, common.PutOptions{IfMatch: "abc"}); err != nil {
+		t.Errorf("put with matching If-Match: %v", err)
+	}
+	if _, err := c.Get(ctx, "some/blob", common.GetOptions{IfMatch: "def"}); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("get with other If-Match: err = %v, want a precondition failure", err)
+	}
+	if err := c.Delete(ctx, "some/blob", common.DeleteOptions{IfMatch: "def"}); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("delete with other If-Match: err = %v, want a precondition failure", err)
+	}
+
+	exists = false
+	if err := c.Delete(ctx, "some/blob", common.DeleteOptions{}); err != nil {
+		t.Errorf("delete of a missing blob: %v", err)
+	}
+	if err := c.Delete(ctx, "some/blob", common.DeleteOptions{IfMatch: "abc"}); !errors.Is(err, common.ErrPreconditionFailed) {
+		t.Errorf("delete of a missing blob with If-Match: err = %v, want a precondition failure", err)
+	}
+}
+
+func TestGetRange(t *testing.T) {
+	const content = "0123456789"
+	var ranges []string
+	supportsRanges := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if !supportsRanges {
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			io.WriteString(w, content) //nolint:errcheck
+			return
+		}
+		http.ServeContent(w, r, "blob", time.Time{}, strings.NewReader(content))
+	}))
+	defer server.Close()
+
+	c := NewStorageClient(davconf.Config{Endpoint: server.URL + "/dav"}, http.DefaultClient)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		byteRange common.ByteRange
+		want      string
+	}{
+		{common.ByteRange{Start: 2, End: 5}, "2345"},
+		{common.ByteRange{Start: 7, End: -1}, "789"},
+		{common.ByteRange{Start: -3, End: -1}, "789"},
+		{common.ByteRange{Start: 8, End: 50}, "89"},
+	} {
+		for _, supportsRanges = range []bool{true, false} {
+			ranges = nil
+			body, err := c.Get(ctx, "some/blob", common.GetOptions{Range: &tc.byteRange})
+			if err != nil {
+				t.Fatalf("get range %s: %v", tc.byteRange, err)
+			}
+			got, err := io.ReadAll(body)
+			body.Close() //nolint:errcheck
+			if err != nil {
+				t.Fatalf("reading range %s: %v", tc.byteRange, err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("range %s (server supports ranges: %t) = %q, want %q", tc.byteRange, supportsRanges, got, tc.want)
+			}
+			if want := []string{tc.byteRange.HTTPHeader()}; !reflect.DeepEqual(ranges, want) {
+				t.Errorf("Range headers = %q, want %q", ranges, want)
+			}
+		}
+	}
+
+	supportsRanges = false
+	if _, err := c.Get(ctx, "some/blob", common.GetOptions{Range: &common.ByteRange{Start: 10, End: -1}}); err == nil {
+		t.Error("get of a range past the blob: expected an error")
+	}
+}
+
+func TestMove(t *testing.T) {
+	blobs := map[string]string{}
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		switch r.Method {
+		case "HEAD":
+			if _, ok := blobs[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case "PUT":
+			body, _ := io.ReadAll(r.Body) //nolint:errcheck
+			blobs[r.URL.Path] = string(body)
+			w.WriteHeader(http.StatusCreated)
+		case "MOVE":
+			dst, err := url.Parse(r.Header.Get("Destination"))
+			if err != nil || r.Header.Get("Overwrite") != "T" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			blobs[dst.Path] = blobs[r.URL.Path]
+			delete(blobs, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	c := NewStorageClient(davconf.Config{Endpoint: server.URL + "/dav"}, http.DefaultClient)
+	if err := c.Put(context.Background(), "src", io.NopCloser(strings.NewReader("content")), 7, common.PutOptions{}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if err := c.Move(context.Background(), "src", "some/dst"); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if len(blobs) != 1 {
+		t.Fatalf("blobs = %v, want only the destination", blobs)
+	}
+	for _, content := range blobs {
+		if content != "content" {
+			t.Errorf("destination content = %q", content)
+		}
+	}
+
+	methods = nil
+	if err := c.Move(context.Background(), "src", "some/dst"); err != nil {
+		t.Fatalf("retried Move: %v", err)
+	}
+	if strings.Join(methods, ",") != "HEAD,HEAD" {
+		t.Errorf("retried Move sent %v, want only HEAD requests", methods)
+	}
+
+	err := c.Move(context.Background(), "missing", "other")
+	if !errors.Is(err, common.ErrNotFound) {
+		t.Errorf("Move of a missing blob: got %v, want a not found error", err)
+	}
+}
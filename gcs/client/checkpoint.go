@@ -0,0 +1,204 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// uploadEndpoint is the JSON API endpoint starting resumable uploads.
+const uploadEndpoint = "https://storage.googleapis.com/upload/storage/v1"
+
+// statusResumeIncomplete is the status GCS answers with while a resumable
+// upload misses bytes.
+const statusResumeIncomplete = 308
+
+// errSessionExpired is returned for a resumable upload session GCS no longer
+// knows, so that a new one is started.
+var errSessionExpired = errors.New("resumable upload session expired")
+
+// putCheckpointed uploads size bytes of src through a resumable upload
+// session whose URI is recorded in checkpoint, continuing the session
+// recorded there from the offset GCS reports if it still exists. The storage
+// client does not expose session URIs, so the session is driven through the
+// JSON API. The object is only written if it satisfies conds, unless they are
+// nil.
+func (client *GCSBlobstore) putCheckpointed(ctx context.Context, src io.ReaderAt, size int64, dest string, opts common.PutOptions, conds *storage.Conditions, checkpoint *common.Checkpoint) error {
+	offset := int64(-1)
+	session, _ := checkpoint.Upload()
+	if session != "" {
+		var err error
+		offset, err = client.uploadOffset(ctx, session, size)
+		if errors.Is(err, errSessionExpired) {
+			slog.Info("Checkpointed upload expired, starting over", "object_name", dest)
+			offset = -1
+		} else if err != nil {
+			return err
+		} else {
+			slog.Info("Resuming checkpointed upload", "object_name", dest, "offset", offset, "size", size)
+		}
+	}
+	if offset < 0 {
+		var err error
+		session, err = client.startSession(ctx, size, dest, opts, conds)
+		if err != nil {
+			return err
+		}
+		if err := checkpoint.Start(session, int64(uploadChunkSize)); err != nil {
+			return err
+		}
+		offset = 0
+	}
+
+	for offset < size {
+		length := min(int64(uploadChunkSize), size-offset)
+		next, err := client.uploadChunk(ctx, session, io.NewSectionReader(src, offset, length), offset, length, size)
+		if errors.Is(classifyError(err), common.ErrPreconditionFailed) {
+			return errors.Join(err, checkpoint.Remove())
+		}
+		if err != nil {
+			return fmt.Errorf("uploading bytes %d-%d: %w", offset, offset+length-1, err)
+		}
+		offset = next
+	}
+	return checkpoint.Remove()
+}
+
+// startSession starts a resumable upload of size bytes to dest and returns
+// its session URI.
+func (client *GCSBlobstore) startSession(ctx context.Context, size int64, dest string, opts common.PutOptions, conds *storage.Conditions) (string, error) {
+	query := url.Values{"uploadType": {"resumable"}, "name": {dest}}
+	if conds != nil {
+		if conds.DoesNotExist {
+			query.Set("ifGenerationMatch", "0")
+		} else {
+			query.Set("ifGenerationMatch", strconv.FormatInt(conds.GenerationMatch, 10))
+		}
+	}
+	body, err := json.Marshal(struct {
+		Name               string            `json:"name"`
+		ContentType        string            `json:"contentType,omitempty"`
+		ContentEncoding    string            `json:"contentEncoding,omitempty"`
+		CacheControl       string            `json:"cacheControl,omitempty"`
+		ContentDisposition string            `json:"contentDisposition,omitempty"`
+		Metadata           map[string]string `json:"metadata,omitempty"`
+		StorageClass       string            `json:"storageClass,omitempty"`
+	}{dest, opts.ContentType, opts.ContentEncoding, opts.CacheControl, opts.ContentDisposition, opts.Metadata, client.config.StorageClass})
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := fmt.Sprintf("%s/b/%s/o?%s", uploadEndpoint, url.PathEscape(client.config.BucketName), query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+	if opts.ContentType != "" {
+		req.Header.Set("X-Upload-Content-Type", opts.ContentType)
+	}
+
+	resp, err := client.sendUploadRequest(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return "", fmt.Errorf("starting resumable upload: %w", err)
+	}
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return "", errors.New("starting resumable upload: no session URI in response")
+	}
+	return session, nil
+}
+
+// uploadOffset returns the number of bytes of the session GCS has persisted,
+// or size if the upload already completed.
+func (client *GCSBlobstore) uploadOffset(ctx context.Context, session string, size int64) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+	offset, err := client.sendChunk(req, size)
+	if err != nil {
+		return 0, fmt.Errorf("querying resumable upload: %w", err)
+	}
+	return offset, nil
+}
+
+// uploadChunk uploads length bytes of chunk at offset of the session and
+// returns the number of bytes GCS has persisted.
+func (client *GCSBlobstore) uploadChunk(ctx context.Context, session string, chunk io.Reader, offset, length, size int64) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, chunk)
+	if err != nil {
+		return 0, err
+	}
+	req.ContentLength = length
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, size))
+	return client.sendChunk(req, size)
+}
+
+// sendChunk sends a request of a resumable upload session and returns the
+// number of bytes GCS has persisted.
+func (client *GCSBlobstore) sendChunk(req *http.Request, size int64) (int64, error) {
+	resp, err := client.sendUploadRequest(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return size, nil
+	case statusResumeIncomplete:
+		return persistedBytes(resp.Header.Get("Range"))
+	case http.StatusNotFound, http.StatusGone:
+		return 0, errSessionExpired
+	}
+	return 0, googleapi.CheckResponse(resp)
+}
+
+// sendUploadRequest sends req with the headers every request of a resumable
+// upload needs.
+func (client *GCSBlobstore) sendUploadRequest(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", uaString)
+	if client.config.EncryptionKey != nil {
+		req.Header.Set("X-Goog-Encryption-Algorithm", "AES256")
+		req.Header.Set("X-Goog-Encryption-Key", client.config.EncryptionKeyEncoded)
+		req.Header.Set("X-Goog-Encryption-Key-Sha256", client.config.EncryptionKeySha256)
+	}
+	return client.authenticatedHTTP.Do(req)
+}
+
+// persistedBytes returns the number of bytes covered by the Range header
+// "bytes=0-N" of a resumable upload, which is absent before the first byte.
+func persistedBytes(header string) (int64, error) {
+	if header == "" {
+		return 0, nil
+	}
+	last, ok := strings.CutPrefix(header, "bytes=0-")
+	if !ok {
+		return 0, fmt.Errorf("unexpected range %q of resumable upload", header)
+	}
+	n, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected range %q of resumable upload: %w", header, err)
+	}
+	return n + 1, nil
+}
@@ -18,11 +18,12 @@ package client
 
 import (
 	"context"
-	"encoding/json"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
 	"os"
 	"strings"
 	"sync"
@@ -34,12 +35,13 @@ import (
 	"cloud.google.com/go/storage"
 	"cloud.google.com/go/storage/transfermanager"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/gcs/config"
 )
 
 // ErrInvalidROWriteOperation is returned when credentials associated with the
 // client disallow an attempted write operation.
-var ErrInvalidROWriteOperation = errors.New("the client operates in read only mode. Change 'credentials_source' parameter value ")
+var ErrInvalidROWriteOperation = common.NewError(common.ErrPermissionDenied, errors.New("the client operates in read only mode. Change 'credentials_source' parameter value "))
 
 // 4 MB of block size.
 // Used in concurrent download
@@ -56,17 +58,13 @@ const maxConcurrency = 5
 // Put retries retryAttempts times
 const retryAttempts = 3
 
-type BlobProperties struct {
-	ETag          string    `json:"etag,omitempty"`
-	LastModified  time.Time `json:"last_modified,omitempty"`
-	ContentLength int64     `json:"content_length,omitempty"`
-}
-
 // GCSBlobstore encapsulates interaction with the GCS blobstore
 type GCSBlobstore struct {
 	authenticatedGCS *storage.Client
 	publicGCS        *storage.Client
-	config           *config.GCSCli
+	// authenticatedHTTP sends the JSON API requests of checkpointed uploads.
+	authenticatedHTTP *http.Client
+	config            *config.GCSCli
 }
 
 // validateRemoteConfig determines if the configuration of the client matches
@@ -74,13 +72,13 @@ type GCSBlobstore struct {
 //
 // If operating in read-only mode, no mutations can be performed
 // so the remote bucket location is always compatible.
-func (client *GCSBlobstore) validateRemoteConfig() error {
+func (client *GCSBlobstore) validateRemoteConfig(ctx context.Context) error {
 	if client.readOnly() {
 		return nil
 	}
 
 	bucket := client.authenticatedGCS.Bucket(client.config.BucketName)
-	_, err := bucket.Attrs(context.Background())
+	_, err := bucket.Attrs(ctx)
 	return err
 }
 
@@ -107,17 +105,23 @@ func New(ctx context.Context, cfg *config.GCSCli) (*GCSBlobstore, error) {
 		return nil, errors.New("expected non-nill config object")
 	}
 
-	authenticatedGCS, publicGCS, err := newStorageClients(ctx, cfg)
+	authenticatedGCS, publicGCS, authenticatedHTTP, err := newStorageClients(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("creating storage client: %v", err)
 	}
 
-	return &GCSBlobstore{authenticatedGCS: authenticatedGCS, publicGCS: publicGCS, config: cfg}, nil
+	return &GCSBlobstore{authenticatedGCS: authenticatedGCS, publicGCS: publicGCS, authenticatedHTTP: authenticatedHTTP, config: cfg}, nil
 }
 
 // Get fetches a blob from the GCS blobstore.
 // Destination will be overwritten if it already exists.
-func (client *GCSBlobstore) Get(src string, dest string) error {
+func (client *GCSBlobstore) Get(ctx context.Context, src string, dest string) error {
+	return client.GetWithOptions(ctx, src, dest, common.GetOptions{})
+}
+
+// GetWithOptions is Get, downloading the object, or the byte range of opts,
+// only if it satisfies the preconditions of opts.
+func (client *GCSBlobstore) GetWithOptions(ctx context.Context, src string, dest string, opts common.GetOptions) error {
 	slog.Info("Getting object into file", "bucket", client.config.BucketName, "object_name", src, "local_path", dest)
 
 	destFile, err := os.Create(dest)
@@ -126,37 +130,121 @@ func (client *GCSBlobstore) Get(src string, dest string) error {
 	}
 	defer destFile.Close() //nolint:errcheck
 
-	gcsClient := client.publicGCS
-	err = client.checkAccess(client.publicGCS, src)
-	if err != nil && client.authenticatedGCS != nil {
-		err = client.checkAccess(client.authenticatedGCS, src)
-		if err == nil {
-			gcsClient = client.authenticatedGCS
-		}
+	gcsClient, err := client.readableClient(ctx, src)
+	if err != nil {
+		return classifyError(err)
 	}
 
+	conds, err := objectConditions(ctx, client.getObjectHandle(gcsClient, src), opts.IfMatch, "")
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	// If object is encrypted, we can't use transfermanager
 	// Fall back to single-part download with encryption support
 	if client.config.EncryptionKey != nil {
-		return client.downloadEncrypted(gcsClient, src, destFile)
+		return classifyError(client.downloadEncrypted(ctx, gcsClient, src, destFile, conds, opts.Range))
+	}
+
+	return classifyError(client.downloadConcurrent(ctx, gcsClient, src, destFile, conds, opts.Range))
+
+}
+
+// GetStream opens an object for reading. The caller must close the returned reader.
+func (client *GCSBlobstore) GetStream(ctx context.Context, src string) (io.ReadCloser, error) {
+	return client.GetStreamWithOptions(ctx, src, common.GetOptions{})
+}
+
+// GetStreamWithOptions is GetStream, opening the object, or the byte range of
+// opts, only if it satisfies the preconditions of opts.
+func (client *GCSBlobstore) GetStreamWithOptions(ctx context.Context, src string, opts common.GetOptions) (io.ReadCloser, error) {
+	slog.Info("Streaming object", "bucket", client.config.BucketName, "object_name", src)
+
+	gcsClient, err := client.readableClient(ctx, src)
+	if err != nil {
+		return nil, classifyError(err)
 	}
 
-	return client.downloadConcurrent(gcsClient, src, destFile)
+	handle := client.getObjectHandle(gcsClient, src)
+	conds, err := objectConditions(ctx, handle, opts.IfMatch, "")
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	if conds != nil {
+		handle = handle.If(*conds)
+	}
+
+	offset, length := rangeReaderArgs(opts.Range)
+	reader, err := handle.NewRangeReader(ctx, offset, length)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	return reader, nil
+}
+
+// rangeReaderArgs converts r into the offset and length of NewRangeReader,
+// which reads the whole object for a nil r.
+func rangeReaderArgs(r *common.ByteRange) (offset, length int64) {
+	if r == nil {
+		return 0, -1
+	}
+	if r.Start < 0 || r.End < 0 {
+		return r.Start, -1
+	}
+	return r.Start, r.End - r.Start + 1
+}
+
+// readableClient returns the public client if it can read src, falling back
+// to the authenticated client.
+func (client *GCSBlobstore) readableClient(ctx context.Context, src string) (*storage.Client, error) {
+	err := client.checkAccess(ctx, client.publicGCS, src)
+	if err == nil {
+		return client.publicGCS, nil
+	}
+
+	if client.authenticatedGCS != nil {
+		err = client.checkAccess(ctx, client.authenticatedGCS, src)
+		if err == nil {
+			return client.authenticatedGCS, nil
+		}
+	}
 
+	return nil, err
 }
 
 // If the client can read object attributes,
 // then it can download the object.
-func (client *GCSBlobstore) checkAccess(gcsClient *storage.Client, src string) error {
-	_, err := client.getObjectHandle(gcsClient, src).Attrs(context.Background())
+func (client *GCSBlobstore) checkAccess(ctx context.Context, gcsClient *storage.Client, src string) error {
+	_, err := client.getObjectHandle(gcsClient, src).Attrs(ctx)
 	return err
 }
 
-func (client *GCSBlobstore) downloadConcurrent(gcsClient *storage.Client, src string, destFile *os.File) error {
+// objectConditions returns the conditions enforcing ifMatch and ifNoneMatch
+// on handle, or nil if neither is set. GCS conditions refer to generations
+// rather than ETags, so ifMatch is checked against the current ETag and then
+// pinned to its generation, failing if the object changes in between.
+func objectConditions(ctx context.Context, handle *storage.ObjectHandle, ifMatch, ifNoneMatch string) (*storage.Conditions, error) {
+	if ifNoneMatch != "" {
+		return &storage.Conditions{DoesNotExist: true}, nil
+	}
+	if ifMatch == "" {
+		return nil, nil
+	}
+
+	attrs, err := handle.Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, common.CheckPreconditions("", false, ifMatch, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := common.CheckPreconditions(attrs.Etag, true, ifMatch, ""); err != nil {
+		return nil, err
+	}
+	return &storage.Conditions{GenerationMatch: attrs.Generation}, nil
+}
+
+func (client *GCSBlobstore) downloadConcurrent(ctx context.Context, gcsClient *storage.Client, src string, destFile *os.File, conds *storage.Conditions, byteRange *common.ByteRange) error {
 	downloader, err := transfermanager.NewDownloader(gcsClient,
 		transfermanager.WithPartSize(blockSize),
 		transfermanager.WithWorkers(maxConcurrency))
@@ -164,9 +252,13 @@ func (client *GCSBlobstore) downloadConcurrent(gcsClient *storage.Client, src st
 		return fmt.Errorf("creating new downloader: %w", err)
 	}
 
-	in := &transfermanager.DownloadObjectInput{Bucket: client.config.BucketName, Object: src, Destination: destFile}
+	in := &transfermanager.DownloadObjectInput{Bucket: client.config.BucketName, Object: src, Destination: destFile, Conditions: conds}
+	if byteRange != nil {
+		offset, length := rangeReaderArgs(byteRange)
+		in.Range = &transfermanager.DownloadRange{Offset: offset, Length: length}
+	}
 
-	if err := downloader.DownloadObject(context.Background(), in); err != nil {
+	if err := downloader.DownloadObject(ctx, in); err != nil {
 		return fmt.Errorf("adding work into queue: %w", err)
 	}
 
@@ -187,8 +279,13 @@ func (client *GCSBlobstore) downloadConcurrent(gcsClient *storage.Client, src st
 	return nil
 }
 
-func (client *GCSBlobstore) downloadEncrypted(gcsClient *storage.Client, src string, destFile *os.File) error {
-	reader, err := client.getObjectHandle(gcsClient, src).NewReader(context.Background())
+func (client *GCSBlobstore) downloadEncrypted(ctx context.Context, gcsClient *storage.Client, src string, destFile *os.File, conds *storage.Conditions, byteRange *common.ByteRange) error {
+	handle := client.getObjectHandle(gcsClient, src)
+	if conds != nil {
+		handle = handle.If(*conds)
+	}
+	offset, length := rangeReaderArgs(byteRange)
+	reader, err := handle.NewRangeReader(ctx, offset, length)
 	if err != nil {
 		return err
 	}
@@ -200,7 +297,14 @@ func (client *GCSBlobstore) downloadEncrypted(gcsClient *storage.Client, src str
 
 // Put uploads a blob to the GCS blobstore.
 // Destination will be overwritten if it already exists.
-func (client *GCSBlobstore) Put(sourceFilePath string, dest string) error {
+func (client *GCSBlobstore) Put(ctx context.Context, sourceFilePath string, dest string) error {
+	return client.PutWithOptions(ctx, sourceFilePath, dest, common.PutOptions{})
+}
+
+// PutWithOptions is Put with the headers and metadata of opts set on the
+// object. Files larger than one chunk record their upload session in the
+// checkpoint of opts, if set, so that a later call can resume it.
+func (client *GCSBlobstore) PutWithOptions(ctx context.Context, sourceFilePath string, dest string, opts common.PutOptions) error {
 	slog.Info("Putting file into object", "bucket", client.config.BucketName, "local_path", sourceFilePath, "object_name", dest)
 
 	src, err := os.Open(sourceFilePath)
@@ -213,8 +317,13 @@ func (client *GCSBlobstore) Put(sourceFilePath string, dest string) error {
 		return ErrInvalidROWriteOperation
 	}
 
-	if err := client.validateRemoteConfig(); err != nil {
-		return err
+	if err := client.validateRemoteConfig(ctx); err != nil {
+		return classifyError(err)
+	}
+
+	conds, err := objectConditions(ctx, client.getObjectHandle(client.authenticatedGCS, dest), opts.IfMatch, opts.IfNoneMatch)
+	if err != nil {
+		return classifyError(err)
 	}
 
 	pos, err := src.Seek(0, io.SeekCurrent)
@@ -222,12 +331,31 @@ func (client *GCSBlobstore) Put(sourceFilePath string, dest string) error {
 		return fmt.Errorf("finding buffer position: %v", err)
 	}
 
+	put := func() error { return client.putResumable(ctx, src, dest, opts, conds) }
+	if opts.Checkpoint != "" {
+		info, err := src.Stat()
+		if err != nil {
+			return err
+		}
+		// A single chunk has no progress worth recording.
+		if info.Size() > int64(uploadChunkSize) {
+			checkpoint, err := common.LoadCheckpoint(opts.Checkpoint, src, dest)
+			if err != nil {
+				return err
+			}
+			put = func() error { return client.putCheckpointed(ctx, src, info.Size(), dest, opts, conds, checkpoint) }
+		}
+	}
+
 	var errs []error
 	for i := range retryAttempts {
-		err := client.putResumable(src, dest)
+		err := put()
 		if err == nil {
 			return nil
 		}
+		if errors.Is(classifyError(err), common.ErrPreconditionFailed) {
+			return fmt.Errorf("upload failed for %s: %w", dest, classifyError(err))
+		}
 
 		errs = append(errs, err)
 		slog.Error("Upload failed", "object_name", dest, "attempt", fmt.Sprintf("%d/%d", i+1, retryAttempts), "error", err)
@@ -237,17 +365,57 @@ func (client *GCSBlobstore) Put(sourceFilePath string, dest string) error {
 		}
 	}
 
-	return fmt.Errorf("upload failed for %s after %d attempts: %v", dest, retryAttempts, errs)
+	return classifyError(fmt.Errorf("upload failed for %s after %d attempts: %w", dest, retryAttempts, errors.Join(errs...)))
+}
+
+// PutStream uploads content of unknown length to the GCS blobstore.
+// The content is only read once, so a failed upload is not retried.
+func (client *GCSBlobstore) PutStream(ctx context.Context, src io.Reader, dest string) error {
+	return client.PutStreamWithOptions(ctx, src, dest, common.PutOptions{})
+}
+
+// PutStreamWithOptions is PutStream with the headers and metadata of opts set
+// on the object.
+func (client *GCSBlobstore) PutStreamWithOptions(ctx context.Context, src io.Reader, dest string, opts common.PutOptions) error {
+	slog.Info("Putting stream into object", "bucket", client.config.BucketName, "object_name", dest)
+
+	if client.readOnly() {
+		return ErrInvalidROWriteOperation
+	}
+
+	if err := client.validateRemoteConfig(ctx); err != nil {
+		return classifyError(err)
+	}
+
+	conds, err := objectConditions(ctx, client.getObjectHandle(client.authenticatedGCS, dest), opts.IfMatch, opts.IfNoneMatch)
+	if err != nil {
+		return classifyError(err)
+	}
+
+	if err := client.putResumable(ctx, src, dest, opts, conds); err != nil {
+		return fmt.Errorf("upload failed for %s: %w", dest, classifyError(err))
+	}
+	return nil
 }
 
 // putResumable performs a resumable upload in chunks of uploadChunkSize (100MB).
 // Chunks are uploaded sequentially with automatic per-chunk retry on failure.
-func (client *GCSBlobstore) putResumable(src io.ReadSeeker, dest string) error {
-	ctx, cancel := context.WithCancel(context.Background())
+// The object is only written if it satisfies conds, unless they are nil.
+func (client *GCSBlobstore) putResumable(ctx context.Context, src io.Reader, dest string, opts common.PutOptions, conds *storage.Conditions) error {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel() // Clean up the context after the function completes
 
-	remoteWriter := client.getObjectHandle(client.authenticatedGCS, dest).NewWriter(ctx) //nolint:staticcheck
-	remoteWriter.ObjectAttrs.StorageClass = client.config.StorageClass                   //nolint:staticcheck
+	handle := client.getObjectHandle(client.authenticatedGCS, dest)
+	if conds != nil {
+		handle = handle.If(*conds)
+	}
+	remoteWriter := handle.NewWriter(ctx)                              //nolint:staticcheck
+	remoteWriter.ObjectAttrs.StorageClass = client.config.StorageClass //nolint:staticcheck
+	remoteWriter.ContentType = opts.ContentType
+	remoteWriter.ContentEncoding = opts.ContentEncoding
+	remoteWriter.CacheControl = opts.CacheControl
+	remoteWriter.ContentDisposition = opts.ContentDisposition
+	remoteWriter.Metadata = opts.Metadata
 	remoteWriter.ChunkSize = uploadChunkSize
 
 	if _, err := io.Copy(remoteWriter, src); err != nil {
@@ -261,38 +429,56 @@ func (client *GCSBlobstore) putResumable(src io.ReadSeeker, dest string) error {
 // Delete removes a blob from from the GCS blobstore.
 //
 // If the object does not exist, Delete returns a nil error.
-func (client *GCSBlobstore) Delete(dest string) error {
+func (client *GCSBlobstore) Delete(ctx context.Context, dest string) error {
+	return client.DeleteWithOptions(ctx, dest, common.DeleteOptions{})
+}
+
+// DeleteWithOptions is Delete, removing the object only if it satisfies the
+// preconditions of opts.
+func (client *GCSBlobstore) DeleteWithOptions(ctx context.Context, dest string, opts common.DeleteOptions) error {
 	slog.Info("Deleting object in bucket", "bucket", client.config.BucketName, "object_name", dest)
 
 	if client.readOnly() {
 		return ErrInvalidROWriteOperation
 	}
 
-	err := client.getObjectHandle(client.authenticatedGCS, dest).Delete(context.Background())
+	handle := client.getObjectHandle(client.authenticatedGCS, dest)
+	conds, err := objectConditions(ctx, handle, opts.IfMatch, "")
+	if err != nil {
+		return classifyError(err)
+	}
+	if conds != nil {
+		handle = handle.If(*conds)
+	}
+
+	err = handle.Delete(ctx)
 	if errors.Is(err, storage.ErrObjectNotExist) {
+		if opts.IfMatch != "" {
+			return common.NewError(common.ErrPreconditionFailed, fmt.Errorf("object %s does not exist", dest))
+		}
 		return nil
 	}
-	return err
+	return classifyError(err)
 }
 
 // Exists checks if a blob exists in the GCS blobstore.
-func (client *GCSBlobstore) Exists(dest string) (exists bool, err error) {
+func (client *GCSBlobstore) Exists(ctx context.Context, dest string) (exists bool, err error) {
 	slog.Info("Checking object exists in bucket", "bucket", client.config.BucketName, "object_name", dest)
 
-	if exists, err = client.exists(client.publicGCS, dest); err == nil {
+	if exists, err = client.exists(ctx, client.publicGCS, dest); err == nil {
 		return exists, nil
 	}
 
 	// If the public client fails, try using it as an authenticated actor
 	if client.authenticatedGCS != nil {
-		return client.exists(client.authenticatedGCS, dest)
+		return client.exists(ctx, client.authenticatedGCS, dest)
 	}
 
 	return
 }
 
-func (client *GCSBlobstore) exists(gcs *storage.Client, dest string) (bool, error) {
-	_, err := client.getObjectHandle(gcs, dest).Attrs(context.Background())
+func (client *GCSBlobstore) exists(ctx context.Context, gcs *storage.Client, dest string) (bool, error) {
+	_, err := client.getObjectHandle(gcs, dest).Attrs(ctx)
 	if err == nil {
 		slog.Info("Object exists in bucket", "bucket", client.config.BucketName, "object_name", dest)
 		return true, nil
@@ -300,14 +486,57 @@ func (client *GCSBlobstore) exists(gcs *storage.Client, dest string) (bool, erro
 		slog.Info("Object does not exist in bucket", "bucket", client.config.BucketName, "object_name", dest)
 		return false, nil
 	}
-	return false, err
+	return false, classifyError(err)
+}
+
+// Stat returns the size, ETag and MD5 of an object. exists is false if the object does not exist.
+func (client *GCSBlobstore) Stat(ctx context.Context, dest string) (info common.ObjectInfo, exists bool, err error) {
+	slog.Info("Getting object metadata", "bucket", client.config.BucketName, "object_name", dest)
+
+	if info, exists, err = client.stat(ctx, client.publicGCS, dest); err == nil {
+		return info, exists, nil
+	}
+
+	// If the public client fails, try using it as an authenticated actor
+	if client.authenticatedGCS != nil {
+		return client.stat(ctx, client.authenticatedGCS, dest)
+	}
+
+	return
+}
+
+func (client *GCSBlobstore) stat(ctx context.Context, gcs *storage.Client, dest string) (common.ObjectInfo, bool, error) {
+	attr, err := client.getObjectHandle(gcs, dest).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return common.ObjectInfo{}, false, nil
+	}
+	if err != nil {
+		return common.ObjectInfo{}, false, classifyError(err)
+	}
+
+	return objectInfo(attr), true, nil
+}
+
+// objectInfo converts the attributes GCS returns for an object.
+func objectInfo(attr *storage.ObjectAttrs) common.ObjectInfo {
+	info := common.ObjectInfo{
+		Name:         attr.Name,
+		Size:         attr.Size,
+		ETag:         common.TrimETag(attr.Etag),
+		LastModified: attr.Updated,
+		StorageClass: attr.StorageClass,
+	}
+	if len(attr.MD5) > 0 {
+		info.ContentMD5 = hex.EncodeToString(attr.MD5)
+	}
+	return info
 }
 
 func (client *GCSBlobstore) readOnly() bool {
 	return client.authenticatedGCS == nil
 }
 
-func (client *GCSBlobstore) Sign(id string, action string, expiry time.Duration) (string, error) {
+func (client *GCSBlobstore) Sign(ctx context.Context, id string, action string, expiry time.Duration) (string, error) {
 	slog.Info("Signing object", "bucket", client.config.BucketName, "object_name", id, "method", action, "expiration", expiry.String())
 
 	action = strings.ToUpper(action)
@@ -336,21 +565,65 @@ func (client *GCSBlobstore) Sign(id string, action string, expiry time.Duration)
 	return storage.SignedURL(client.config.BucketName, id, &options)
 }
 
-func (client *GCSBlobstore) List(prefix string) ([]string, error) {
-	if prefix != "" {
-		slog.Info("Listing all objects in bucket", "bucket", client.config.BucketName, "prefix", prefix)
+func (client *GCSBlobstore) List(ctx context.Context, prefix string) ([]string, error) {
+	objects, err := client.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return common.ObjectNames(objects), nil
+}
+
+func (client *GCSBlobstore) ListObjects(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	result, err := client.ListWithOptions(ctx, common.ListOptions{Prefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+	return result.Objects, nil
+}
+
+func (client *GCSBlobstore) ListWithOptions(ctx context.Context, opts common.ListOptions) (common.ListResult, error) {
+	if opts.Prefix != "" {
+		slog.Info("Listing all objects in bucket", "bucket", client.config.BucketName, "prefix", opts.Prefix, "delimiter", opts.Delimiter)
 	} else {
-		slog.Info("Listing all objects in bucket", "bucket", client.config.BucketName)
+		slog.Info("Listing all objects in bucket", "bucket", client.config.BucketName, "delimiter", opts.Delimiter)
 	}
 	if client.readOnly() {
-		return nil, ErrInvalidROWriteOperation
+		return common.ListResult{}, ErrInvalidROWriteOperation
 	}
 
 	bh := client.getBucketHandle(client.authenticatedGCS)
 
-	it := bh.Objects(context.Background(), &storage.Query{Prefix: prefix})
+	// StartOffset is inclusive, the name equal to it is skipped below.
+	it := bh.Objects(ctx, &storage.Query{Prefix: opts.Prefix, Delimiter: opts.Delimiter, StartOffset: opts.StartAfter})
+
+	var result common.ListResult
+	add := func(attr *storage.ObjectAttrs) {
+		// With a delimiter, common prefixes are returned as attributes
+		// holding only the prefix.
+		switch {
+		case attr.Prefix != "":
+			if attr.Prefix != opts.StartAfter {
+				result.Prefixes = append(result.Prefixes, attr.Prefix)
+			}
+		case attr.Name != opts.StartAfter:
+			result.Objects = append(result.Objects, objectInfo(attr))
+		}
+	}
+
+	if opts.MaxKeys > 0 {
+		var attrs []*storage.ObjectAttrs
+		nextPageToken, err := iterator.NewPager(it, opts.MaxKeys, opts.PageToken).NextPage(&attrs)
+		if err != nil {
+			return common.ListResult{}, classifyError(err)
+		}
+		for _, attr := range attrs {
+			add(attr)
+		}
+		result.NextPageToken = nextPageToken
+		return result, nil
+	}
 
-	var names []string
+	it.PageInfo().Token = opts.PageToken
 	for {
 		attr, err := it.Next()
 		if err == iterator.Done {
@@ -358,17 +631,17 @@ func (client *GCSBlobstore) List(prefix string) ([]string, error) {
 		}
 
 		if err != nil {
-			return nil, err
+			return common.ListResult{}, classifyError(err)
 		}
 
-		names = append(names, attr.Name)
+		add(attr)
 	}
 
-	return names, nil
+	return result, nil
 
 }
 
-func (client *GCSBlobstore) Copy(srcBlob string, dstBlob string) error {
+func (client *GCSBlobstore) Copy(ctx context.Context, srcBlob string, dstBlob string) error {
 	slog.Info("Copying object", "bucket", client.config.BucketName, "source_object", srcBlob, "destination_object", dstBlob)
 
 	if client.readOnly() {
@@ -378,52 +651,59 @@ func (client *GCSBlobstore) Copy(srcBlob string, dstBlob string) error {
 	srcHandle := client.getObjectHandle(client.authenticatedGCS, srcBlob)
 	dstHandle := client.getObjectHandle(client.authenticatedGCS, dstBlob)
 
-	_, err := dstHandle.CopierFrom(srcHandle).Run(context.Background())
+	_, err := dstHandle.CopierFrom(srcHandle).Run(ctx)
 	if err != nil {
-		return fmt.Errorf("copying object: %w", err)
+		return fmt.Errorf("copying object: %w", classifyError(err))
 	}
 	return nil
 }
 
-func (client *GCSBlobstore) Properties(dest string) error {
-	slog.Info("Getting properties for object", "bucket", client.config.BucketName, "object_name", dest)
+func (client *GCSBlobstore) Move(ctx context.Context, srcBlob string, dstBlob string) error {
+	slog.Info("Moving object", "bucket", client.config.BucketName, "source_object", srcBlob, "destination_object", dstBlob)
 
 	if client.readOnly() {
 		return ErrInvalidROWriteOperation
 	}
-	oh := client.getObjectHandle(client.authenticatedGCS, dest)
-	attr, err := oh.Attrs(context.Background())
+	return common.MoveByCopy(ctx, client, srcBlob, dstBlob)
+}
 
-	if err != nil {
-		if errors.Is(err, storage.ErrObjectNotExist) {
-			fmt.Println(`{}`)
-			return nil
-		}
-		return fmt.Errorf("getting attributes: %w", err)
-	}
+func (client *GCSBlobstore) Properties(ctx context.Context, dest string) (common.Properties, error) {
+	slog.Info("Getting properties for object", "bucket", client.config.BucketName, "object_name", dest)
 
-	props := BlobProperties{
-		ETag:          strings.Trim(attr.Etag, `"`),
-		LastModified:  attr.Updated,
-		ContentLength: attr.Size,
+	if client.readOnly() {
+		return common.Properties{}, ErrInvalidROWriteOperation
 	}
-
-	output, err := json.MarshalIndent(props, "", "  ")
+	oh := client.getObjectHandle(client.authenticatedGCS, dest)
+	attr, err := oh.Attrs(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to marshal blob properties: %w", err)
-	}
-
-	fmt.Println(string(output))
-	return nil
+		return common.Properties{}, fmt.Errorf("getting attributes: %w", classifyError(err))
+	}
+
+	return common.Properties{
+		ETag:               common.TrimETag(attr.Etag),
+		LastModified:       attr.Updated,
+		ContentLength:      attr.Size,
+		ContentType:        attr.ContentType,
+		ContentEncoding:    attr.ContentEncoding,
+		CacheControl:       attr.CacheControl,
+		ContentDisposition: attr.ContentDisposition,
+		ContentMD5:         hex.EncodeToString(attr.MD5),
+		CRC32C:             fmt.Sprintf("%08x", attr.CRC32C),
+		StorageClass:       attr.StorageClass,
+		Encryption: common.Encryption{
+			KMSKeyID:          attr.KMSKeyName,
+			CustomerKeySHA256: attr.CustomerKeySHA256,
+		},
+		Metadata: attr.Metadata,
+	}, nil
 }
 
-func (client *GCSBlobstore) EnsureStorageExists() error {
+func (client *GCSBlobstore) EnsureStorageExists(ctx context.Context) error {
 	slog.Info("Ensuring bucket exists", "bucket", client.config.BucketName)
 
 	if client.readOnly() {
 		return ErrInvalidROWriteOperation
 	}
-	ctx := context.Background()
 	bh := client.getBucketHandle(client.authenticatedGCS)
 
 	_, err := bh.Attrs(ctx)
@@ -444,18 +724,25 @@ func (client *GCSBlobstore) EnsureStorageExists() error {
 
 		err = bh.Create(ctx, projectID, battr)
 		if err != nil {
-			return fmt.Errorf("creating bucket: %w", err)
+			return fmt.Errorf("creating bucket: %w", classifyError(err))
 		}
 		return nil
 	}
 	if err != nil {
-		return fmt.Errorf("checking bucket: %w", err)
+		return fmt.Errorf("checking bucket: %w", classifyError(err))
 	}
 
 	return nil
 }
 
-func (client *GCSBlobstore) DeleteRecursive(prefix string) error {
+// DeleteMany deletes dests concurrently, as GCS has no batch delete in its
+// Go client.
+func (client *GCSBlobstore) DeleteMany(ctx context.Context, dests []string) []common.DeleteFailure {
+	slog.Info("Deleting objects in bucket", "bucket", client.config.BucketName, "objects", len(dests))
+	return common.DeleteConcurrently(ctx, dests, common.DefaultDeleteParallelism, client.Delete)
+}
+
+func (client *GCSBlobstore) DeleteRecursive(ctx context.Context, prefix string) error {
 	if prefix != "" {
 		slog.Info("Deleting all the objects in bucket", "bucket", client.config.BucketName, "prefix", prefix)
 	} else {
@@ -466,7 +753,7 @@ func (client *GCSBlobstore) DeleteRecursive(prefix string) error {
 		return ErrInvalidROWriteOperation
 	}
 
-	names, err := client.List(prefix)
+	names, err := client.List(ctx, prefix)
 	if err != nil {
 		return fmt.Errorf("listing objects: %w", err)
 	}
@@ -483,9 +770,9 @@ func (client *GCSBlobstore) DeleteRecursive(prefix string) error {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			err := client.getObjectHandle(client.authenticatedGCS, name).Delete(context.Background())
+			err := client.getObjectHandle(client.authenticatedGCS, name).Delete(ctx)
 			if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
-				errChan <- fmt.Errorf("deleting object %s: %w", name, err)
+				errChan <- fmt.Errorf("deleting object %s: %w", name, classifyError(err))
 			}
 		}()
 	}
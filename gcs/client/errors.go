@@ -0,0 +1,27 @@
+package client
+
+import (
+	"errors"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// classifyError marks err with the kind of failure reported by GCS.
+func classifyError(err error) error {
+	if err == nil || common.ErrorKind(err) != nil {
+		return err
+	}
+
+	if errors.Is(err, storage.ErrObjectNotExist) || errors.Is(err, storage.ErrBucketNotExist) {
+		return common.NewError(common.ErrNotFound, err)
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return common.ErrorFromHTTPStatus(apiErr.Code, err)
+	}
+	return err
+}
@@ -38,9 +38,14 @@ import (
 
 const uaString = "storage-cli-gcs"
 
-func newStorageClients(ctx context.Context, cfg *config.GCSCli) (*storage.Client, *storage.Client, error) {
+// newStorageClients returns the authenticated and public storage clients,
+// and an authenticated HTTP client for the JSON API requests the storage
+// client does not cover. The authenticated clients are nil for read-only
+// credentials.
+func newStorageClients(ctx context.Context, cfg *config.GCSCli) (*storage.Client, *storage.Client, *http.Client, error) {
 	publicClient, err := storage.NewClient(ctx, option.WithUserAgent(uaString), option.WithHTTPClient(http.DefaultClient))
 	var authenticatedClient *storage.Client
+	var httpClient *http.Client
 	var tokenSource oauth2.TokenSource
 	var token *jwt.Config
 
@@ -54,6 +59,7 @@ func newStorageClients(ctx context.Context, cfg *config.GCSCli) (*storage.Client
 		}
 	case config.DefaultCredentialsSource:
 		if tokenSource, err = google.DefaultTokenSource(ctx, storage.ScopeFullControl); err == nil {
+			httpClient = newHTTPClient(ctx, tokenSource)
 			if common.IsDebug() {
 				baseClient := oauth2.NewClient(ctx, tokenSource)
 				baseClient.Transport = middleware.NewLoggingTransport(baseClient.Transport)
@@ -65,6 +71,7 @@ func newStorageClients(ctx context.Context, cfg *config.GCSCli) (*storage.Client
 		}
 	case config.ServiceAccountFileCredentialsSource:
 		if token, err = google.JWTConfigFromJSON([]byte(cfg.ServiceAccountFile), storage.ScopeFullControl); err == nil {
+			httpClient = newHTTPClient(ctx, token.TokenSource(ctx))
 			if common.IsDebug() {
 				tokenSource := token.TokenSource(ctx)
 				baseClient := oauth2.NewClient(ctx, tokenSource)
@@ -75,9 +82,19 @@ func newStorageClients(ctx context.Context, cfg *config.GCSCli) (*storage.Client
 			}
 		}
 	default:
-		return nil, nil, errors.New("unknown credentials_source in configuration")
+		return nil, nil, nil, errors.New("unknown credentials_source in configuration")
 	}
-	return authenticatedClient, publicClient, err
+	return authenticatedClient, publicClient, httpClient, err
+}
+
+// newHTTPClient returns an HTTP client authorizing its requests with
+// tokenSource, logging them in debug mode.
+func newHTTPClient(ctx context.Context, tokenSource oauth2.TokenSource) *http.Client {
+	httpClient := oauth2.NewClient(ctx, tokenSource)
+	if common.IsDebug() {
+		httpClient.Transport = middleware.NewLoggingTransport(httpClient.Transport)
+	}
+	return httpClient
 }
 
 func extractProjectID(ctx context.Context, cfg *config.GCSCli) (string, error) {
@@ -17,6 +17,7 @@
 package integration
 
 import (
+	"context"
 	"crypto/sha256"
 	"io"
 	"log"
@@ -76,7 +77,7 @@ var _ = Describe("Integration", func() {
 
 			tmpFileName := "gcscli-test-wrong-enc-key"
 			defer os.Remove(tmpFileName) //nolint:errcheck
-			err = blobstoreClient.Get(env.GCSFileName, tmpFileName)
+			err = blobstoreClient.Get(context.Background(), env.GCSFileName, tmpFileName)
 			Expect(err).To(HaveOccurred())
 
 			session, err = RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete", env.GCSFileName)
@@ -101,7 +102,7 @@ var _ = Describe("Integration", func() {
 
 			tmpFileName := "gcscli-test-no-enc-key"
 			defer os.Remove(tmpFileName) //nolint:errcheck
-			err = blobstoreClient.Get(env.GCSFileName, tmpFileName)
+			err = blobstoreClient.Get(context.Background(), env.GCSFileName, tmpFileName)
 			Expect(err).To(HaveOccurred())
 
 			session, err = RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete", env.GCSFileName)
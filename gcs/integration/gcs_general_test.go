@@ -83,10 +83,10 @@ var _ = Describe("Integration", func() {
 				blobstoreClient, err := client.New(env.ctx, env.Config)
 				Expect(err).ToNot(HaveOccurred())
 
-				err = blobstoreClient.Put(largeFile, env.GCSFileName)
+				err = blobstoreClient.Put(context.Background(), largeFile, env.GCSFileName)
 				Expect(err).ToNot(HaveOccurred())
 
-				blobstoreClient.Delete(env.GCSFileName) //nolint:errcheck
+				blobstoreClient.Delete(context.Background(), env.GCSFileName) //nolint:errcheck
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
@@ -111,7 +111,7 @@ var _ = Describe("Integration", func() {
 					}
 				}()
 
-				err = blobstoreClient.Put(pipePath, env.GCSFileName)
+				err = blobstoreClient.Put(context.Background(), pipePath, env.GCSFileName)
 				Expect(err).To(MatchError(ContainSubstring("illegal seek")))
 			},
 			configurations)
@@ -190,11 +190,11 @@ var _ = Describe("Integration", func() {
 		DescribeTable("delete-recursive is idempotent", func(config *config.GCSCli) {
 			env.AddConfig(config)
 
-			session, err := RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete-recursive")
+			session, err := RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete-recursive", "--all")
 			Expect(err).ToNot(HaveOccurred())
 			Expect(session.ExitCode()).To(BeZero())
 
-			session, err = RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete-recursive")
+			session, err = RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete-recursive", "--all")
 			Expect(err).ToNot(HaveOccurred())
 			Expect(session.ExitCode()).To(BeZero())
 
@@ -0,0 +1,724 @@
+package client
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+	URLsigner "github.com/cloudfoundry/storage-cli/dav/signer"
+	"github.com/cloudfoundry/storage-cli/local/config"
+)
+
+// Blobs are written to a temporary file next to their final location and
+// renamed into place, so readers never observe a partially written blob.
+// List skips these files.
+const tempFilePrefix = ".storage-cli-upload-"
+
+const (
+	dirMode  = os.FileMode(0755)
+	fileMode = os.FileMode(0644)
+)
+
+// LocalBlobstore stores blobs as files below a root directory on disk
+type LocalBlobstore struct {
+	config config.LocalConfig
+
+	// md5s caches the MD5 of blobs by path, see fileMD5.
+	md5sMu sync.Mutex
+	md5s   map[string]cachedMD5
+}
+
+type cachedMD5 struct {
+	size    int64
+	modTime time.Time
+	sum     string
+}
+
+// New returns a LocalBlobstore rooted at the configured directory
+func New(cfg config.LocalConfig) (*LocalBlobstore, error) {
+	root, err := filepath.Abs(cfg.RootDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("resolving root directory: %w", err)
+	}
+	cfg.RootDirectory = root
+
+	if cfg.DirectoryKey == "" {
+		cfg.DirectoryKey = filepath.Base(root)
+	}
+
+	return &LocalBlobstore{config: cfg}, nil
+}
+
+func (client *LocalBlobstore) blobPath(blobID string) (string, error) {
+	if err := validateBlobID(blobID); err != nil {
+		return "", err
+	}
+	return filepath.Join(client.config.RootDirectory, filepath.FromSlash(blobID)), nil
+}
+
+func (client *LocalBlobstore) Put(ctx context.Context, sourceFilePath string, dest string) error {
+	return client.PutWithOptions(ctx, sourceFilePath, dest, common.PutOptions{})
+}
+
+// PutWithOptions is Put with the preconditions of opts. Files have no content
+// headers or user-defined metadata to store them in, and are copied in one go
+// rather than in parts that a checkpoint could record.
+func (client *LocalBlobstore) PutWithOptions(ctx context.Context, sourceFilePath string, dest string, opts common.PutOptions) error {
+	if err := checkPutOptions(opts); err != nil {
+		return err
+	}
+	if opts.Checkpoint != "" {
+		return errors.New("local storage does not support checkpointed uploads")
+	}
+	slog.Info("Putting file into local storage", "root", client.config.RootDirectory, "local_path", sourceFilePath, "blob", dest)
+
+	source, err := os.Open(sourceFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open source file: %w", err)
+	}
+	defer source.Close() //nolint:errcheck
+
+	return client.writeAtomically(ctx, dest, source, opts)
+}
+
+// PutStream writes content of unknown length to a blob.
+func (client *LocalBlobstore) PutStream(ctx context.Context, source io.Reader, dest string) error {
+	return client.PutStreamWithOptions(ctx, source, dest, common.PutOptions{})
+}
+
+// PutStreamWithOptions is PutStream with the preconditions of opts, see
+// PutWithOptions.
+func (client *LocalBlobstore) PutStreamWithOptions(ctx context.Context, source io.Reader, dest string, opts common.PutOptions) error {
+	if err := checkPutOptions(opts); err != nil {
+		return err
+	}
+	slog.Info("Putting stream into local storage", "root", client.config.RootDirectory, "blob", dest)
+
+	return client.writeAtomically(ctx, dest, source, opts)
+}
+
+func checkPutOptions(opts common.PutOptions) error {
+	if opts.ContentType != "" || opts.ContentEncoding != "" || opts.CacheControl != "" || opts.ContentDisposition != "" || len(opts.Metadata) > 0 {
+		return errors.New("local storage does not support content headers or user-defined metadata")
+	}
+	return nil
+}
+
+// writeAtomically streams content into a temporary file in the destination
+// directory and renames it over the blob once it is fully written and synced.
+// With opts.IfNoneMatch the file is hard-linked instead, which fails if the
+// blob exists. opts.IfMatch is checked just before the rename, so it can race
+// with a concurrent write.
+func (client *LocalBlobstore) writeAtomically(ctx context.Context, dest string, content io.Reader, opts common.PutOptions) error {
+	blobPath, err := client.blobPath(dest)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(blobPath)
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return classifyError(fmt.Errorf("creating directory for blob %q: %w", dest, err))
+	}
+
+	tmpFile, err := os.CreateTemp(dir, tempFilePrefix+"*")
+	if err != nil {
+		return classifyError(fmt.Errorf("creating temporary file for blob %q: %w", dest, err))
+	}
+	tmpPath := tmpFile.Name()
+
+	renamed := false
+	defer func() {
+		if !renamed {
+			tmpFile.Close()    //nolint:errcheck
+			os.Remove(tmpPath) //nolint:errcheck
+		}
+	}()
+
+	if _, err := io.Copy(tmpFile, contextReader(ctx, content)); err != nil {
+		return fmt.Errorf("writing blob %q: %w", dest, err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		return fmt.Errorf("syncing blob %q: %w", dest, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("closing blob %q: %w", dest, err)
+	}
+	if err := os.Chmod(tmpPath, fileMode); err != nil {
+		return fmt.Errorf("setting permissions of blob %q: %w", dest, err)
+	}
+	if opts.IfMatch != "" {
+		if err := client.checkIfMatch(ctx, blobPath, opts.IfMatch); err != nil {
+			return fmt.Errorf("writing blob %q: %w", dest, err)
+		}
+	}
+	if opts.IfNoneMatch != "" {
+		if err := os.Link(tmpPath, blobPath); err != nil {
+			if errors.Is(err, fs.ErrExist) {
+				return common.NewError(common.ErrPreconditionFailed, fmt.Errorf("blob %q already exists", dest))
+			}
+			return fmt.Errorf("moving blob %q into place: %w", dest, err)
+		}
+		// The deferred cleanup removes the temporary name.
+		return nil
+	}
+	if err := os.Rename(tmpPath, blobPath); err != nil {
+		return fmt.Errorf("moving blob %q into place: %w", dest, err)
+	}
+	renamed = true
+
+	slog.Debug("Successfully wrote blob", "blob", dest)
+	return nil
+}
+
+func (client *LocalBlobstore) Get(ctx context.Context, source string, dest string) error {
+	return client.GetWithOptions(ctx, source, dest, common.GetOptions{})
+}
+
+// GetWithOptions is Get, failing unless the blob satisfies the preconditions
+// of opts. They are checked on the opened file, so the download is of the
+// matching content even if the blob is replaced meanwhile. Only the byte
+// range of opts is downloaded, if set.
+func (client *LocalBlobstore) GetWithOptions(ctx context.Context, source string, dest string, opts common.GetOptions) error {
+	slog.Info("Getting blob from local storage", "root", client.config.RootDirectory, "blob", source, "local_path", dest)
+
+	blobPath, err := client.blobPath(source)
+	if err != nil {
+		return err
+	}
+
+	blobFile, err := os.Open(blobPath)
+	if err != nil {
+		return classifyError(fmt.Errorf("opening blob %q: %w", source, err))
+	}
+	defer blobFile.Close() //nolint:errcheck
+
+	if err := client.checkFileIfMatch(ctx, blobFile, opts.IfMatch); err != nil {
+		return fmt.Errorf("getting blob %q: %w", source, err)
+	}
+	content, err := rangeReader(blobFile, opts.Range)
+	if err != nil {
+		return fmt.Errorf("getting blob %q: %w", source, err)
+	}
+
+	destFile, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to create destination file: %w", err)
+	}
+	defer destFile.Close() //nolint:errcheck
+
+	if _, err := io.Copy(destFile, contextReader(ctx, content)); err != nil {
+		return fmt.Errorf("failed to write to destination file: %w", err)
+	}
+
+	return nil
+}
+
+// GetStream opens a blob for reading. The caller must close the returned reader.
+func (client *LocalBlobstore) GetStream(ctx context.Context, source string) (io.ReadCloser, error) {
+	return client.GetStreamWithOptions(ctx, source, common.GetOptions{})
+}
+
+// GetStreamWithOptions is GetStream with the preconditions and the byte range
+// of opts, see GetWithOptions.
+func (client *LocalBlobstore) GetStreamWithOptions(ctx context.Context, source string, opts common.GetOptions) (io.ReadCloser, error) {
+	slog.Info("Streaming blob from local storage", "root", client.config.RootDirectory, "blob", source)
+
+	blobPath, err := client.blobPath(source)
+	if err != nil {
+		return nil, err
+	}
+
+	blobFile, err := os.Open(blobPath)
+	if err != nil {
+		return nil, classifyError(fmt.Errorf("opening blob %q: %w", source, err))
+	}
+	if err := client.checkFileIfMatch(ctx, blobFile, opts.IfMatch); err != nil {
+		blobFile.Close() //nolint:errcheck
+		return nil, fmt.Errorf("getting blob %q: %w", source, err)
+	}
+	content, err := rangeReader(blobFile, opts.Range)
+	if err != nil {
+		blobFile.Close() //nolint:errcheck
+		return nil, fmt.Errorf("getting blob %q: %w", source, err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{contextReader(ctx, content), blobFile}, nil
+}
+
+// rangeReader positions blobFile at the start of byteRange and returns a
+// reader ending with it, or blobFile itself without a range.
+func rangeReader(blobFile *os.File, byteRange *common.ByteRange) (io.Reader, error) {
+	if byteRange == nil {
+		return blobFile, nil
+	}
+	info, err := blobFile.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset, length, err := byteRange.Resolve(info.Size())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := blobFile.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.LimitReader(blobFile, length), nil
+}
+
+// Delete removes a blob. If the blob does not exist, Delete returns a nil error.
+func (client *LocalBlobstore) Delete(ctx context.Context, dest string) error {
+	return client.DeleteWithOptions(ctx, dest, common.DeleteOptions{})
+}
+
+// DeleteWithOptions is Delete, failing unless the blob satisfies the
+// preconditions of opts. The check can race with a concurrent write.
+func (client *LocalBlobstore) DeleteWithOptions(ctx context.Context, dest string, opts common.DeleteOptions) error {
+	slog.Info("Deleting blob from local storage", "root", client.config.RootDirectory, "blob", dest)
+
+	blobPath, err := client.blobPath(dest)
+	if err != nil {
+		return err
+	}
+
+	if opts.IfMatch != "" {
+		if err := client.checkIfMatch(ctx, blobPath, opts.IfMatch); err != nil {
+			return fmt.Errorf("deleting blob %q: %w", dest, err)
+		}
+	}
+
+	err = os.Remove(blobPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return classifyError(fmt.Errorf("deleting blob %q: %w", dest, err))
+	}
+	return nil
+}
+
+// DeleteMany deletes blobs concurrently. Missing blobs count as deleted.
+func (client *LocalBlobstore) DeleteMany(ctx context.Context, blobs []string) []common.DeleteFailure {
+	return common.DeleteConcurrently(ctx, blobs, common.DefaultDeleteParallelism, client.Delete)
+}
+
+func (client *LocalBlobstore) DeleteRecursive(ctx context.Context, prefix string) error {
+	if prefix != "" {
+		slog.Info("Deleting all blobs in local storage", "root", client.config.RootDirectory, "prefix", prefix)
+	} else {
+		slog.Info("Deleting all blobs in local storage", "root", client.config.RootDirectory)
+	}
+
+	blobs, err := client.List(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("listing blobs under %q: %w", prefix, err)
+	}
+
+	var failures []common.DeleteFailure
+	for _, blob := range blobs {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(err, common.JoinDeleteFailures(failures))
+		}
+		if err := client.Delete(ctx, blob); err != nil {
+			failures = append(failures, common.DeleteFailure{Key: blob, Err: err})
+			continue
+		}
+		client.pruneDirs(blob)
+	}
+	return common.JoinDeleteFailures(failures)
+}
+
+// pruneDirs removes the directories of the deleted blob that are left
+// empty, up to but excluding the root directory.
+func (client *LocalBlobstore) pruneDirs(blob string) {
+	for dir := path.Dir(blob); dir != "."; dir = path.Dir(dir) {
+		// Removing a directory that is not empty fails, which ends the pruning.
+		if err := os.Remove(filepath.Join(client.config.RootDirectory, filepath.FromSlash(dir))); err != nil {
+			return
+		}
+	}
+}
+
+func (client *LocalBlobstore) Exists(ctx context.Context, dest string) (bool, error) {
+	slog.Info("Checking if blob exists in local storage", "root", client.config.RootDirectory, "blob", dest)
+
+	blobPath, err := client.blobPath(dest)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := os.Stat(blobPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("checking if blob %q exists: %w", dest, err)
+	}
+	return info.Mode().IsRegular(), nil
+}
+
+// Stat returns the size, modification time and MD5 of a blob. exists is false
+// if the blob does not exist. The MD5 is only computed again if the size or
+// modification time of the file changed since it was last computed.
+func (client *LocalBlobstore) Stat(ctx context.Context, dest string) (common.ObjectInfo, bool, error) {
+	slog.Info("Getting metadata for blob in local storage", "root", client.config.RootDirectory, "blob", dest)
+
+	blobPath, err := client.blobPath(dest)
+	if err != nil {
+		return common.ObjectInfo{}, false, err
+	}
+
+	blobFile, err := os.Open(blobPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return common.ObjectInfo{}, false, nil
+	}
+	if err != nil {
+		return common.ObjectInfo{}, false, fmt.Errorf("opening blob %q: %w", dest, err)
+	}
+	defer blobFile.Close() //nolint:errcheck
+
+	info, err := blobFile.Stat()
+	if err != nil {
+		return common.ObjectInfo{}, false, fmt.Errorf("failed to get metadata for blob %s: %w", dest, err)
+	}
+	if !info.Mode().IsRegular() {
+		return common.ObjectInfo{}, false, nil
+	}
+
+	sum, err := client.fileMD5(ctx, blobFile, info)
+	if err != nil {
+		return common.ObjectInfo{}, false, err
+	}
+
+	return common.ObjectInfo{
+		Name:         dest,
+		Size:         info.Size(),
+		ETag:         sum,
+		ContentMD5:   sum,
+		LastModified: info.ModTime().UTC(),
+	}, true, nil
+}
+
+// checkIfMatch fails unless the file at blobPath exists and its ETag, the MD5
+// of its content, matches ifMatch.
+func (client *LocalBlobstore) checkIfMatch(ctx context.Context, blobPath string, ifMatch string) error {
+	blobFile, err := os.Open(blobPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return common.CheckPreconditions("", false, ifMatch, "")
+	}
+	if err != nil {
+		return classifyError(err)
+	}
+	defer blobFile.Close() //nolint:errcheck
+	return client.checkFileIfMatch(ctx, blobFile, ifMatch)
+}
+
+// checkFileIfMatch fails unless the ETag of the open blobFile matches ifMatch,
+// if set, and leaves it positioned at the start for reading.
+func (client *LocalBlobstore) checkFileIfMatch(ctx context.Context, blobFile *os.File, ifMatch string) error {
+	if ifMatch == "" {
+		return nil
+	}
+	info, err := blobFile.Stat()
+	if err != nil {
+		return err
+	}
+	sum, err := client.fileMD5(ctx, blobFile, info)
+	if err != nil {
+		return err
+	}
+	return common.CheckPreconditions(sum, true, ifMatch, "")
+}
+
+// fileMD5 returns the hex-encoded MD5 of the open blobFile, described by
+// info, and leaves it positioned at the start. Sums are cached by path and
+// reused while the size and modification time of the file stay the same, so
+// a blob is read once per process rather than on every Stat.
+func (client *LocalBlobstore) fileMD5(ctx context.Context, blobFile *os.File, info fs.FileInfo) (string, error) {
+	client.md5sMu.Lock()
+	cached, ok := client.md5s[blobFile.Name()]
+	client.md5sMu.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.sum, nil
+	}
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, contextReader(ctx, blobFile)); err != nil {
+		return "", fmt.Errorf("failed to calculate md5: %w", err)
+	}
+	if _, err := blobFile.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	client.md5sMu.Lock()
+	if client.md5s == nil {
+		client.md5s = map[string]cachedMD5{}
+	}
+	client.md5s[blobFile.Name()] = cachedMD5{size: info.Size(), modTime: info.ModTime(), sum: sum}
+	client.md5sMu.Unlock()
+	return sum, nil
+}
+
+// Sign returns a URL in the nginx secure_link_hmac format produced by the
+// dav/signer package, so the root directory can be served by the same nginx
+// configuration as a WebDAV blobstore.
+func (client *LocalBlobstore) Sign(ctx context.Context, dest string, action string, expiration time.Duration) (string, error) {
+	slog.Info("Signing url for local storage", "blob", dest, "action", action, "expiration", expiration)
+
+	if err := validateBlobID(dest); err != nil {
+		return "", err
+	}
+	if client.config.Secret == "" {
+		return "", common.NewError(common.ErrInvalidConfig, errors.New("secret must be set in the configuration to sign URLs"))
+	}
+	if client.config.Endpoint == "" {
+		return "", common.NewError(common.ErrInvalidConfig, errors.New("endpoint must be set in the configuration to sign URLs"))
+	}
+
+	action = strings.ToUpper(action)
+	switch action {
+	case "GET", "PUT":
+		signer := URLsigner.NewSigner(client.config.Secret)
+		signedURL, err := signer.GenerateSignedURL(client.config.Endpoint, client.config.DirectoryKey, dest, action, time.Now(), expiration)
+		if err != nil {
+			return "", fmt.Errorf("failed to sign URL: %w", err)
+		}
+		return signedURL, nil
+	default:
+		return "", fmt.Errorf("action not implemented: %s", action)
+	}
+}
+
+// List returns the IDs of all blobs starting with prefix, in lexical order.
+func (client *LocalBlobstore) List(ctx context.Context, prefix string) ([]string, error) {
+	objects, err := client.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return common.ObjectNames(objects), nil
+}
+
+// ListObjects returns the size and modification time of all blobs starting
+// with prefix, in lexical order of their IDs. Unlike Stat, it does not read
+// the blobs to compute their MD5 digest.
+func (client *LocalBlobstore) ListObjects(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	if prefix != "" {
+		slog.Info("Listing blobs in local storage", "root", client.config.RootDirectory, "prefix", prefix)
+	} else {
+		slog.Info("Listing blobs in local storage", "root", client.config.RootDirectory)
+	}
+
+	if err := validatePrefix(prefix); err != nil {
+		return nil, err
+	}
+
+	root := client.config.RootDirectory
+
+	// Start the walk at the deepest directory the prefix fully names, so the
+	// traversal is bounded by the prefix's subtree instead of the whole root.
+	start := root
+	if dir := prefixDir(prefix); dir != "" {
+		start = filepath.Join(root, filepath.FromSlash(dir))
+	}
+
+	var blobs []common.ObjectInfo
+	err := filepath.WalkDir(start, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		blobID := filepath.ToSlash(rel)
+
+		if entry.IsDir() {
+			if path != start && !dirMayContainPrefix(blobID, prefix) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), tempFilePrefix) {
+			return nil
+		}
+
+		if !strings.HasPrefix(blobID, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, common.ObjectInfo{
+			Name:         blobID,
+			Size:         info.Size(),
+			LastModified: info.ModTime().UTC(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, classifyError(fmt.Errorf("walking root directory: %w", err))
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Name < blobs[j].Name })
+	return blobs, nil
+}
+
+// ListWithOptions reads a single directory when the delimiter is "/". Other
+// delimiters do not match the directory structure, so the blobs below the
+// prefix are walked and grouped instead. Pages are cut from the entries in
+// lexical order, their tokens are the name of the last entry of the page.
+func (client *LocalBlobstore) ListWithOptions(ctx context.Context, opts common.ListOptions) (common.ListResult, error) {
+	if opts.Delimiter != "/" {
+		objects, err := client.ListObjects(ctx, opts.Prefix)
+		if err != nil {
+			return common.ListResult{}, err
+		}
+		return common.Paginate(common.GroupByDelimiter(objects, opts.Prefix, opts.Delimiter), opts), nil
+	}
+
+	slog.Info("Listing directory in local storage", "root", client.config.RootDirectory, "prefix", opts.Prefix)
+
+	if err := validatePrefix(opts.Prefix); err != nil {
+		return common.ListResult{}, err
+	}
+
+	dir := prefixDir(opts.Prefix)
+	entries, err := os.ReadDir(filepath.Join(client.config.RootDirectory, filepath.FromSlash(dir)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return common.ListResult{}, nil
+	}
+	if err != nil {
+		return common.ListResult{}, classifyError(fmt.Errorf("reading directory: %w", err))
+	}
+
+	var result common.ListResult
+	for _, entry := range entries {
+		blobID := path.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if strings.HasPrefix(blobID+"/", opts.Prefix) {
+				result.Prefixes = append(result.Prefixes, blobID+"/")
+			}
+			continue
+		}
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), tempFilePrefix) || !strings.HasPrefix(blobID, opts.Prefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return common.ListResult{}, err
+		}
+		result.Objects = append(result.Objects, common.ObjectInfo{
+			Name:         blobID,
+			Size:         info.Size(),
+			LastModified: info.ModTime().UTC(),
+		})
+	}
+	return common.Paginate(result, opts), nil
+}
+
+func (client *LocalBlobstore) Copy(ctx context.Context, srcBlob string, dstBlob string) error {
+	slog.Info("Copying blob in local storage", "root", client.config.RootDirectory, "source_blob", srcBlob, "dest_blob", dstBlob)
+
+	srcPath, err := client.blobPath(srcBlob)
+	if err != nil {
+		return fmt.Errorf("invalid source blob ID: %w", err)
+	}
+
+	source, err := os.Open(srcPath)
+	if err != nil {
+		return classifyError(fmt.Errorf("opening source blob %q: %w", srcBlob, err))
+	}
+	defer source.Close() //nolint:errcheck
+
+	return client.writeAtomically(ctx, dstBlob, source, common.PutOptions{})
+}
+
+// Move renames the file of srcBlob.
+func (client *LocalBlobstore) Move(ctx context.Context, srcBlob string, dstBlob string) error {
+	slog.Info("Moving blob in local storage", "root", client.config.RootDirectory, "source_blob", srcBlob, "dest_blob", dstBlob)
+
+	srcPath, err := client.blobPath(srcBlob)
+	if err != nil {
+		return fmt.Errorf("invalid source blob ID: %w", err)
+	}
+	dstPath, err := client.blobPath(dstBlob)
+	if err != nil {
+		return fmt.Errorf("invalid destination blob ID: %w", err)
+	}
+
+	if _, err := os.Stat(srcPath); errors.Is(err, fs.ErrNotExist) {
+		moved, err := client.Exists(ctx, dstBlob)
+		if err != nil {
+			return err
+		}
+		if moved {
+			return nil
+		}
+		return common.NewError(common.ErrNotFound, fmt.Errorf("source blob %q does not exist", srcBlob))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dstPath), dirMode); err != nil {
+		return classifyError(fmt.Errorf("creating directory for blob %q: %w", dstBlob, err))
+	}
+	if err := os.Rename(srcPath, dstPath); err != nil {
+		return classifyError(fmt.Errorf("moving blob %q to %q: %w", srcBlob, dstBlob, err))
+	}
+	return nil
+}
+
+// Properties returns the blob's metadata. The ETag is the hex-encoded MD5 of
+// the content.
+func (client *LocalBlobstore) Properties(ctx context.Context, dest string) (common.Properties, error) {
+	slog.Info("Getting properties for blob in local storage", "root", client.config.RootDirectory, "blob", dest)
+
+	info, exists, err := client.Stat(ctx, dest)
+	if err != nil {
+		return common.Properties{}, err
+	}
+	if !exists {
+		return common.Properties{}, common.NewError(common.ErrNotFound, fmt.Errorf("blob %q does not exist", dest))
+	}
+
+	return common.Properties{
+		ETag:          info.ETag,
+		LastModified:  info.LastModified,
+		ContentLength: info.Size,
+		ContentMD5:    info.ContentMD5,
+	}, nil
+}
+
+func (client *LocalBlobstore) EnsureStorageExists(ctx context.Context) error {
+	slog.Info("Ensuring root directory exists", "root", client.config.RootDirectory)
+
+	if err := os.MkdirAll(client.config.RootDirectory, dirMode); err != nil {
+		return fmt.Errorf("creating root directory: %w", err)
+	}
+	return nil
+}
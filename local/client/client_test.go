@@ -0,0 +1,546 @@
+package client_test
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/storage-cli/common"
+	"github.com/cloudfoundry/storage-cli/local/client"
+	"github.com/cloudfoundry/storage-cli/local/config"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		rootDir       string
+		localStorage  *client.LocalBlobstore
+		localFilePath string
+	)
+
+	BeforeEach(func() {
+		rootDir = GinkgoT().TempDir()
+
+		var err error
+		localStorage, err = client.New(config.LocalConfig{
+			RootDirectory: rootDir,
+			Endpoint:      "https://blobstore.internal",
+			Secret:        "the-secret",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		localFilePath = filepath.Join(GinkgoT().TempDir(), "local-file")
+		Expect(os.WriteFile(localFilePath, []byte("some content"), 0644)).To(Succeed())
+	})
+
+	Context("Put", func() {
+		It("writes the file below the root directory", func() {
+			err := localStorage.Put(context.Background(), localFilePath, "some/nested/blob")
+			Expect(err).ToNot(HaveOccurred())
+
+			content, err := os.ReadFile(filepath.Join(rootDir, "some", "nested", "blob"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some content"))
+		})
+
+		It("does not leave temporary files behind", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+
+			entries, err := os.ReadDir(rootDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Name()).To(Equal("blob"))
+		})
+
+		It("overwrites an existing blob", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+			Expect(os.WriteFile(localFilePath, []byte("new content"), 0644)).To(Succeed())
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+
+			content, err := os.ReadFile(filepath.Join(rootDir, "blob"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("new content"))
+		})
+
+		It("rejects blob IDs escaping the root directory", func() {
+			err := localStorage.Put(context.Background(), localFilePath, "../outside")
+			Expect(err).To(MatchError(ContainSubstring("path traversal")))
+		})
+
+		It("rejects content headers and metadata", func() {
+			err := localStorage.PutWithOptions(context.Background(), localFilePath, "blob", common.PutOptions{ContentType: "text/plain"})
+			Expect(err).To(MatchError(ContainSubstring("does not support content headers")))
+
+			Expect(localStorage.PutWithOptions(context.Background(), localFilePath, "blob", common.PutOptions{})).To(Succeed())
+		})
+
+		It("fails if the source file does not exist", func() {
+			err := localStorage.Put(context.Background(), "nonexistent/path", "blob")
+			Expect(err).To(MatchError(ContainSubstring("failed to open source file")))
+		})
+	})
+
+	Context("Get", func() {
+		It("copies the blob into the destination file", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "some/blob")).To(Succeed())
+
+			destPath := filepath.Join(GinkgoT().TempDir(), "downloaded")
+			Expect(localStorage.Get(context.Background(), "some/blob", destPath)).To(Succeed())
+
+			content, err := os.ReadFile(destPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some content"))
+		})
+
+		It("fails if the blob does not exist", func() {
+			destPath := filepath.Join(GinkgoT().TempDir(), "downloaded")
+			err := localStorage.Get(context.Background(), "missing", destPath)
+			Expect(err).To(MatchError(os.ErrNotExist))
+			Expect(err).To(MatchError(common.ErrNotFound))
+		})
+	})
+
+	Context("GetStream", func() {
+		It("returns the blob content", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "some/blob")).To(Succeed())
+
+			reader, err := localStorage.GetStream(context.Background(), "some/blob")
+			Expect(err).ToNot(HaveOccurred())
+			defer reader.Close() //nolint:errcheck
+
+			content, err := io.ReadAll(reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some content"))
+		})
+
+		It("fails if the blob does not exist", func() {
+			_, err := localStorage.GetStream(context.Background(), "missing")
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+	})
+
+	Context("PutStream", func() {
+		It("writes the stream below the root directory", func() {
+			Expect(localStorage.PutStream(context.Background(), strings.NewReader("streamed"), "a/blob")).To(Succeed())
+
+			content, err := os.ReadFile(filepath.Join(rootDir, "a", "blob"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("streamed"))
+		})
+
+		It("stops and leaves no blob behind when the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := localStorage.PutStream(ctx, strings.NewReader("streamed"), "a/blob")
+			Expect(err).To(MatchError(context.Canceled))
+
+			entries, err := os.ReadDir(filepath.Join(rootDir, "a"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+
+	Context("Stat", func() {
+		It("returns size and MD5 of the blob", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+
+			info, exists, err := localStorage.Stat(context.Background(), "blob")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeTrue())
+			Expect(info.Name).To(Equal("blob"))
+			Expect(info.Size).To(Equal(int64(len("some content"))))
+			Expect(info.ContentMD5).To(Equal("9893532233caff98cd083a116b013c0b"))
+			Expect(info.ETag).To(Equal(info.ContentMD5))
+		})
+
+		It("computes the MD5 again only when the size or modification time changed", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+			info, _, err := localStorage.Stat(context.Background(), "blob")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.ContentMD5).To(Equal("9893532233caff98cd083a116b013c0b"))
+
+			blobPath := filepath.Join(rootDir, "blob")
+			modTime := time.Now().Add(-time.Hour)
+			Expect(os.WriteFile(blobPath, []byte("same length!"), 0644)).To(Succeed())
+			Expect(os.Chtimes(blobPath, modTime, modTime)).To(Succeed())
+			info, _, err = localStorage.Stat(context.Background(), "blob")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.ContentMD5).To(Equal("c31464c068059cb2615fdbbe90cdde92"))
+
+			// Not read again, as neither the size nor the modification time changed.
+			Expect(os.WriteFile(blobPath, []byte("other length"), 0644)).To(Succeed())
+			Expect(os.Chtimes(blobPath, modTime, modTime)).To(Succeed())
+			info, _, err = localStorage.Stat(context.Background(), "blob")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.ContentMD5).To(Equal("c31464c068059cb2615fdbbe90cdde92"))
+		})
+
+		It("reports missing blobs", func() {
+			_, exists, err := localStorage.Stat(context.Background(), "missing")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+	})
+
+	Context("Properties", func() {
+		It("returns the metadata of the blob", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+
+			props, err := localStorage.Properties(context.Background(), "blob")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(props.ContentLength).To(Equal(int64(len("some content"))))
+			Expect(props.ETag).To(Equal("9893532233caff98cd083a116b013c0b"))
+			Expect(props.LastModified).ToNot(BeZero())
+		})
+
+		It("fails with a not found error for missing blobs", func() {
+			_, err := localStorage.Properties(context.Background(), "missing")
+			Expect(err).To(MatchError(common.ErrNotFound))
+		})
+	})
+
+	Context("Delete", func() {
+		It("removes the blob", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+			Expect(localStorage.Delete(context.Background(), "blob")).To(Succeed())
+
+			exists, err := localStorage.Exists(context.Background(), "blob")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		It("does not fail if the blob does not exist", func() {
+			Expect(localStorage.Delete(context.Background(), "missing")).To(Succeed())
+		})
+	})
+
+	Context("Preconditions", func() {
+		const etag = "9893532233caff98cd083a116b013c0b"
+
+		It("creates a blob with if-none-match only if it does not exist", func() {
+			opts := common.PutOptions{IfNoneMatch: "*"}
+			Expect(localStorage.PutWithOptions(context.Background(), localFilePath, "blob", opts)).To(Succeed())
+
+			err := localStorage.PutStreamWithOptions(context.Background(), strings.NewReader("other"), "blob", opts)
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+
+			content, err := os.ReadFile(filepath.Join(rootDir, "blob"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some content"))
+			entries, err := os.ReadDir(rootDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		})
+
+		It("replaces a blob with if-match only if the ETag matches", func() {
+			err := localStorage.PutStreamWithOptions(context.Background(), strings.NewReader("new"), "blob", common.PutOptions{IfMatch: etag})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+			err = localStorage.PutStreamWithOptions(context.Background(), strings.NewReader("new"), "blob", common.PutOptions{IfMatch: "other"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+			Expect(localStorage.PutStreamWithOptions(context.Background(), strings.NewReader("new"), "blob", common.PutOptions{IfMatch: etag})).To(Succeed())
+		})
+
+		It("gets a blob with if-match only if the ETag matches", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+
+			_, err := localStorage.GetStreamWithOptions(context.Background(), "blob", common.GetOptions{IfMatch: "other"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+
+			reader, err := localStorage.GetStreamWithOptions(context.Background(), "blob", common.GetOptions{IfMatch: `"` + etag + `"`})
+			Expect(err).ToNot(HaveOccurred())
+			defer reader.Close() //nolint:errcheck
+			content, err := io.ReadAll(reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some content"))
+		})
+
+		It("deletes a blob with if-match only if the ETag matches", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+
+			err := localStorage.DeleteWithOptions(context.Background(), "blob", common.DeleteOptions{IfMatch: "other"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+			Expect(localStorage.DeleteWithOptions(context.Background(), "blob", common.DeleteOptions{IfMatch: etag})).To(Succeed())
+
+			err = localStorage.DeleteWithOptions(context.Background(), "blob", common.DeleteOptions{IfMatch: etag})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+		})
+	})
+
+	Context("Byte ranges", func() {
+		BeforeEach(func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "blob")).To(Succeed())
+		})
+
+		It("downloads a range to a file", func() {
+			dest := filepath.Join(GinkgoT().TempDir(), "range")
+			opts := common.GetOptions{Range: &common.ByteRange{Start: 5, End: 8}}
+			Expect(localStorage.GetWithOptions(context.Background(), "blob", dest, opts)).To(Succeed())
+
+			content, err := os.ReadFile(dest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("cont"))
+		})
+
+		It("streams the end of a blob after checking its ETag", func() {
+			opts := common.GetOptions{IfMatch: "9893532233caff98cd083a116b013c0b", Range: &common.ByteRange{Start: -4, End: -1}}
+			reader, err := localStorage.GetStreamWithOptions(context.Background(), "blob", opts)
+			Expect(err).ToNot(HaveOccurred())
+			defer reader.Close() //nolint:errcheck
+			content, err := io.ReadAll(reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("tent"))
+		})
+
+		It("fails for a range starting past the blob", func() {
+			_, err := localStorage.GetStreamWithOptions(context.Background(), "blob", common.GetOptions{Range: &common.ByteRange{Start: 12, End: -1}})
+			Expect(err).To(MatchError(ContainSubstring("starts beyond the end")))
+		})
+	})
+
+	Context("Exists", func() {
+		It("returns true for existing blobs", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "a/blob")).To(Succeed())
+
+			exists, err := localStorage.Exists(context.Background(), "a/blob")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeTrue())
+		})
+
+		It("returns false for directories", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "a/blob")).To(Succeed())
+
+			exists, err := localStorage.Exists(context.Background(), "a")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+	})
+
+	Context("List", func() {
+		BeforeEach(func() {
+			for _, blob := range []string{"a.txt", "a/b/c", "a/b/d", "ab/e", "z"} {
+				Expect(localStorage.Put(context.Background(), localFilePath, blob)).To(Succeed())
+			}
+		})
+
+		It("lists all blobs in lexical order", func() {
+			blobs, err := localStorage.List(context.Background(), "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blobs).To(Equal([]string{"a.txt", "a/b/c", "a/b/d", "ab/e", "z"}))
+		})
+
+		It("lists blobs matching a prefix", func() {
+			blobs, err := localStorage.List(context.Background(), "a/")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blobs).To(Equal([]string{"a/b/c", "a/b/d"}))
+
+			blobs, err = localStorage.List(context.Background(), "a")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blobs).To(Equal([]string{"a.txt", "a/b/c", "a/b/d", "ab/e"}))
+
+			blobs, err = localStorage.List(context.Background(), "a/b/c")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blobs).To(Equal([]string{"a/b/c"}))
+		})
+
+		It("returns nothing for prefixes below missing directories", func() {
+			blobs, err := localStorage.List(context.Background(), "missing/dir/")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blobs).To(BeEmpty())
+		})
+
+		It("lists blobs with their size and modification time", func() {
+			objects, err := localStorage.ListObjects(context.Background(), "a/")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objects).To(HaveLen(2))
+			Expect(objects[0].Name).To(Equal("a/b/c"))
+			Expect(objects[0].Size).To(BeEquivalentTo(len("some content")))
+			Expect(objects[0].LastModified).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+
+		It("lists a single directory with the / delimiter", func() {
+			result, err := localStorage.ListWithOptions(context.Background(), common.ListOptions{Prefix: "a", Delimiter: "/"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(common.ObjectNames(result.Objects)).To(Equal([]string{"a.txt"}))
+			Expect(result.Prefixes).To(Equal([]string{"a/", "ab/"}))
+
+			result, err = localStorage.ListWithOptions(context.Background(), common.ListOptions{Prefix: "a/", Delimiter: "/"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Objects).To(BeEmpty())
+			Expect(result.Prefixes).To(Equal([]string{"a/b/"}))
+		})
+
+		It("groups blobs by other delimiters", func() {
+			result, err := localStorage.ListWithOptions(context.Background(), common.ListOptions{Delimiter: "."})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(common.ObjectNames(result.Objects)).To(Equal([]string{"a/b/c", "a/b/d", "ab/e", "z"}))
+			Expect(result.Prefixes).To(Equal([]string{"a."}))
+		})
+
+		It("pages through the listing", func() {
+			result, err := localStorage.ListWithOptions(context.Background(), common.ListOptions{MaxKeys: 2, StartAfter: "a.txt"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(common.ObjectNames(result.Objects)).To(Equal([]string{"a/b/c", "a/b/d"}))
+			Expect(result.NextPageToken).To(Equal("a/b/d"))
+
+			result, err = localStorage.ListWithOptions(context.Background(), common.ListOptions{MaxKeys: 2, PageToken: result.NextPageToken})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(common.ObjectNames(result.Objects)).To(Equal([]string{"ab/e", "z"}))
+			Expect(result.NextPageToken).To(BeEmpty())
+		})
+
+		It("skips in-flight uploads", func() {
+			Expect(os.WriteFile(filepath.Join(rootDir, ".storage-cli-upload-123"), nil, 0644)).To(Succeed())
+
+			blobs, err := localStorage.List(context.Background(), "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blobs).ToNot(ContainElement(ContainSubstring("storage-cli-upload")))
+		})
+	})
+
+	Context("DeleteRecursive", func() {
+		It("deletes all blobs matching the prefix", func() {
+			for _, blob := range []string{"a/b", "a/c", "b/d"} {
+				Expect(localStorage.Put(context.Background(), localFilePath, blob)).To(Succeed())
+			}
+
+			Expect(localStorage.DeleteRecursive(context.Background(), "a/")).To(Succeed())
+
+			blobs, err := localStorage.List(context.Background(), "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blobs).To(Equal([]string{"b/d"}))
+		})
+
+		It("removes the directories left empty", func() {
+			for _, blob := range []string{"a/b/c", "a/b/d/e", "a/f"} {
+				Expect(localStorage.Put(context.Background(), localFilePath, blob)).To(Succeed())
+			}
+
+			Expect(localStorage.DeleteRecursive(context.Background(), "a/b")).To(Succeed())
+
+			Expect(filepath.Join(rootDir, "a", "b")).ToNot(BeADirectory())
+			Expect(filepath.Join(rootDir, "a", "f")).To(BeARegularFile())
+
+			Expect(localStorage.DeleteRecursive(context.Background(), "")).To(Succeed())
+			entries, err := os.ReadDir(rootDir)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+
+	Context("DeleteMany", func() {
+		It("deletes the blobs, counting missing blobs as deleted", func() {
+			for _, blob := range []string{"a/b", "a/c", "b/d"} {
+				Expect(localStorage.Put(context.Background(), localFilePath, blob)).To(Succeed())
+			}
+
+			failures := localStorage.DeleteMany(context.Background(), []string{"a/b", "b/d", "missing", "../escape"})
+			Expect(failures).To(HaveLen(1))
+			Expect(failures[0].Key).To(Equal("../escape"))
+
+			blobs, err := localStorage.List(context.Background(), "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blobs).To(Equal([]string{"a/c"}))
+		})
+	})
+
+	Context("Copy", func() {
+		It("copies the blob to the destination", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "source")).To(Succeed())
+			Expect(localStorage.Copy(context.Background(), "source", "dest/blob")).To(Succeed())
+
+			content, err := os.ReadFile(filepath.Join(rootDir, "dest", "blob"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some content"))
+		})
+
+		It("fails if the source does not exist", func() {
+			err := localStorage.Copy(context.Background(), "missing", "dest")
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+	})
+
+	Context("Move", func() {
+		It("moves the blob to the destination", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "source")).To(Succeed())
+			Expect(localStorage.Move(context.Background(), "source", "dest/blob")).To(Succeed())
+
+			content, err := os.ReadFile(filepath.Join(rootDir, "dest", "blob"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("some content"))
+			Expect(filepath.Join(rootDir, "source")).ToNot(BeAnExistingFile())
+		})
+
+		It("succeeds when retried after the blob was moved", func() {
+			Expect(localStorage.Put(context.Background(), localFilePath, "source")).To(Succeed())
+			Expect(localStorage.Move(context.Background(), "source", "dest")).To(Succeed())
+			Expect(localStorage.Move(context.Background(), "source", "dest")).To(Succeed())
+		})
+
+		It("fails if neither blob exists", func() {
+			err := localStorage.Move(context.Background(), "missing", "dest")
+			Expect(err).To(MatchError(common.ErrNotFound))
+		})
+	})
+
+	Context("Sign", func() {
+		It("returns a URL in the dav signer format", func() {
+			signedURL, err := localStorage.Sign(context.Background(), "some/blob", "get", time.Hour)
+			Expect(err).ToNot(HaveOccurred())
+
+			parsed, err := url.Parse(signedURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Host).To(Equal("blobstore.internal"))
+			Expect(parsed.Path).To(Equal("/signed/" + filepath.Base(rootDir) + "/some/blob"))
+			Expect(parsed.Query().Get("st")).ToNot(BeEmpty())
+			Expect(parsed.Query().Get("ts")).ToNot(BeEmpty())
+			Expect(parsed.Query().Get("e")).To(Equal("3600"))
+		})
+
+		It("uses the configured directory key", func() {
+			keyed, err := client.New(config.LocalConfig{
+				RootDirectory: rootDir,
+				Endpoint:      "https://blobstore.internal",
+				DirectoryKey:  "packages",
+				Secret:        "the-secret",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			signedURL, err := keyed.Sign(context.Background(), "blob", "put", time.Minute)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(signedURL).To(HavePrefix("https://blobstore.internal/signed/packages/blob?"))
+		})
+
+		It("requires a secret", func() {
+			unsigned, err := client.New(config.LocalConfig{RootDirectory: rootDir, Endpoint: "https://blobstore.internal"})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = unsigned.Sign(context.Background(), "blob", "get", time.Minute)
+			Expect(err).To(MatchError(ContainSubstring("secret must be set")))
+		})
+
+		It("rejects unknown actions", func() {
+			_, err := localStorage.Sign(context.Background(), "blob", "delete", time.Minute)
+			Expect(err).To(MatchError("action not implemented: DELETE"))
+		})
+	})
+
+	Context("EnsureStorageExists", func() {
+		It("creates the root directory", func() {
+			missingRoot := filepath.Join(rootDir, "not", "yet", "there")
+			fresh, err := client.New(config.LocalConfig{RootDirectory: missingRoot})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fresh.EnsureStorageExists(context.Background())).To(Succeed())
+			Expect(missingRoot).To(BeADirectory())
+		})
+	})
+})
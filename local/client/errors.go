@@ -0,0 +1,21 @@
+package client
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// classifyError marks file system errors on blobs with the matching kind of
+// failure.
+func classifyError(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return common.NewError(common.ErrNotFound, err)
+	case errors.Is(err, fs.ErrPermission):
+		return common.NewError(common.ErrPermissionDenied, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// validateBlobID rejects blob IDs that could escape the root directory or
+// confuse path joining: empty, leading/trailing slashes, double slashes,
+// . or .. segments, and control characters.
+func validateBlobID(blobID string) error {
+	if blobID == "" {
+		return fmt.Errorf("blob ID cannot be empty")
+	}
+
+	if strings.HasPrefix(blobID, "/") || strings.HasSuffix(blobID, "/") {
+		return fmt.Errorf("blob ID cannot start or end with slash: %q", blobID)
+	}
+
+	if strings.Contains(blobID, "//") {
+		return fmt.Errorf("blob ID cannot contain empty path segments (//): %q", blobID)
+	}
+
+	for _, segment := range strings.Split(blobID, "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("blob ID cannot contain path traversal segments (. or ..): %q", blobID)
+		}
+	}
+
+	for _, r := range blobID {
+		if r < 32 || r == 127 {
+			return fmt.Errorf("blob ID cannot contain control characters: %q", blobID)
+		}
+	}
+
+	return nil
+}
+
+// validatePrefix is like validateBlobID but allows an empty prefix and a
+// trailing slash.
+func validatePrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(prefix, "/") {
+		return fmt.Errorf("prefix cannot start with slash: %q", prefix)
+	}
+
+	if strings.Contains(prefix, "//") {
+		return fmt.Errorf("prefix cannot contain empty path segments (//): %q", prefix)
+	}
+
+	for _, segment := range strings.Split(strings.TrimSuffix(prefix, "/"), "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("prefix cannot contain path traversal segments (. or ..): %q", prefix)
+		}
+	}
+
+	for _, r := range prefix {
+		if r < 32 || r == 127 {
+			return fmt.Errorf("prefix cannot contain control characters: %q", prefix)
+		}
+	}
+
+	return nil
+}
+
+// prefixDir returns the deepest directory a blob-ID prefix fully names, or ""
+// when the prefix does not name one. Only the portion of the prefix up to its
+// last "/" is guaranteed to be a directory.
+func prefixDir(prefix string) string {
+	dir := path.Dir(prefix)
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir
+}
+
+// dirMayContainPrefix reports whether a directory with the given relative
+// path can hold blob IDs matching the prefix.
+func dirMayContainPrefix(rel, prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+	rel = strings.TrimSuffix(rel, "/") + "/"
+	return strings.HasPrefix(prefix, rel) || strings.HasPrefix(rel, prefix)
+}
+
+// contextReader returns a reader that fails with ctx's error once ctx is done,
+// so copies of large blobs stop when the operation is cancelled.
+func contextReader(ctx context.Context, r io.Reader) io.Reader {
+	return readerFunc(func(p []byte) (int, error) {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		return r.Read(p)
+	})
+}
+
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
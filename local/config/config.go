@@ -0,0 +1,45 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// LocalConfig represents the configuration for the local filesystem storage
+type LocalConfig struct {
+	// RootDirectory is the directory on disk that holds all blobs.
+	RootDirectory string `json:"root_directory"`
+	// Endpoint is the base URL (scheme and host) used when signing URLs,
+	// e.g. the nginx instance serving RootDirectory under /signed/.
+	Endpoint string `json:"endpoint"`
+	// DirectoryKey is the path segment following /signed/ in signed URLs.
+	// Defaults to the base name of RootDirectory.
+	DirectoryKey string `json:"directory_key"`
+	// Secret is the HMAC key used to sign URLs in the dav/signer format.
+	Secret string `json:"secret"`
+}
+
+// ErrEmptyRootDirectory is returned when root_directory in the config is empty
+var ErrEmptyRootDirectory = errors.New("root_directory must be set")
+
+// NewFromReader returns a new local storage configuration struct from the contents of reader.
+// reader.Read() is expected to return valid JSON
+func NewFromReader(reader io.Reader) (LocalConfig, error) {
+	bytes, err := io.ReadAll(reader)
+	if err != nil {
+		return LocalConfig{}, err
+	}
+	config := LocalConfig{}
+
+	err = json.Unmarshal(bytes, &config)
+	if err != nil {
+		return LocalConfig{}, err
+	}
+
+	if config.RootDirectory == "" {
+		return LocalConfig{}, ErrEmptyRootDirectory
+	}
+
+	return config, nil
+}
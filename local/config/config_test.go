@@ -0,0 +1,66 @@
+package config_test
+
+import (
+	"bytes"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/storage-cli/local/config"
+)
+
+var _ = Describe("Config", func() {
+
+	It("contains all properties", func() {
+		configJson := []byte(`{"root_directory": "/var/vcap/store/blobs",
+								"endpoint": "http://blobstore.internal:8080",
+								"directory_key": "cc-packages",
+								"secret": "the-secret"}`)
+		configReader := bytes.NewReader(configJson)
+
+		config, err := config.NewFromReader(configReader)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.RootDirectory).To(Equal("/var/vcap/store/blobs"))
+		Expect(config.Endpoint).To(Equal("http://blobstore.internal:8080"))
+		Expect(config.DirectoryKey).To(Equal("cc-packages"))
+		Expect(config.Secret).To(Equal("the-secret"))
+	})
+
+	It("requires a root directory", func() {
+		configJson := []byte(`{"endpoint": "http://blobstore.internal:8080"}`)
+		configReader := bytes.NewReader(configJson)
+
+		_, err := config.NewFromReader(configReader)
+
+		Expect(err).To(MatchError(config.ErrEmptyRootDirectory))
+	})
+
+	It("is empty if config cannot be parsed", func() {
+		configJson := []byte(`~`)
+		configReader := bytes.NewReader(configJson)
+
+		config, err := config.NewFromReader(configReader)
+
+		Expect(err.Error()).To(Equal("invalid character '~' looking for beginning of value"))
+		Expect(config.RootDirectory).Should(BeEmpty())
+		Expect(config.Secret).Should(BeEmpty())
+	})
+
+	Context("when the configuration file cannot be read", func() {
+		It("returns an error", func() {
+			f := explodingReader{}
+
+			_, err := config.NewFromReader(f)
+			Expect(err).To(MatchError("explosion"))
+		})
+	})
+
+})
+
+type explodingReader struct{}
+
+func (e explodingReader) Read([]byte) (int, error) {
+	return 0, errors.New("explosion")
+}
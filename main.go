@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"log/slog"
+	"net"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	"github.com/cloudfoundry/storage-cli/common"
 	storage "github.com/cloudfoundry/storage-cli/storage"
@@ -16,6 +21,19 @@ import (
 
 var version string
 
+// Exit codes by kind of failure. `1` is used for any other failure and `2`
+// for invalid flags.
+var exitCodes = map[error]int{
+	common.ErrNotFound:           3,
+	common.ErrPermissionDenied:   4,
+	common.ErrAlreadyExists:      5,
+	common.ErrPreconditionFailed: 6,
+	common.ErrThrottled:          7,
+	common.ErrTimeout:            8,
+	common.ErrInvalidConfig:      9,
+	common.ErrChecksumMismatch:   10,
+}
+
 func fatalLog(cmd string, err error) {
 	if err == nil {
 		return
@@ -26,6 +44,9 @@ func fatalLog(cmd string, err error) {
 		os.Exit(3)
 	}
 	slog.Error("performing operation", "command", cmd, "error", err)
+	if code, ok := exitCodes[common.ErrorKind(err)]; ok {
+		os.Exit(code)
+	}
 	os.Exit(1)
 
 }
@@ -73,13 +94,97 @@ func configureSlog(m io.Writer, logLevel string) {
 	slog.SetDefault(logger)
 }
 
+// serve exposes client over HTTP on a TCP address or a Unix domain socket
+// until ctx is done.
+func serve(ctx context.Context, client storage.Storager, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := flags.String("listen", "", "TCP address to listen on, such as 127.0.0.1:8080")
+	socket := flags.String("socket", "", "Unix domain socket to listen on instead of a TCP address")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("serve method expected 0 arguments got %d", flags.NArg())
+	}
+	// The server does not authenticate its callers, so where it can be
+	// reached from must be chosen explicitly.
+	if (*listen == "") == (*socket == "") {
+		return errors.New("serve needs exactly one of --listen and --socket")
+	}
+
+	var listener net.Listener
+	var err error
+	if *socket != "" {
+		listener, err = listenUnix(*socket)
+	} else {
+		listener, err = net.Listen("tcp", *listen)
+	}
+	if err != nil {
+		return err
+	}
+
+	return storage.NewServer(client).Serve(ctx, listener)
+}
+
+// listenUnix listens on a Unix domain socket accessible by the current user
+// only, replacing a stale socket left behind by a previous server. The
+// socket is created in a private directory and moved into place once its
+// permissions are restricted, so others can never connect to it.
+func listenUnix(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".storage-cli-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	privatePath := filepath.Join(dir, "socket")
+	listener, err := net.Listen("unix", privatePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(privatePath, 0600); err != nil {
+		listener.Close() //nolint:errcheck
+		return nil, err
+	}
+	if err := os.Rename(privatePath, path); err != nil {
+		listener.Close() //nolint:errcheck
+		return nil, err
+	}
+	return &unixListener{Listener: listener, path: path}, nil
+}
+
+// unixListener removes the socket at path once closed.
+type unixListener struct {
+	net.Listener
+	path string
+}
+
+func (l *unixListener) Close() error {
+	err := l.Listener.Close()
+	if removeErr := os.Remove(l.path); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) && err == nil {
+		err = removeErr
+	}
+	return err
+}
+
 func main() {
 
 	configPath := flag.String("c", "", "configuration path")
 	showVer := flag.Bool("v", false, "version")
-	storageType := flag.String("s", "", "storage type: azurebs|alioss|s3|gcs|dav")
+	storageType := flag.String("s", "", "storage type: azurebs|alioss|s3|gcs|dav|local")
 	logFile := flag.String("log-file", "", "optional file with full path to write logs(if not specified log to os.Stderr, default behavior)")
 	logLevel := flag.String("log-level", "warn", "log level: debug|info|warn|error")
+	output := flag.String("output", storage.OutputText, "output format: text|json")
+	timeout := flag.Duration("timeout", 0, "optional deadline for the command, e.g. 30s or 5m (0 means no deadline)")
 	flag.Parse()
 
 	if *showVer {
@@ -99,9 +204,17 @@ func main() {
 	// configure storage-cli config
 	common.InitConfig(parseLogLevel(*logLevel))
 
+	// the executor reports failures in the chosen output format
+	cex := storage.NewCommandExecuter(nil)
+	if err := cex.SetOutputFormat(*output); err != nil {
+		fatalLog("", err)
+	}
+
 	// check client config file exists
 	configFile, err := os.Open(*configPath)
 	if err != nil {
+		err = common.NewError(common.ErrInvalidConfig, err)
+		cex.WriteError(err)
 		fatalLog("", err)
 	}
 	defer configFile.Close() //nolint:errcheck
@@ -109,21 +222,40 @@ func main() {
 	// create client
 	client, err := storage.NewStorageClient(*storageType, configFile)
 	if err != nil {
+		cex.WriteError(err)
 		fatalLog("", err)
 	}
 
 	// inject client into executor
-	cex := storage.NewCommandExecuter(client)
+	cex.SetStorager(client)
 
 	// simple check for any command
 	nonFlagArgs := flag.Args()
 	if len(nonFlagArgs) < 1 {
-		fatalLog("", errors.New("expected at least 1 argument (command) got 0"))
+		err := errors.New("expected at least 1 argument (command) got 0")
+		cex.WriteError(err)
+		fatalLog("", err)
 	}
 
+	// SIGINT and SIGTERM cancel in-flight requests
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// execute command
 	cmd := nonFlagArgs[0]
-	err = cex.Execute(cmd, nonFlagArgs[1:])
+	if cmd == "serve" {
+		err := serve(ctx, client, nonFlagArgs[1:])
+		cex.WriteError(err)
+		fatalLog(cmd, err)
+		return
+	}
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	err = cex.Execute(ctx, cmd, nonFlagArgs[1:])
 	fatalLog(cmd, err)
 
 }
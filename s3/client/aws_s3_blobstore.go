@@ -1,7 +1,6 @@
 package client
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -17,10 +16,11 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/s3/config"
 )
 
-var errorInvalidCredentialsSourceValue = errors.New("the client operates in read only mode. Change 'credentials_source' parameter value ")
+var errorInvalidCredentialsSourceValue = common.NewError(common.ErrPermissionDenied, errors.New("the client operates in read only mode. Change 'credentials_source' parameter value "))
 
 // Default settings for transfer concurrency and part size.
 // These values are chosen to align with typical AWS CLI and SDK defaults for efficient S3 uploads and downloads.
@@ -33,6 +33,10 @@ const (
 	defaultMultipartCopyThreshold = int64(5 * 1024 * 1024 * 1024) // 5 GB
 	defaultMultipartCopyPartSize  = int64(100 * 1024 * 1024)      // 100 MB
 	maxRetries                    = 3
+	// ListObjectsV2 returns at most 1000 keys per page.
+	maxListKeys = 1000
+	// DeleteObjects accepts at most 1000 keys per request.
+	deleteObjectsBatchSize = 1000
 )
 
 // awsS3Client encapsulates AWS S3 blobstore interactions
@@ -42,7 +46,7 @@ type awsS3Client struct {
 }
 
 // Get fetches a blob, destination will be overwritten if exists
-func (b *awsS3Client) Get(src string, dest io.WriterAt) error {
+func (b *awsS3Client) Get(ctx context.Context, src string, dest io.WriterAt, opts common.GetOptions) error {
 	cfg := b.s3cliConfig
 
 	downloader := manager.NewDownloader(b.s3Client, func(d *manager.Downloader) { //nolint:staticcheck
@@ -57,10 +61,7 @@ func (b *awsS3Client) Get(src string, dest io.WriterAt) error {
 		}
 	})
 
-	_, err := downloader.Download(context.TODO(), dest, &s3.GetObjectInput{ //nolint:staticcheck
-		Bucket: aws.String(b.s3cliConfig.BucketName),
-		Key:    b.key(src),
-	})
+	_, err := downloader.Download(ctx, dest, b.getObjectInput(src, opts)) //nolint:staticcheck
 
 	if err != nil {
 		return err
@@ -69,14 +70,254 @@ func (b *awsS3Client) Get(src string, dest io.WriterAt) error {
 	return nil
 }
 
+// GetStream opens a blob for reading. The caller must close the returned reader.
+func (b *awsS3Client) GetStream(ctx context.Context, src string, opts common.GetOptions) (io.ReadCloser, error) {
+	output, err := b.s3Client.GetObject(ctx, b.getObjectInput(src, opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Body, nil
+}
+
+func (b *awsS3Client) getObjectInput(src string, opts common.GetOptions) *s3.GetObjectInput {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(b.s3cliConfig.BucketName),
+		Key:    b.key(src),
+	}
+	if opts.IfMatch != "" {
+		input.IfMatch = aws.String(common.QuoteETag(opts.IfMatch))
+	}
+	if opts.Range != nil {
+		input.Range = aws.String(opts.Range.HTTPHeader())
+	}
+	return input
+}
+
 // Put uploads a blob
-func (b *awsS3Client) Put(src io.ReadSeeker, dest string) error {
+func (b *awsS3Client) Put(ctx context.Context, src io.ReadSeeker, dest string, opts common.PutOptions) error {
+	cfg := b.s3cliConfig
+	if cfg.CredentialsSource == config.NoneCredentialsSource {
+		return errorInvalidCredentialsSourceValue
+	}
+
+	uploader := b.newUploader()
+	uploadInput := b.putObjectInput(src, dest, opts)
+
+	retry := 0
+	for {
+		putResult, err := uploader.Upload(ctx, uploadInput) //nolint:staticcheck
+		if err != nil {
+			if _, ok := err.(manager.MultiUploadFailure); ok && !isPreconditionFailed(err) {
+				if retry == maxRetries {
+					return fmt.Errorf("upload retry limit exceeded: %w", err)
+				}
+				retry++
+				if err := sleepContext(ctx, time.Second*time.Duration(retry)); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("upload failure: %w", err)
+		}
+
+		slog.Info("Successfully uploaded file", "location", putResult.Location)
+		return nil
+	}
+}
+
+// PutStream uploads a blob of unknown length from a non-seekable reader.
+// The uploader buffers one part at a time, so the content is never held in
+// memory as a whole. A failed upload cannot be retried as the reader cannot
+// be rewound.
+func (b *awsS3Client) PutStream(ctx context.Context, src io.Reader, dest string, opts common.PutOptions) error {
+	if b.s3cliConfig.CredentialsSource == config.NoneCredentialsSource {
+		return errorInvalidCredentialsSourceValue
+	}
+
+	putResult, err := b.newUploader().Upload(ctx, b.putObjectInput(src, dest, opts)) //nolint:staticcheck
+	if err != nil {
+		return fmt.Errorf("upload failure: %w", err)
+	}
+
+	slog.Info("Successfully uploaded stream", "location", putResult.Location)
+	return nil
+}
+
+// PutCheckpointed uploads size bytes of src with a multipart upload whose
+// upload ID and completed parts are recorded in checkpoint, resuming the
+// upload recorded there if S3 still has it. Parts are retried by the SDK,
+// and an upload that still fails is left in place for a later call to
+// resume, so buckets should have a lifecycle rule aborting incomplete
+// multipart uploads.
+func (b *awsS3Client) PutCheckpointed(ctx context.Context, src io.ReaderAt, size int64, dest string, opts common.PutOptions, checkpoint *common.Checkpoint) error {
 	cfg := b.s3cliConfig
 	if cfg.CredentialsSource == config.NoneCredentialsSource {
 		return errorInvalidCredentialsSourceValue
 	}
 
-	uploader := manager.NewUploader(b.s3Client, func(u *manager.Uploader) { //nolint:staticcheck
+	// Like the uploader, have S3 verify a CRC32 of every part unless the
+	// provider does not support it.
+	checksumAlgorithm := types.ChecksumAlgorithmCrc32
+	var clientOptions []func(*s3.Options)
+	if cfg.ShouldDisableUploaderRequestChecksumCalculation() {
+		checksumAlgorithm = ""
+		clientOptions = append(clientOptions, func(o *s3.Options) {
+			o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		})
+	}
+
+	uploadID, partSize := checkpoint.Upload()
+	if uploadID != "" {
+		parts, err := b.listParts(ctx, dest, uploadID, size, partSize)
+		var noSuchUpload *types.NoSuchUpload
+		switch {
+		case errors.As(err, &noSuchUpload):
+			slog.Warn("Checkpointed upload no longer exists, starting over", "key", dest, "upload_id", uploadID)
+			uploadID = ""
+		case err != nil:
+			return fmt.Errorf("listing parts of upload %s: %w", uploadID, err)
+		default:
+			if err := checkpoint.SetParts(parts); err != nil {
+				return err
+			}
+			slog.Info("Resuming checkpointed upload", "key", dest, "upload_id", uploadID, "uploaded_parts", len(parts))
+		}
+	}
+	if uploadID == "" {
+		input := b.putObjectInput(nil, dest, opts)
+		output, err := b.s3Client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+			Bucket:               input.Bucket,
+			Key:                  input.Key,
+			ServerSideEncryption: input.ServerSideEncryption,
+			SSEKMSKeyId:          input.SSEKMSKeyId,
+			ContentType:          input.ContentType,
+			ContentEncoding:      input.ContentEncoding,
+			CacheControl:         input.CacheControl,
+			ContentDisposition:   input.ContentDisposition,
+			Metadata:             input.Metadata,
+			ChecksumAlgorithm:    checksumAlgorithm,
+		}, clientOptions...)
+		if err != nil {
+			return fmt.Errorf("failed to create multipart upload: %w", err)
+		}
+		uploadID = aws.ToString(output.UploadId)
+		if err := checkpoint.Start(uploadID, b.uploadPartSize(size)); err != nil {
+			return err
+		}
+	}
+
+	concurrency := defaultTransferConcurrency
+	if cfg.UploadConcurrency > 0 {
+		concurrency = cfg.UploadConcurrency
+	}
+	err := checkpoint.UploadParts(ctx, concurrency, func(ctx context.Context, number int, offset, length int64) (common.CheckpointPart, error) {
+		output, err := b.s3Client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:            aws.String(cfg.BucketName),
+			Key:               b.key(dest),
+			UploadId:          aws.String(uploadID),
+			PartNumber:        aws.Int32(int32(number)),
+			Body:              io.NewSectionReader(src, offset, length),
+			ContentLength:     aws.Int64(length),
+			ChecksumAlgorithm: checksumAlgorithm,
+		}, clientOptions...)
+		if err != nil {
+			return common.CheckpointPart{}, err
+		}
+		return common.CheckpointPart{ETag: aws.ToString(output.ETag), Checksum: aws.ToString(output.ChecksumCRC32)}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("upload failure, rerun to resume upload %s: %w", uploadID, err)
+	}
+
+	parts := checkpoint.Parts()
+	completedParts := make([]types.CompletedPart, 0, len(parts))
+	for number := 1; number <= checkpoint.PartCount(); number++ {
+		completed := types.CompletedPart{PartNumber: aws.Int32(int32(number)), ETag: aws.String(parts[number].ETag)}
+		if parts[number].Checksum != "" {
+			completed.ChecksumCRC32 = aws.String(parts[number].Checksum)
+		}
+		completedParts = append(completedParts, completed)
+	}
+	input := b.putObjectInput(nil, dest, opts)
+	_, err = b.s3Client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          input.Bucket,
+		Key:             input.Key,
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completedParts},
+		IfMatch:         input.IfMatch,
+		IfNoneMatch:     input.IfNoneMatch,
+	}, clientOptions...)
+	if isPreconditionFailed(err) {
+		// Resuming cannot succeed either, so drop the upload.
+		b.abortMultipartUpload(ctx, dest, uploadID)
+		checkpoint.Remove() //nolint:errcheck
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload, rerun to resume upload %s: %w", uploadID, err)
+	}
+
+	slog.Info("Successfully uploaded file", "key", dest, "upload_id", uploadID)
+	return checkpoint.Remove()
+}
+
+// uploadPartSize returns the configured part size, raised as needed to fit
+// size bytes into the maximum number of parts.
+func (b *awsS3Client) uploadPartSize(size int64) int64 {
+	partSize := defaultTransferPartSize
+	if b.s3cliConfig.UploadPartSize > 0 {
+		partSize = b.s3cliConfig.UploadPartSize
+	}
+	if minPartSize := (size + int64(manager.MaxUploadParts) - 1) / int64(manager.MaxUploadParts); partSize < minPartSize {
+		partSize = minPartSize
+	}
+	return partSize
+}
+
+// listParts returns the parts S3 holds for the upload, leaving out any whose
+// size does not match a part of partSize bytes of a size-byte upload.
+func (b *awsS3Client) listParts(ctx context.Context, dest string, uploadID string, size int64, partSize int64) (map[int]common.CheckpointPart, error) {
+	parts := map[int]common.CheckpointPart{}
+	paginator := s3.NewListPartsPaginator(b.s3Client, &s3.ListPartsInput{
+		Bucket:   aws.String(b.s3cliConfig.BucketName),
+		Key:      b.key(dest),
+		UploadId: aws.String(uploadID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, part := range page.Parts {
+			number := int(aws.ToInt32(part.PartNumber))
+			offset := int64(number-1) * partSize
+			if offset >= size || aws.ToInt64(part.Size) != min(partSize, size-offset) {
+				continue
+			}
+			parts[number] = common.CheckpointPart{ETag: aws.ToString(part.ETag), Checksum: aws.ToString(part.ChecksumCRC32)}
+		}
+	}
+	return parts, nil
+}
+
+// abortMultipartUpload aborts an upload even if ctx was cancelled, so no
+// parts are left behind.
+func (b *awsS3Client) abortMultipartUpload(ctx context.Context, dest string, uploadID string) {
+	_, err := b.s3Client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(b.s3cliConfig.BucketName),
+		Key:      b.key(dest),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		slog.Warn("Failed to abort multipart upload", "uploadId", uploadID, "error", err)
+	}
+}
+
+func (b *awsS3Client) newUploader() *manager.Uploader { //nolint:staticcheck
+	cfg := b.s3cliConfig
+
+	return manager.NewUploader(b.s3Client, func(u *manager.Uploader) { //nolint:staticcheck
 		u.LeavePartsOnError = false
 
 		u.Concurrency = defaultTransferConcurrency
@@ -95,74 +336,77 @@ func (b *awsS3Client) Put(src io.ReadSeeker, dest string) error {
 			u.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
 		}
 	})
-	uploadInput := &s3.PutObjectInput{
-		Body:   src,
+}
+
+func (b *awsS3Client) putObjectInput(body io.Reader, dest string, opts common.PutOptions) *s3.PutObjectInput {
+	cfg := b.s3cliConfig
+
+	input := &s3.PutObjectInput{
+		Body:   body,
 		Bucket: aws.String(cfg.BucketName),
 		Key:    b.key(dest),
 	}
 	if cfg.ServerSideEncryption != "" {
-		uploadInput.ServerSideEncryption = types.ServerSideEncryption(cfg.ServerSideEncryption)
+		input.ServerSideEncryption = types.ServerSideEncryption(cfg.ServerSideEncryption)
 	}
 	if cfg.SSEKMSKeyID != "" {
-		uploadInput.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyID)
+		input.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyID)
 	}
-
-	retry := 0
-	for {
-		putResult, err := uploader.Upload(context.TODO(), uploadInput) //nolint:staticcheck
-		if err != nil {
-			if _, ok := err.(manager.MultiUploadFailure); ok {
-				if retry == maxRetries {
-					return fmt.Errorf("upload retry limit exceeded: %s", err.Error())
-				}
-				retry++
-				time.Sleep(time.Second * time.Duration(retry))
-				continue
-			}
-			return fmt.Errorf("upload failure: %s", err.Error())
-		}
-
-		slog.Info("Successfully uploaded file", "location", putResult.Location)
-		return nil
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	if opts.ContentEncoding != "" {
+		input.ContentEncoding = aws.String(opts.ContentEncoding)
 	}
+	if opts.CacheControl != "" {
+		input.CacheControl = aws.String(opts.CacheControl)
+	}
+	if opts.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(opts.ContentDisposition)
+	}
+	input.Metadata = opts.Metadata
+	// The uploader copies the conditions onto CompleteMultipartUpload, where
+	// S3 evaluates them for multipart uploads.
+	if opts.IfMatch != "" {
+		input.IfMatch = aws.String(common.QuoteETag(opts.IfMatch))
+	}
+	if opts.IfNoneMatch != "" {
+		input.IfNoneMatch = aws.String(opts.IfNoneMatch)
+	}
+	return input
 }
 
 // PutSinglePart uploads a blob using a single PutObject call (no multipart).
 // Use this for small files where multipart overhead is unnecessary.
-func (b *awsS3Client) PutSinglePart(src io.ReadSeeker, dest string) error {
+func (b *awsS3Client) PutSinglePart(ctx context.Context, src io.ReadSeeker, dest string, opts common.PutOptions) error {
 	cfg := b.s3cliConfig
 	if cfg.CredentialsSource == config.NoneCredentialsSource {
 		return errorInvalidCredentialsSourceValue
 	}
 
-	input := &s3.PutObjectInput{
-		Body:   src,
-		Bucket: aws.String(cfg.BucketName),
-		Key:    b.key(dest),
-	}
-	if cfg.ServerSideEncryption != "" {
-		input.ServerSideEncryption = types.ServerSideEncryption(cfg.ServerSideEncryption)
-	}
-	if cfg.SSEKMSKeyID != "" {
-		input.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyID)
-	}
+	input := b.putObjectInput(src, dest, opts)
 
 	retry := 0
 	for {
 		// Seek back to the start on retries so the full body is re-sent
 		if retry > 0 {
 			if _, seekErr := src.Seek(0, io.SeekStart); seekErr != nil {
-				return fmt.Errorf("failed to seek source for retry: %s", seekErr.Error())
+				return fmt.Errorf("failed to seek source for retry: %w", seekErr)
 			}
 		}
 
-		_, err := b.s3Client.PutObject(context.TODO(), input)
+		_, err := b.s3Client.PutObject(ctx, input)
 		if err != nil {
+			if isPreconditionFailed(err) {
+				return err
+			}
 			if retry == maxRetries {
-				return fmt.Errorf("single part upload retry limit exceeded: %s", err.Error())
+				return fmt.Errorf("single part upload retry limit exceeded: %w", err)
 			}
 			retry++
-			time.Sleep(time.Second * time.Duration(retry))
+			if err := sleepContext(ctx, time.Second*time.Duration(retry)); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -171,8 +415,9 @@ func (b *awsS3Client) PutSinglePart(src io.ReadSeeker, dest string) error {
 	}
 }
 
-// Delete removes a blob - no error is returned if the object does not exist
-func (b *awsS3Client) Delete(dest string) error {
+// Delete removes a blob - no error is returned if the object does not exist,
+// unless opts.IfMatch is set
+func (b *awsS3Client) Delete(ctx context.Context, dest string, opts common.DeleteOptions) error {
 	if b.s3cliConfig.CredentialsSource == config.NoneCredentialsSource {
 		return errorInvalidCredentialsSourceValue
 	}
@@ -181,8 +426,11 @@ func (b *awsS3Client) Delete(dest string) error {
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 		Key:    b.key(dest),
 	}
+	if opts.IfMatch != "" {
+		deleteParams.IfMatch = aws.String(common.QuoteETag(opts.IfMatch))
+	}
 
-	_, err := b.s3Client.DeleteObject(context.TODO(), deleteParams)
+	_, err := b.s3Client.DeleteObject(ctx, deleteParams)
 
 	if err == nil {
 		return nil
@@ -190,19 +438,98 @@ func (b *awsS3Client) Delete(dest string) error {
 
 	var apiErr smithy.APIError
 	if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NotFound" || apiErr.ErrorCode() == "NoSuchKey") {
+		if opts.IfMatch != "" {
+			return common.NewError(common.ErrPreconditionFailed, fmt.Errorf("object %q does not exist", dest))
+		}
 		return nil
 	}
 	return err
 }
 
+// deleteKey deletes key unconditionally, for DeleteConcurrently.
+func (b *awsS3Client) deleteKey(ctx context.Context, key string) error {
+	return b.Delete(ctx, key, common.DeleteOptions{})
+}
+
+// DeleteMany deletes keys with DeleteObjects, deleteObjectsBatchSize at a time.
+// Providers without multi-object delete, like GCS, delete them concurrently.
+func (b *awsS3Client) DeleteMany(ctx context.Context, keys []string) []common.DeleteFailure {
+	if b.s3cliConfig.CredentialsSource == config.NoneCredentialsSource {
+		return deleteFailures(keys, errorInvalidCredentialsSourceValue)
+	}
+	if b.s3cliConfig.IsGoogle() {
+		return common.DeleteConcurrently(ctx, keys, common.DefaultDeleteParallelism, b.deleteKey)
+	}
+
+	var failures []common.DeleteFailure
+	for start := 0; start < len(keys); start += deleteObjectsBatchSize {
+		batch := keys[start:min(start+deleteObjectsBatchSize, len(keys))]
+		batchFailures, err := b.deleteObjects(ctx, batch)
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotImplemented" {
+			slog.Info("Multi-object delete not supported by provider, falling back to single deletes", "keys", len(batch))
+			batchFailures = common.DeleteConcurrently(ctx, batch, common.DefaultDeleteParallelism, b.deleteKey)
+		} else if err != nil {
+			batchFailures = deleteFailures(batch, fmt.Errorf("failed to delete objects: %w", err))
+		}
+		failures = append(failures, batchFailures...)
+	}
+	return failures
+}
+
+// deleteObjects deletes at most deleteObjectsBatchSize keys with a single
+// request and returns the keys S3 reported errors for.
+func (b *awsS3Client) deleteObjects(ctx context.Context, keys []string) ([]common.DeleteFailure, error) {
+	objects := make([]types.ObjectIdentifier, len(keys))
+	names := make(map[string]string, len(keys))
+	for i, key := range keys {
+		objects[i] = types.ObjectIdentifier{Key: b.key(key)}
+		names[*objects[i].Key] = key
+	}
+
+	output, err := b.s3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(b.s3cliConfig.BucketName),
+		Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []common.DeleteFailure
+	for _, deleteErr := range output.Errors {
+		code := aws.ToString(deleteErr.Code)
+		if code == "NoSuchKey" || code == "NotFound" {
+			continue
+		}
+		key, ok := names[aws.ToString(deleteErr.Key)]
+		if !ok {
+			key = aws.ToString(deleteErr.Key)
+		}
+		failures = append(failures, common.DeleteFailure{
+			Key: key,
+			Err: &smithy.GenericAPIError{Code: code, Message: aws.ToString(deleteErr.Message)},
+		})
+	}
+	return failures, nil
+}
+
+// deleteFailures reports every key as failed with err.
+func deleteFailures(keys []string, err error) []common.DeleteFailure {
+	failures := make([]common.DeleteFailure, len(keys))
+	for i, key := range keys {
+		failures[i] = common.DeleteFailure{Key: key, Err: err}
+	}
+	return failures
+}
+
 // Exists checks if blob exists
-func (b *awsS3Client) Exists(dest string) (bool, error) {
+func (b *awsS3Client) Exists(ctx context.Context, dest string) (bool, error) {
 	existsParams := &s3.HeadObjectInput{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 		Key:    b.key(dest),
 	}
 
-	_, err := b.s3Client.HeadObject(context.TODO(), existsParams)
+	_, err := b.s3Client.HeadObject(ctx, existsParams)
 
 	if err == nil {
 		slog.Info("Blob exists in bucket", "bucket", b.s3cliConfig.BucketName, "blob", dest)
@@ -217,19 +544,59 @@ func (b *awsS3Client) Exists(dest string) (bool, error) {
 	return false, err
 }
 
+// Stat returns the size, ETag and MD5 of a blob. exists is false if the blob does not exist.
+func (b *awsS3Client) Stat(ctx context.Context, dest string) (info common.ObjectInfo, exists bool, err error) {
+	headOutput, err := b.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(b.s3cliConfig.BucketName),
+		Key:    b.key(dest),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFound" {
+			return common.ObjectInfo{}, false, nil
+		}
+		return common.ObjectInfo{}, false, fmt.Errorf("failed to fetch blob metadata: %w", err)
+	}
+
+	info = common.ObjectInfo{Name: dest}
+	if headOutput.ContentLength != nil {
+		info.Size = *headOutput.ContentLength
+	}
+	if headOutput.ETag != nil {
+		info.ETag = common.TrimETag(*headOutput.ETag)
+		info.ContentMD5 = headContentMD5(headOutput)
+	}
+	if headOutput.LastModified != nil {
+		info.LastModified = *headOutput.LastModified
+	}
+	return info, true, nil
+}
+
 // Sign creates a presigned URL
-func (b *awsS3Client) Sign(objectID string, action string, expiration time.Duration) (string, error) {
+func (b *awsS3Client) Sign(ctx context.Context, objectID string, action string, expiration time.Duration) (string, error) {
 	action = strings.ToUpper(action)
 	switch action {
 	case "GET":
-		return b.getSigned(objectID, expiration)
+		return b.getSigned(ctx, objectID, expiration)
 	case "PUT":
-		return b.putSigned(objectID, expiration)
+		return b.putSigned(ctx, objectID, expiration)
 	default:
 		return "", fmt.Errorf("action not implemented: %s", action)
 	}
 }
 
+// sleepContext waits for d, unless ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (b *awsS3Client) key(srcOrDest string) *string {
 	formattedKey := aws.String(srcOrDest)
 	if len(b.s3cliConfig.FolderName) != 0 {
@@ -239,14 +606,22 @@ func (b *awsS3Client) key(srcOrDest string) *string {
 	return formattedKey
 }
 
-func (b *awsS3Client) getSigned(objectID string, expiration time.Duration) (string, error) {
+// unkey returns the name of the object stored at key, the inverse of key.
+func (b *awsS3Client) unkey(key string) string {
+	if len(b.s3cliConfig.FolderName) != 0 {
+		return strings.TrimPrefix(key, b.s3cliConfig.FolderName+"/")
+	}
+	return key
+}
+
+func (b *awsS3Client) getSigned(ctx context.Context, objectID string, expiration time.Duration) (string, error) {
 	presignClient := s3.NewPresignClient(b.s3Client)
 	signParams := &s3.GetObjectInput{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 		Key:    b.key(objectID),
 	}
 
-	req, err := presignClient.PresignGetObject(context.TODO(), signParams, s3.WithPresignExpires(expiration))
+	req, err := presignClient.PresignGetObject(ctx, signParams, s3.WithPresignExpires(expiration))
 	if err != nil {
 		return "", err
 	}
@@ -254,14 +629,14 @@ func (b *awsS3Client) getSigned(objectID string, expiration time.Duration) (stri
 	return req.URL, nil
 }
 
-func (b *awsS3Client) putSigned(objectID string, expiration time.Duration) (string, error) {
+func (b *awsS3Client) putSigned(ctx context.Context, objectID string, expiration time.Duration) (string, error) {
 	presignClient := s3.NewPresignClient(b.s3Client)
 	signParams := &s3.PutObjectInput{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 		Key:    b.key(objectID),
 	}
 
-	req, err := presignClient.PresignPutObject(context.TODO(), signParams, s3.WithPresignExpires(expiration))
+	req, err := presignClient.PresignPutObject(ctx, signParams, s3.WithPresignExpires(expiration))
 	if err != nil {
 		return "", err
 	}
@@ -269,9 +644,9 @@ func (b *awsS3Client) putSigned(objectID string, expiration time.Duration) (stri
 	return req.URL, nil
 }
 
-func (b *awsS3Client) EnsureStorageExists() error {
+func (b *awsS3Client) EnsureStorageExists(ctx context.Context) error {
 	slog.Info("Ensuring bucket exists", "bucket", b.s3cliConfig.BucketName)
-	_, err := b.s3Client.HeadBucket(context.TODO(), &s3.HeadBucketInput{
+	_, err := b.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 	})
 
@@ -297,7 +672,7 @@ func (b *awsS3Client) EnsureStorageExists() error {
 		}
 	}
 
-	_, err = b.s3Client.CreateBucket(context.TODO(), createBucketInput)
+	_, err = b.s3Client.CreateBucket(ctx, createBucketInput)
 	if err != nil {
 		var alreadyOwned *types.BucketAlreadyOwnedByYou
 		var alreadyExists *types.BucketAlreadyExists
@@ -310,7 +685,7 @@ func (b *awsS3Client) EnsureStorageExists() error {
 
 	slog.Info("Bucket created, waiting for it to be accessible", "bucket", b.s3cliConfig.BucketName)
 	waiter := s3.NewBucketExistsWaiter(b.s3Client)
-	if waitErr := waiter.Wait(context.TODO(), &s3.HeadBucketInput{
+	if waitErr := waiter.Wait(ctx, &s3.HeadBucketInput{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 	}, 60*time.Second); waitErr != nil {
 		return fmt.Errorf("bucket created but did not become accessible: %w", waitErr)
@@ -320,7 +695,7 @@ func (b *awsS3Client) EnsureStorageExists() error {
 	return nil
 }
 
-func (b *awsS3Client) Copy(srcBlob string, dstBlob string) error {
+func (b *awsS3Client) Copy(ctx context.Context, srcBlob string, dstBlob string) error {
 	cfg := b.s3cliConfig
 
 	copyThreshold := defaultMultipartCopyThreshold
@@ -332,7 +707,7 @@ func (b *awsS3Client) Copy(srcBlob string, dstBlob string) error {
 		copyPartSize = cfg.MultipartCopyPartSize
 	}
 
-	headOutput, err := b.s3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+	headOutput, err := b.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(cfg.BucketName),
 		Key:    b.key(srcBlob),
 	})
@@ -349,19 +724,19 @@ func (b *awsS3Client) Copy(srcBlob string, dstBlob string) error {
 	// Use simple copy if file is below threshold or is empty
 	if objectSize < copyThreshold {
 		slog.Info("Copying object", "source", srcBlob, "destination", dstBlob, "size", objectSize)
-		return b.simpleCopy(copySource, dstBlob)
+		return b.simpleCopy(ctx, copySource, dstBlob)
 	}
 
 	// For large files, try multipart copy first (works for AWS, MinIO, Ceph, AliCloud)
 	// Fall back to simple copy if provider doesn't support UploadPartCopy (e.g., GCS)
 	slog.Info("Copying large object using multipart copy", "source", srcBlob, "destination", dstBlob, "size", objectSize)
 
-	err = b.multipartCopy(copySource, dstBlob, objectSize, copyPartSize)
+	err = b.multipartCopy(ctx, copySource, dstBlob, objectSize, copyPartSize)
 	if err != nil {
 		var apiErr smithy.APIError
 		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotImplemented" {
 			slog.Info("Multipart copy not supported by provider, falling back to simple copy", "source", srcBlob, "destination", dstBlob)
-			return b.simpleCopy(copySource, dstBlob)
+			return b.simpleCopy(ctx, copySource, dstBlob)
 		}
 		return err
 	}
@@ -370,7 +745,7 @@ func (b *awsS3Client) Copy(srcBlob string, dstBlob string) error {
 }
 
 // simpleCopy performs a single CopyObject request
-func (b *awsS3Client) simpleCopy(copySource string, dstBlob string) error {
+func (b *awsS3Client) simpleCopy(ctx context.Context, copySource string, dstBlob string) error {
 	cfg := b.s3cliConfig
 
 	copyInput := &s3.CopyObjectInput{
@@ -385,7 +760,7 @@ func (b *awsS3Client) simpleCopy(copySource string, dstBlob string) error {
 		copyInput.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyID)
 	}
 
-	_, err := b.s3Client.CopyObject(context.TODO(), copyInput)
+	_, err := b.s3Client.CopyObject(ctx, copyInput)
 	if err != nil {
 		return fmt.Errorf("failed to copy object: %w", err)
 	}
@@ -393,7 +768,7 @@ func (b *awsS3Client) simpleCopy(copySource string, dstBlob string) error {
 }
 
 // multipartCopy performs a multipart copy using CreateMultipartUpload, UploadPartCopy, and CompleteMultipartUpload
-func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSize int64, copyPartSize int64) error {
+func (b *awsS3Client) multipartCopy(ctx context.Context, copySource string, dstBlob string, objectSize int64, copyPartSize int64) error {
 	cfg := b.s3cliConfig
 	// Calculate number of parts using ceiling division (avoids floating-point arithmetic).
 	// Example: objectSize=550MB, partSize=100MB => (550 + 100 - 1) / 100 = 6 parts
@@ -410,7 +785,7 @@ func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSiz
 		createInput.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyID)
 	}
 
-	createOutput, err := b.s3Client.CreateMultipartUpload(context.TODO(), createInput)
+	createOutput, err := b.s3Client.CreateMultipartUpload(ctx, createInput)
 	if err != nil {
 		return fmt.Errorf("failed to create multipart upload: %w", err)
 	}
@@ -420,14 +795,7 @@ func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSiz
 	var completed bool
 	defer func() {
 		if !completed {
-			_, err := b.s3Client.AbortMultipartUpload(context.TODO(), &s3.AbortMultipartUploadInput{
-				Bucket:   aws.String(cfg.BucketName),
-				Key:      b.key(dstBlob),
-				UploadId: aws.String(uploadID),
-			})
-			if err != nil {
-				slog.Warn("Failed to abort multipart upload", "uploadId", uploadID, "error", err)
-			}
+			b.abortMultipartUpload(ctx, dstBlob, uploadID)
 		}
 	}()
 
@@ -441,7 +809,7 @@ func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSiz
 		}
 		byteRange := fmt.Sprintf("bytes=%d-%d", start, end)
 
-		output, err := b.s3Client.UploadPartCopy(context.TODO(), &s3.UploadPartCopyInput{
+		output, err := b.s3Client.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
 			Bucket:          aws.String(cfg.BucketName),
 			CopySource:      aws.String(copySource),
 			CopySourceRange: aws.String(byteRange),
@@ -460,7 +828,7 @@ func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSiz
 		slog.Debug("Copied part", "part", partNumber, "range", byteRange)
 	}
 
-	_, err = b.s3Client.CompleteMultipartUpload(context.TODO(), &s3.CompleteMultipartUploadInput{
+	_, err = b.s3Client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
 		Bucket:   aws.String(cfg.BucketName),
 		Key:      b.key(dstBlob),
 		UploadId: aws.String(uploadID),
@@ -477,115 +845,169 @@ func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSiz
 	return nil
 }
 
-type BlobProperties struct {
-	ETag          string    `json:"etag,omitempty"`
-	LastModified  time.Time `json:"last_modified,omitempty"`
-	ContentLength int64     `json:"content_length,omitempty"`
-}
-
-func (b *awsS3Client) Properties(dest string) error {
+func (b *awsS3Client) Properties(ctx context.Context, dest string) (common.Properties, error) {
 	slog.Info("Fetching blob properties", "bucket", b.s3cliConfig.BucketName, "blob", dest)
 
-	headObjectOutput, err := b.s3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+	input := &s3.HeadObjectInput{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 		Key:    b.key(dest),
-	})
-
+	}
+	if !b.s3cliConfig.ShouldDisableResponseChecksumCalculation() {
+		// Additional checksums are only returned when asked for.
+		input.ChecksumMode = types.ChecksumModeEnabled
+	}
+	headObjectOutput, err := b.s3Client.HeadObject(ctx, input)
 	if err != nil {
-		var apiErr smithy.APIError
-		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFound" {
-			fmt.Println(`{}`)
-			return nil
-		}
-		return fmt.Errorf("failed to fetch blob properties: %w", err)
+		return common.Properties{}, fmt.Errorf("failed to fetch blob properties: %w", err)
+	}
+
+	properties := common.Properties{
+		ContentLength:      aws.ToInt64(headObjectOutput.ContentLength),
+		LastModified:       aws.ToTime(headObjectOutput.LastModified),
+		ContentType:        aws.ToString(headObjectOutput.ContentType),
+		ContentEncoding:    aws.ToString(headObjectOutput.ContentEncoding),
+		CacheControl:       aws.ToString(headObjectOutput.CacheControl),
+		ContentDisposition: aws.ToString(headObjectOutput.ContentDisposition),
+		CRC32:              common.HexFromBase64(aws.ToString(headObjectOutput.ChecksumCRC32)),
+		CRC32C:             common.HexFromBase64(aws.ToString(headObjectOutput.ChecksumCRC32C)),
+		SHA256:             common.HexFromBase64(aws.ToString(headObjectOutput.ChecksumSHA256)),
+		StorageClass:       string(headObjectOutput.StorageClass),
+		Encryption: common.Encryption{
+			Algorithm: string(headObjectOutput.ServerSideEncryption),
+			KMSKeyID:  aws.ToString(headObjectOutput.SSEKMSKeyId),
+		},
+		Metadata: headObjectOutput.Metadata,
+	}
+	if properties.Encryption.Algorithm == "" {
+		properties.Encryption.Algorithm = aws.ToString(headObjectOutput.SSECustomerAlgorithm)
 	}
-
-	properties := BlobProperties{}
 	if headObjectOutput.ETag != nil {
-		properties.ETag = strings.Trim(*headObjectOutput.ETag, `"`)
+		properties.ETag = common.TrimETag(*headObjectOutput.ETag)
 	}
-	if headObjectOutput.LastModified != nil {
-		properties.LastModified = *headObjectOutput.LastModified
+	properties.ContentMD5 = headContentMD5(headObjectOutput)
+	return properties, nil
+}
+
+// headContentMD5 returns the hex-encoded MD5 of an object from its MD5
+// checksum, or else its ETag. The ETags of objects encrypted with SSE-KMS or
+// SSE-C are not digests of their content.
+func headContentMD5(head *s3.HeadObjectOutput) string {
+	if md5 := common.HexFromBase64(aws.ToString(head.ChecksumMD5)); md5 != "" {
+		return md5
 	}
-	if headObjectOutput.ContentLength != nil {
-		properties.ContentLength = *headObjectOutput.ContentLength
+	if strings.HasPrefix(string(head.ServerSideEncryption), "aws:kms") || head.SSECustomerAlgorithm != nil {
+		return ""
 	}
+	return common.MD5FromETag(aws.ToString(head.ETag))
+}
 
-	output, err := json.MarshalIndent(properties, "", "  ")
+func (b *awsS3Client) List(ctx context.Context, prefix string) ([]string, error) {
+	objects, err := b.ListObjects(ctx, prefix)
 	if err != nil {
-		return fmt.Errorf("failed to marshal blob properties: %w", err)
+		return nil, err
 	}
+	return common.ObjectNames(objects), nil
+}
 
-	fmt.Println(string(output))
-
-	return nil
+func (b *awsS3Client) ListObjects(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	result, err := b.ListWithOptions(ctx, common.ListOptions{Prefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+	return result.Objects, nil
 }
 
-func (b *awsS3Client) List(prefix string) ([]string, error) {
+func (b *awsS3Client) ListWithOptions(ctx context.Context, opts common.ListOptions) (common.ListResult, error) {
 	input := &s3.ListObjectsV2Input{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 	}
 
-	if prefix != "" {
-		slog.Info("Listing all objects in bucket with prefix", "bucket", b.s3cliConfig.BucketName, "prefix", prefix)
-		input.Prefix = b.key(prefix)
+	if opts.Prefix != "" {
+		slog.Info("Listing all objects in bucket with prefix", "bucket", b.s3cliConfig.BucketName, "prefix", opts.Prefix, "delimiter", opts.Delimiter)
 	} else {
-		slog.Info("Listing all objects in bucket", "bucket", b.s3cliConfig.BucketName)
+		slog.Info("Listing all objects in bucket", "bucket", b.s3cliConfig.BucketName, "delimiter", opts.Delimiter)
+	}
+	if opts.Prefix != "" || b.s3cliConfig.FolderName != "" {
+		input.Prefix = b.key(opts.Prefix)
+	}
+	if opts.Delimiter != "" {
+		input.Delimiter = aws.String(opts.Delimiter)
+	}
+	if opts.StartAfter != "" {
+		input.StartAfter = b.key(opts.StartAfter)
+	}
+	if opts.PageToken != "" {
+		input.ContinuationToken = aws.String(opts.PageToken)
+	}
+	if opts.MaxKeys > 0 {
+		input.MaxKeys = aws.Int32(int32(min(opts.MaxKeys, maxListKeys)))
 	}
 
-	var names []string
+	var result common.ListResult
 	objectPaginator := s3.NewListObjectsV2Paginator(b.s3Client, input)
 	for objectPaginator.HasMorePages() {
-		page, err := objectPaginator.NextPage(context.TODO())
+		page, err := objectPaginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list objects: %w", err)
+			return common.ListResult{}, fmt.Errorf("failed to list objects: %w", err)
 		}
 
-		if len(page.Contents) == 0 {
-			continue
+		for _, obj := range page.Contents {
+			info := objectInfo(obj)
+			info.Name = b.unkey(info.Name)
+			result.Objects = append(result.Objects, info)
+		}
+		for _, commonPrefix := range page.CommonPrefixes {
+			result.Prefixes = append(result.Prefixes, b.unkey(aws.ToString(commonPrefix.Prefix)))
 		}
 
-		for _, obj := range page.Contents {
-			names = append(names, *obj.Key)
+		if opts.MaxKeys > 0 {
+			if aws.ToBool(page.IsTruncated) {
+				result.NextPageToken = aws.ToString(page.NextContinuationToken)
+			}
+			break
 		}
 	}
 
-	return names, nil
+	return result, nil
 }
 
-func (b *awsS3Client) DeleteRecursive(prefix string) error {
-	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(b.s3cliConfig.BucketName),
+// objectInfo converts an entry of a ListObjectsV2 page.
+func objectInfo(obj types.Object) common.ObjectInfo {
+	info := common.ObjectInfo{
+		Name:         aws.ToString(obj.Key),
+		Size:         aws.ToInt64(obj.Size),
+		StorageClass: string(obj.StorageClass),
+	}
+	if obj.ETag != nil {
+		info.ETag = common.TrimETag(*obj.ETag)
+		info.ContentMD5 = common.MD5FromETag(info.ETag)
 	}
+	if obj.LastModified != nil {
+		info.LastModified = *obj.LastModified
+	}
+	return info
+}
 
+// DeleteRecursive deletes the objects below prefix a page of the listing at
+// a time, with DeleteMany.
+func (b *awsS3Client) DeleteRecursive(ctx context.Context, prefix string) error {
 	if prefix != "" {
 		slog.Info("Deleting all objects in bucket with given prefix", "bucket", b.s3cliConfig.BucketName, "prefix", prefix)
-		input.Prefix = b.key(prefix)
 	} else {
 		slog.Info("Deleting all objects in bucket", "bucket", b.s3cliConfig.BucketName)
 	}
 
-	objectPaginator := s3.NewListObjectsV2Paginator(b.s3Client, input)
-	for objectPaginator.HasMorePages() {
-		page, err := objectPaginator.NextPage(context.TODO())
+	var failures []common.DeleteFailure
+	opts := common.ListOptions{Prefix: prefix, MaxKeys: maxListKeys}
+	for {
+		page, err := b.ListWithOptions(ctx, opts)
 		if err != nil {
-			return fmt.Errorf("failed to list objects for deletion: %w", err)
+			return errors.Join(fmt.Errorf("failed to list objects for deletion: %w", err), common.JoinDeleteFailures(failures))
 		}
-
-		for _, obj := range page.Contents {
-			slog.Debug("Deleting object", "key", *obj.Key)
-			_, err := b.s3Client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
-				Bucket: aws.String(b.s3cliConfig.BucketName),
-				Key:    obj.Key,
-			})
-			if err != nil {
-				var apiErr smithy.APIError
-				if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NotFound" || apiErr.ErrorCode() == "NoSuchKey") {
-					continue // Object already deleted, which is fine
-				}
-				return fmt.Errorf("failed to delete object '%s': %w", *obj.Key, err)
-			}
+		failures = append(failures, b.DeleteMany(ctx, common.ObjectNames(page.Objects))...)
+		if page.NextPageToken == "" {
+			return common.JoinDeleteFailures(failures)
 		}
+		opts.PageToken = page.NextPageToken
 	}
-	return nil
 }
@@ -1,11 +1,14 @@
 package client
 
 import (
+	"context"
+	"io"
 	"os"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/s3/config"
 )
 
@@ -29,16 +32,24 @@ func New(s3Client *s3.Client, s3cliConfig *config.S3Cli) *S3CompatibleClient {
 	}
 }
 
-func (c *S3CompatibleClient) Get(src string, dest string) error {
+func (c *S3CompatibleClient) Get(ctx context.Context, src string, dest string) error {
+	return c.GetWithOptions(ctx, src, dest, common.GetOptions{})
+}
+
+func (c *S3CompatibleClient) GetWithOptions(ctx context.Context, src string, dest string, opts common.GetOptions) error {
 	dstFile, err := os.Create(dest)
 	if err != nil {
 		return err
 	}
 	defer dstFile.Close() //nolint:errcheck
-	return c.awsS3BlobstoreClient.Get(src, dstFile)
+	return classifyError(c.awsS3BlobstoreClient.Get(ctx, src, dstFile, opts))
+}
+
+func (c *S3CompatibleClient) Put(ctx context.Context, src string, dest string) error {
+	return c.PutWithOptions(ctx, src, dest, common.PutOptions{})
 }
 
-func (c *S3CompatibleClient) Put(src string, dest string) error {
+func (c *S3CompatibleClient) PutWithOptions(ctx context.Context, src string, dest string, opts common.PutOptions) error {
 	sourceFile, err := os.Open(src)
 	if err != nil {
 		return err
@@ -52,46 +63,104 @@ func (c *S3CompatibleClient) Put(src string, dest string) error {
 	size := info.Size()
 
 	if size <= c.s3cliConfig.SingleUploadThreshold {
-		return c.awsS3BlobstoreClient.PutSinglePart(sourceFile, dest)
+		return classifyError(c.awsS3BlobstoreClient.PutSinglePart(ctx, sourceFile, dest, opts))
 	}
-	return c.awsS3BlobstoreClient.Put(sourceFile, dest)
+	if opts.Checkpoint != "" {
+		checkpoint, err := common.LoadCheckpoint(opts.Checkpoint, sourceFile, dest)
+		if err != nil {
+			return err
+		}
+		return classifyError(c.awsS3BlobstoreClient.PutCheckpointed(ctx, sourceFile, size, dest, opts, checkpoint))
+	}
+	return classifyError(c.awsS3BlobstoreClient.Put(ctx, sourceFile, dest, opts))
+}
+
+func (c *S3CompatibleClient) GetStream(ctx context.Context, src string) (io.ReadCloser, error) {
+	return c.GetStreamWithOptions(ctx, src, common.GetOptions{})
+}
+
+func (c *S3CompatibleClient) GetStreamWithOptions(ctx context.Context, src string, opts common.GetOptions) (io.ReadCloser, error) {
+	reader, err := c.awsS3BlobstoreClient.GetStream(ctx, src, opts)
+	return reader, classifyError(err)
+}
+
+func (c *S3CompatibleClient) PutStream(ctx context.Context, src io.Reader, dest string) error {
+	return c.PutStreamWithOptions(ctx, src, dest, common.PutOptions{})
+}
+
+func (c *S3CompatibleClient) PutStreamWithOptions(ctx context.Context, src io.Reader, dest string, opts common.PutOptions) error {
+	return classifyError(c.awsS3BlobstoreClient.PutStream(ctx, src, dest, opts))
 }
 
-func (c *S3CompatibleClient) Delete(dest string) error {
-	return c.awsS3BlobstoreClient.Delete(dest)
+func (c *S3CompatibleClient) Stat(ctx context.Context, dest string) (common.ObjectInfo, bool, error) {
+	info, exists, err := c.awsS3BlobstoreClient.Stat(ctx, dest)
+	return info, exists, classifyError(err)
 }
 
-func (c *S3CompatibleClient) Exists(dest string) (bool, error) {
-	return c.awsS3BlobstoreClient.Exists(dest)
+func (c *S3CompatibleClient) Delete(ctx context.Context, dest string) error {
+	return c.DeleteWithOptions(ctx, dest, common.DeleteOptions{})
 }
 
-func (c *S3CompatibleClient) Sign(objectID string, action string, expiration time.Duration) (string, error) {
+func (c *S3CompatibleClient) DeleteWithOptions(ctx context.Context, dest string, opts common.DeleteOptions) error {
+	return classifyError(c.awsS3BlobstoreClient.Delete(ctx, dest, opts))
+}
+
+func (c *S3CompatibleClient) Exists(ctx context.Context, dest string) (bool, error) {
+	exists, err := c.awsS3BlobstoreClient.Exists(ctx, dest)
+	return exists, classifyError(err)
+}
+
+func (c *S3CompatibleClient) Sign(ctx context.Context, objectID string, action string, expiration time.Duration) (string, error) {
 	if c.s3cliConfig.SwiftAuthAccount != "" {
 		return c.openstackSwiftBlobstore.Sign(objectID, action, expiration)
 	}
 
-	return c.awsS3BlobstoreClient.Sign(objectID, action, expiration)
+	signedURL, err := c.awsS3BlobstoreClient.Sign(ctx, objectID, action, expiration)
+	return signedURL, classifyError(err)
+}
+
+func (c *S3CompatibleClient) EnsureStorageExists(ctx context.Context) error {
+	return classifyError(c.awsS3BlobstoreClient.EnsureStorageExists(ctx))
 }
 
-func (c *S3CompatibleClient) EnsureStorageExists() error {
-	return c.awsS3BlobstoreClient.EnsureStorageExists()
+func (c *S3CompatibleClient) Copy(ctx context.Context, srcBlob string, dstBlob string) error {
+	return classifyError(c.awsS3BlobstoreClient.Copy(ctx, srcBlob, dstBlob))
+
 }
 
-func (c *S3CompatibleClient) Copy(srcBlob string, dstBlob string) error {
-	return c.awsS3BlobstoreClient.Copy(srcBlob, dstBlob)
+func (c *S3CompatibleClient) Move(ctx context.Context, srcBlob string, dstBlob string) error {
+	return common.MoveByCopy(ctx, c, srcBlob, dstBlob)
+}
 
+func (c *S3CompatibleClient) Properties(ctx context.Context, dest string) (common.Properties, error) {
+	props, err := c.awsS3BlobstoreClient.Properties(ctx, dest)
+	return props, classifyError(err)
 }
 
-func (c *S3CompatibleClient) Properties(dest string) error {
-	return c.awsS3BlobstoreClient.Properties(dest)
+func (c *S3CompatibleClient) List(ctx context.Context, prefix string) ([]string, error) {
+	names, err := c.awsS3BlobstoreClient.List(ctx, prefix)
+	return names, classifyError(err)
+
+}
 
+func (c *S3CompatibleClient) ListObjects(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	objects, err := c.awsS3BlobstoreClient.ListObjects(ctx, prefix)
+	return objects, classifyError(err)
 }
 
-func (c *S3CompatibleClient) List(prefix string) ([]string, error) {
-	return c.awsS3BlobstoreClient.List(prefix)
+func (c *S3CompatibleClient) ListWithOptions(ctx context.Context, opts common.ListOptions) (common.ListResult, error) {
+	result, err := c.awsS3BlobstoreClient.ListWithOptions(ctx, opts)
+	return result, classifyError(err)
+}
 
+func (c *S3CompatibleClient) DeleteMany(ctx context.Context, keys []string) []common.DeleteFailure {
+	failures := c.awsS3BlobstoreClient.DeleteMany(ctx, keys)
+	for i := range failures {
+		failures[i].Err = classifyError(failures[i].Err)
+	}
+	return failures
 }
 
-func (c *S3CompatibleClient) DeleteRecursive(prefix string) error {
-	return c.awsS3BlobstoreClient.DeleteRecursive(prefix)
+func (c *S3CompatibleClient) DeleteRecursive(ctx context.Context, prefix string) error {
+	return classifyError(c.awsS3BlobstoreClient.DeleteRecursive(ctx, prefix))
 }
@@ -1,12 +1,22 @@
 package client_test
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/s3/client"
 	"github.com/cloudfoundry/storage-cli/s3/config"
 
@@ -62,7 +72,7 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns a signed URL", func() {
-					url, err := blobstoreClient.Sign(objectId, action, expiration)
+					url, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(url).To(MatchRegexp(urlRegexp))
@@ -75,7 +85,7 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns a signed URL", func() {
-					url, err := blobstoreClient.Sign(objectId, action, expiration)
+					url, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(url).To(MatchRegexp(urlRegexp))
@@ -88,7 +98,7 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := blobstoreClient.Sign(objectId, action, expiration)
+					_, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).To(HaveOccurred())
 				})
 			})
@@ -122,7 +132,7 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns a signed URL", func() {
-					url, err := blobstoreClient.Sign(objectId, action, expiration)
+					url, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(url).To(MatchRegexp(urlRegexp))
@@ -135,7 +145,7 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns a signed URL", func() {
-					url, err := blobstoreClient.Sign(objectId, action, expiration)
+					url, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(url).To(MatchRegexp(urlRegexp))
@@ -148,10 +158,536 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := blobstoreClient.Sign(objectId, action, expiration)
+					_, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).To(HaveOccurred())
 				})
 			})
 		})
 	})
+
+	Describe("errors", func() {
+		BeforeEach(func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/xml")
+				if r.Method == http.MethodDelete {
+					w.WriteHeader(http.StatusForbidden)
+					fmt.Fprint(w, `<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`) //nolint:errcheck
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`) //nolint:errcheck
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = &config.S3Cli{BucketName: "some-bucket"}
+			s3Client := s3.NewFromConfig(aws.Config{
+				Region:      "us-west-2",
+				Credentials: credentials.NewStaticCredentialsProvider("id", "key", ""),
+			}, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(server.URL)
+				o.UsePathStyle = true
+			})
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("marks S3 responses with the kind of failure", func() {
+			_, err := blobstoreClient.GetStream(context.Background(), "missing")
+			Expect(err).To(MatchError(common.ErrNotFound))
+
+			_, err = blobstoreClient.Properties(context.Background(), "missing")
+			Expect(err).To(MatchError(common.ErrNotFound))
+
+			err = blobstoreClient.Delete(context.Background(), "object")
+			Expect(err).To(MatchError(common.ErrPermissionDenied))
+		})
+	})
+
+	Describe("PutWithOptions()", func() {
+		var header http.Header
+
+		BeforeEach(func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header
+				io.Copy(io.Discard, r.Body) //nolint:errcheck
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = &config.S3Cli{BucketName: "some-bucket", SingleUploadThreshold: 1024}
+			s3Client := s3.NewFromConfig(aws.Config{
+				Region:      "us-west-2",
+				Credentials: credentials.NewStaticCredentialsProvider("id", "key", ""),
+			}, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(server.URL)
+				o.UsePathStyle = true
+			})
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("sets the headers and metadata of the object", func() {
+			source := filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(source, []byte("content"), 0644)).To(Succeed())
+
+			err := blobstoreClient.PutWithOptions(context.Background(), source, "object", common.PutOptions{
+				ContentType:        "text/plain",
+				CacheControl:       "no-cache",
+				ContentDisposition: "attachment",
+				Metadata:           map[string]string{"owner": "cc"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(header.Get("Content-Type")).To(Equal("text/plain"))
+			Expect(header.Get("Cache-Control")).To(Equal("no-cache"))
+			Expect(header.Get("Content-Disposition")).To(Equal("attachment"))
+			Expect(header.Get("X-Amz-Meta-Owner")).To(Equal("cc"))
+		})
+	})
+
+	Describe("preconditions", func() {
+		var (
+			requests int
+			header   http.Header
+		)
+
+		BeforeEach(func() {
+			requests = 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				header = r.Header
+				io.Copy(io.Discard, r.Body) //nolint:errcheck
+				w.Header().Set("Content-Type", "application/xml")
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/missing"):
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`) //nolint:errcheck
+				case r.Header.Get("If-None-Match") == "*" || (r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != `"abc"`):
+					w.WriteHeader(http.StatusPreconditionFailed)
+					fmt.Fprint(w, `<Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>`) //nolint:errcheck
+				case r.Method == http.MethodGet:
+					fmt.Fprint(w, "content") //nolint:errcheck
+				case r.Method == http.MethodDelete:
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = &config.S3Cli{BucketName: "some-bucket", SingleUploadThreshold: 1024}
+			s3Client := s3.NewFromConfig(aws.Config{
+				Region:      "us-west-2",
+				Credentials: credentials.NewStaticCredentialsProvider("id", "key", ""),
+			}, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(server.URL)
+				o.UsePathStyle = true
+			})
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("sends If-None-Match and does not retry a failed precondition", func() {
+			source := filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(source, []byte("content"), 0644)).To(Succeed())
+
+			err := blobstoreClient.PutWithOptions(context.Background(), source, "object", common.PutOptions{IfNoneMatch: "*"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+			Expect(requests).To(Equal(1))
+		})
+
+		It("quotes the ETag of If-Match", func() {
+			reader, err := blobstoreClient.GetStreamWithOptions(context.Background(), "object", common.GetOptions{IfMatch: "abc"})
+			Expect(err).ToNot(HaveOccurred())
+			reader.Close() //nolint:errcheck
+			Expect(header.Get("If-Match")).To(Equal(`"abc"`))
+
+			err = blobstoreClient.DeleteWithOptions(context.Background(), "object", common.DeleteOptions{IfMatch: "def"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+		})
+
+		It("fails a conditional delete of a missing object", func() {
+			Expect(blobstoreClient.Delete(context.Background(), "missing")).To(Succeed())
+
+			err := blobstoreClient.DeleteWithOptions(context.Background(), "missing", common.DeleteOptions{IfMatch: "abc"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+		})
+	})
+
+	Describe("checkpointed uploads", func() {
+		var (
+			parts      map[string]string
+			creates    int
+			failPart   string
+			completed  string
+			checkpoint string
+			source     string
+		)
+
+		BeforeEach(func() {
+			parts = map[string]string{}
+			creates = 0
+			failPart = ""
+			completed = ""
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				body, _ := io.ReadAll(r.Body) //nolint:errcheck
+				w.Header().Set("Content-Type", "application/xml")
+				switch {
+				case r.Method == http.MethodPost && query.Has("uploads"):
+					creates++
+					fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`) //nolint:errcheck
+				case r.Method == http.MethodPut && query.Has("partNumber"):
+					number := query.Get("partNumber")
+					if number == failPart {
+						w.WriteHeader(http.StatusBadRequest)
+						fmt.Fprint(w, `<Error><Code>BadDigest</Code></Error>`) //nolint:errcheck
+						return
+					}
+					parts[number] = string(body)
+					w.Header().Set("ETag", `"etag-`+number+`"`)
+				case r.Method == http.MethodGet && query.Has("uploadId"):
+					fmt.Fprint(w, `<ListPartsResult><IsTruncated>false</IsTruncated>`) //nolint:errcheck
+					for number, content := range parts {
+						fmt.Fprintf(w, `<Part><PartNumber>%s</PartNumber><ETag>"etag-%s"</ETag><Size>%d</Size></Part>`, number, number, len(content)) //nolint:errcheck
+					}
+					fmt.Fprint(w, `</ListPartsResult>`) //nolint:errcheck
+				case r.Method == http.MethodPost && query.Has("uploadId"):
+					completed = string(body)
+					fmt.Fprint(w, `<CompleteMultipartUploadResult><ETag>"done"</ETag></CompleteMultipartUploadResult>`) //nolint:errcheck
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = &config.S3Cli{BucketName: "some-bucket", SingleUploadThreshold: 4, UploadPartSize: 4, UploadConcurrency: 1}
+			s3Client := s3.NewFromConfig(aws.Config{
+				Region:      "us-west-2",
+				Credentials: credentials.NewStaticCredentialsProvider("id", "key", ""),
+			}, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(server.URL)
+				o.UsePathStyle = true
+			})
+			blobstoreClient = client.New(s3Client, s3Config)
+
+			dir := GinkgoT().TempDir()
+			checkpoint = filepath.Join(dir, "checkpoint")
+			source = filepath.Join(dir, "source")
+			Expect(os.WriteFile(source, []byte("0123456789"), 0644)).To(Succeed())
+		})
+
+		It("resumes a failed upload with the parts S3 already has", func() {
+			failPart = "2"
+			err := blobstoreClient.PutWithOptions(context.Background(), source, "object", common.PutOptions{Checkpoint: checkpoint})
+			Expect(err).To(MatchError(ContainSubstring("rerun to resume upload upload-1")))
+			Expect(checkpoint).To(BeAnExistingFile())
+			Expect(parts).To(Equal(map[string]string{"1": "0123"}))
+
+			failPart = ""
+			err = blobstoreClient.PutWithOptions(context.Background(), source, "object", common.PutOptions{Checkpoint: checkpoint})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(creates).To(Equal(1))
+			Expect(parts).To(Equal(map[string]string{"1": "0123", "2": "4567", "3": "89"}))
+			Expect(completed).To(ContainSubstring(`<Part><ETag>&#34;etag-1&#34;</ETag><PartNumber>1</PartNumber></Part><Part><ETag>&#34;etag-2&#34;</ETag><PartNumber>2</PartNumber></Part><Part><ETag>&#34;etag-3&#34;</ETag><PartNumber>3</PartNumber></Part>`))
+			Expect(checkpoint).ToNot(BeAnExistingFile())
+		})
+
+		It("starts over if the source file changed", func() {
+			failPart = "2"
+			Expect(blobstoreClient.PutWithOptions(context.Background(), source, "object", common.PutOptions{Checkpoint: checkpoint})).ToNot(Succeed())
+
+			failPart = ""
+			Expect(os.WriteFile(source, []byte("abcdefghij"), 0644)).To(Succeed())
+			Expect(os.Chtimes(source, time.Time{}, time.Now().Add(time.Minute))).To(Succeed())
+			Expect(blobstoreClient.PutWithOptions(context.Background(), source, "object", common.PutOptions{Checkpoint: checkpoint})).To(Succeed())
+			Expect(creates).To(Equal(2))
+			Expect(parts).To(Equal(map[string]string{"1": "abcd", "2": "efgh", "3": "ij"}))
+		})
+	})
+
+	Describe("byte ranges", func() {
+		var ranges []string
+
+		BeforeEach(func() {
+			ranges = nil
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ranges = append(ranges, r.Header.Get("Range"))
+				http.ServeContent(w, r, "object", time.Time{}, strings.NewReader("0123456789"))
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = &config.S3Cli{BucketName: "some-bucket"}
+			s3Client := s3.NewFromConfig(aws.Config{
+				Region:      "us-west-2",
+				Credentials: credentials.NewStaticCredentialsProvider("id", "key", ""),
+			}, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(server.URL)
+				o.UsePathStyle = true
+			})
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("downloads a range to a file in a single request", func() {
+			dest := filepath.Join(GinkgoT().TempDir(), "dest")
+			err := blobstoreClient.GetWithOptions(context.Background(), "object", dest, common.GetOptions{Range: &common.ByteRange{Start: 2, End: 5}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.ReadFile(dest)).To(Equal([]byte("2345")))
+			Expect(ranges).To(Equal([]string{"bytes=2-5"}))
+		})
+
+		It("streams the end of an object", func() {
+			reader, err := blobstoreClient.GetStreamWithOptions(context.Background(), "object", common.GetOptions{Range: &common.ByteRange{Start: -3, End: -1}})
+			Expect(err).ToNot(HaveOccurred())
+			defer reader.Close() //nolint:errcheck
+			Expect(io.ReadAll(reader)).To(Equal([]byte("789")))
+			Expect(ranges).To(Equal([]string{"bytes=-3"}))
+		})
+	})
+
+	Describe("Properties()", func() {
+		BeforeEach(func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Header.Get("X-Amz-Checksum-Mode")).To(Equal("ENABLED"))
+				w.Header().Set("ETag", `"acbd18db4cc2f85cedef654fccc4a4d8"`)
+				w.Header().Set("Content-Length", "3")
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("Cache-Control", "no-cache")
+				w.Header().Set("X-Amz-Checksum-Crc32", "jHNlIQ==")
+				w.Header().Set("X-Amz-Checksum-Crc32c", "4waSgw==")
+				w.Header().Set("X-Amz-Storage-Class", "STANDARD_IA")
+				w.Header().Set("X-Amz-Server-Side-Encryption", "AES256")
+				w.Header().Set("X-Amz-Meta-Owner", "cc")
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = &config.S3Cli{BucketName: "some-bucket", ResponseChecksumCalculationEnabled: true}
+			s3Client := s3.NewFromConfig(aws.Config{
+				Region:      "us-west-2",
+				Credentials: credentials.NewStaticCredentialsProvider("id", "key", ""),
+			}, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(server.URL)
+				o.UsePathStyle = true
+			})
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("returns the headers of the object", func() {
+			props, err := blobstoreClient.Properties(context.Background(), "object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(props).To(Equal(common.Properties{
+				ETag:          "acbd18db4cc2f85cedef654fccc4a4d8",
+				ContentLength: 3,
+				ContentType:   "text/plain",
+				CacheControl:  "no-cache",
+				ContentMD5:    "acbd18db4cc2f85cedef654fccc4a4d8",
+				CRC32:         "8c736521",
+				CRC32C:        "e3069283",
+				StorageClass:  "STANDARD_IA",
+				Encryption:    common.Encryption{Algorithm: "AES256"},
+				Metadata:      map[string]string{"owner": "cc"},
+			}))
+		})
+	})
+
+	Describe("DeleteMany()", func() {
+		var requests []int
+
+		BeforeEach(func() {
+			requests = nil
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Query().Has("delete")).To(BeTrue())
+				body, err := io.ReadAll(r.Body)
+				Expect(err).ToNot(HaveOccurred())
+				requests = append(requests, strings.Count(string(body), "<Key>"))
+
+				w.Header().Set("Content-Type", "application/xml")
+				fmt.Fprint(w, `<DeleteResult>`+ //nolint:errcheck
+					`<Error><Key>folder/denied</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`+
+					`<Error><Key>folder/missing</Key><Code>NoSuchKey</Code><Message>Not Found</Message></Error>`+
+					`</DeleteResult>`)
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = &config.S3Cli{BucketName: "some-bucket", FolderName: "folder"}
+			s3Client := s3.NewFromConfig(aws.Config{
+				Region:      "us-west-2",
+				Credentials: credentials.NewStaticCredentialsProvider("id", "key", ""),
+			}, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(server.URL)
+				o.UsePathStyle = true
+			})
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("deletes 1000 keys per request and reports the keys S3 failed to delete", func() {
+			keys := []string{"denied", "missing"}
+			for i := range 1000 {
+				keys = append(keys, fmt.Sprintf("key-%d", i))
+			}
+
+			failures := blobstoreClient.DeleteMany(context.Background(), keys)
+			Expect(requests).To(Equal([]int{1000, 2}))
+			// The stub reports the same errors for each request.
+			Expect(failures).To(HaveLen(2))
+			Expect(failures[0].Key).To(Equal("denied"))
+			Expect(failures[0].Err).To(MatchError(common.ErrPermissionDenied))
+		})
+	})
+
+	Describe("Move()", func() {
+		// etags holds the ETag of each object. Copies of SSE-KMS objects get
+		// a new ETag, as on AWS.
+		var etags map[string]string
+
+		BeforeEach(func() {
+			etags = map[string]string{"/some-bucket/src": "acbd18db4cc2f85cedef654fccc4a4d8"}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				etag, exists := etags[r.URL.Path]
+				switch {
+				case r.Method == http.MethodHead && exists:
+					w.Header().Set("ETag", `"`+etag+`"`)
+					w.Header().Set("Content-Length", "3")
+					w.Header().Set("X-Amz-Server-Side-Encryption", "aws:kms")
+				case r.Method == http.MethodHead:
+					w.WriteHeader(http.StatusNotFound)
+				case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+					etags[r.URL.Path] = "37b51d194a7513e45b56f6524f2d51f2"
+					fmt.Fprint(w, `<CopyObjectResult><ETag>"37b51d194a7513e45b56f6524f2d51f2"</ETag></CopyObjectResult>`) //nolint:errcheck
+				case r.Method == http.MethodDelete:
+					delete(etags, r.URL.Path)
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = &config.S3Cli{BucketName: "some-bucket"}
+			s3Client := s3.NewFromConfig(aws.Config{
+				Region:      "us-west-2",
+				Credentials: credentials.NewStaticCredentialsProvider("id", "key", ""),
+			}, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(server.URL)
+				o.UsePathStyle = true
+			})
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("copies the object and deletes the source, also when retried", func() {
+			Expect(blobstoreClient.Move(context.Background(), "src", "dst")).To(Succeed())
+			Expect(etags).To(HaveKey("/some-bucket/dst"))
+			Expect(etags).ToNot(HaveKey("/some-bucket/src"))
+
+			Expect(blobstoreClient.Move(context.Background(), "src", "dst")).To(Succeed())
+		})
+
+		It("fails with a not found error when neither object exists", func() {
+			err := blobstoreClient.Move(context.Background(), "missing", "dst")
+			Expect(err).To(MatchError(common.ErrNotFound))
+		})
+	})
+
+	Describe("ListObjects()", func() {
+		BeforeEach(func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/xml")
+				if r.URL.Query().Get("max-keys") == "1" {
+					fmt.Fprint(w, `<ListBucketResult><Name>some-bucket</Name><IsTruncated>true</IsTruncated>`+ //nolint:errcheck
+						`<NextContinuationToken>token1</NextContinuationToken><Contents><Key>`+r.URL.Query().Get("start-after")+`b</Key></Contents></ListBucketResult>`)
+					return
+				}
+				if r.URL.Query().Get("delimiter") == "/" {
+					fmt.Fprint(w, `<ListBucketResult><Name>some-bucket</Name><IsTruncated>false</IsTruncated>`+ //nolint:errcheck
+						`<Contents><Key>a</Key><Size>42</Size></Contents><CommonPrefixes><Prefix>dir/</Prefix></CommonPrefixes></ListBucketResult>`)
+					return
+				}
+				fmt.Fprint(w, `<ListBucketResult><Name>some-bucket</Name><IsTruncated>false</IsTruncated>`+ //nolint:errcheck
+					`<Contents><Key>a</Key><Size>42</Size><ETag>"acbd18db4cc2f85cedef654fccc4a4d8"</ETag>`+
+					`<LastModified>2024-01-02T03:04:05.000Z</LastModified><StorageClass>STANDARD_IA</StorageClass></Contents>`+
+					`</ListBucketResult>`)
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = &config.S3Cli{BucketName: "some-bucket"}
+			s3Client := s3.NewFromConfig(aws.Config{
+				Region:      "us-west-2",
+				Credentials: credentials.NewStaticCredentialsProvider("id", "key", ""),
+			}, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(server.URL)
+				o.UsePathStyle = true
+			})
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("returns the metadata of the listing", func() {
+			objects, err := blobstoreClient.ListObjects(context.Background(), "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objects).To(Equal([]common.ObjectInfo{{
+				Name:         "a",
+				Size:         42,
+				ETag:         "acbd18db4cc2f85cedef654fccc4a4d8",
+				ContentMD5:   "acbd18db4cc2f85cedef654fccc4a4d8",
+				LastModified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				StorageClass: "STANDARD_IA",
+			}}))
+		})
+
+		It("returns common prefixes separately with a delimiter", func() {
+			result, err := blobstoreClient.ListWithOptions(context.Background(), common.ListOptions{Delimiter: "/"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(common.ObjectNames(result.Objects)).To(Equal([]string{"a"}))
+			Expect(result.Prefixes).To(Equal([]string{"dir/"}))
+		})
+
+		It("returns a single page and its continuation token with MaxKeys", func() {
+			result, err := blobstoreClient.ListWithOptions(context.Background(), common.ListOptions{MaxKeys: 1, StartAfter: "a"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(common.ObjectNames(result.Objects)).To(Equal([]string{"ab"}))
+			Expect(result.NextPageToken).To(Equal("token1"))
+		})
+	})
+	Describe("with a folder", func() {
+		var (
+			listedPrefixes []string
+			deletedKeys    []string
+		)
+
+		BeforeEach(func() {
+			listedPrefixes, deletedKeys = nil, nil
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/xml")
+				if r.Method == http.MethodPost {
+					body, err := io.ReadAll(r.Body)
+					Expect(err).ToNot(HaveOccurred())
+					for _, match := range regexp.MustCompile(`<Key>([^<]*)</Key>`).FindAllStringSubmatch(string(body), -1) {
+						deletedKeys = append(deletedKeys, match[1])
+					}
+					fmt.Fprint(w, `<DeleteResult></DeleteResult>`) //nolint:errcheck
+					return
+				}
+				listedPrefixes = append(listedPrefixes, r.URL.Query().Get("prefix"))
+				fmt.Fprint(w, `<ListBucketResult><Name>some-bucket</Name><IsTruncated>false</IsTruncated>`+ //nolint:errcheck
+					`<Contents><Key>folder/a</Key><Size>1</Size></Contents><Contents><Key>folder/b/c</Key><Size>1</Size></Contents>`+
+					`<CommonPrefixes><Prefix>folder/d/</Prefix></CommonPrefixes></ListBucketResult>`)
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = &config.S3Cli{BucketName: "some-bucket", FolderName: "folder"}
+			s3Client := s3.NewFromConfig(aws.Config{
+				Region:      "us-west-2",
+				Credentials: credentials.NewStaticCredentialsProvider("id", "key", ""),
+			}, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(server.URL)
+				o.UsePathStyle = true
+			})
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("lists the objects in the folder by their names", func() {
+			result, err := blobstoreClient.ListWithOptions(context.Background(), common.ListOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(common.ObjectNames(result.Objects)).To(Equal([]string{"a", "b/c"}))
+			Expect(result.Prefixes).To(Equal([]string{"d/"}))
+			Expect(listedPrefixes).To(Equal([]string{"folder/"}))
+
+			_, err = blobstoreClient.List(context.Background(), "b/")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(listedPrefixes[1]).To(Equal("folder/b/"))
+		})
+
+		It("deletes the listed objects recursively without prefixing them twice", func() {
+			Expect(blobstoreClient.DeleteRecursive(context.Background(), "")).To(Succeed())
+			Expect(deletedKeys).To(Equal([]string{"folder/a", "folder/b/c"}))
+		})
+	})
 })
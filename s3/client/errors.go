@@ -0,0 +1,55 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/aws/smithy-go"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// errorCodeKinds maps S3 error codes onto the shared kinds of failures, for
+// the codes whose HTTP status is ambiguous or missing.
+var errorCodeKinds = map[string]error{
+	"NoSuchKey":             common.ErrNotFound,
+	"NoSuchBucket":          common.ErrNotFound,
+	"NotFound":              common.ErrNotFound,
+	"AccessDenied":          common.ErrPermissionDenied,
+	"InvalidAccessKeyId":    common.ErrPermissionDenied,
+	"SignatureDoesNotMatch": common.ErrPermissionDenied,
+	"BucketAlreadyExists":   common.ErrAlreadyExists,
+	"PreconditionFailed":    common.ErrPreconditionFailed,
+	// A concurrent conditional write to the same key won the race.
+	"ConditionalRequestConflict": common.ErrPreconditionFailed,
+	"SlowDown":                   common.ErrThrottled,
+	"Throttling":                 common.ErrThrottled,
+	"ThrottlingException":        common.ErrThrottled,
+	"RequestLimitExceeded":       common.ErrThrottled,
+	"RequestTimeout":             common.ErrTimeout,
+}
+
+// classifyError marks err with the kind of failure reported by S3.
+func classifyError(err error) error {
+	if err == nil || common.ErrorKind(err) != nil {
+		return err
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if kind, ok := errorCodeKinds[apiErr.ErrorCode()]; ok {
+			return common.NewError(kind, err)
+		}
+	}
+
+	var respErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &respErr) {
+		return common.ErrorFromHTTPStatus(respErr.HTTPStatusCode(), err)
+	}
+	return err
+}
+
+// isPreconditionFailed reports whether err is a failed If-Match or
+// If-None-Match condition, which retrying cannot fix.
+func isPreconditionFailed(err error) bool {
+	return errors.Is(classifyError(err), common.ErrPreconditionFailed)
+}
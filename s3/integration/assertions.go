@@ -243,7 +243,7 @@ func AssertOnBulkOperations(s3CLIPath string, cfg *config.S3Cli) {
 	output = strings.TrimSpace(string(s3CLISession.Out.Contents()))
 	Expect(strings.Split(output, "\n")).To(HaveLen(3))
 
-	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "delete-recursive")
+	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "delete-recursive", "--all")
 	Expect(err).ToNot(HaveOccurred())
 	Expect(s3CLISession.ExitCode()).To(BeZero())
 
@@ -321,7 +321,7 @@ func AssertOnPutFailures(cfg *config.S3Cli, content, errorMessage string) {
 	}
 	blobstoreClient := client.New(s3Client, &s3Config)
 
-	err = blobstoreClient.Put(sourceFile, s3Filename)
+	err = blobstoreClient.Put(context.Background(), sourceFile, s3Filename)
 	Expect(err).To(HaveOccurred())
 	Expect(err.Error()).To(ContainSubstring(errorMessage))
 }
@@ -418,7 +418,7 @@ func AssertOnMultipartUploads(s3CLIPath string, cfg *config.S3Cli, content strin
 
 	blobstoreClient := client.New(s3Client, &s3Config)
 
-	err = blobstoreClient.Put(sourceFile, s3Filename)
+	err = blobstoreClient.Put(context.Background(), sourceFile, s3Filename)
 	Expect(err).ToNot(HaveOccurred())
 
 	switch config.Provider(cfg.Host) {
@@ -467,7 +467,7 @@ func AssertOnSignedURLs(s3CLIPath string, cfg *config.S3Cli) {
 	regex := `(?m)((([A-Za-z]{3,9}:(?:\/\/?)?)(?:[-;:&=\+\$,\w]+@)?[A-Za-z0-9.-]+(:[0-9]+)?|(?:www.|[-;:&=\+\$,\w]+@)[A-Za-z0-9.-]+)((?:\/[\+~%\/.\w-_]*)?\??(?:[-\+=&;%@.\w_]*)#?(?:[\w]*))?)`
 
 	// Test GET signed URL
-	getURL, err := blobstoreClient.Sign(s3Filename, "get", 1*time.Minute)
+	getURL, err := blobstoreClient.Sign(context.Background(), s3Filename, "get", 1*time.Minute)
 	Expect(err).ToNot(HaveOccurred())
 	Expect(getURL).To(MatchRegexp(regex))
 
@@ -482,7 +482,7 @@ func AssertOnSignedURLs(s3CLIPath string, cfg *config.S3Cli) {
 	Expect(string(body)).To(Equal(expectedContent))
 
 	// Test PUT signed URL
-	putURL, err := blobstoreClient.Sign(s3Filename+"_put_test", "put", 1*time.Minute)
+	putURL, err := blobstoreClient.Sign(context.Background(), s3Filename+"_put_test", "put", 1*time.Minute)
 	Expect(err).ToNot(HaveOccurred())
 	Expect(putURL).To(MatchRegexp(regex))
 
@@ -535,7 +535,7 @@ func AssertSinglePartUploadWorks(s3CLIPath string, cfg *config.S3Cli) {
 
 	blobstoreClient := client.New(s3Client, &s3Config)
 
-	err = blobstoreClient.Put(sourceFile, s3Filename)
+	err = blobstoreClient.Put(context.Background(), sourceFile, s3Filename)
 	Expect(err).ToNot(HaveOccurred())
 
 	// A single PutObject call is the fingerprint of PutSinglePart
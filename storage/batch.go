@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const defaultBatchConcurrency = 1
+
+// Operations are single JSON lines; allow generous argument lists without
+// letting a corrupt input exhaust memory.
+const maxBatchLineSize = 1024 * 1024
+
+// batchOperation is a single line of batch input.
+type batchOperation struct {
+	// ID is echoed in the result so callers can correlate results, which are
+	// emitted in completion order.
+	ID   json.RawMessage `json:"id,omitempty"`
+	Cmd  string          `json:"cmd"`
+	Args []string        `json:"args"`
+}
+
+// batchResult is a single line of batch output.
+type batchResult struct {
+	Line   int             `json:"line"`
+	ID     json.RawMessage `json:"id,omitempty"`
+	Cmd    string          `json:"cmd,omitempty"`
+	OK     bool            `json:"ok"`
+	Output string          `json:"output,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// runBatch executes the JSONL operations read from in against sty's storage,
+// at most concurrency at a time, and writes one JSON result line per
+// operation to out. A failed operation does not stop the batch; the returned
+// error reports how many operations failed.
+func (sty *CommandExecuter) runBatch(ctx context.Context, in io.Reader, out io.Writer, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	type job struct {
+		line int
+		raw  []byte
+	}
+
+	var (
+		encMu   sync.Mutex
+		enc     = json.NewEncoder(out)
+		total   int
+		failed  int
+		encErr  error
+		jobs    = make(chan job)
+		workers = &sync.WaitGroup{}
+	)
+
+	for range concurrency {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for j := range jobs {
+				result := sty.runBatchOperation(ctx, j.line, j.raw)
+
+				encMu.Lock()
+				if !result.OK {
+					failed++
+				}
+				if err := enc.Encode(result); err != nil && encErr == nil {
+					encErr = err
+				}
+				encMu.Unlock()
+			}
+		}()
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBatchLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		total++
+		jobs <- job{line: line, raw: append([]byte(nil), raw...)}
+	}
+	close(jobs)
+	workers.Wait()
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading batch input after line %d: %w", line, err)
+	}
+	if encErr != nil {
+		return fmt.Errorf("writing batch results: %w", encErr)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d batch operations failed", failed, total)
+	}
+	return nil
+}
+
+func (sty *CommandExecuter) runBatchOperation(ctx context.Context, line int, raw []byte) batchResult {
+	result := batchResult{Line: line}
+
+	var op batchOperation
+	if err := json.Unmarshal(raw, &op); err != nil {
+		result.Error = fmt.Sprintf("invalid operation: %v", err)
+		return result
+	}
+	result.ID, result.Cmd = op.ID, op.Cmd
+
+	switch op.Cmd {
+	case "":
+		result.Error = "invalid operation: missing cmd"
+		return result
+	case "batch":
+		result.Error = "batch operations cannot be nested"
+		return result
+	}
+
+	start := time.Now()
+	output, err := sty.executeBuffered(ctx, op.Cmd, op.Args)
+	slog.Debug("Executed batch operation", "line", line, "command", op.Cmd, "duration", time.Since(start), "error", err)
+
+	result.Output = output
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.OK = true
+	return result
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("batch", func() {
+	var (
+		fakeStorager    *FakeStorager
+		commandExecuter *CommandExecuter
+		out             *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		commandExecuter = &CommandExecuter{str: fakeStorager}
+		out = &bytes.Buffer{}
+	})
+
+	decodeResults := func() map[int]batchResult {
+		results := map[int]batchResult{}
+		decoder := json.NewDecoder(out)
+		for decoder.More() {
+			var result batchResult
+			Expect(decoder.Decode(&result)).To(Succeed())
+			results[result.Line] = result
+		}
+		return results
+	}
+
+	It("executes every operation and emits one result per line", func() {
+		fakeStorager.ListWithOptionsReturns(ListResult{Objects: []ObjectInfo{{Name: "a"}, {Name: "b"}}}, nil)
+		input := strings.Join([]string{
+			`{"id":"first","cmd":"delete","args":["object"]}`,
+			``,
+			`{"id":2,"cmd":"list","args":["prefix"]}`,
+		}, "\n")
+
+		Expect(commandExecuter.runBatch(context.Background(), strings.NewReader(input), out, 1)).To(Succeed())
+
+		results := decodeResults()
+		Expect(results).To(HaveLen(2))
+		Expect(results[1]).To(Equal(batchResult{Line: 1, ID: json.RawMessage(`"first"`), Cmd: "delete", OK: true}))
+		Expect(results[3]).To(Equal(batchResult{Line: 3, ID: json.RawMessage(`2`), Cmd: "list", OK: true, Output: "a\nb\n"}))
+		_, arg := fakeStorager.DeleteArgsForCall(0)
+		Expect(arg).To(Equal("object"))
+	})
+
+	It("does not abort the batch on failed operations", func() {
+		fakeStorager.DeleteStub = func(_ context.Context, name string) error {
+			if name == "bad" {
+				return errors.New("boom")
+			}
+			return nil
+		}
+		input := strings.Join([]string{
+			`{"cmd":"delete","args":["bad"]}`,
+			`not json`,
+			`{"args":["x"]}`,
+			`{"cmd":"batch","args":[]}`,
+			`{"cmd":"delete","args":["good"]}`,
+			`{"cmd":"put","args":["-","object"]}`,
+		}, "\n")
+
+		err := commandExecuter.runBatch(context.Background(), strings.NewReader(input), out, 1)
+		Expect(err).To(MatchError("5 of 6 batch operations failed"))
+
+		results := decodeResults()
+		Expect(results[1].Error).To(Equal("boom"))
+		Expect(results[2].Error).To(ContainSubstring("invalid operation"))
+		Expect(results[3].Error).To(Equal("invalid operation: missing cmd"))
+		Expect(results[4].Error).To(Equal("batch operations cannot be nested"))
+		Expect(results[5].OK).To(BeTrue())
+		Expect(results[6].Error).To(Equal("put cannot stream from stdin or to stdout here"))
+		Expect(fakeStorager.PutStreamCallCount()).To(Equal(0))
+		Expect(fakeStorager.DeleteCallCount()).To(Equal(2))
+	})
+
+	It("runs operations concurrently", func() {
+		var running, maxRunning atomic.Int32
+		fakeStorager.DeleteStub = func(context.Context, string) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		}
+		input := strings.Repeat(`{"cmd":"delete","args":["object"]}`+"\n", 6)
+
+		Expect(commandExecuter.runBatch(context.Background(), strings.NewReader(input), out, 3)).To(Succeed())
+		Expect(decodeResults()).To(HaveLen(6))
+		Expect(maxRunning.Load()).To(BeNumerically(">", 1))
+		Expect(maxRunning.Load()).To(BeNumerically("<=", 3))
+	})
+
+	It("renders properties", func() {
+		modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		fakeStorager.PropertiesReturnsOnCall(0, Properties{ContentLength: 42, ETag: "etag", LastModified: modified}, nil)
+		fakeStorager.PropertiesReturnsOnCall(1, Properties{}, common.NewError(common.ErrNotFound, errors.New("missing")))
+		input := `{"cmd":"properties","args":["object"]}` + "\n" + `{"cmd":"properties","args":["missing"]}`
+
+		Expect(commandExecuter.runBatch(context.Background(), strings.NewReader(input), out, 1)).To(Succeed())
+
+		results := decodeResults()
+		Expect(results[1].Output).To(MatchJSON(`{"etag":"etag","last_modified":"2024-01-02T03:04:05Z","content_length":42}`))
+		Expect(results[2].Output).To(MatchJSON(`{}`))
+		Expect(fakeStorager.PropertiesCallCount()).To(Equal(2))
+	})
+})
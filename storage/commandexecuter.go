@@ -1,10 +1,18 @@
 package storage
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type NotExistsError struct{}
@@ -13,8 +21,28 @@ func (e *NotExistsError) Error() string {
 	return "object does not exist"
 }
 
+// Is makes a NotExistsError match common.ErrNotFound.
+func (e *NotExistsError) Is(target error) bool {
+	return target == common.ErrNotFound
+}
+
+// stdioPath as the local path of put or get streams from stdin or to stdout.
+const stdioPath = "-"
+
 type CommandExecuter struct {
 	str Storager
+	// output is the format of results and failures, OutputText if empty.
+	output string
+	// in is read by put with stdioPath. Defaults to os.Stdin.
+	in io.Reader
+	// out receives the command output. Defaults to os.Stdout.
+	out io.Writer
+	// errOut receives notes about the output, such as the token of the next
+	// page of a listing. Defaults to os.Stderr.
+	errOut io.Writer
+	// newStorageClient creates the additional clients needed by commands
+	// spanning two storages, such as transfer. Defaults to NewStorageClient.
+	newStorageClient func(storageType string, configFile *os.File) (Storager, error)
 }
 
 func NewCommandExecuter(s Storager) *CommandExecuter {
@@ -25,27 +53,33 @@ func (sty *CommandExecuter) SetStorager(s Storager) {
 	sty.str = s
 }
 
-func (sty *CommandExecuter) Execute(cmd string, nonFlagArgs []string) error {
+// Execute runs cmd and prints its result to stdout. With JSON output, a
+// failure is printed as well, except for a missing object checked by exists,
+// for get streaming the object to stdout, for delete-many and
+// delete-recursive, whose results list the failed keys, and for transfer,
+// whose summary counts the failed objects.
+func (sty *CommandExecuter) Execute(ctx context.Context, cmd string, nonFlagArgs []string) error {
+	err := sty.execute(ctx, cmd, nonFlagArgs)
+
+	var (
+		notExists   *NotExistsError
+		deleteErr   *deleteFailuresError
+		transferErr *transferFailuresError
+	)
+	if err != nil && !(cmd == "exists" && errors.As(err, &notExists)) && !(cmd == "get" && usesStdio(cmd, nonFlagArgs)) && !errors.As(err, &deleteErr) && !errors.As(err, &transferErr) {
+		sty.WriteError(err)
+	}
+	return err
+}
+
+func (sty *CommandExecuter) execute(ctx context.Context, cmd string, nonFlagArgs []string) error {
 
 	switch cmd {
 	case "put":
-		if len(nonFlagArgs) != 2 {
-			return fmt.Errorf("put method expected 2 arguments got %d", len(nonFlagArgs))
-		}
-		sourceFilePath, dst := nonFlagArgs[0], nonFlagArgs[1]
-
-		_, err := os.Stat(sourceFilePath)
-		if err != nil {
-			return fmt.Errorf("%w", err)
-		}
-		return sty.str.Put(sourceFilePath, dst)
+		return sty.put(ctx, nonFlagArgs)
 
 	case "get":
-		if len(nonFlagArgs) != 2 {
-			return fmt.Errorf("get method expected 2 arguments got %d", len(nonFlagArgs))
-		}
-		src, dst := nonFlagArgs[0], nonFlagArgs[1]
-		return sty.str.Get(src, dst)
+		return sty.get(ctx, nonFlagArgs)
 
 	case "copy":
 		if len(nonFlagArgs) != 2 {
@@ -53,37 +87,102 @@ func (sty *CommandExecuter) Execute(cmd string, nonFlagArgs []string) error {
 		}
 
 		srcBlob, dstBlob := nonFlagArgs[0], nonFlagArgs[1]
-		return sty.str.Copy(srcBlob, dstBlob)
+		start := time.Now()
+		if err := sty.str.Copy(ctx, srcBlob, dstBlob); err != nil {
+			return err
+		}
+		if !sty.jsonOutput() {
+			return nil
+		}
+		// Backends copy server side without reporting a size.
+		info, _, err := sty.str.Stat(ctx, dstBlob)
+		if err != nil {
+			return fmt.Errorf("reading size of copied object: %w", err)
+		}
+		return sty.writeTransferred(info.Size, start)
+
+	case "move":
+		if len(nonFlagArgs) != 2 {
+			return fmt.Errorf("move method expected 2 arguments got %d", len(nonFlagArgs))
+		}
+		if err := sty.str.Move(ctx, nonFlagArgs[0], nonFlagArgs[1]); err != nil {
+			return err
+		}
+		return sty.writeDone()
 
 	case "delete":
-		if len(nonFlagArgs) != 1 {
-			return fmt.Errorf("delete method expected 1 argument got %d", len(nonFlagArgs))
+		var opts DeleteOptions
+		flags := flag.NewFlagSet("delete", flag.ContinueOnError)
+		flags.StringVar(&opts.IfMatch, "if-match", "", "only delete the object if its ETag matches")
+		if err := flags.Parse(nonFlagArgs); err != nil {
+			return err
 		}
-		return sty.str.Delete(nonFlagArgs[0])
+
+		args := flags.Args()
+		if len(args) != 1 {
+			return fmt.Errorf("delete method expected 1 argument got %d", len(args))
+		}
+		var err error
+		if opts.IfMatch != "" {
+			err = sty.str.DeleteWithOptions(ctx, args[0], opts)
+		} else {
+			err = sty.str.Delete(ctx, args[0])
+		}
+		if err != nil {
+			return err
+		}
+		return sty.writeDone()
 
 	case "delete-recursive":
-		var prefix string
+		flags := flag.NewFlagSet("delete-recursive", flag.ContinueOnError)
+		all := flags.Bool("all", false, "allow deleting every object when no prefix is given")
+		dryRun := flags.Bool("dry-run", false, "only print the objects that would be deleted")
+		maxObjects := flags.Int("max-objects", 0, "abort without deleting anything if more objects match (0 means no limit)")
+		if err := flags.Parse(nonFlagArgs); err != nil {
+			return err
+		}
+
+		args := flags.Args()
+		if len(args) > 1 {
+			return fmt.Errorf("delete-recursive takes at most 1 argument (prefix) got %d", len(args))
+		}
+		if *maxObjects < 0 {
+			return fmt.Errorf("--max-objects must not be negative, got %d", *maxObjects)
+		}
+
+		opts := deleteRecursiveOptions{all: *all, dryRun: *dryRun, maxObjects: *maxObjects}
+		if len(args) == 1 {
+			opts.prefix = args[0]
+		}
+		return sty.deleteRecursive(ctx, opts)
+
+	case "delete-many":
 		if len(nonFlagArgs) > 1 {
-			return fmt.Errorf("delete-recursive takes at most 1 argument (prefix) got %d", len(nonFlagArgs))
+			return fmt.Errorf("delete-many method takes at most 1 argument (file) got %d", len(nonFlagArgs))
 		}
+		var path string
 		if len(nonFlagArgs) == 1 {
-			prefix = nonFlagArgs[0]
+			path = nonFlagArgs[0]
 		}
-
-		return sty.str.DeleteRecursive(prefix)
+		return sty.deleteMany(ctx, path)
 
 	case "exists":
 		if len(nonFlagArgs) != 1 {
 			return fmt.Errorf("exists method expected 1 argument got %d", len(nonFlagArgs))
 		}
 
-		exists, err := sty.str.Exists(nonFlagArgs[0])
-		if err == nil && !exists {
-			return &NotExistsError{}
-		}
+		exists, err := sty.str.Exists(ctx, nonFlagArgs[0])
 		if err != nil {
 			return fmt.Errorf("failed to check exist: %w", err)
 		}
+		if sty.jsonOutput() {
+			if err := sty.writeJSON(existsResult{Exists: exists}); err != nil {
+				return err
+			}
+		}
+		if !exists {
+			return &NotExistsError{}
+		}
 
 	case "sign":
 		if len(nonFlagArgs) != 3 {
@@ -101,11 +200,11 @@ func (sty *CommandExecuter) Execute(cmd string, nonFlagArgs []string) error {
 			return fmt.Errorf("expiration should be in the format of a duration i.e. 1h, 60m, 3600s. Got: %s", nonFlagArgs[2])
 		}
 
-		signedURL, err := sty.str.Sign(objectID, action, expiration)
+		signedURL, err := sty.str.Sign(ctx, objectID, action, expiration)
 		if err != nil {
 			return fmt.Errorf("failed to sign request: %w", err)
 		}
-		fmt.Print(signedURL)
+		return sty.writeSigned(signedURL, expiration)
 
 	case "sign-internal", "sign-public":
 		if len(nonFlagArgs) != 3 {
@@ -124,7 +223,7 @@ func (sty *CommandExecuter) Execute(cmd string, nonFlagArgs []string) error {
 		}
 
 		// DAV-specific: type-assert rather than pollute the cross-backend Storager interface.
-		dualSigner, ok := sty.str.(interface {
+		dualSigner, ok := unwrap(sty.str).(interface {
 			SignInternal(string, string, time.Duration) (string, error)
 			SignPublic(string, string, time.Duration) (string, error)
 		})
@@ -141,38 +240,175 @@ func (sty *CommandExecuter) Execute(cmd string, nonFlagArgs []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to %s request: %w", cmd, err)
 		}
-		fmt.Print(signedURL)
+		return sty.writeSigned(signedURL, expiration)
 
 	case "list":
-		var prefix string
-		if len(nonFlagArgs) > 1 {
-			return fmt.Errorf("list method takes at most 1 argument (prefix) got %d", len(nonFlagArgs))
+		flags := flag.NewFlagSet("list", flag.ContinueOnError)
+		long := flags.Bool("long", false, "print the size, last modification time, ETag and storage class of each object")
+		delimiter := flags.String("delimiter", "", "group the objects whose name contains the delimiter after the prefix into common prefixes")
+		maxKeys := flags.Int("max-keys", 0, "list a single page of at most this many objects and prefixes, followed by the token of the next page")
+		startAfter := flags.String("start-after", "", "list the names after this one")
+		pageToken := flags.String("page-token", "", "continue a listing from the token printed with its previous page")
+		if err := flags.Parse(nonFlagArgs); err != nil {
+			return err
 		}
-		if len(nonFlagArgs) == 1 {
-			prefix = nonFlagArgs[0]
+		if *maxKeys < 0 {
+			return fmt.Errorf("--max-keys must not be negative, got %d", *maxKeys)
 		}
 
-		var objects []string
-		objects, err := sty.str.List(prefix)
-		if err != nil {
-			return fmt.Errorf("failed to list objects: %w", err)
+		args := flags.Args()
+		var prefix string
+		if len(args) > 1 {
+			return fmt.Errorf("list method takes at most 1 argument (prefix) got %d", len(args))
 		}
-
-		for _, object := range objects {
-			fmt.Println(object)
+		if len(args) == 1 {
+			prefix = args[0]
 		}
+		opts := ListOptions{Prefix: prefix, Delimiter: *delimiter, MaxKeys: *maxKeys, StartAfter: *startAfter, PageToken: *pageToken}
+		return sty.listPages(ctx, opts, *long)
+
+	case "verify":
+		return sty.verify(ctx, nonFlagArgs)
 
 	case "properties":
 		if len(nonFlagArgs) != 1 {
 			return fmt.Errorf("properties method expected 1 argument got %d", len(nonFlagArgs))
 		}
-		return sty.str.Properties(nonFlagArgs[0])
+		return sty.properties(ctx, nonFlagArgs[0])
 
 	case "ensure-storage-exists":
 		if len(nonFlagArgs) != 0 {
 			return fmt.Errorf("ensureStorageExists method expected 0 argument got %d", len(nonFlagArgs))
 		}
-		return sty.str.EnsureStorageExists()
+		if err := sty.str.EnsureStorageExists(ctx); err != nil {
+			return err
+		}
+		return sty.writeDone()
+
+	case "transfer":
+		flags := flag.NewFlagSet("transfer", flag.ContinueOnError)
+		toStorageType := flags.String("to-s", "", "destination storage type: azurebs|alioss|s3|gcs|dav|local")
+		toConfigPath := flags.String("to-c", "", "destination configuration path")
+		parallel := flags.Int("parallel", defaultTransferParallelism, "number of objects transferred concurrently")
+		sizeOnly := flags.Bool("size-only", false, "skip objects whose size matches at the destination, without comparing ETags or checksums")
+		if err := flags.Parse(nonFlagArgs); err != nil {
+			return err
+		}
+
+		args := flags.Args()
+		if len(args) > 1 {
+			return fmt.Errorf("transfer method takes at most 1 argument (prefix) got %d", len(args))
+		}
+		if *toStorageType == "" || *toConfigPath == "" {
+			return errors.New("transfer method requires --to-s and --to-c for the destination storage")
+		}
+		if *parallel < 1 {
+			return fmt.Errorf("--parallel must be at least 1, got %d", *parallel)
+		}
+
+		opts := transferOptions{parallel: *parallel, sizeOnly: *sizeOnly}
+		if len(args) == 1 {
+			opts.prefix = args[0]
+		}
+
+		dst, err := sty.openStorager(*toStorageType, *toConfigPath)
+		if err != nil {
+			return fmt.Errorf("failed to create destination client: %w", err)
+		}
+
+		summary, err := transfer(ctx, sty.str, dst, opts)
+		if sty.jsonOutput() {
+			var failures *transferFailuresError
+			if err != nil && !errors.As(err, &failures) {
+				return err
+			}
+			if err := sty.writeJSON(summary); err != nil {
+				return err
+			}
+			return err
+		}
+		fmt.Fprintln(sty.stdout(), summary)
+		return err
+
+	case "sync":
+		if len(nonFlagArgs) < 1 || (nonFlagArgs[0] != "up" && nonFlagArgs[0] != "down") {
+			return errors.New("sync method expects a direction: 'up' or 'down'")
+		}
+		direction := nonFlagArgs[0]
+
+		flags := flag.NewFlagSet("sync "+direction, flag.ContinueOnError)
+		deleteExtraneous := flags.Bool("delete", false, "delete entries at the destination that do not exist at the source")
+		dryRun := flags.Bool("dry-run", false, "only report the planned actions")
+		parallel := flags.Int("parallel", defaultTransferParallelism, "number of files transferred concurrently")
+		if err := flags.Parse(nonFlagArgs[1:]); err != nil {
+			return err
+		}
+
+		args := flags.Args()
+		if len(args) != 2 {
+			return fmt.Errorf("sync %s method expected 2 arguments got %d", direction, len(args))
+		}
+		if *parallel < 1 {
+			return fmt.Errorf("--parallel must be at least 1, got %d", *parallel)
+		}
+
+		opts := syncOptions{delete: *deleteExtraneous, dryRun: *dryRun, parallel: *parallel}
+		var (
+			actions []syncAction
+			summary syncSummary
+			err     error
+		)
+		if direction == "up" {
+			actions, summary, err = syncUp(ctx, sty.str, args[0], args[1], opts)
+		} else {
+			actions, summary, err = syncDown(ctx, sty.str, args[0], args[1], opts)
+		}
+
+		if sty.jsonOutput() {
+			if err != nil {
+				return err
+			}
+			if actions == nil {
+				actions = []syncAction{}
+			}
+			return sty.writeJSON(syncResult{DryRun: opts.dryRun, Actions: actions, Summary: summary})
+		}
+
+		for _, action := range actions {
+			if opts.dryRun {
+				fmt.Fprintln(sty.stdout(), "(dry-run)", action)
+			} else {
+				fmt.Fprintln(sty.stdout(), action)
+			}
+		}
+		fmt.Fprintln(sty.stdout(), summary)
+		return err
+
+	case "batch":
+		flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+		concurrency := flags.Int("concurrency", defaultBatchConcurrency, "number of operations executed concurrently")
+		if err := flags.Parse(nonFlagArgs); err != nil {
+			return err
+		}
+
+		args := flags.Args()
+		if len(args) > 1 {
+			return fmt.Errorf("batch method takes at most 1 argument (file) got %d", len(args))
+		}
+		if *concurrency < 1 {
+			return fmt.Errorf("--concurrency must be at least 1, got %d", *concurrency)
+		}
+
+		var in io.Reader = sty.stdin()
+		if len(args) == 1 && args[0] != stdioPath {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open batch file: %w", err)
+			}
+			defer file.Close() //nolint:errcheck
+			in = file
+		}
+		return sty.runBatch(ctx, in, sty.stdout(), *concurrency)
 
 	default:
 		return fmt.Errorf("unknown command: '%s'", cmd)
@@ -180,3 +416,105 @@ func (sty *CommandExecuter) Execute(cmd string, nonFlagArgs []string) error {
 
 	return nil
 }
+
+func (sty *CommandExecuter) stdout() io.Writer {
+	if sty.out == nil {
+		return os.Stdout
+	}
+	return sty.out
+}
+
+func (sty *CommandExecuter) stderr() io.Writer {
+	if sty.errOut == nil {
+		return os.Stderr
+	}
+	return sty.errOut
+}
+
+func (sty *CommandExecuter) stdin() io.Reader {
+	if sty.in == nil {
+		return os.Stdin
+	}
+	return sty.in
+}
+
+func (sty *CommandExecuter) writeSigned(signedURL string, expiration time.Duration) error {
+	if sty.jsonOutput() {
+		return sty.writeJSON(signResult{URL: signedURL, ExpiresAt: time.Now().Add(expiration).UTC().Truncate(time.Second)})
+	}
+	_, err := fmt.Fprint(sty.stdout(), signedURL)
+	return err
+}
+
+func (sty *CommandExecuter) openStorager(storageType string, configPath string) (Storager, error) {
+	configFile, err := os.Open(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer configFile.Close() //nolint:errcheck
+
+	newStorageClient := sty.newStorageClient
+	if newStorageClient == nil {
+		newStorageClient = NewStorageClient
+	}
+	return newStorageClient(storageType, configFile)
+}
+
+// usesStdio reports whether put or delete-many reads stdin or get writes
+// stdout. The source and destination of put and get are the last two
+// arguments, after any flags.
+func usesStdio(cmd string, args []string) bool {
+	n := len(args)
+	if cmd == "delete-many" {
+		return n == 0 || args[n-1] == stdioPath
+	}
+	return n >= 2 && ((cmd == "put" && args[n-2] == stdioPath) || (cmd == "get" && args[n-1] == stdioPath))
+}
+
+// unwrap returns the backend below the wrappers around str, such as
+// client-side encryption, for commands only some backends implement.
+func unwrap(str Storager) Storager {
+	for {
+		wrapper, ok := str.(interface{ Unwrap() Storager })
+		if !ok {
+			return str
+		}
+		str = wrapper.Unwrap()
+	}
+}
+
+// executeBuffered executes cmd like Execute, but returns its output instead
+// of writing it to stdout, so several commands can share one client.
+func (sty *CommandExecuter) executeBuffered(ctx context.Context, cmd string, args []string) (string, error) {
+	if usesStdio(cmd, args) {
+		return "", fmt.Errorf("%s cannot stream from stdin or to stdout here", cmd)
+	}
+
+	var output bytes.Buffer
+	executer := &CommandExecuter{str: sty.str, out: &output, newStorageClient: sty.newStorageClient}
+	err := executer.Execute(ctx, cmd, args)
+	return output.String(), err
+}
+
+// properties prints the properties of dest as JSON, or `{}` if it does not
+// exist, as the command always did.
+func (sty *CommandExecuter) properties(ctx context.Context, dest string) error {
+	props, err := sty.str.Properties(ctx, dest)
+	if errors.Is(err, common.ErrNotFound) {
+		return sty.writeJSON(struct{}{})
+	}
+	if err != nil {
+		return err
+	}
+
+	result := newPropertiesResult(props)
+	if sty.jsonOutput() {
+		return sty.writeJSON(result)
+	}
+	output, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal blob properties: %w", err)
+	}
+	_, err = fmt.Fprintln(sty.stdout(), string(output))
+	return err
+}
@@ -1,9 +1,17 @@
 package storage
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -27,19 +35,36 @@ var _ = Describe("Execute Command", func() {
 			DeferCleanup(func() {
 				os.Remove(tempFile.Name()) //nolint:errcheck
 			})
-			err := commandExecuter.Execute("put", []string{tempFile.Name(), "destination"})
+			err := commandExecuter.Execute(context.Background(), "put", []string{tempFile.Name(), "destination"})
 			Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
+		It("From stdin", func() {
+			commandExecuter.in = strings.NewReader("piped content")
+			var uploaded string
+			fakeStorager.PutStreamStub = func(_ context.Context, r io.Reader, _ string) error {
+				content, err := io.ReadAll(r)
+				uploaded = string(content)
+				return err
+			}
+
+			err := commandExecuter.Execute(context.Background(), "put", []string{"-", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(0))
+			_, _, dest := fakeStorager.PutStreamArgsForCall(0)
+			Expect(dest).To(Equal("destination"))
+			Expect(uploaded).To(Equal("piped content"))
+		})
+
 		It("No Source File", func() {
-			err := commandExecuter.Execute("put", []string{"source", "destination"})
+			err := commandExecuter.Execute(context.Background(), "put", []string{"source", "destination"})
 			Expect(errors.Unwrap(err).Error()).To(ContainSubstring("no such file or directory"))
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("put", []string{"source"})
+			err := commandExecuter.Execute(context.Background(), "put", []string{"source"})
 			Expect(err.Error()).To(ContainSubstring("put method expected 2 arguments got"))
 		})
 
@@ -47,14 +72,82 @@ var _ = Describe("Execute Command", func() {
 
 	Context("Get", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("get", []string{"source", "destination"})
+			err := commandExecuter.Execute(context.Background(), "get", []string{"source", "destination"})
 			Expect(fakeStorager.GetCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
+		It("To stdout", func() {
+			output := &strings.Builder{}
+			commandExecuter.out = output
+			fakeStorager.GetStreamReturns(io.NopCloser(strings.NewReader("object content")), nil)
+
+			err := commandExecuter.Execute(context.Background(), "get", []string{"source", "-"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.GetCallCount()).To(BeEquivalentTo(0))
+			_, arg := fakeStorager.GetStreamArgsForCall(0)
+			Expect(arg).To(Equal("source"))
+			Expect(output.String()).To(Equal("object content"))
+		})
+
+		It("With --if-match", func() {
+			err := commandExecuter.Execute(context.Background(), "get", []string{"--if-match", "abc", "source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.GetCallCount()).To(Equal(0))
+			_, src, dst, opts := fakeStorager.GetWithOptionsArgsForCall(0)
+			Expect(src).To(Equal("source"))
+			Expect(dst).To(Equal("destination"))
+			Expect(opts).To(Equal(GetOptions{IfMatch: "abc"}))
+		})
+
+		It("To stdout with --if-match", func() {
+			commandExecuter.out = &strings.Builder{}
+			fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(strings.NewReader("object content")), nil)
+
+			err := commandExecuter.Execute(context.Background(), "get", []string{"--if-match", "abc", "source", "-"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.GetStreamCallCount()).To(Equal(0))
+			_, _, opts := fakeStorager.GetStreamWithOptionsArgsForCall(0)
+			Expect(opts).To(Equal(GetOptions{IfMatch: "abc"}))
+		})
+
+		It("To stdout with --range", func() {
+			output := &strings.Builder{}
+			commandExecuter.out = output
+			fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(strings.NewReader("tail")), nil)
+
+			err := commandExecuter.Execute(context.Background(), "get", []string{"--range", "-22", "archive.zip", "-"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.GetStreamCallCount()).To(Equal(0))
+			_, src, opts := fakeStorager.GetStreamWithOptionsArgsForCall(0)
+			Expect(src).To(Equal("archive.zip"))
+			Expect(opts).To(Equal(GetOptions{Range: &ByteRange{Start: -22, End: -1}}))
+			Expect(output.String()).To(Equal("tail"))
+		})
+
+		It("With --range and --if-match", func() {
+			err := commandExecuter.Execute(context.Background(), "get", []string{"--range", "0-511", "--if-match", "abc", "source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+			_, _, _, opts := fakeStorager.GetWithOptionsArgsForCall(0)
+			Expect(opts).To(Equal(GetOptions{IfMatch: "abc", Range: &ByteRange{Start: 0, End: 511}}))
+		})
+
+		It("With an invalid --range", func() {
+			err := commandExecuter.Execute(context.Background(), "get", []string{"--range", "10-5", "source", "destination"})
+			Expect(err).To(MatchError(ContainSubstring(`invalid range "10-5"`)))
+			Expect(fakeStorager.GetWithOptionsCallCount()).To(Equal(0))
+		})
+
+		It("To stdout fails", func() {
+			fakeStorager.GetStreamReturns(nil, errors.New("boom"))
+
+			err := commandExecuter.Execute(context.Background(), "get", []string{"source", "-"})
+			Expect(err).To(MatchError("boom"))
+		})
+
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("get", []string{"source"})
+			err := commandExecuter.Execute(context.Background(), "get", []string{"source"})
 			Expect(err.Error()).To(ContainSubstring("get method expected 2 arguments got"))
 		})
 
@@ -62,53 +155,93 @@ var _ = Describe("Execute Command", func() {
 
 	Context("Copy", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("copy", []string{"source", "destination"})
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"source", "destination"})
 			Expect(fakeStorager.CopyCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("copy", []string{"source"})
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"source"})
 			Expect(err.Error()).To(ContainSubstring("copy method expected 2 arguments got"))
 		})
 
 	})
 
+	Context("Move", func() {
+		It("Successfull", func() {
+			err := commandExecuter.Execute(context.Background(), "move", []string{"source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.MoveCallCount()).To(Equal(1))
+			_, src, dst := fakeStorager.MoveArgsForCall(0)
+			Expect(src).To(Equal("source"))
+			Expect(dst).To(Equal("destination"))
+		})
+
+		It("Wrong number of parameters", func() {
+			err := commandExecuter.Execute(context.Background(), "move", []string{"source"})
+			Expect(err.Error()).To(ContainSubstring("move method expected 2 arguments got"))
+		})
+	})
+
 	Context("Delete", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("delete", []string{"destination"})
+			err := commandExecuter.Execute(context.Background(), "delete", []string{"destination"})
 			Expect(fakeStorager.DeleteCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
+		It("With --if-match", func() {
+			fakeStorager.DeleteWithOptionsReturns(common.NewError(common.ErrPreconditionFailed, errors.New("ETag mismatch")))
+
+			err := commandExecuter.Execute(context.Background(), "delete", []string{"--if-match", "abc", "destination"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+			Expect(fakeStorager.DeleteCallCount()).To(Equal(0))
+			_, dest, opts := fakeStorager.DeleteWithOptionsArgsForCall(0)
+			Expect(dest).To(Equal("destination"))
+			Expect(opts).To(Equal(DeleteOptions{IfMatch: "abc"}))
+		})
+
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("delete", []string{})
+			err := commandExecuter.Execute(context.Background(), "delete", []string{})
 			Expect(err.Error()).To(ContainSubstring("delete method expected 1 argument got"))
 		})
 
 	})
 
 	Context("Delete-Recursive", func() {
+		It("Refuses to delete every object without --all", func() {
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{})
+			Expect(err).To(MatchError(ContainSubstring("pass --all to confirm")))
+			Expect(fakeStorager.ListWithOptionsCallCount()).To(Equal(0))
+			Expect(fakeStorager.DeleteManyCallCount()).To(Equal(0))
+		})
+
 		It("Successfull", func() {
-			err := commandExecuter.Execute("delete-recursive", []string{})
-			Expect(fakeStorager.DeleteRecursiveCallCount()).To(BeEquivalentTo(1))
-			Expect(fakeStorager.deleteRecursiveArgsForCall[0].arg1).To(Equal(""))
-			Expect(err).ToNot(HaveOccurred())
+			fakeStorager.ListWithOptionsReturns(ListResult{Objects: []ObjectInfo{{Name: "a"}, {Name: "b"}}}, nil)
 
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--all"})
+			Expect(err).ToNot(HaveOccurred())
+			_, opts := fakeStorager.ListWithOptionsArgsForCall(0)
+			Expect(opts.Prefix).To(Equal(""))
+			_, keys := fakeStorager.DeleteManyArgsForCall(0)
+			Expect(keys).To(Equal([]string{"a", "b"}))
 		})
 
 		It("Successfull With Prefix", func() {
-			err := commandExecuter.Execute("delete-recursive", []string{"prefix"})
-			Expect(fakeStorager.DeleteRecursiveCallCount()).To(BeEquivalentTo(1))
-			Expect(fakeStorager.deleteRecursiveArgsForCall[0].arg1).To(Equal("prefix"))
-			Expect(err).ToNot(HaveOccurred())
+			fakeStorager.ListWithOptionsReturns(ListResult{Objects: []ObjectInfo{{Name: "prefix/a"}}}, nil)
 
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"prefix"})
+			Expect(err).ToNot(HaveOccurred())
+			_, opts := fakeStorager.ListWithOptionsArgsForCall(0)
+			Expect(opts.Prefix).To(Equal("prefix"))
+			_, keys := fakeStorager.DeleteManyArgsForCall(0)
+			Expect(keys).To(Equal([]string{"prefix/a"}))
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("delete-recursive", []string{"prefix", "extra-prefix"})
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"prefix", "extra-prefix"})
 			Expect(err.Error()).To(ContainSubstring("delete-recursive takes at most 1 argument (prefix) got"))
 		})
 
@@ -116,10 +249,10 @@ var _ = Describe("Execute Command", func() {
 
 	Context("Exists", func() {
 		It("Successfull", func() {
-			fakeStorager.ExistsStub = func(file string) (bool, error) {
+			fakeStorager.ExistsStub = func(_ context.Context, file string) (bool, error) {
 				return true, nil
 			}
-			err := commandExecuter.Execute("exists", []string{"object"})
+			err := commandExecuter.Execute(context.Background(), "exists", []string{"object"})
 
 			Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
@@ -127,18 +260,18 @@ var _ = Describe("Execute Command", func() {
 		})
 
 		It("Not found", func() {
-			fakeStorager.ExistsStub = func(file string) (bool, error) {
+			fakeStorager.ExistsStub = func(_ context.Context, file string) (bool, error) {
 				return false, nil
 			}
 
-			err := commandExecuter.Execute("exists", []string{"object"})
+			err := commandExecuter.Execute(context.Background(), "exists", []string{"object"})
 			Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(1))
 			Expect(err).To(BeAssignableToTypeOf(&NotExistsError{}))
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("exists", []string{"object", "extra-object"})
+			err := commandExecuter.Execute(context.Background(), "exists", []string{"object", "extra-object"})
 			Expect(err.Error()).To(ContainSubstring("exists method expected 1 argument got"))
 		})
 
@@ -146,7 +279,7 @@ var _ = Describe("Execute Command", func() {
 
 	Context("Sign", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("sign", []string{"object", "put", "10s"})
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"object", "put", "10s"})
 
 			Expect(fakeStorager.SignCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
@@ -154,19 +287,19 @@ var _ = Describe("Execute Command", func() {
 		})
 
 		It("Wrong action", func() {
-			err := commandExecuter.Execute("sign", []string{"object", "delete", "10s"})
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"object", "delete", "10s"})
 			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("action not implemented: %s. Available actions are 'get' and 'put'", "delete")))
 
 		})
 
 		It("Wrong time format", func() {
-			err := commandExecuter.Execute("sign", []string{"object", "put", "10"})
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"object", "put", "10"})
 			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("expiration should be in the format of a duration i.e. 1h, 60m, 3600s. Got: %s", "10")))
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("sign", []string{"object", "put"})
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"object", "put"})
 			Expect(err.Error()).To(ContainSubstring("sign method expects 3 arguments got"))
 
 		})
@@ -175,39 +308,116 @@ var _ = Describe("Execute Command", func() {
 
 	Context("List", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("list", []string{})
+			err := commandExecuter.Execute(context.Background(), "list", []string{})
 
-			Expect(fakeStorager.ListCallCount()).To(BeEquivalentTo(1))
-			Expect(fakeStorager.listArgsForCall[0].arg1).To(Equal(""))
+			Expect(fakeStorager.ListWithOptionsCallCount()).To(BeEquivalentTo(1))
+			_, opts := fakeStorager.ListWithOptionsArgsForCall(0)
+			Expect(opts).To(Equal(ListOptions{MaxKeys: defaultListPageSize}))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("With Prefix", func() {
-			err := commandExecuter.Execute("exists", []string{"prefix"})
+			err := commandExecuter.Execute(context.Background(), "exists", []string{"prefix"})
 			Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(1))
-			Expect(fakeStorager.existsArgsForCall[0].arg1).To(Equal("prefix"))
+			Expect(fakeStorager.existsArgsForCall[0].arg2).To(Equal("prefix"))
 			Expect(err).To(BeAssignableToTypeOf(&NotExistsError{}))
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("list", []string{"prefix-1", "prefix-2"})
+			err := commandExecuter.Execute(context.Background(), "list", []string{"prefix-1", "prefix-2"})
 			Expect(err.Error()).To(ContainSubstring("list method takes at most 1 argument (prefix) got"))
 		})
 
+		It("prints metadata with --long", func() {
+			out := &bytes.Buffer{}
+			commandExecuter.out = out
+			fakeStorager.ListWithOptionsReturns(ListResult{Objects: []ObjectInfo{
+				{Name: "a", Size: 3, ETag: "abc", LastModified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), StorageClass: "STANDARD"},
+				{Name: "b"},
+			}}, nil)
+
+			err := commandExecuter.Execute(context.Background(), "list", []string{"--long", "prefix"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.ListCallCount()).To(BeZero())
+			_, opts := fakeStorager.ListWithOptionsArgsForCall(0)
+			Expect(opts.Prefix).To(Equal("prefix"))
+			Expect(out.String()).To(Equal("3\t2024-01-02T03:04:05Z\tabc\tSTANDARD\ta\n0\t-\t-\t-\tb\n"))
+		})
+
+		It("prints common prefixes before objects with --delimiter", func() {
+			out := &bytes.Buffer{}
+			commandExecuter.out = out
+			fakeStorager.ListWithOptionsReturns(ListResult{
+				Objects:  []ObjectInfo{{Name: "dir/a"}},
+				Prefixes: []string{"dir/sub/"},
+			}, nil)
+
+			err := commandExecuter.Execute(context.Background(), "list", []string{"--delimiter", "/", "dir/"})
+			Expect(err).ToNot(HaveOccurred())
+			_, opts := fakeStorager.ListWithOptionsArgsForCall(0)
+			Expect(opts).To(Equal(ListOptions{Prefix: "dir/", Delimiter: "/", MaxKeys: defaultListPageSize}))
+			Expect(out.String()).To(Equal("dir/sub/\ndir/a\n"))
+		})
+
 	})
 
 	Context("Properties", func() {
+		var out *bytes.Buffer
+
+		BeforeEach(func() {
+			out = &bytes.Buffer{}
+			commandExecuter.out = out
+		})
+
 		It("Successfull", func() {
-			err := commandExecuter.Execute("properties", []string{"object"})
+			err := commandExecuter.Execute(context.Background(), "properties", []string{"object"})
 			Expect(fakeStorager.PropertiesCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
+		It("prints the properties as indented JSON", func() {
+			modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			fakeStorager.PropertiesReturns(Properties{ETag: "etag", LastModified: modified}, nil)
+
+			Expect(commandExecuter.Execute(context.Background(), "properties", []string{"object"})).To(Succeed())
+			Expect(out.String()).To(Equal("{\n  \"etag\": \"etag\",\n  \"last_modified\": \"2024-01-02T03:04:05Z\",\n  \"content_length\": 0\n}\n"))
+		})
+
+		It("prints the extended properties the backend reports", func() {
+			fakeStorager.PropertiesReturns(Properties{
+				ContentLength: 3,
+				ContentType:   "text/plain",
+				CRC32C:        "e3069283",
+				StorageClass:  "COLDLINE",
+				Encryption:    common.Encryption{KMSKeyID: "key"},
+				Metadata:      map[string]string{"owner": "cc"},
+			}, nil)
+			Expect(commandExecuter.SetOutputFormat(OutputJSON)).To(Succeed())
+
+			Expect(commandExecuter.Execute(context.Background(), "properties", []string{"object"})).To(Succeed())
+			Expect(out.String()).To(MatchJSON(`{"content_length":3,"content_type":"text/plain","crc32c":"e3069283",` +
+				`"storage_class":"COLDLINE","encryption":{"kms_key_id":"key"},"metadata":{"owner":"cc"}}`))
+		})
+
+		It("prints {} for missing objects", func() {
+			fakeStorager.PropertiesReturns(Properties{}, common.NewError(common.ErrNotFound, errors.New("missing")))
+
+			Expect(commandExecuter.Execute(context.Background(), "properties", []string{"object"})).To(Succeed())
+			Expect(out.String()).To(Equal("{}\n"))
+		})
+
+		It("returns other errors", func() {
+			fakeStorager.PropertiesReturns(Properties{}, errors.New("boom"))
+
+			err := commandExecuter.Execute(context.Background(), "properties", []string{"object"})
+			Expect(err).To(MatchError("boom"))
+		})
+
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("properties", []string{})
+			err := commandExecuter.Execute(context.Background(), "properties", []string{})
 			Expect(err.Error()).To(ContainSubstring("properties method expected 1 argument got"))
 		})
 
@@ -215,22 +425,120 @@ var _ = Describe("Execute Command", func() {
 
 	Context("Ensure storage exists", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("ensure-storage-exists", []string{})
+			err := commandExecuter.Execute(context.Background(), "ensure-storage-exists", []string{})
 			Expect(fakeStorager.EnsureStorageExistsCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("ensure-storage-exists", []string{"extra-parameter"})
+			err := commandExecuter.Execute(context.Background(), "ensure-storage-exists", []string{"extra-parameter"})
 			Expect(err.Error()).To(ContainSubstring("ensureStorageExists method expected 0 argument got"))
 		})
 
 	})
 
+	Context("Transfer", func() {
+		var destination *FakeStorager
+		var configPath string
+
+		BeforeEach(func() {
+			destination = &FakeStorager{}
+			commandExecuter.newStorageClient = func(storageType string, configFile *os.File) (Storager, error) {
+				Expect(storageType).To(Equal("gcs"))
+				return destination, nil
+			}
+
+			configPath = filepath.Join(GinkgoT().TempDir(), "destination.json")
+			Expect(os.WriteFile(configPath, []byte("{}"), 0644)).To(Succeed())
+
+			fakeStorager.ListObjectsReturns([]ObjectInfo{{Name: "a", Size: 1, ETag: "etag"}, {Name: "b", Size: 1, ETag: "etag"}}, nil)
+			fakeStorager.GetStreamStub = func(context.Context, string) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("x")), nil
+			}
+		})
+
+		It("Successfull", func() {
+			err := commandExecuter.Execute(context.Background(), "transfer", []string{"--to-s", "gcs", "--to-c", configPath, "--parallel", "2", "prefix/"})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, arg := fakeStorager.ListObjectsArgsForCall(0)
+
+			Expect(arg).To(Equal("prefix/"))
+			Expect(destination.PutStreamCallCount()).To(Equal(2))
+		})
+
+		It("Missing destination", func() {
+			err := commandExecuter.Execute(context.Background(), "transfer", []string{"--to-s", "gcs"})
+			Expect(err).To(MatchError(ContainSubstring("requires --to-s and --to-c")))
+		})
+
+		It("Missing destination config", func() {
+			err := commandExecuter.Execute(context.Background(), "transfer", []string{"--to-s", "gcs", "--to-c", "does-not-exist.json"})
+			Expect(err).To(MatchError(ContainSubstring("failed to create destination client")))
+		})
+
+		It("Wrong number of parameters", func() {
+			err := commandExecuter.Execute(context.Background(), "transfer", []string{"--to-s", "gcs", "--to-c", configPath, "prefix-1", "prefix-2"})
+			Expect(err.Error()).To(ContainSubstring("transfer method takes at most 1 argument (prefix) got"))
+		})
+
+	})
+
+	Context("Sync", func() {
+		It("Successfull", func() {
+			err := commandExecuter.Execute(context.Background(), "sync", []string{"down", "--dry-run", "prefix", GinkgoT().TempDir()})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.ListObjectsCallCount()).To(BeEquivalentTo(1))
+			Expect(fakeStorager.GetCallCount()).To(BeEquivalentTo(0))
+		})
+
+		It("Missing direction", func() {
+			err := commandExecuter.Execute(context.Background(), "sync", []string{"sideways", "a", "b"})
+			Expect(err.Error()).To(ContainSubstring("sync method expects a direction"))
+		})
+
+		It("Wrong number of parameters", func() {
+			err := commandExecuter.Execute(context.Background(), "sync", []string{"up", "dir"})
+			Expect(err.Error()).To(ContainSubstring("sync up method expected 2 arguments got"))
+		})
+
+	})
+
+	Context("Batch", func() {
+		var output *strings.Builder
+
+		BeforeEach(func() {
+			output = &strings.Builder{}
+			commandExecuter.out = output
+		})
+
+		It("Successfull", func() {
+			batchFile := filepath.Join(GinkgoT().TempDir(), "batch.jsonl")
+			Expect(os.WriteFile(batchFile, []byte(`{"cmd":"exists","args":["object"]}`+"\n"), 0644)).To(Succeed())
+			fakeStorager.ExistsReturns(true, nil)
+
+			err := commandExecuter.Execute(context.Background(), "batch", []string{"--concurrency", "2", batchFile})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(1))
+			Expect(output.String()).To(ContainSubstring(`"ok":true`))
+		})
+
+		It("Invalid concurrency", func() {
+			err := commandExecuter.Execute(context.Background(), "batch", []string{"--concurrency", "0"})
+			Expect(err.Error()).To(ContainSubstring("--concurrency must be at least 1"))
+		})
+
+		It("Wrong number of parameters", func() {
+			err := commandExecuter.Execute(context.Background(), "batch", []string{"file-1", "file-2"})
+			Expect(err.Error()).To(ContainSubstring("batch method takes at most 1 argument (file) got"))
+		})
+
+	})
+
 	Context("Unsupported command", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("unsupported-command", []string{})
+			err := commandExecuter.Execute(context.Background(), "unsupported-command", []string{})
 			Expect(err.Error()).To(ContainSubstring("unknown command: '%s'", "unsupported-command"))
 
 		})
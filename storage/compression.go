@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// The magic numbers starting gzip and zstd streams.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// errCompressedRange is returned for byte ranges of compressed objects,
+// whose bytes do not line up with the bytes of the content.
+var errCompressedRange = errors.New("byte ranges of compressed objects are not supported")
+
+// compressingStorager compresses the content of uploads with
+// PutOptions.Compression set into a plain gzip or zstd stream, naming the
+// algorithm in the Content-Encoding of the object, and decompresses objects
+// with that Content-Encoding when they are downloaded. Content is
+// compressed and decompressed while streaming, so memory stays bounded.
+// Stat, Properties and listings report objects as stored, with their size
+// after compression.
+type compressingStorager struct {
+	Storager
+	// headers is set if the backend keeps the Content-Encoding of uploads.
+	// Otherwise no object is compressed, and downloads pass through.
+	headers bool
+}
+
+// newCompressingStorager wraps str, see PutOptions.Compression.
+func newCompressingStorager(str Storager, headers bool) Storager {
+	return &compressingStorager{Storager: str, headers: headers}
+}
+
+// Unwrap returns the wrapped backend.
+func (c *compressingStorager) Unwrap() Storager {
+	return c.Storager
+}
+
+// PutWithOptions streams the compressed file, so checkpoints, which rely on
+// the backend reading the file itself, are not supported.
+func (c *compressingStorager) PutWithOptions(ctx context.Context, sourceFilePath string, dest string, opts PutOptions) error {
+	if opts.Compression == "" {
+		return c.Storager.PutWithOptions(ctx, sourceFilePath, dest, opts)
+	}
+	if opts.Checkpoint != "" {
+		return errors.New("checkpointed uploads cannot be compressed")
+	}
+	source, err := os.Open(sourceFilePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close() //nolint:errcheck
+	return c.putCompressed(ctx, source, dest, opts)
+}
+
+func (c *compressingStorager) PutStreamWithOptions(ctx context.Context, source io.Reader, dest string, opts PutOptions) error {
+	if opts.Compression == "" {
+		return c.Storager.PutStreamWithOptions(ctx, source, dest, opts)
+	}
+	return c.putCompressed(ctx, source, dest, opts)
+}
+
+// putCompressed uploads source compressed with opts.Compression, which
+// becomes the Content-Encoding of dest.
+func (c *compressingStorager) putCompressed(ctx context.Context, source io.Reader, dest string, opts PutOptions) error {
+	if !c.headers {
+		return errors.New("compressed uploads need a Content-Encoding, which local storage and WebDAV do not keep")
+	}
+	newWriter, err := compressor(opts.Compression)
+	if err != nil {
+		return err
+	}
+	if opts.ContentEncoding != "" && compressionOf(opts.ContentEncoding) != opts.Compression {
+		return fmt.Errorf("content encoding %q conflicts with %s compression", opts.ContentEncoding, opts.Compression)
+	}
+	opts.ContentEncoding, opts.Compression = opts.Compression, ""
+
+	compressed, writer := io.Pipe()
+	go func() {
+		w, err := newWriter(writer)
+		if err == nil {
+			_, err = io.Copy(w, source)
+			if closeErr := w.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		writer.CloseWithError(err) //nolint:errcheck
+	}()
+	// Stops the compression if the upload fails before reading everything.
+	defer compressed.Close() //nolint:errcheck
+
+	return c.Storager.PutStreamWithOptions(ctx, compressed, dest, opts)
+}
+
+func compressor(algorithm string) (func(io.Writer) (io.WriteCloser, error), error) {
+	switch algorithm {
+	case common.CompressionGzip:
+		return func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }, nil
+	case common.CompressionZstd:
+		return func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) }, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %q, expected gzip or zstd", algorithm)
+	}
+}
+
+func (c *compressingStorager) Get(ctx context.Context, source string, dest string) error {
+	return c.GetWithOptions(ctx, source, dest, GetOptions{})
+}
+
+// GetWithOptions streams source to dest, decompressing it while it is
+// written. On backends without content headers, and for byte ranges, the
+// backend downloads dest itself.
+func (c *compressingStorager) GetWithOptions(ctx context.Context, source string, dest string, opts GetOptions) error {
+	if !c.headers {
+		return c.Storager.GetWithOptions(ctx, source, dest, opts)
+	}
+	if opts.Range != nil {
+		if err := c.checkUncompressed(ctx, source); err != nil {
+			return err
+		}
+		return c.Storager.GetWithOptions(ctx, source, dest, opts)
+	}
+
+	content, err := c.GetStreamWithOptions(ctx, source, opts)
+	if err != nil {
+		return err
+	}
+	defer content.Close() //nolint:errcheck
+
+	file, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close() //nolint:errcheck
+		return errors.Join(fmt.Errorf("downloading %s: %w", source, err), os.Remove(dest))
+	}
+	return file.Close()
+}
+
+func (c *compressingStorager) GetStream(ctx context.Context, source string) (io.ReadCloser, error) {
+	return c.GetStreamWithOptions(ctx, source, GetOptions{})
+}
+
+// GetStreamWithOptions decompresses the content of compressed objects. Only
+// content starting with the magic number of gzip or zstd costs a request
+// for the Content-Encoding of source, as do byte ranges, which are only
+// passed through for objects that are not compressed.
+func (c *compressingStorager) GetStreamWithOptions(ctx context.Context, source string, opts GetOptions) (io.ReadCloser, error) {
+	if !c.headers {
+		return c.Storager.GetStreamWithOptions(ctx, source, opts)
+	}
+	if opts.Range != nil {
+		if err := c.checkUncompressed(ctx, source); err != nil {
+			return nil, err
+		}
+		return c.Storager.GetStreamWithOptions(ctx, source, opts)
+	}
+	content, err := c.Storager.GetStreamWithOptions(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	decompressed, err := c.decompressing(ctx, source, content)
+	if err != nil {
+		content.Close() //nolint:errcheck
+		return nil, fmt.Errorf("decompressing %s: %w", source, err)
+	}
+	return decompressed, nil
+}
+
+// checkUncompressed returns errCompressedRange if source is compressed.
+func (c *compressingStorager) checkUncompressed(ctx context.Context, source string) error {
+	props, err := c.Storager.Properties(ctx, source)
+	if err != nil {
+		return err
+	}
+	if compressionOf(props.ContentEncoding) != "" {
+		return fmt.Errorf("reading %s: %w", source, errCompressedRange)
+	}
+	return nil
+}
+
+// compressionOf returns the algorithm named by contentEncoding, or "" if it
+// names none of the supported ones.
+func compressionOf(contentEncoding string) string {
+	for _, algorithm := range []string{common.CompressionGzip, common.CompressionZstd} {
+		if strings.EqualFold(contentEncoding, algorithm) {
+			return algorithm
+		}
+	}
+	return ""
+}
+
+// decompressing returns the decompressed content of source if content
+// starts with the magic number of the algorithm named by the
+// Content-Encoding of source, or else content unchanged. Checking the
+// content first spares the request for the Content-Encoding of most
+// objects, and leaves content alone that an HTTP client already
+// decompressed. Closing the returned reader closes content.
+func (c *compressingStorager) decompressing(ctx context.Context, source string, content io.ReadCloser) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(content)
+	passthrough := struct {
+		io.Reader
+		io.Closer
+	}{buffered, content}
+	start, err := buffered.Peek(len(zstdMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	var algorithm string
+	switch {
+	case bytes.HasPrefix(start, gzipMagic):
+		algorithm = common.CompressionGzip
+	case bytes.HasPrefix(start, zstdMagic):
+		algorithm = common.CompressionZstd
+	default:
+		return passthrough, nil
+	}
+	props, err := c.Storager.Properties(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if compressionOf(props.ContentEncoding) != algorithm {
+		return passthrough, nil
+	}
+
+	var decompressed io.ReadCloser
+	if algorithm == common.CompressionGzip {
+		decompressed, err = gzip.NewReader(buffered)
+	} else {
+		var decoder *zstd.Decoder
+		if decoder, err = zstd.NewReader(buffered); err == nil {
+			decompressed = decoder.IOReadCloser()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &decompressedContent{Reader: decompressed, Closer: closers{decompressed, content}, algorithm: algorithm}, nil
+}
+
+// decompressedContent is the content of a compressed object, whose size is
+// not known until it is read.
+type decompressedContent struct {
+	io.Reader
+	io.Closer
+	algorithm string
+}
+
+// decompressedWith returns the algorithm content is decompressed with if it
+// was returned by compressingStorager for a compressed object, or else "".
+func decompressedWith(content io.Reader) string {
+	if decompressed, ok := content.(*decompressedContent); ok {
+		return decompressed.algorithm
+	}
+	return ""
+}
+
+// closers closes all of its elements, returning the first error.
+type closers []io.Closer
+
+func (c closers) Close() error {
+	var first error
+	for _, closer := range c {
+		if err := closer.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+var _ = Describe("compression", func() {
+	var (
+		fakeStorager *FakeStorager
+		compressing  Storager
+		stored       []byte
+		storedOpts   PutOptions
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		stored, storedOpts = nil, PutOptions{}
+		fakeStorager.PutStreamWithOptionsStub = func(_ context.Context, source io.Reader, _ string, opts PutOptions) error {
+			var err error
+			stored, err = io.ReadAll(source)
+			storedOpts = opts
+			return err
+		}
+		fakeStorager.PropertiesStub = func(context.Context, string) (Properties, error) {
+			return Properties{ETag: "etag", ContentLength: int64(len(stored)), ContentEncoding: storedOpts.ContentEncoding}, nil
+		}
+		fakeStorager.GetStreamWithOptionsStub = func(context.Context, string, GetOptions) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(stored)), nil
+		}
+		fakeStorager.GetWithOptionsStub = func(_ context.Context, _ string, dest string, _ GetOptions) error {
+			return os.WriteFile(dest, stored, 0644)
+		}
+		compressing = newCompressingStorager(fakeStorager, true)
+	})
+
+	gzipped := func(content string) []byte {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	DescribeTable("compresses uploads and decompresses them on download",
+		func(algorithm string, decompress func([]byte) ([]byte, error)) {
+			content := strings.Repeat("droplet content ", 1000)
+			source := filepath.Join(GinkgoT().TempDir(), "droplet.tgz")
+			Expect(os.WriteFile(source, []byte(content), 0644)).To(Succeed())
+
+			Expect(compressing.PutWithOptions(context.Background(), source, "droplet", PutOptions{Compression: algorithm, Metadata: map[string]string{"owner": "cc"}})).To(Succeed())
+			Expect(len(stored)).To(BeNumerically("<", len(content)))
+			Expect(decompress(stored)).To(Equal([]byte(content)))
+			Expect(storedOpts).To(Equal(PutOptions{ContentEncoding: algorithm, Metadata: map[string]string{"owner": "cc"}}))
+
+			dest := filepath.Join(GinkgoT().TempDir(), "download")
+			Expect(compressing.Get(context.Background(), "droplet", dest)).To(Succeed())
+			Expect(os.ReadFile(dest)).To(Equal([]byte(content)))
+			Expect(fakeStorager.GetWithOptionsCallCount()).To(BeZero())
+
+			stream, err := compressing.GetStream(context.Background(), "droplet")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(decompressedWith(stream)).To(Equal(algorithm))
+			Expect(io.ReadAll(stream)).To(Equal([]byte(content)))
+			Expect(stream.Close()).To(Succeed())
+			Expect(fakeStorager.PropertiesCallCount()).To(Equal(2))
+		},
+		Entry("gzip", common.CompressionGzip, func(data []byte) ([]byte, error) {
+			r, err := gzip.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			return io.ReadAll(r)
+		}),
+		Entry("zstd", common.CompressionZstd, func(data []byte) ([]byte, error) {
+			decoder, err := zstd.NewReader(nil)
+			if err != nil {
+				return nil, err
+			}
+			defer decoder.Close()
+			return decoder.DecodeAll(data, nil)
+		}),
+	)
+
+	It("passes other objects through, even gzipped ones", func() {
+		tgz := gzipped("droplet")
+		Expect(compressing.PutStreamWithOptions(context.Background(), bytes.NewReader(tgz), "droplet.tgz", PutOptions{})).To(Succeed())
+
+		stream, err := compressing.GetStream(context.Background(), "droplet.tgz")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decompressedWith(stream)).To(BeEmpty())
+		Expect(io.ReadAll(stream)).To(Equal(tgz))
+
+		dest := filepath.Join(GinkgoT().TempDir(), "download")
+		Expect(compressing.Get(context.Background(), "droplet.tgz", dest)).To(Succeed())
+		Expect(os.ReadFile(dest)).To(Equal(tgz))
+	})
+
+	It("only looks up the Content-Encoding of content starting like a compressed stream", func() {
+		stored, storedOpts = []byte("droplet"), PutOptions{ContentEncoding: common.CompressionGzip}
+
+		// As if an HTTP client decompressed the content in transit.
+		stream, err := compressing.GetStream(context.Background(), "droplet")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(io.ReadAll(stream)).To(Equal([]byte("droplet")))
+		Expect(fakeStorager.PropertiesCallCount()).To(BeZero())
+	})
+
+	It("rejects byte ranges of compressed objects, checkpoints and other content encodings", func() {
+		Expect(compressing.PutStreamWithOptions(context.Background(), strings.NewReader("content"), "plain", PutOptions{})).To(Succeed())
+		stream, err := compressing.GetStreamWithOptions(context.Background(), "plain", GetOptions{Range: &ByteRange{Start: 1, End: 3}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stream.Close()).To(Succeed())
+
+		Expect(compressing.PutStreamWithOptions(context.Background(), strings.NewReader("content"), "droplet", PutOptions{Compression: common.CompressionZstd})).To(Succeed())
+		_, err = compressing.GetStreamWithOptions(context.Background(), "droplet", GetOptions{Range: &ByteRange{Start: 0, End: 3}})
+		Expect(err).To(MatchError(errCompressedRange))
+		Expect(fakeStorager.GetStreamWithOptionsCallCount()).To(Equal(1))
+
+		err = compressing.PutWithOptions(context.Background(), "droplet.tgz", "droplet", PutOptions{Compression: common.CompressionGzip, Checkpoint: "checkpoint"})
+		Expect(err).To(MatchError("checkpointed uploads cannot be compressed"))
+
+		err = compressing.PutStreamWithOptions(context.Background(), strings.NewReader("content"), "droplet", PutOptions{Compression: common.CompressionGzip, ContentEncoding: "br"})
+		Expect(err).To(MatchError(`content encoding "br" conflicts with gzip compression`))
+	})
+
+	It("refuses to resume the download of compressed objects", func() {
+		Expect(compressing.PutStreamWithOptions(context.Background(), strings.NewReader("content"), "droplet", PutOptions{Compression: common.CompressionGzip})).To(Succeed())
+		fakeStorager.StatReturns(ObjectInfo{Name: "droplet", Size: int64(len(stored)), ETag: "etag"}, true, nil)
+
+		dest := filepath.Join(GinkgoT().TempDir(), "download")
+		_, err := getResumable(context.Background(), compressing, "droplet", dest, "")
+		Expect(err).To(MatchError(`cannot resume the download of "droplet": it is compressed with gzip`))
+		Expect(dest).NotTo(BeAnExistingFile())
+		Expect(dest + resumeStateSuffix).NotTo(BeAnExistingFile())
+	})
+
+	Context("on backends without content headers", func() {
+		BeforeEach(func() {
+			compressing = newCompressingStorager(fakeStorager, false)
+		})
+
+		It("rejects compressed uploads and passes downloads through", func() {
+			err := compressing.PutStreamWithOptions(context.Background(), strings.NewReader("content"), "droplet", PutOptions{Compression: common.CompressionGzip})
+			Expect(err).To(MatchError(ContainSubstring("local storage and WebDAV do not keep")))
+			Expect(fakeStorager.PutStreamWithOptionsCallCount()).To(BeZero())
+
+			stored = gzipped("droplet")
+			dest := filepath.Join(GinkgoT().TempDir(), "download")
+			Expect(compressing.Get(context.Background(), "droplet.tgz", dest)).To(Succeed())
+			Expect(fakeStorager.GetWithOptionsCallCount()).To(Equal(1))
+
+			stream, err := compressing.GetStream(context.Background(), "droplet.tgz")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(io.ReadAll(stream)).To(Equal(stored))
+			Expect(fakeStorager.PropertiesCallCount()).To(BeZero())
+		})
+
+		It("rejects compressed uploads to local storage before writing anything", func() {
+			root := GinkgoT().TempDir()
+			configFile, err := os.Create(filepath.Join(GinkgoT().TempDir(), "config.json"))
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(configFile.Close)
+			_, err = fmt.Fprintf(configFile, `{"root_directory":%q}`, root)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = configFile.Seek(0, io.SeekStart)
+			Expect(err).NotTo(HaveOccurred())
+
+			local, err := NewStorageClient("local", configFile)
+			Expect(err).NotTo(HaveOccurred())
+			err = local.PutStreamWithOptions(context.Background(), strings.NewReader("content"), "droplet", PutOptions{Compression: common.CompressionGzip})
+			Expect(err).To(MatchError(ContainSubstring("local storage and WebDAV do not keep")))
+			Expect(os.ReadDir(root)).To(BeEmpty())
+
+			Expect(local.PutStream(context.Background(), bytes.NewReader(gzipped("droplet")), "droplet.tgz")).To(Succeed())
+			stream, err := local.GetStream(context.Background(), "droplet.tgz")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(stream.Close)
+			Expect(io.ReadAll(stream)).To(Equal(gzipped("droplet")))
+		})
+	})
+})
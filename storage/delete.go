@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// deleteFailuresError reports that some objects of delete-many or
+// delete-recursive were not deleted, after the failures were printed.
+type deleteFailuresError struct {
+	failed int
+	total  int
+}
+
+func (e *deleteFailuresError) Error() string {
+	return fmt.Sprintf("%d of %d objects failed to delete", e.failed, e.total)
+}
+
+type deleteFailureEntry struct {
+	Key   string    `json:"key"`
+	Error errorBody `json:"error"`
+}
+
+type deleteResult struct {
+	Deleted int                  `json:"deleted"`
+	Failed  []deleteFailureEntry `json:"failed"`
+}
+
+type deleteRecursiveOptions struct {
+	prefix string
+	// all allows an empty prefix, deleting every object.
+	all    bool
+	dryRun bool
+	// maxObjects aborts before deleting anything if more objects match.
+	maxObjects int
+}
+
+// deleteMany deletes the keys listed one per line in the file at path, or in
+// stdin if path is empty or "-", and prints the keys that failed and why.
+func (sty *CommandExecuter) deleteMany(ctx context.Context, path string) error {
+	var in io.Reader = sty.stdin()
+	if path != "" && path != stdioPath {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open key file: %w", err)
+		}
+		defer file.Close() //nolint:errcheck
+		in = file
+	}
+
+	keys, err := readKeys(in)
+	if err != nil {
+		return err
+	}
+	return sty.deleteKeys(ctx, keys)
+}
+
+// deleteRecursive deletes the objects below opts.prefix a page of the listing
+// at a time. With opts.maxObjects, the objects are counted first, so that
+// nothing is deleted when more of them match.
+func (sty *CommandExecuter) deleteRecursive(ctx context.Context, opts deleteRecursiveOptions) error {
+	if opts.prefix == "" && !opts.all {
+		return errors.New("delete-recursive without a prefix deletes every object, pass --all to confirm")
+	}
+
+	if opts.maxObjects > 0 {
+		count := 0
+		for page, err := range ListPages(ctx, sty.str, ListOptions{Prefix: opts.prefix}) {
+			if err != nil {
+				return fmt.Errorf("failed to list objects to delete: %w", err)
+			}
+			count += len(page.Objects)
+			if count > opts.maxObjects {
+				return fmt.Errorf("at least %d objects match prefix %q, more than --max-objects %d, nothing was deleted", count, opts.prefix, opts.maxObjects)
+			}
+		}
+	}
+	if opts.dryRun {
+		return sty.printDeleteDryRun(ctx, opts.prefix)
+	}
+
+	var (
+		total    int
+		failures []DeleteFailure
+	)
+	for page, err := range ListPages(ctx, sty.str, ListOptions{Prefix: opts.prefix}) {
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to list objects to delete: %w", err), common.JoinDeleteFailures(failures))
+		}
+		if len(page.Objects) == 0 {
+			continue
+		}
+		total += len(page.Objects)
+		failures = append(failures, sty.str.DeleteMany(ctx, common.ObjectNames(page.Objects))...)
+	}
+	return sty.reportDeletes(total, failures)
+}
+
+// printDeleteDryRun prints the objects below prefix as their pages arrive.
+func (sty *CommandExecuter) printDeleteDryRun(ctx context.Context, prefix string) error {
+	var objects *jsonArrayWriter
+	if sty.jsonOutput() {
+		if _, err := io.WriteString(sty.stdout(), `{"dry_run":true,"objects":`); err != nil {
+			return err
+		}
+		var err error
+		if objects, err = newJSONArrayWriter(sty.stdout()); err != nil {
+			return err
+		}
+	}
+
+	count := 0
+	for page, err := range ListPages(ctx, sty.str, ListOptions{Prefix: prefix}) {
+		if err != nil {
+			return fmt.Errorf("failed to list objects to delete: %w", err)
+		}
+		for _, object := range page.Objects {
+			count++
+			if objects != nil {
+				err = objects.write(object.Name)
+			} else {
+				_, err = fmt.Fprintln(sty.stdout(), "(dry-run) delete", object.Name)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if objects != nil {
+		return objects.close("}\n")
+	}
+	_, err := fmt.Fprintf(sty.stdout(), "would delete: %d\n", count)
+	return err
+}
+
+// deleteKeys deletes keys and prints the keys that failed and why, followed
+// by the number of deleted and failed keys.
+func (sty *CommandExecuter) deleteKeys(ctx context.Context, keys []string) error {
+	return sty.reportDeletes(len(keys), sty.str.DeleteMany(ctx, keys))
+}
+
+// reportDeletes prints the keys that failed to delete and why, followed by
+// the number of deleted and failed keys out of total.
+func (sty *CommandExecuter) reportDeletes(total int, failures []DeleteFailure) error {
+	result := deleteResult{Deleted: total - len(failures), Failed: []deleteFailureEntry{}}
+	for _, failure := range failures {
+		result.Failed = append(result.Failed, deleteFailureEntry{
+			Key:   failure.Key,
+			Error: errorBody{Code: errorCode(failure.Err), Message: failure.Err.Error()},
+		})
+	}
+
+	if sty.jsonOutput() {
+		if err := sty.writeJSON(result); err != nil {
+			return err
+		}
+	} else {
+		for _, failure := range failures {
+			fmt.Fprintf(sty.stdout(), "failed to delete %s: %v\n", failure.Key, failure.Err)
+		}
+		fmt.Fprintf(sty.stdout(), "deleted: %d, failed: %d\n", result.Deleted, len(failures))
+	}
+
+	if len(failures) > 0 {
+		return &deleteFailuresError{failed: len(failures), total: total}
+	}
+	return nil
+}
+
+// readKeys reads one key per line, skipping empty lines. Keys are taken
+// verbatim, as object names may start or end with spaces.
+func readKeys(in io.Reader) ([]string, error) {
+	var keys []string
+	scanner := bufio.NewScanner(in)
+	line := 0
+	for scanner.Scan() {
+		line++
+		key := strings.TrimSuffix(scanner.Text(), "\r")
+		if key == "" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading keys after line %d: %w", line, err)
+	}
+	return keys, nil
+}
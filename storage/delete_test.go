@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+var _ = Describe("delete-many", func() {
+	var (
+		fakeStorager    *FakeStorager
+		commandExecuter *CommandExecuter
+		output          *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		output = &bytes.Buffer{}
+		commandExecuter = &CommandExecuter{str: fakeStorager, out: output}
+	})
+
+	It("deletes the keys read from stdin, skipping empty lines", func() {
+		commandExecuter.in = strings.NewReader("a\n\nb/c\r\n d \n")
+
+		Expect(commandExecuter.Execute(context.Background(), "delete-many", nil)).To(Succeed())
+		_, keys := fakeStorager.DeleteManyArgsForCall(0)
+		Expect(keys).To(Equal([]string{"a", "b/c", " d "}))
+		Expect(output.String()).To(Equal("deleted: 3, failed: 0\n"))
+	})
+
+	It("reads the keys from a file", func() {
+		keyFile := filepath.Join(GinkgoT().TempDir(), "keys")
+		Expect(os.WriteFile(keyFile, []byte("a\nb\n"), 0644)).To(Succeed())
+
+		Expect(commandExecuter.Execute(context.Background(), "delete-many", []string{keyFile})).To(Succeed())
+		_, keys := fakeStorager.DeleteManyArgsForCall(0)
+		Expect(keys).To(Equal([]string{"a", "b"}))
+	})
+
+	It("prints the failed keys and fails", func() {
+		commandExecuter.in = strings.NewReader("a\nb\nc\n")
+		fakeStorager.DeleteManyReturns([]DeleteFailure{{Key: "b", Err: errors.New("boom")}})
+
+		err := commandExecuter.Execute(context.Background(), "delete-many", []string{"-"})
+		Expect(err).To(MatchError("1 of 3 objects failed to delete"))
+		Expect(output.String()).To(Equal("failed to delete b: boom\ndeleted: 2, failed: 1\n"))
+	})
+
+	It("reports the failed keys with their error codes as a single JSON document", func() {
+		Expect(commandExecuter.SetOutputFormat(OutputJSON)).To(Succeed())
+		commandExecuter.in = strings.NewReader("a\nb\n")
+		fakeStorager.DeleteManyReturns([]DeleteFailure{
+			{Key: "b", Err: common.NewError(common.ErrPermissionDenied, errors.New("access denied"))},
+		})
+
+		Expect(commandExecuter.Execute(context.Background(), "delete-many", nil)).To(HaveOccurred())
+
+		var result map[string]any
+		Expect(json.Unmarshal(output.Bytes(), &result)).To(Succeed())
+		Expect(result).To(Equal(map[string]any{
+			"deleted": float64(1),
+			"failed": []any{map[string]any{
+				"key":   "b",
+				"error": map[string]any{"code": "permission_denied", "message": "access denied"},
+			}},
+		}))
+	})
+
+	It("does not read stdin in batches", func() {
+		_, err := commandExecuter.executeBuffered(context.Background(), "delete-many", nil)
+		Expect(err).To(MatchError(ContainSubstring("cannot stream from stdin")))
+		Expect(fakeStorager.DeleteManyCallCount()).To(Equal(0))
+	})
+})
+
+var _ = Describe("delete-recursive", func() {
+	var (
+		fakeStorager    *FakeStorager
+		commandExecuter *CommandExecuter
+		output          *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		fakeStorager.ListWithOptionsReturns(ListResult{Objects: []ObjectInfo{{Name: "cache/a"}, {Name: "cache/b"}, {Name: "cache/c"}}}, nil)
+		output = &bytes.Buffer{}
+		commandExecuter = &CommandExecuter{str: fakeStorager, out: output}
+	})
+
+	It("prints the objects it would delete with --dry-run", func() {
+		Expect(commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--dry-run", "cache/"})).To(Succeed())
+		Expect(fakeStorager.DeleteManyCallCount()).To(Equal(0))
+		Expect(output.String()).To(Equal("(dry-run) delete cache/a\n(dry-run) delete cache/b\n(dry-run) delete cache/c\nwould delete: 3\n"))
+	})
+
+	It("lists the objects it would delete as JSON with --dry-run", func() {
+		Expect(commandExecuter.SetOutputFormat(OutputJSON)).To(Succeed())
+		Expect(commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--dry-run", "cache/"})).To(Succeed())
+		Expect(output.String()).To(MatchJSON(`{"dry_run":true,"objects":["cache/a","cache/b","cache/c"]}`))
+	})
+
+	It("deletes nothing when more objects than --max-objects match", func() {
+		err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--max-objects", "2", "cache/"})
+		Expect(err).To(MatchError(ContainSubstring("more than --max-objects 2, nothing was deleted")))
+		Expect(fakeStorager.DeleteManyCallCount()).To(Equal(0))
+
+		Expect(commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--max-objects", "3", "cache/"})).To(Succeed())
+		Expect(fakeStorager.DeleteManyCallCount()).To(Equal(1))
+	})
+
+	It("deletes the objects a page at a time", func() {
+		fakeStorager.ListWithOptionsStub = func(_ context.Context, opts ListOptions) (ListResult, error) {
+			if opts.PageToken == "" {
+				return ListResult{Objects: []ObjectInfo{{Name: "cache/a"}, {Name: "cache/b"}}, NextPageToken: "next"}, nil
+			}
+			Expect(fakeStorager.DeleteManyCallCount()).To(Equal(1))
+			return ListResult{Objects: []ObjectInfo{{Name: "cache/c"}}}, nil
+		}
+
+		Expect(commandExecuter.Execute(context.Background(), "delete-recursive", []string{"cache/"})).To(Succeed())
+		_, opts := fakeStorager.ListWithOptionsArgsForCall(0)
+		Expect(opts.Prefix).To(Equal("cache/"))
+		Expect(fakeStorager.DeleteManyCallCount()).To(Equal(2))
+		_, keys := fakeStorager.DeleteManyArgsForCall(1)
+		Expect(keys).To(Equal([]string{"cache/c"}))
+		Expect(output.String()).To(Equal("deleted: 3, failed: 0\n"))
+	})
+
+	It("reports the deleted and failed objects and fails if any failed", func() {
+		fakeStorager.DeleteManyReturns([]DeleteFailure{{Key: "cache/b", Err: errors.New("boom")}})
+
+		err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"cache/"})
+		Expect(err).To(MatchError("1 of 3 objects failed to delete"))
+		Expect(output.String()).To(Equal("failed to delete cache/b: boom\ndeleted: 2, failed: 1\n"))
+	})
+})
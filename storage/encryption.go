@@ -0,0 +1,249 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strconv"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// plaintextSizeMetadataKey is the user-defined metadata recording the size
+// of uploaded files before encryption, so that Stat and Properties do not
+// read the header of the object.
+const plaintextSizeMetadataKey = "storage_cli_plaintext_size"
+
+// errEncryptedRange is returned for byte ranges of encrypted objects, whose
+// chunks do not line up with the bytes of the content.
+var errEncryptedRange = errors.New("byte ranges are not supported with client-side encryption")
+
+// encryptingStorager encrypts the content put into the backend it wraps and
+// decrypts the content read from it, see common.Keyring. Stat, Properties
+// and listings report the size of the content, while copies, deletions and
+// signed URLs pass through to the encrypted objects.
+type encryptingStorager struct {
+	Storager
+	keyring *common.Keyring
+	// metadata is set if the backend keeps the user-defined metadata of
+	// uploads.
+	metadata bool
+}
+
+// newEncryptingStorager wraps str with the client_side_encryption section
+// of configFile, if it has one. If metadata is set, the size of uploaded
+// files is recorded in the metadata of their objects.
+func newEncryptingStorager(str Storager, configFile *os.File, metadata bool) (Storager, error) {
+	if _, err := configFile.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(configFile)
+	if err != nil || len(data) == 0 {
+		return str, err
+	}
+	var config struct {
+		ClientSideEncryption *common.EncryptionConfig `json:"client_side_encryption"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, invalidConfig(err)
+	}
+	if config.ClientSideEncryption == nil {
+		return str, nil
+	}
+	keyring, err := common.NewKeyring(*config.ClientSideEncryption)
+	if err != nil {
+		return nil, invalidConfig(err)
+	}
+	return &encryptingStorager{Storager: str, keyring: keyring, metadata: metadata}, nil
+}
+
+// Unwrap returns the wrapped backend.
+func (e *encryptingStorager) Unwrap() Storager {
+	return e.Storager
+}
+
+func (e *encryptingStorager) Put(ctx context.Context, sourceFilePath string, dest string) error {
+	return e.PutWithOptions(ctx, sourceFilePath, dest, PutOptions{})
+}
+
+// PutWithOptions streams the encrypted file, so checkpoints, which rely on
+// the backend reading the file itself, are not supported.
+func (e *encryptingStorager) PutWithOptions(ctx context.Context, sourceFilePath string, dest string, opts PutOptions) error {
+	if opts.Checkpoint != "" {
+		return errors.New("checkpointed uploads are not supported with client-side encryption")
+	}
+	source, err := os.Open(sourceFilePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close() //nolint:errcheck
+	if e.metadata {
+		info, err := source.Stat()
+		if err != nil {
+			return err
+		}
+		opts.Metadata = maps.Clone(opts.Metadata)
+		if opts.Metadata == nil {
+			opts.Metadata = map[string]string{}
+		}
+		opts.Metadata[plaintextSizeMetadataKey] = strconv.FormatInt(info.Size(), 10)
+	}
+	return e.PutStreamWithOptions(ctx, source, dest, opts)
+}
+
+func (e *encryptingStorager) PutStream(ctx context.Context, source io.Reader, dest string) error {
+	return e.PutStreamWithOptions(ctx, source, dest, PutOptions{})
+}
+
+func (e *encryptingStorager) PutStreamWithOptions(ctx context.Context, source io.Reader, dest string, opts PutOptions) error {
+	encrypted, err := e.keyring.EncryptReader(source)
+	if err != nil {
+		return err
+	}
+	return e.Storager.PutStreamWithOptions(ctx, encrypted, dest, opts)
+}
+
+func (e *encryptingStorager) Get(ctx context.Context, source string, dest string) error {
+	return e.GetWithOptions(ctx, source, dest, GetOptions{})
+}
+
+func (e *encryptingStorager) GetWithOptions(ctx context.Context, source string, dest string, opts GetOptions) error {
+	content, err := e.GetStreamWithOptions(ctx, source, opts)
+	if err != nil {
+		return err
+	}
+	defer content.Close() //nolint:errcheck
+
+	file, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close() //nolint:errcheck
+		return errors.Join(fmt.Errorf("downloading %s: %w", source, err), os.Remove(dest))
+	}
+	return file.Close()
+}
+
+func (e *encryptingStorager) GetStream(ctx context.Context, source string) (io.ReadCloser, error) {
+	return e.GetStreamWithOptions(ctx, source, GetOptions{})
+}
+
+func (e *encryptingStorager) GetStreamWithOptions(ctx context.Context, source string, opts GetOptions) (io.ReadCloser, error) {
+	if opts.Range != nil {
+		return nil, errEncryptedRange
+	}
+	content, err := e.Storager.GetStreamWithOptions(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := e.keyring.DecryptReader(content)
+	if err != nil {
+		content.Close() //nolint:errcheck
+		return nil, fmt.Errorf("reading %s: %w", source, err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{decrypted, content}, nil
+}
+
+// Stat reports the size of the content. On backends keeping metadata it
+// reads the properties of dest instead, which cost as much as its stat, and
+// only falls back to reading the header of objects uploaded from streams.
+func (e *encryptingStorager) Stat(ctx context.Context, dest string) (ObjectInfo, bool, error) {
+	if e.metadata {
+		props, err := e.Properties(ctx, dest)
+		if errors.Is(err, common.ErrNotFound) {
+			return ObjectInfo{}, false, nil
+		}
+		if err != nil {
+			return ObjectInfo{}, false, err
+		}
+		return ObjectInfo{
+			Name:         dest,
+			Size:         props.ContentLength,
+			ETag:         props.ETag,
+			LastModified: props.LastModified,
+			StorageClass: props.StorageClass,
+		}, true, nil
+	}
+
+	info, exists, err := e.Storager.Stat(ctx, dest)
+	if err != nil || !exists {
+		return info, exists, err
+	}
+	if info.Size, err = e.plaintextSize(ctx, dest, info.Size); err != nil {
+		return info, exists, err
+	}
+	// The digest is the one of the encrypted object.
+	info.ContentMD5 = ""
+	return info, exists, nil
+}
+
+// Properties reports the size of the content, recorded in the metadata of
+// uploaded files or else read from the header of the object. The checksums
+// the backend computed are the ones of the encrypted object and are left
+// out.
+func (e *encryptingStorager) Properties(ctx context.Context, dest string) (Properties, error) {
+	props, err := e.Storager.Properties(ctx, dest)
+	if err != nil {
+		return props, err
+	}
+	if size, err := strconv.ParseInt(props.Metadata[plaintextSizeMetadataKey], 10, 64); err == nil {
+		props.ContentLength = size
+	} else if props.ContentLength, err = e.plaintextSize(ctx, dest, props.ContentLength); err != nil {
+		return props, err
+	}
+	props.ContentMD5, props.CRC32, props.CRC32C, props.CRC64, props.SHA256 = "", "", "", "", ""
+	return props, nil
+}
+
+// ListObjects reports the size of the content of the listed objects, or -1
+// if it cannot be derived from their size, see common.Keyring.PlaintextSizeOf.
+func (e *encryptingStorager) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects, err := e.Storager.ListObjects(ctx, prefix)
+	e.contentSizes(objects)
+	return objects, err
+}
+
+// ListWithOptions reports sizes as ListObjects does.
+func (e *encryptingStorager) ListWithOptions(ctx context.Context, opts ListOptions) (ListResult, error) {
+	page, err := e.Storager.ListWithOptions(ctx, opts)
+	e.contentSizes(page.Objects)
+	return page, err
+}
+
+// contentSizes replaces the sizes of the listed objects with the sizes
+// of their content and drops the digests of the encrypted objects.
+func (e *encryptingStorager) contentSizes(objects []ObjectInfo) {
+	for i := range objects {
+		size, known := e.keyring.PlaintextSizeOf(objects[i].Size)
+		if !known {
+			size = -1
+		}
+		objects[i].Size = size
+		objects[i].ContentMD5 = ""
+	}
+}
+
+func (e *encryptingStorager) plaintextSize(ctx context.Context, dest string, size int64) (int64, error) {
+	if size == 0 {
+		// Only unencrypted objects are empty.
+		return 0, nil
+	}
+	prefix, err := e.Storager.GetStreamWithOptions(ctx, dest, GetOptions{Range: &ByteRange{Start: 0, End: int64(common.EnvelopePrefixMax) - 1}})
+	if err != nil {
+		return 0, fmt.Errorf("reading encryption header of %s: %w", dest, err)
+	}
+	defer prefix.Close() //nolint:errcheck
+	plaintextSize, err := e.keyring.PlaintextSize(prefix, size)
+	if err != nil {
+		return 0, fmt.Errorf("reading encryption header of %s: %w", dest, err)
+	}
+	return plaintextSize, nil
+}
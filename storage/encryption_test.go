@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+var _ = Describe("client-side encryption", func() {
+	var (
+		fakeStorager *FakeStorager
+		configFile   *os.File
+		encrypted    Storager
+		stored       []byte
+	)
+
+	BeforeEach(func() {
+		key := make([]byte, 32)
+		_, err := rand.Read(key)
+		Expect(err).NotTo(HaveOccurred())
+
+		configFile, err = os.Create(filepath.Join(GinkgoT().TempDir(), "config.json"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(configFile.Close)
+		_, err = fmt.Fprintf(configFile, `{"bucket_name":"b","client_side_encryption":{"key_id":"k1","keys":[{"id":"k1","key":%q}]}}`, base64.StdEncoding.EncodeToString(key))
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeStorager = &FakeStorager{}
+		stored = nil
+		fakeStorager.PutStreamWithOptionsStub = func(_ context.Context, source io.Reader, _ string, _ PutOptions) error {
+			var err error
+			stored, err = io.ReadAll(source)
+			return err
+		}
+		fakeStorager.GetStreamWithOptionsStub = func(_ context.Context, _ string, opts GetOptions) (io.ReadCloser, error) {
+			content := stored
+			if opts.Range != nil {
+				content = content[opts.Range.Start:min(opts.Range.End+1, int64(len(content)))]
+			}
+			return io.NopCloser(bytes.NewReader(content)), nil
+		}
+
+		encrypted, err = newEncryptingStorager(fakeStorager, configFile, false)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("stores encrypted content and reads it back", func() {
+		source := filepath.Join(GinkgoT().TempDir(), "droplet.tgz")
+		Expect(os.WriteFile(source, []byte("secret content"), 0644)).To(Succeed())
+
+		Expect(encrypted.Put(context.Background(), source, "droplet")).To(Succeed())
+		Expect(stored).NotTo(BeEmpty())
+		Expect(string(stored)).NotTo(ContainSubstring("secret content"))
+
+		dest := filepath.Join(GinkgoT().TempDir(), "download")
+		Expect(encrypted.Get(context.Background(), "droplet", dest)).To(Succeed())
+		Expect(os.ReadFile(dest)).To(Equal([]byte("secret content")))
+	})
+
+	It("reports the size of the content", func() {
+		Expect(encrypted.PutStream(context.Background(), bytes.NewReader([]byte("secret content")), "droplet")).To(Succeed())
+		fakeStorager.StatReturns(ObjectInfo{Name: "droplet", Size: int64(len(stored)), ContentMD5: "abc"}, true, nil)
+		fakeStorager.PropertiesReturns(Properties{ContentLength: int64(len(stored)), ContentMD5: "abc", CRC32C: "def"}, nil)
+
+		info, exists, err := encrypted.Stat(context.Background(), "droplet")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeTrue())
+		Expect(info).To(Equal(ObjectInfo{Name: "droplet", Size: 14}))
+
+		props, err := encrypted.Properties(context.Background(), "droplet")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(props).To(Equal(Properties{ContentLength: 14}))
+	})
+
+	It("reports the size of the content in listings", func() {
+		Expect(encrypted.PutStream(context.Background(), bytes.NewReader([]byte("secret content")), "droplet")).To(Succeed())
+		fakeStorager.ListObjectsReturns([]ObjectInfo{{Name: "droplet", Size: int64(len(stored)), ETag: "etag", ContentMD5: "abc"}}, nil)
+		fakeStorager.ListWithOptionsReturns(ListResult{Objects: []ObjectInfo{{Name: "droplet", Size: int64(len(stored)), ContentMD5: "abc"}, {Name: "empty"}}}, nil)
+
+		objects, err := encrypted.ListObjects(context.Background(), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objects).To(Equal([]ObjectInfo{{Name: "droplet", Size: 14, ETag: "etag"}}))
+
+		page, err := encrypted.ListWithOptions(context.Background(), ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Objects).To(Equal([]ObjectInfo{{Name: "droplet", Size: 14}, {Name: "empty"}}))
+		Expect(fakeStorager.GetStreamWithOptionsCallCount()).To(BeZero())
+	})
+
+	Context("on backends keeping metadata", func() {
+		BeforeEach(func() {
+			var err error
+			encrypted, err = newEncryptingStorager(fakeStorager, configFile, true)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("records the size of uploaded files and reports it without reading the object", func() {
+			source := filepath.Join(GinkgoT().TempDir(), "droplet.tgz")
+			Expect(os.WriteFile(source, []byte("secret content"), 0644)).To(Succeed())
+			metadata := map[string]string{"owner": "cc"}
+
+			Expect(encrypted.PutWithOptions(context.Background(), source, "droplet", PutOptions{Metadata: metadata})).To(Succeed())
+			_, _, _, opts := fakeStorager.PutStreamWithOptionsArgsForCall(0)
+			Expect(opts.Metadata).To(Equal(map[string]string{"owner": "cc", plaintextSizeMetadataKey: "14"}))
+			Expect(metadata).To(Equal(map[string]string{"owner": "cc"}))
+
+			fakeStorager.PropertiesReturns(Properties{ETag: "etag", ContentLength: int64(len(stored)), ContentMD5: "abc", Metadata: opts.Metadata}, nil)
+			info, exists, err := encrypted.Stat(context.Background(), "droplet")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+			Expect(info).To(Equal(ObjectInfo{Name: "droplet", Size: 14, ETag: "etag"}))
+			Expect(fakeStorager.StatCallCount()).To(BeZero())
+			Expect(fakeStorager.GetStreamWithOptionsCallCount()).To(BeZero())
+		})
+
+		It("reads the header of objects uploaded from streams", func() {
+			Expect(encrypted.PutStream(context.Background(), bytes.NewReader([]byte("secret content")), "droplet")).To(Succeed())
+			_, _, _, opts := fakeStorager.PutStreamWithOptionsArgsForCall(0)
+			Expect(opts.Metadata).To(BeEmpty())
+
+			fakeStorager.PropertiesReturns(Properties{ContentLength: int64(len(stored))}, nil)
+			props, err := encrypted.Properties(context.Background(), "droplet")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(props.ContentLength).To(Equal(int64(14)))
+			Expect(fakeStorager.GetStreamWithOptionsCallCount()).To(Equal(1))
+		})
+
+		It("reports missing objects", func() {
+			fakeStorager.PropertiesReturns(Properties{}, common.NewError(common.ErrNotFound, errors.New("no such key")))
+			_, exists, err := encrypted.Stat(context.Background(), "droplet")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+	})
+
+	It("rejects byte ranges and checkpoints", func() {
+		_, err := encrypted.GetStreamWithOptions(context.Background(), "droplet", GetOptions{Range: &ByteRange{Start: 0, End: 3}})
+		Expect(err).To(MatchError(errEncryptedRange))
+
+		err = encrypted.PutWithOptions(context.Background(), "droplet.tgz", "droplet", PutOptions{Checkpoint: "checkpoint"})
+		Expect(err).To(MatchError(ContainSubstring("checkpointed uploads are not supported")))
+	})
+
+	It("fails to read objects written without encryption", func() {
+		stored = []byte("plain content")
+		_, err := encrypted.GetStream(context.Background(), "droplet")
+		Expect(err).To(MatchError(common.ErrNotEncrypted))
+	})
+
+	It("keeps the backend reachable for backend-specific commands", func() {
+		Expect(unwrap(encrypted)).To(BeIdenticalTo(fakeStorager))
+	})
+
+	It("leaves backends without the section unwrapped", func() {
+		configFile, err := os.Create(filepath.Join(GinkgoT().TempDir(), "config.json"))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(configFile.Close)
+		_, err = configFile.WriteString(`{"bucket_name":"b"}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(newEncryptingStorager(fakeStorager, configFile, true)).To(BeIdenticalTo(fakeStorager))
+	})
+})
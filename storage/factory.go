@@ -9,10 +9,13 @@ import (
 	aliossconfig "github.com/cloudfoundry/storage-cli/alioss/config"
 	azurebs "github.com/cloudfoundry/storage-cli/azurebs/client"
 	azureconfigbs "github.com/cloudfoundry/storage-cli/azurebs/config"
+	"github.com/cloudfoundry/storage-cli/common"
 	dav "github.com/cloudfoundry/storage-cli/dav/client"
 	davconfig "github.com/cloudfoundry/storage-cli/dav/config"
 	gcs "github.com/cloudfoundry/storage-cli/gcs/client"
 	gcsconfig "github.com/cloudfoundry/storage-cli/gcs/config"
+	local "github.com/cloudfoundry/storage-cli/local/client"
+	localconfig "github.com/cloudfoundry/storage-cli/local/config"
 	s3 "github.com/cloudfoundry/storage-cli/s3/client"
 	s3config "github.com/cloudfoundry/storage-cli/s3/config"
 )
@@ -20,7 +23,7 @@ import (
 var newAzurebsClient = func(configFile *os.File) (Storager, error) {
 	conf, err := azureconfigbs.NewFromReader(configFile)
 	if err != nil {
-		return nil, err
+		return nil, invalidConfig(err)
 	}
 
 	sc, err := azurebs.NewStorageClient(conf)
@@ -38,7 +41,7 @@ var newAzurebsClient = func(configFile *os.File) (Storager, error) {
 var newAliossClient = func(configFile *os.File) (Storager, error) {
 	aliConfig, err := aliossconfig.NewFromReader(configFile)
 	if err != nil {
-		return nil, err
+		return nil, invalidConfig(err)
 	}
 
 	storageClient, err := alioss.NewStorageClient(aliConfig)
@@ -57,7 +60,7 @@ var newAliossClient = func(configFile *os.File) (Storager, error) {
 var newGcsClient = func(configFile *os.File) (Storager, error) {
 	gcsConfig, err := gcsconfig.NewFromReader(configFile)
 	if err != nil {
-		return nil, err
+		return nil, invalidConfig(err)
 	}
 
 	ctx := context.Background()
@@ -72,7 +75,7 @@ var newGcsClient = func(configFile *os.File) (Storager, error) {
 var newS3Client = func(configFile *os.File) (Storager, error) {
 	s3Config, err := s3config.NewFromReader(configFile)
 	if err != nil {
-		return nil, err
+		return nil, invalidConfig(err)
 	}
 
 	s3Client, err := s3.NewAwsS3Client(&s3Config)
@@ -87,7 +90,7 @@ var newS3Client = func(configFile *os.File) (Storager, error) {
 var newDavClient = func(configFile *os.File) (Storager, error) {
 	davConfig, err := davconfig.NewFromReader(configFile)
 	if err != nil {
-		return nil, err
+		return nil, invalidConfig(err)
 	}
 
 	davClient, err := dav.New(davConfig)
@@ -98,7 +101,51 @@ var newDavClient = func(configFile *os.File) (Storager, error) {
 	return davClient, nil
 }
 
+var newLocalClient = func(configFile *os.File) (Storager, error) {
+	localConfig, err := localconfig.NewFromReader(configFile)
+	if err != nil {
+		return nil, invalidConfig(err)
+	}
+
+	localClient, err := local.New(localConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return localClient, nil
+}
+
+// NewStorageClient creates the client of storageType configured by
+// configFile, encrypting objects if the file has a client_side_encryption
+// section. Uploads with PutOptions.Compression are compressed before they
+// are encrypted, on backends keeping their Content-Encoding. Configuration files that cannot be parsed or are invalid
+// are reported as common.ErrInvalidConfig, while other failures, like
+// resolving credentials, keep their own kind.
 func NewStorageClient(storageType string, configFile *os.File) (Storager, error) {
+	client, err := newStorageClient(storageType, configFile)
+	if err == nil {
+		client, err = newEncryptingStorager(client, configFile, supportsMetadata(storageType))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newCompressingStorager(client, supportsMetadata(storageType)), nil
+}
+
+// supportsMetadata reports whether backends of storageType keep the content
+// headers and user-defined metadata of uploads, which local storage and
+// WebDAV do not.
+func supportsMetadata(storageType string) bool {
+	return storageType != "local" && storageType != "dav"
+}
+
+// invalidConfig reports err, a failure to parse or validate a configuration
+// file, as common.ErrInvalidConfig.
+func invalidConfig(err error) error {
+	return common.NewError(common.ErrInvalidConfig, err)
+}
+
+func newStorageClient(storageType string, configFile *os.File) (Storager, error) {
 	switch storageType {
 	case "azurebs":
 		return newAzurebsClient(configFile)
@@ -110,7 +157,9 @@ func NewStorageClient(storageType string, configFile *os.File) (Storager, error)
 		return newGcsClient(configFile)
 	case "dav":
 		return newDavClient(configFile)
+	case "local":
+		return newLocalClient(configFile)
 	default:
-		return nil, fmt.Errorf("storage %s not implemented", storageType)
+		return nil, invalidConfig(fmt.Errorf("storage %s not implemented", storageType))
 	}
 }
@@ -1,8 +1,12 @@
 package storage
 
 import (
+	"errors"
+	"io"
 	"os"
 
+	"github.com/cloudfoundry/storage-cli/common"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -35,7 +39,7 @@ var _ = Describe("Factory", func() {
 				client, err := NewStorageClient("alioss", configFile)
 				Expect(client).ToNot(BeNil())
 				Expect(err).ToNot(HaveOccurred())
-				Expect(client).To(Equal(mockClient))
+				Expect(unwrap(client)).To(Equal(mockClient))
 			})
 
 		})
@@ -55,7 +59,7 @@ var _ = Describe("Factory", func() {
 				client, err := NewStorageClient("azurebs", configFile)
 				Expect(client).ToNot(BeNil())
 				Expect(err).ToNot(HaveOccurred())
-				Expect(client).To(Equal(mockClient))
+				Expect(unwrap(client)).To(Equal(mockClient))
 			})
 
 		})
@@ -75,7 +79,7 @@ var _ = Describe("Factory", func() {
 				client, err := NewStorageClient("dav", configFile)
 				Expect(client).ToNot(BeNil())
 				Expect(err).ToNot(HaveOccurred())
-				Expect(client).To(Equal(mockClient))
+				Expect(unwrap(client)).To(Equal(mockClient))
 			})
 
 		})
@@ -95,7 +99,27 @@ var _ = Describe("Factory", func() {
 				client, err := NewStorageClient("gcs", configFile)
 				Expect(client).ToNot(BeNil())
 				Expect(err).ToNot(HaveOccurred())
-				Expect(client).To(Equal(mockClient))
+				Expect(unwrap(client)).To(Equal(mockClient))
+			})
+
+		})
+
+		Context("local", func() {
+			It("Create a client", func() {
+				original := newLocalClient
+				DeferCleanup(func() {
+					newLocalClient = original
+				})
+
+				mockClient := &FakeStorager{}
+				newLocalClient = func(configFile *os.File) (Storager, error) {
+					return mockClient, nil
+				}
+
+				client, err := NewStorageClient("local", configFile)
+				Expect(client).ToNot(BeNil())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(unwrap(client)).To(Equal(mockClient))
 			})
 
 		})
@@ -115,15 +139,39 @@ var _ = Describe("Factory", func() {
 				client, err := NewStorageClient("s3", configFile)
 				Expect(client).ToNot(BeNil())
 				Expect(err).ToNot(HaveOccurred())
-				Expect(client).To(Equal(mockClient))
+				Expect(unwrap(client)).To(Equal(mockClient))
 			})
 
 		})
 
 		It("Unimplemented Client", func() {
 			client, err := NewStorageClient("random-client", configFile)
-			Expect(err).To(HaveOccurred())
+			Expect(err).To(MatchError(common.ErrInvalidConfig))
 			Expect(client).To(BeNil())
 		})
+
+		It("reports configuration files that cannot be parsed as invalid", func() {
+			_, err := configFile.WriteString("{")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = configFile.Seek(0, io.SeekStart)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = NewStorageClient("local", configFile)
+			Expect(err).To(MatchError(common.ErrInvalidConfig))
+		})
+
+		It("keeps the kind of other failures", func() {
+			original := newGcsClient
+			DeferCleanup(func() {
+				newGcsClient = original
+			})
+			newGcsClient = func(configFile *os.File) (Storager, error) {
+				return nil, errors.New("resolving credentials: connection refused")
+			}
+
+			_, err := NewStorageClient("gcs", configFile)
+			Expect(err).To(MatchError("resolving credentials: connection refused"))
+			Expect(common.ErrorKind(err)).To(BeNil())
+		})
 	})
 })
@@ -2,16 +2,19 @@
 package storage
 
 import (
+	"context"
+	"io"
 	"sync"
 	"time"
 )
 
 type FakeStorager struct {
-	CopyStub        func(string, string) error
+	CopyStub        func(context.Context, string, string) error
 	copyMutex       sync.RWMutex
 	copyArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
 	}
 	copyReturns struct {
 		result1 error
@@ -19,10 +22,11 @@ type FakeStorager struct {
 	copyReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteStub        func(string) error
+	DeleteStub        func(context.Context, string) error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	deleteReturns struct {
 		result1 error
@@ -30,10 +34,23 @@ type FakeStorager struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteRecursiveStub        func(string) error
+	DeleteManyStub        func(context.Context, []string) []DeleteFailure
+	deleteManyMutex       sync.RWMutex
+	deleteManyArgsForCall []struct {
+		arg1 context.Context
+		arg2 []string
+	}
+	deleteManyReturns struct {
+		result1 []DeleteFailure
+	}
+	deleteManyReturnsOnCall map[int]struct {
+		result1 []DeleteFailure
+	}
+	DeleteRecursiveStub        func(context.Context, string) error
 	deleteRecursiveMutex       sync.RWMutex
 	deleteRecursiveArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	deleteRecursiveReturns struct {
 		result1 error
@@ -41,9 +58,23 @@ type FakeStorager struct {
 	deleteRecursiveReturnsOnCall map[int]struct {
 		result1 error
 	}
-	EnsureStorageExistsStub        func() error
+	DeleteWithOptionsStub        func(context.Context, string, DeleteOptions) error
+	deleteWithOptionsMutex       sync.RWMutex
+	deleteWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 DeleteOptions
+	}
+	deleteWithOptionsReturns struct {
+		result1 error
+	}
+	deleteWithOptionsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	EnsureStorageExistsStub        func(context.Context) error
 	ensureStorageExistsMutex       sync.RWMutex
 	ensureStorageExistsArgsForCall []struct {
+		arg1 context.Context
 	}
 	ensureStorageExistsReturns struct {
 		result1 error
@@ -51,10 +82,11 @@ type FakeStorager struct {
 	ensureStorageExistsReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ExistsStub        func(string) (bool, error)
+	ExistsStub        func(context.Context, string) (bool, error)
 	existsMutex       sync.RWMutex
 	existsArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	existsReturns struct {
 		result1 bool
@@ -64,11 +96,12 @@ type FakeStorager struct {
 		result1 bool
 		result2 error
 	}
-	GetStub        func(string, string) error
+	GetStub        func(context.Context, string, string) error
 	getMutex       sync.RWMutex
 	getArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
 	}
 	getReturns struct {
 		result1 error
@@ -76,10 +109,54 @@ type FakeStorager struct {
 	getReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ListStub        func(string) ([]string, error)
+	GetStreamStub        func(context.Context, string) (io.ReadCloser, error)
+	getStreamMutex       sync.RWMutex
+	getStreamArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getStreamReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	getStreamReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	GetStreamWithOptionsStub        func(context.Context, string, GetOptions) (io.ReadCloser, error)
+	getStreamWithOptionsMutex       sync.RWMutex
+	getStreamWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 GetOptions
+	}
+	getStreamWithOptionsReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	getStreamWithOptionsReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	GetWithOptionsStub        func(context.Context, string, string, GetOptions) error
+	getWithOptionsMutex       sync.RWMutex
+	getWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 GetOptions
+	}
+	getWithOptionsReturns struct {
+		result1 error
+	}
+	getWithOptionsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ListStub        func(context.Context, string) ([]string, error)
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	listReturns struct {
 		result1 []string
@@ -89,22 +166,67 @@ type FakeStorager struct {
 		result1 []string
 		result2 error
 	}
-	PropertiesStub        func(string) error
+	ListObjectsStub        func(context.Context, string) ([]ObjectInfo, error)
+	listObjectsMutex       sync.RWMutex
+	listObjectsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	listObjectsReturns struct {
+		result1 []ObjectInfo
+		result2 error
+	}
+	listObjectsReturnsOnCall map[int]struct {
+		result1 []ObjectInfo
+		result2 error
+	}
+	ListWithOptionsStub        func(context.Context, ListOptions) (ListResult, error)
+	listWithOptionsMutex       sync.RWMutex
+	listWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 ListOptions
+	}
+	listWithOptionsReturns struct {
+		result1 ListResult
+		result2 error
+	}
+	listWithOptionsReturnsOnCall map[int]struct {
+		result1 ListResult
+		result2 error
+	}
+	MoveStub        func(context.Context, string, string) error
+	moveMutex       sync.RWMutex
+	moveArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	moveReturns struct {
+		result1 error
+	}
+	moveReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PropertiesStub        func(context.Context, string) (Properties, error)
 	propertiesMutex       sync.RWMutex
 	propertiesArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	propertiesReturns struct {
-		result1 error
+		result1 Properties
+		result2 error
 	}
 	propertiesReturnsOnCall map[int]struct {
-		result1 error
+		result1 Properties
+		result2 error
 	}
-	PutStub        func(string, string) error
+	PutStub        func(context.Context, string, string) error
 	putMutex       sync.RWMutex
 	putArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
 	}
 	putReturns struct {
 		result1 error
@@ -112,12 +234,54 @@ type FakeStorager struct {
 	putReturnsOnCall map[int]struct {
 		result1 error
 	}
-	SignStub        func(string, string, time.Duration) (string, error)
+	PutStreamStub        func(context.Context, io.Reader, string) error
+	putStreamMutex       sync.RWMutex
+	putStreamArgsForCall []struct {
+		arg1 context.Context
+		arg2 io.Reader
+		arg3 string
+	}
+	putStreamReturns struct {
+		result1 error
+	}
+	putStreamReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PutStreamWithOptionsStub        func(context.Context, io.Reader, string, PutOptions) error
+	putStreamWithOptionsMutex       sync.RWMutex
+	putStreamWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 io.Reader
+		arg3 string
+		arg4 PutOptions
+	}
+	putStreamWithOptionsReturns struct {
+		result1 error
+	}
+	putStreamWithOptionsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	PutWithOptionsStub        func(context.Context, string, string, PutOptions) error
+	putWithOptionsMutex       sync.RWMutex
+	putWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 PutOptions
+	}
+	putWithOptionsReturns struct {
+		result1 error
+	}
+	putWithOptionsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SignStub        func(context.Context, string, string, time.Duration) (string, error)
 	signMutex       sync.RWMutex
 	signArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 time.Duration
+		arg3 string
+		arg4 time.Duration
 	}
 	signReturns struct {
 		result1 string
@@ -127,23 +291,40 @@ type FakeStorager struct {
 		result1 string
 		result2 error
 	}
+	StatStub        func(context.Context, string) (ObjectInfo, bool, error)
+	statMutex       sync.RWMutex
+	statArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	statReturns struct {
+		result1 ObjectInfo
+		result2 bool
+		result3 error
+	}
+	statReturnsOnCall map[int]struct {
+		result1 ObjectInfo
+		result2 bool
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStorager) Copy(arg1 string, arg2 string) error {
+func (fake *FakeStorager) Copy(arg1 context.Context, arg2 string, arg3 string) error {
 	fake.copyMutex.Lock()
 	ret, specificReturn := fake.copyReturnsOnCall[len(fake.copyArgsForCall)]
 	fake.copyArgsForCall = append(fake.copyArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+	}{arg1, arg2, arg3})
 	stub := fake.CopyStub
 	fakeReturns := fake.copyReturns
-	fake.recordInvocation("Copy", []interface{}{arg1, arg2})
+	fake.recordInvocation("Copy", []interface{}{arg1, arg2, arg3})
 	fake.copyMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -157,17 +338,17 @@ func (fake *FakeStorager) CopyCallCount() int {
 	return len(fake.copyArgsForCall)
 }
 
-func (fake *FakeStorager) CopyCalls(stub func(string, string) error) {
+func (fake *FakeStorager) CopyCalls(stub func(context.Context, string, string) error) {
 	fake.copyMutex.Lock()
 	defer fake.copyMutex.Unlock()
 	fake.CopyStub = stub
 }
 
-func (fake *FakeStorager) CopyArgsForCall(i int) (string, string) {
+func (fake *FakeStorager) CopyArgsForCall(i int) (context.Context, string, string) {
 	fake.copyMutex.RLock()
 	defer fake.copyMutex.RUnlock()
 	argsForCall := fake.copyArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorager) CopyReturns(result1 error) {
@@ -193,18 +374,19 @@ func (fake *FakeStorager) CopyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorager) Delete(arg1 string) error {
+func (fake *FakeStorager) Delete(arg1 context.Context, arg2 string) error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
 	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.DeleteStub
 	fakeReturns := fake.deleteReturns
-	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
 	fake.deleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -218,17 +400,17 @@ func (fake *FakeStorager) DeleteCallCount() int {
 	return len(fake.deleteArgsForCall)
 }
 
-func (fake *FakeStorager) DeleteCalls(stub func(string) error) {
+func (fake *FakeStorager) DeleteCalls(stub func(context.Context, string) error) {
 	fake.deleteMutex.Lock()
 	defer fake.deleteMutex.Unlock()
 	fake.DeleteStub = stub
 }
 
-func (fake *FakeStorager) DeleteArgsForCall(i int) string {
+func (fake *FakeStorager) DeleteArgsForCall(i int) (context.Context, string) {
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
 	argsForCall := fake.deleteArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorager) DeleteReturns(result1 error) {
@@ -254,18 +436,86 @@ func (fake *FakeStorager) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorager) DeleteRecursive(arg1 string) error {
+func (fake *FakeStorager) DeleteMany(arg1 context.Context, arg2 []string) []DeleteFailure {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.deleteManyMutex.Lock()
+	ret, specificReturn := fake.deleteManyReturnsOnCall[len(fake.deleteManyArgsForCall)]
+	fake.deleteManyArgsForCall = append(fake.deleteManyArgsForCall, struct {
+		arg1 context.Context
+		arg2 []string
+	}{arg1, arg2Copy})
+	stub := fake.DeleteManyStub
+	fakeReturns := fake.deleteManyReturns
+	fake.recordInvocation("DeleteMany", []interface{}{arg1, arg2Copy})
+	fake.deleteManyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) DeleteManyCallCount() int {
+	fake.deleteManyMutex.RLock()
+	defer fake.deleteManyMutex.RUnlock()
+	return len(fake.deleteManyArgsForCall)
+}
+
+func (fake *FakeStorager) DeleteManyCalls(stub func(context.Context, []string) []DeleteFailure) {
+	fake.deleteManyMutex.Lock()
+	defer fake.deleteManyMutex.Unlock()
+	fake.DeleteManyStub = stub
+}
+
+func (fake *FakeStorager) DeleteManyArgsForCall(i int) (context.Context, []string) {
+	fake.deleteManyMutex.RLock()
+	defer fake.deleteManyMutex.RUnlock()
+	argsForCall := fake.deleteManyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorager) DeleteManyReturns(result1 []DeleteFailure) {
+	fake.deleteManyMutex.Lock()
+	defer fake.deleteManyMutex.Unlock()
+	fake.DeleteManyStub = nil
+	fake.deleteManyReturns = struct {
+		result1 []DeleteFailure
+	}{result1}
+}
+
+func (fake *FakeStorager) DeleteManyReturnsOnCall(i int, result1 []DeleteFailure) {
+	fake.deleteManyMutex.Lock()
+	defer fake.deleteManyMutex.Unlock()
+	fake.DeleteManyStub = nil
+	if fake.deleteManyReturnsOnCall == nil {
+		fake.deleteManyReturnsOnCall = make(map[int]struct {
+			result1 []DeleteFailure
+		})
+	}
+	fake.deleteManyReturnsOnCall[i] = struct {
+		result1 []DeleteFailure
+	}{result1}
+}
+
+func (fake *FakeStorager) DeleteRecursive(arg1 context.Context, arg2 string) error {
 	fake.deleteRecursiveMutex.Lock()
 	ret, specificReturn := fake.deleteRecursiveReturnsOnCall[len(fake.deleteRecursiveArgsForCall)]
 	fake.deleteRecursiveArgsForCall = append(fake.deleteRecursiveArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.DeleteRecursiveStub
 	fakeReturns := fake.deleteRecursiveReturns
-	fake.recordInvocation("DeleteRecursive", []interface{}{arg1})
+	fake.recordInvocation("DeleteRecursive", []interface{}{arg1, arg2})
 	fake.deleteRecursiveMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -279,17 +529,17 @@ func (fake *FakeStorager) DeleteRecursiveCallCount() int {
 	return len(fake.deleteRecursiveArgsForCall)
 }
 
-func (fake *FakeStorager) DeleteRecursiveCalls(stub func(string) error) {
+func (fake *FakeStorager) DeleteRecursiveCalls(stub func(context.Context, string) error) {
 	fake.deleteRecursiveMutex.Lock()
 	defer fake.deleteRecursiveMutex.Unlock()
 	fake.DeleteRecursiveStub = stub
 }
 
-func (fake *FakeStorager) DeleteRecursiveArgsForCall(i int) string {
+func (fake *FakeStorager) DeleteRecursiveArgsForCall(i int) (context.Context, string) {
 	fake.deleteRecursiveMutex.RLock()
 	defer fake.deleteRecursiveMutex.RUnlock()
 	argsForCall := fake.deleteRecursiveArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorager) DeleteRecursiveReturns(result1 error) {
@@ -315,17 +565,81 @@ func (fake *FakeStorager) DeleteRecursiveReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorager) EnsureStorageExists() error {
+func (fake *FakeStorager) DeleteWithOptions(arg1 context.Context, arg2 string, arg3 DeleteOptions) error {
+	fake.deleteWithOptionsMutex.Lock()
+	ret, specificReturn := fake.deleteWithOptionsReturnsOnCall[len(fake.deleteWithOptionsArgsForCall)]
+	fake.deleteWithOptionsArgsForCall = append(fake.deleteWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 DeleteOptions
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteWithOptionsStub
+	fakeReturns := fake.deleteWithOptionsReturns
+	fake.recordInvocation("DeleteWithOptions", []interface{}{arg1, arg2, arg3})
+	fake.deleteWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) DeleteWithOptionsCallCount() int {
+	fake.deleteWithOptionsMutex.RLock()
+	defer fake.deleteWithOptionsMutex.RUnlock()
+	return len(fake.deleteWithOptionsArgsForCall)
+}
+
+func (fake *FakeStorager) DeleteWithOptionsCalls(stub func(context.Context, string, DeleteOptions) error) {
+	fake.deleteWithOptionsMutex.Lock()
+	defer fake.deleteWithOptionsMutex.Unlock()
+	fake.DeleteWithOptionsStub = stub
+}
+
+func (fake *FakeStorager) DeleteWithOptionsArgsForCall(i int) (context.Context, string, DeleteOptions) {
+	fake.deleteWithOptionsMutex.RLock()
+	defer fake.deleteWithOptionsMutex.RUnlock()
+	argsForCall := fake.deleteWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorager) DeleteWithOptionsReturns(result1 error) {
+	fake.deleteWithOptionsMutex.Lock()
+	defer fake.deleteWithOptionsMutex.Unlock()
+	fake.DeleteWithOptionsStub = nil
+	fake.deleteWithOptionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) DeleteWithOptionsReturnsOnCall(i int, result1 error) {
+	fake.deleteWithOptionsMutex.Lock()
+	defer fake.deleteWithOptionsMutex.Unlock()
+	fake.DeleteWithOptionsStub = nil
+	if fake.deleteWithOptionsReturnsOnCall == nil {
+		fake.deleteWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteWithOptionsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) EnsureStorageExists(arg1 context.Context) error {
 	fake.ensureStorageExistsMutex.Lock()
 	ret, specificReturn := fake.ensureStorageExistsReturnsOnCall[len(fake.ensureStorageExistsArgsForCall)]
 	fake.ensureStorageExistsArgsForCall = append(fake.ensureStorageExistsArgsForCall, struct {
-	}{})
+		arg1 context.Context
+	}{arg1})
 	stub := fake.EnsureStorageExistsStub
 	fakeReturns := fake.ensureStorageExistsReturns
-	fake.recordInvocation("EnsureStorageExists", []interface{}{})
+	fake.recordInvocation("EnsureStorageExists", []interface{}{arg1})
 	fake.ensureStorageExistsMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -339,12 +653,19 @@ func (fake *FakeStorager) EnsureStorageExistsCallCount() int {
 	return len(fake.ensureStorageExistsArgsForCall)
 }
 
-func (fake *FakeStorager) EnsureStorageExistsCalls(stub func() error) {
+func (fake *FakeStorager) EnsureStorageExistsCalls(stub func(context.Context) error) {
 	fake.ensureStorageExistsMutex.Lock()
 	defer fake.ensureStorageExistsMutex.Unlock()
 	fake.EnsureStorageExistsStub = stub
 }
 
+func (fake *FakeStorager) EnsureStorageExistsArgsForCall(i int) context.Context {
+	fake.ensureStorageExistsMutex.RLock()
+	defer fake.ensureStorageExistsMutex.RUnlock()
+	argsForCall := fake.ensureStorageExistsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeStorager) EnsureStorageExistsReturns(result1 error) {
 	fake.ensureStorageExistsMutex.Lock()
 	defer fake.ensureStorageExistsMutex.Unlock()
@@ -368,18 +689,19 @@ func (fake *FakeStorager) EnsureStorageExistsReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
-func (fake *FakeStorager) Exists(arg1 string) (bool, error) {
+func (fake *FakeStorager) Exists(arg1 context.Context, arg2 string) (bool, error) {
 	fake.existsMutex.Lock()
 	ret, specificReturn := fake.existsReturnsOnCall[len(fake.existsArgsForCall)]
 	fake.existsArgsForCall = append(fake.existsArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.ExistsStub
 	fakeReturns := fake.existsReturns
-	fake.recordInvocation("Exists", []interface{}{arg1})
+	fake.recordInvocation("Exists", []interface{}{arg1, arg2})
 	fake.existsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -393,17 +715,17 @@ func (fake *FakeStorager) ExistsCallCount() int {
 	return len(fake.existsArgsForCall)
 }
 
-func (fake *FakeStorager) ExistsCalls(stub func(string) (bool, error)) {
+func (fake *FakeStorager) ExistsCalls(stub func(context.Context, string) (bool, error)) {
 	fake.existsMutex.Lock()
 	defer fake.existsMutex.Unlock()
 	fake.ExistsStub = stub
 }
 
-func (fake *FakeStorager) ExistsArgsForCall(i int) string {
+func (fake *FakeStorager) ExistsArgsForCall(i int) (context.Context, string) {
 	fake.existsMutex.RLock()
 	defer fake.existsMutex.RUnlock()
 	argsForCall := fake.existsArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorager) ExistsReturns(result1 bool, result2 error) {
@@ -432,19 +754,20 @@ func (fake *FakeStorager) ExistsReturnsOnCall(i int, result1 bool, result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeStorager) Get(arg1 string, arg2 string) error {
+func (fake *FakeStorager) Get(arg1 context.Context, arg2 string, arg3 string) error {
 	fake.getMutex.Lock()
 	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
 	fake.getArgsForCall = append(fake.getArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+	}{arg1, arg2, arg3})
 	stub := fake.GetStub
 	fakeReturns := fake.getReturns
-	fake.recordInvocation("Get", []interface{}{arg1, arg2})
+	fake.recordInvocation("Get", []interface{}{arg1, arg2, arg3})
 	fake.getMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -458,17 +781,17 @@ func (fake *FakeStorager) GetCallCount() int {
 	return len(fake.getArgsForCall)
 }
 
-func (fake *FakeStorager) GetCalls(stub func(string, string) error) {
+func (fake *FakeStorager) GetCalls(stub func(context.Context, string, string) error) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = stub
 }
 
-func (fake *FakeStorager) GetArgsForCall(i int) (string, string) {
+func (fake *FakeStorager) GetArgsForCall(i int) (context.Context, string, string) {
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
 	argsForCall := fake.getArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorager) GetReturns(result1 error) {
@@ -494,18 +817,214 @@ func (fake *FakeStorager) GetReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorager) List(arg1 string) ([]string, error) {
+func (fake *FakeStorager) GetStream(arg1 context.Context, arg2 string) (io.ReadCloser, error) {
+	fake.getStreamMutex.Lock()
+	ret, specificReturn := fake.getStreamReturnsOnCall[len(fake.getStreamArgsForCall)]
+	fake.getStreamArgsForCall = append(fake.getStreamArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetStreamStub
+	fakeReturns := fake.getStreamReturns
+	fake.recordInvocation("GetStream", []interface{}{arg1, arg2})
+	fake.getStreamMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) GetStreamCallCount() int {
+	fake.getStreamMutex.RLock()
+	defer fake.getStreamMutex.RUnlock()
+	return len(fake.getStreamArgsForCall)
+}
+
+func (fake *FakeStorager) GetStreamCalls(stub func(context.Context, string) (io.ReadCloser, error)) {
+	fake.getStreamMutex.Lock()
+	defer fake.getStreamMutex.Unlock()
+	fake.GetStreamStub = stub
+}
+
+func (fake *FakeStorager) GetStreamArgsForCall(i int) (context.Context, string) {
+	fake.getStreamMutex.RLock()
+	defer fake.getStreamMutex.RUnlock()
+	argsForCall := fake.getStreamArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorager) GetStreamReturns(result1 io.ReadCloser, result2 error) {
+	fake.getStreamMutex.Lock()
+	defer fake.getStreamMutex.Unlock()
+	fake.GetStreamStub = nil
+	fake.getStreamReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) GetStreamReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.getStreamMutex.Lock()
+	defer fake.getStreamMutex.Unlock()
+	fake.GetStreamStub = nil
+	if fake.getStreamReturnsOnCall == nil {
+		fake.getStreamReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.getStreamReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) GetStreamWithOptions(arg1 context.Context, arg2 string, arg3 GetOptions) (io.ReadCloser, error) {
+	fake.getStreamWithOptionsMutex.Lock()
+	ret, specificReturn := fake.getStreamWithOptionsReturnsOnCall[len(fake.getStreamWithOptionsArgsForCall)]
+	fake.getStreamWithOptionsArgsForCall = append(fake.getStreamWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 GetOptions
+	}{arg1, arg2, arg3})
+	stub := fake.GetStreamWithOptionsStub
+	fakeReturns := fake.getStreamWithOptionsReturns
+	fake.recordInvocation("GetStreamWithOptions", []interface{}{arg1, arg2, arg3})
+	fake.getStreamWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) GetStreamWithOptionsCallCount() int {
+	fake.getStreamWithOptionsMutex.RLock()
+	defer fake.getStreamWithOptionsMutex.RUnlock()
+	return len(fake.getStreamWithOptionsArgsForCall)
+}
+
+func (fake *FakeStorager) GetStreamWithOptionsCalls(stub func(context.Context, string, GetOptions) (io.ReadCloser, error)) {
+	fake.getStreamWithOptionsMutex.Lock()
+	defer fake.getStreamWithOptionsMutex.Unlock()
+	fake.GetStreamWithOptionsStub = stub
+}
+
+func (fake *FakeStorager) GetStreamWithOptionsArgsForCall(i int) (context.Context, string, GetOptions) {
+	fake.getStreamWithOptionsMutex.RLock()
+	defer fake.getStreamWithOptionsMutex.RUnlock()
+	argsForCall := fake.getStreamWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorager) GetStreamWithOptionsReturns(result1 io.ReadCloser, result2 error) {
+	fake.getStreamWithOptionsMutex.Lock()
+	defer fake.getStreamWithOptionsMutex.Unlock()
+	fake.GetStreamWithOptionsStub = nil
+	fake.getStreamWithOptionsReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) GetStreamWithOptionsReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.getStreamWithOptionsMutex.Lock()
+	defer fake.getStreamWithOptionsMutex.Unlock()
+	fake.GetStreamWithOptionsStub = nil
+	if fake.getStreamWithOptionsReturnsOnCall == nil {
+		fake.getStreamWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.getStreamWithOptionsReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) GetWithOptions(arg1 context.Context, arg2 string, arg3 string, arg4 GetOptions) error {
+	fake.getWithOptionsMutex.Lock()
+	ret, specificReturn := fake.getWithOptionsReturnsOnCall[len(fake.getWithOptionsArgsForCall)]
+	fake.getWithOptionsArgsForCall = append(fake.getWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 GetOptions
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.GetWithOptionsStub
+	fakeReturns := fake.getWithOptionsReturns
+	fake.recordInvocation("GetWithOptions", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) GetWithOptionsCallCount() int {
+	fake.getWithOptionsMutex.RLock()
+	defer fake.getWithOptionsMutex.RUnlock()
+	return len(fake.getWithOptionsArgsForCall)
+}
+
+func (fake *FakeStorager) GetWithOptionsCalls(stub func(context.Context, string, string, GetOptions) error) {
+	fake.getWithOptionsMutex.Lock()
+	defer fake.getWithOptionsMutex.Unlock()
+	fake.GetWithOptionsStub = stub
+}
+
+func (fake *FakeStorager) GetWithOptionsArgsForCall(i int) (context.Context, string, string, GetOptions) {
+	fake.getWithOptionsMutex.RLock()
+	defer fake.getWithOptionsMutex.RUnlock()
+	argsForCall := fake.getWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStorager) GetWithOptionsReturns(result1 error) {
+	fake.getWithOptionsMutex.Lock()
+	defer fake.getWithOptionsMutex.Unlock()
+	fake.GetWithOptionsStub = nil
+	fake.getWithOptionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) GetWithOptionsReturnsOnCall(i int, result1 error) {
+	fake.getWithOptionsMutex.Lock()
+	defer fake.getWithOptionsMutex.Unlock()
+	fake.GetWithOptionsStub = nil
+	if fake.getWithOptionsReturnsOnCall == nil {
+		fake.getWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.getWithOptionsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) List(arg1 context.Context, arg2 string) ([]string, error) {
 	fake.listMutex.Lock()
 	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
 	fake.listArgsForCall = append(fake.listArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.ListStub
 	fakeReturns := fake.listReturns
-	fake.recordInvocation("List", []interface{}{arg1})
+	fake.recordInvocation("List", []interface{}{arg1, arg2})
 	fake.listMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -519,17 +1038,17 @@ func (fake *FakeStorager) ListCallCount() int {
 	return len(fake.listArgsForCall)
 }
 
-func (fake *FakeStorager) ListCalls(stub func(string) ([]string, error)) {
+func (fake *FakeStorager) ListCalls(stub func(context.Context, string) ([]string, error)) {
 	fake.listMutex.Lock()
 	defer fake.listMutex.Unlock()
 	fake.ListStub = stub
 }
 
-func (fake *FakeStorager) ListArgsForCall(i int) string {
+func (fake *FakeStorager) ListArgsForCall(i int) (context.Context, string) {
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
 	argsForCall := fake.listArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorager) ListReturns(result1 []string, result2 error) {
@@ -558,23 +1077,217 @@ func (fake *FakeStorager) ListReturnsOnCall(i int, result1 []string, result2 err
 	}{result1, result2}
 }
 
-func (fake *FakeStorager) Properties(arg1 string) error {
+func (fake *FakeStorager) ListObjects(arg1 context.Context, arg2 string) ([]ObjectInfo, error) {
+	fake.listObjectsMutex.Lock()
+	ret, specificReturn := fake.listObjectsReturnsOnCall[len(fake.listObjectsArgsForCall)]
+	fake.listObjectsArgsForCall = append(fake.listObjectsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListObjectsStub
+	fakeReturns := fake.listObjectsReturns
+	fake.recordInvocation("ListObjects", []interface{}{arg1, arg2})
+	fake.listObjectsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) ListObjectsCallCount() int {
+	fake.listObjectsMutex.RLock()
+	defer fake.listObjectsMutex.RUnlock()
+	return len(fake.listObjectsArgsForCall)
+}
+
+func (fake *FakeStorager) ListObjectsCalls(stub func(context.Context, string) ([]ObjectInfo, error)) {
+	fake.listObjectsMutex.Lock()
+	defer fake.listObjectsMutex.Unlock()
+	fake.ListObjectsStub = stub
+}
+
+func (fake *FakeStorager) ListObjectsArgsForCall(i int) (context.Context, string) {
+	fake.listObjectsMutex.RLock()
+	defer fake.listObjectsMutex.RUnlock()
+	argsForCall := fake.listObjectsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorager) ListObjectsReturns(result1 []ObjectInfo, result2 error) {
+	fake.listObjectsMutex.Lock()
+	defer fake.listObjectsMutex.Unlock()
+	fake.ListObjectsStub = nil
+	fake.listObjectsReturns = struct {
+		result1 []ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) ListObjectsReturnsOnCall(i int, result1 []ObjectInfo, result2 error) {
+	fake.listObjectsMutex.Lock()
+	defer fake.listObjectsMutex.Unlock()
+	fake.ListObjectsStub = nil
+	if fake.listObjectsReturnsOnCall == nil {
+		fake.listObjectsReturnsOnCall = make(map[int]struct {
+			result1 []ObjectInfo
+			result2 error
+		})
+	}
+	fake.listObjectsReturnsOnCall[i] = struct {
+		result1 []ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) ListWithOptions(arg1 context.Context, arg2 ListOptions) (ListResult, error) {
+	fake.listWithOptionsMutex.Lock()
+	ret, specificReturn := fake.listWithOptionsReturnsOnCall[len(fake.listWithOptionsArgsForCall)]
+	fake.listWithOptionsArgsForCall = append(fake.listWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 ListOptions
+	}{arg1, arg2})
+	stub := fake.ListWithOptionsStub
+	fakeReturns := fake.listWithOptionsReturns
+	fake.recordInvocation("ListWithOptions", []interface{}{arg1, arg2})
+	fake.listWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) ListWithOptionsCallCount() int {
+	fake.listWithOptionsMutex.RLock()
+	defer fake.listWithOptionsMutex.RUnlock()
+	return len(fake.listWithOptionsArgsForCall)
+}
+
+func (fake *FakeStorager) ListWithOptionsCalls(stub func(context.Context, ListOptions) (ListResult, error)) {
+	fake.listWithOptionsMutex.Lock()
+	defer fake.listWithOptionsMutex.Unlock()
+	fake.ListWithOptionsStub = stub
+}
+
+func (fake *FakeStorager) ListWithOptionsArgsForCall(i int) (context.Context, ListOptions) {
+	fake.listWithOptionsMutex.RLock()
+	defer fake.listWithOptionsMutex.RUnlock()
+	argsForCall := fake.listWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorager) ListWithOptionsReturns(result1 ListResult, result2 error) {
+	fake.listWithOptionsMutex.Lock()
+	defer fake.listWithOptionsMutex.Unlock()
+	fake.ListWithOptionsStub = nil
+	fake.listWithOptionsReturns = struct {
+		result1 ListResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) ListWithOptionsReturnsOnCall(i int, result1 ListResult, result2 error) {
+	fake.listWithOptionsMutex.Lock()
+	defer fake.listWithOptionsMutex.Unlock()
+	fake.ListWithOptionsStub = nil
+	if fake.listWithOptionsReturnsOnCall == nil {
+		fake.listWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 ListResult
+			result2 error
+		})
+	}
+	fake.listWithOptionsReturnsOnCall[i] = struct {
+		result1 ListResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) Move(arg1 context.Context, arg2 string, arg3 string) error {
+	fake.moveMutex.Lock()
+	ret, specificReturn := fake.moveReturnsOnCall[len(fake.moveArgsForCall)]
+	fake.moveArgsForCall = append(fake.moveArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.MoveStub
+	fakeReturns := fake.moveReturns
+	fake.recordInvocation("Move", []interface{}{arg1, arg2, arg3})
+	fake.moveMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) MoveCallCount() int {
+	fake.moveMutex.RLock()
+	defer fake.moveMutex.RUnlock()
+	return len(fake.moveArgsForCall)
+}
+
+func (fake *FakeStorager) MoveCalls(stub func(context.Context, string, string) error) {
+	fake.moveMutex.Lock()
+	defer fake.moveMutex.Unlock()
+	fake.MoveStub = stub
+}
+
+func (fake *FakeStorager) MoveArgsForCall(i int) (context.Context, string, string) {
+	fake.moveMutex.RLock()
+	defer fake.moveMutex.RUnlock()
+	argsForCall := fake.moveArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorager) MoveReturns(result1 error) {
+	fake.moveMutex.Lock()
+	defer fake.moveMutex.Unlock()
+	fake.MoveStub = nil
+	fake.moveReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) MoveReturnsOnCall(i int, result1 error) {
+	fake.moveMutex.Lock()
+	defer fake.moveMutex.Unlock()
+	fake.MoveStub = nil
+	if fake.moveReturnsOnCall == nil {
+		fake.moveReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.moveReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) Properties(arg1 context.Context, arg2 string) (Properties, error) {
 	fake.propertiesMutex.Lock()
 	ret, specificReturn := fake.propertiesReturnsOnCall[len(fake.propertiesArgsForCall)]
 	fake.propertiesArgsForCall = append(fake.propertiesArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.PropertiesStub
 	fakeReturns := fake.propertiesReturns
-	fake.recordInvocation("Properties", []interface{}{arg1})
+	fake.recordInvocation("Properties", []interface{}{arg1, arg2})
 	fake.propertiesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeStorager) PropertiesCallCount() int {
@@ -583,55 +1296,59 @@ func (fake *FakeStorager) PropertiesCallCount() int {
 	return len(fake.propertiesArgsForCall)
 }
 
-func (fake *FakeStorager) PropertiesCalls(stub func(string) error) {
+func (fake *FakeStorager) PropertiesCalls(stub func(context.Context, string) (Properties, error)) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = stub
 }
 
-func (fake *FakeStorager) PropertiesArgsForCall(i int) string {
+func (fake *FakeStorager) PropertiesArgsForCall(i int) (context.Context, string) {
 	fake.propertiesMutex.RLock()
 	defer fake.propertiesMutex.RUnlock()
 	argsForCall := fake.propertiesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeStorager) PropertiesReturns(result1 error) {
+func (fake *FakeStorager) PropertiesReturns(result1 Properties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	fake.propertiesReturns = struct {
-		result1 error
-	}{result1}
+		result1 Properties
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorager) PropertiesReturnsOnCall(i int, result1 error) {
+func (fake *FakeStorager) PropertiesReturnsOnCall(i int, result1 Properties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	if fake.propertiesReturnsOnCall == nil {
 		fake.propertiesReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 Properties
+			result2 error
 		})
 	}
 	fake.propertiesReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 Properties
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorager) Put(arg1 string, arg2 string) error {
+func (fake *FakeStorager) Put(arg1 context.Context, arg2 string, arg3 string) error {
 	fake.putMutex.Lock()
 	ret, specificReturn := fake.putReturnsOnCall[len(fake.putArgsForCall)]
 	fake.putArgsForCall = append(fake.putArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+	}{arg1, arg2, arg3})
 	stub := fake.PutStub
 	fakeReturns := fake.putReturns
-	fake.recordInvocation("Put", []interface{}{arg1, arg2})
+	fake.recordInvocation("Put", []interface{}{arg1, arg2, arg3})
 	fake.putMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -645,17 +1362,17 @@ func (fake *FakeStorager) PutCallCount() int {
 	return len(fake.putArgsForCall)
 }
 
-func (fake *FakeStorager) PutCalls(stub func(string, string) error) {
+func (fake *FakeStorager) PutCalls(stub func(context.Context, string, string) error) {
 	fake.putMutex.Lock()
 	defer fake.putMutex.Unlock()
 	fake.PutStub = stub
 }
 
-func (fake *FakeStorager) PutArgsForCall(i int) (string, string) {
+func (fake *FakeStorager) PutArgsForCall(i int) (context.Context, string, string) {
 	fake.putMutex.RLock()
 	defer fake.putMutex.RUnlock()
 	argsForCall := fake.putArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorager) PutReturns(result1 error) {
@@ -681,20 +1398,212 @@ func (fake *FakeStorager) PutReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorager) Sign(arg1 string, arg2 string, arg3 time.Duration) (string, error) {
+func (fake *FakeStorager) PutStream(arg1 context.Context, arg2 io.Reader, arg3 string) error {
+	fake.putStreamMutex.Lock()
+	ret, specificReturn := fake.putStreamReturnsOnCall[len(fake.putStreamArgsForCall)]
+	fake.putStreamArgsForCall = append(fake.putStreamArgsForCall, struct {
+		arg1 context.Context
+		arg2 io.Reader
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.PutStreamStub
+	fakeReturns := fake.putStreamReturns
+	fake.recordInvocation("PutStream", []interface{}{arg1, arg2, arg3})
+	fake.putStreamMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) PutStreamCallCount() int {
+	fake.putStreamMutex.RLock()
+	defer fake.putStreamMutex.RUnlock()
+	return len(fake.putStreamArgsForCall)
+}
+
+func (fake *FakeStorager) PutStreamCalls(stub func(context.Context, io.Reader, string) error) {
+	fake.putStreamMutex.Lock()
+	defer fake.putStreamMutex.Unlock()
+	fake.PutStreamStub = stub
+}
+
+func (fake *FakeStorager) PutStreamArgsForCall(i int) (context.Context, io.Reader, string) {
+	fake.putStreamMutex.RLock()
+	defer fake.putStreamMutex.RUnlock()
+	argsForCall := fake.putStreamArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorager) PutStreamReturns(result1 error) {
+	fake.putStreamMutex.Lock()
+	defer fake.putStreamMutex.Unlock()
+	fake.PutStreamStub = nil
+	fake.putStreamReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) PutStreamReturnsOnCall(i int, result1 error) {
+	fake.putStreamMutex.Lock()
+	defer fake.putStreamMutex.Unlock()
+	fake.PutStreamStub = nil
+	if fake.putStreamReturnsOnCall == nil {
+		fake.putStreamReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putStreamReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) PutStreamWithOptions(arg1 context.Context, arg2 io.Reader, arg3 string, arg4 PutOptions) error {
+	fake.putStreamWithOptionsMutex.Lock()
+	ret, specificReturn := fake.putStreamWithOptionsReturnsOnCall[len(fake.putStreamWithOptionsArgsForCall)]
+	fake.putStreamWithOptionsArgsForCall = append(fake.putStreamWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 io.Reader
+		arg3 string
+		arg4 PutOptions
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.PutStreamWithOptionsStub
+	fakeReturns := fake.putStreamWithOptionsReturns
+	fake.recordInvocation("PutStreamWithOptions", []interface{}{arg1, arg2, arg3, arg4})
+	fake.putStreamWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) PutStreamWithOptionsCallCount() int {
+	fake.putStreamWithOptionsMutex.RLock()
+	defer fake.putStreamWithOptionsMutex.RUnlock()
+	return len(fake.putStreamWithOptionsArgsForCall)
+}
+
+func (fake *FakeStorager) PutStreamWithOptionsCalls(stub func(context.Context, io.Reader, string, PutOptions) error) {
+	fake.putStreamWithOptionsMutex.Lock()
+	defer fake.putStreamWithOptionsMutex.Unlock()
+	fake.PutStreamWithOptionsStub = stub
+}
+
+func (fake *FakeStorager) PutStreamWithOptionsArgsForCall(i int) (context.Context, io.Reader, string, PutOptions) {
+	fake.putStreamWithOptionsMutex.RLock()
+	defer fake.putStreamWithOptionsMutex.RUnlock()
+	argsForCall := fake.putStreamWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStorager) PutStreamWithOptionsReturns(result1 error) {
+	fake.putStreamWithOptionsMutex.Lock()
+	defer fake.putStreamWithOptionsMutex.Unlock()
+	fake.PutStreamWithOptionsStub = nil
+	fake.putStreamWithOptionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) PutStreamWithOptionsReturnsOnCall(i int, result1 error) {
+	fake.putStreamWithOptionsMutex.Lock()
+	defer fake.putStreamWithOptionsMutex.Unlock()
+	fake.PutStreamWithOptionsStub = nil
+	if fake.putStreamWithOptionsReturnsOnCall == nil {
+		fake.putStreamWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putStreamWithOptionsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) PutWithOptions(arg1 context.Context, arg2 string, arg3 string, arg4 PutOptions) error {
+	fake.putWithOptionsMutex.Lock()
+	ret, specificReturn := fake.putWithOptionsReturnsOnCall[len(fake.putWithOptionsArgsForCall)]
+	fake.putWithOptionsArgsForCall = append(fake.putWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 PutOptions
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.PutWithOptionsStub
+	fakeReturns := fake.putWithOptionsReturns
+	fake.recordInvocation("PutWithOptions", []interface{}{arg1, arg2, arg3, arg4})
+	fake.putWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) PutWithOptionsCallCount() int {
+	fake.putWithOptionsMutex.RLock()
+	defer fake.putWithOptionsMutex.RUnlock()
+	return len(fake.putWithOptionsArgsForCall)
+}
+
+func (fake *FakeStorager) PutWithOptionsCalls(stub func(context.Context, string, string, PutOptions) error) {
+	fake.putWithOptionsMutex.Lock()
+	defer fake.putWithOptionsMutex.Unlock()
+	fake.PutWithOptionsStub = stub
+}
+
+func (fake *FakeStorager) PutWithOptionsArgsForCall(i int) (context.Context, string, string, PutOptions) {
+	fake.putWithOptionsMutex.RLock()
+	defer fake.putWithOptionsMutex.RUnlock()
+	argsForCall := fake.putWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStorager) PutWithOptionsReturns(result1 error) {
+	fake.putWithOptionsMutex.Lock()
+	defer fake.putWithOptionsMutex.Unlock()
+	fake.PutWithOptionsStub = nil
+	fake.putWithOptionsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) PutWithOptionsReturnsOnCall(i int, result1 error) {
+	fake.putWithOptionsMutex.Lock()
+	defer fake.putWithOptionsMutex.Unlock()
+	fake.PutWithOptionsStub = nil
+	if fake.putWithOptionsReturnsOnCall == nil {
+		fake.putWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putWithOptionsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) Sign(arg1 context.Context, arg2 string, arg3 string, arg4 time.Duration) (string, error) {
 	fake.signMutex.Lock()
 	ret, specificReturn := fake.signReturnsOnCall[len(fake.signArgsForCall)]
 	fake.signArgsForCall = append(fake.signArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 time.Duration
-	}{arg1, arg2, arg3})
+		arg3 string
+		arg4 time.Duration
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.SignStub
 	fakeReturns := fake.signReturns
-	fake.recordInvocation("Sign", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("Sign", []interface{}{arg1, arg2, arg3, arg4})
 	fake.signMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -708,17 +1617,17 @@ func (fake *FakeStorager) SignCallCount() int {
 	return len(fake.signArgsForCall)
 }
 
-func (fake *FakeStorager) SignCalls(stub func(string, string, time.Duration) (string, error)) {
+func (fake *FakeStorager) SignCalls(stub func(context.Context, string, string, time.Duration) (string, error)) {
 	fake.signMutex.Lock()
 	defer fake.signMutex.Unlock()
 	fake.SignStub = stub
 }
 
-func (fake *FakeStorager) SignArgsForCall(i int) (string, string, time.Duration) {
+func (fake *FakeStorager) SignArgsForCall(i int) (context.Context, string, string, time.Duration) {
 	fake.signMutex.RLock()
 	defer fake.signMutex.RUnlock()
 	argsForCall := fake.signArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStorager) SignReturns(result1 string, result2 error) {
@@ -747,6 +1656,74 @@ func (fake *FakeStorager) SignReturnsOnCall(i int, result1 string, result2 error
 	}{result1, result2}
 }
 
+func (fake *FakeStorager) Stat(arg1 context.Context, arg2 string) (ObjectInfo, bool, error) {
+	fake.statMutex.Lock()
+	ret, specificReturn := fake.statReturnsOnCall[len(fake.statArgsForCall)]
+	fake.statArgsForCall = append(fake.statArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.StatStub
+	fakeReturns := fake.statReturns
+	fake.recordInvocation("Stat", []interface{}{arg1, arg2})
+	fake.statMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeStorager) StatCallCount() int {
+	fake.statMutex.RLock()
+	defer fake.statMutex.RUnlock()
+	return len(fake.statArgsForCall)
+}
+
+func (fake *FakeStorager) StatCalls(stub func(context.Context, string) (ObjectInfo, bool, error)) {
+	fake.statMutex.Lock()
+	defer fake.statMutex.Unlock()
+	fake.StatStub = stub
+}
+
+func (fake *FakeStorager) StatArgsForCall(i int) (context.Context, string) {
+	fake.statMutex.RLock()
+	defer fake.statMutex.RUnlock()
+	argsForCall := fake.statArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorager) StatReturns(result1 ObjectInfo, result2 bool, result3 error) {
+	fake.statMutex.Lock()
+	defer fake.statMutex.Unlock()
+	fake.StatStub = nil
+	fake.statReturns = struct {
+		result1 ObjectInfo
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeStorager) StatReturnsOnCall(i int, result1 ObjectInfo, result2 bool, result3 error) {
+	fake.statMutex.Lock()
+	defer fake.statMutex.Unlock()
+	fake.StatStub = nil
+	if fake.statReturnsOnCall == nil {
+		fake.statReturnsOnCall = make(map[int]struct {
+			result1 ObjectInfo
+			result2 bool
+			result3 error
+		})
+	}
+	fake.statReturnsOnCall[i] = struct {
+		result1 ObjectInfo
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeStorager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
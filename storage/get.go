@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// get downloads an object, or the byte range given by its flags, to a file,
+// or to stdout with stdioPath as destination, if it satisfies the
+// preconditions given by its flags. With --resume it continues a partial
+// download, see getResumable, and with --verify it compares the download with
+// the checksums the backend computed, see getVerified.
+func (sty *CommandExecuter) get(ctx context.Context, args []string) error {
+	var (
+		opts      GetOptions
+		byteRange string
+		resume    bool
+		verify    bool
+	)
+	flags := flag.NewFlagSet("get", flag.ContinueOnError)
+	flags.StringVar(&opts.IfMatch, "if-match", "", "only download the object if its ETag matches")
+	flags.StringVar(&byteRange, "range", "", "only download the bytes <start>-<end>, <start>- or the last -<length>")
+	flags.BoolVar(&resume, "resume", false, "continue a partial download left by an earlier get --resume if the object is unchanged")
+	flags.BoolVar(&verify, "verify", false, "compare the checksums of the download with the ones the backend computed, removing the file if they differ")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if byteRange != "" {
+		r, err := common.ParseByteRange(byteRange)
+		if err != nil {
+			return err
+		}
+		opts.Range = &r
+	}
+
+	args = flags.Args()
+	if len(args) != 2 {
+		return fmt.Errorf("get method expected 2 arguments got %d", len(args))
+	}
+	src, dst := args[0], args[1]
+	if resume {
+		if verify {
+			return errors.New("--resume and --verify cannot be combined")
+		}
+		return sty.getResume(ctx, src, dst, opts)
+	}
+	if verify {
+		return sty.getVerify(ctx, src, dst, opts)
+	}
+	withOptions := opts.IfMatch != "" || opts.Range != nil
+	if dst == stdioPath {
+		// The object is the output, there is no room for a result.
+		return sty.getToStdout(ctx, src, opts, withOptions)
+	}
+
+	start := time.Now()
+	var err error
+	if withOptions {
+		err = sty.str.GetWithOptions(ctx, src, dst, opts)
+	} else {
+		err = sty.str.Get(ctx, src, dst)
+	}
+	if err != nil {
+		return err
+	}
+	if !sty.jsonOutput() {
+		return nil
+	}
+	info, err := os.Stat(dst)
+	if err != nil {
+		return err
+	}
+	return sty.writeTransferred(info.Size(), start)
+}
+
+func (sty *CommandExecuter) getResume(ctx context.Context, src, dst string, opts GetOptions) error {
+	if dst == stdioPath {
+		return errors.New("--resume needs a file to download to")
+	}
+	if opts.Range != nil {
+		return errors.New("--resume and --range cannot be combined")
+	}
+
+	start := time.Now()
+	n, err := getResumable(ctx, sty.str, src, dst, opts.IfMatch)
+	if err != nil {
+		return err
+	}
+	return sty.writeTransferred(n, start)
+}
+
+func (sty *CommandExecuter) getVerify(ctx context.Context, src, dst string, opts GetOptions) error {
+	if opts.Range != nil {
+		return errors.New("--verify and --range cannot be combined")
+	}
+	if dst == stdioPath {
+		// The content is already written when a mismatch is found, only the
+		// exit code tells.
+		_, err := getVerified(ctx, sty.str, src, sty.stdout(), opts.IfMatch)
+		return err
+	}
+
+	start := time.Now()
+	file, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	n, err := getVerified(ctx, sty.str, src, file, opts.IfMatch)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(dst))
+	}
+	return sty.writeTransferred(n, start)
+}
+
+func (sty *CommandExecuter) getToStdout(ctx context.Context, src string, opts GetOptions, withOptions bool) error {
+	var (
+		content io.ReadCloser
+		err     error
+	)
+	if withOptions {
+		content, err = sty.str.GetStreamWithOptions(ctx, src, opts)
+	} else {
+		content, err = sty.str.GetStream(ctx, src)
+	}
+	if err != nil {
+		return err
+	}
+	defer content.Close() //nolint:errcheck
+
+	if _, err := io.Copy(sty.stdout(), content); err != nil {
+		return fmt.Errorf("writing %s to stdout: %w", src, err)
+	}
+	return nil
+}
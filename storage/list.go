@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"time"
+)
+
+// defaultListPageSize is the size of the pages requested by ListPages when
+// the options do not set one.
+const defaultListPageSize = 1000
+
+// ListPages returns an iterator over the pages of the listing selected by
+// opts, following their page tokens, so that callers can process listings of
+// any size without holding them in memory. opts.MaxKeys is the size of the
+// pages, defaultListPageSize if not set. The iteration stops after the first
+// error.
+//
+//	for page, err := range storage.ListPages(ctx, str, storage.ListOptions{Prefix: "droplets/"}) {
+//		...
+//	}
+func ListPages(ctx context.Context, str Storager, opts ListOptions) iter.Seq2[ListResult, error] {
+	if opts.MaxKeys <= 0 {
+		opts.MaxKeys = defaultListPageSize
+	}
+	return func(yield func(ListResult, error) bool) {
+		for {
+			page, err := str.ListWithOptions(ctx, opts)
+			if err != nil {
+				yield(ListResult{}, err)
+				return
+			}
+			if !yield(page, nil) || page.NextPageToken == "" {
+				return
+			}
+			opts.PageToken = page.NextPageToken
+		}
+	}
+}
+
+// listPages prints the listing selected by opts as its pages arrive, so
+// output starts before large listings are complete. With opts.MaxKeys, only
+// that page is printed, followed by the token of the next page if there is
+// one.
+func (sty *CommandExecuter) listPages(ctx context.Context, opts ListOptions, long bool) error {
+	if opts.MaxKeys > 0 {
+		page, err := sty.str.ListWithOptions(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		if sty.jsonOutput() {
+			return sty.writeListJSON(page, true, long)
+		}
+		if err := sty.writeListText(page, long); err != nil {
+			return err
+		}
+		if page.NextPageToken != "" {
+			_, err := fmt.Fprintf(sty.stderr(), "Next page token: %s\n", page.NextPageToken)
+			return err
+		}
+		return nil
+	}
+	if sty.jsonOutput() {
+		return sty.streamListJSON(ctx, opts, long)
+	}
+
+	for page, err := range ListPages(ctx, sty.str, opts) {
+		if err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		if err := sty.writeListText(page, long); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamListJSON prints the objects of the listing selected by opts as an
+// array, one object at a time as the pages arrive. With a delimiter the array
+// is the "objects" of a JSON object, followed by the "prefixes", which are
+// collected until the listing is complete. A listing failing midway leaves
+// the document unfinished.
+func (sty *CommandExecuter) streamListJSON(ctx context.Context, opts ListOptions, long bool) error {
+	if opts.Delimiter != "" {
+		if _, err := io.WriteString(sty.stdout(), `{"objects":`); err != nil {
+			return err
+		}
+	}
+	objects, err := newJSONArrayWriter(sty.stdout())
+	if err != nil {
+		return err
+	}
+
+	prefixes := []string{}
+	for page, err := range ListPages(ctx, sty.str, opts) {
+		if err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		prefixes = append(prefixes, page.Prefixes...)
+		for _, object := range page.Objects {
+			var entry any = listEntry{Name: object.Name}
+			if long {
+				entry = newObjectEntry(object)
+			}
+			if err := objects.write(entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.Delimiter == "" {
+		return objects.close("\n")
+	}
+	if err := objects.close(`,"prefixes":`); err != nil {
+		return err
+	}
+	prefixArray, err := newJSONArrayWriter(sty.stdout())
+	if err != nil {
+		return err
+	}
+	for _, prefix := range prefixes {
+		if err := prefixArray.write(prefix); err != nil {
+			return err
+		}
+	}
+	return prefixArray.close("}\n")
+}
+
+// writeListJSON prints the objects of result as an array, or as an object
+// holding its prefixes and next page token as well with full.
+func (sty *CommandExecuter) writeListJSON(result ListResult, full bool, long bool) error {
+	var objects any
+	if long {
+		entries := make([]objectEntry, 0, len(result.Objects))
+		for _, object := range result.Objects {
+			entries = append(entries, newObjectEntry(object))
+		}
+		objects = entries
+	} else {
+		entries := make([]listEntry, 0, len(result.Objects))
+		for _, object := range result.Objects {
+			entries = append(entries, listEntry{Name: object.Name})
+		}
+		objects = entries
+	}
+	if !full {
+		return sty.writeJSON(objects)
+	}
+
+	prefixes := result.Prefixes
+	if prefixes == nil {
+		prefixes = []string{}
+	}
+	return sty.writeJSON(listResult{Prefixes: prefixes, Objects: objects, NextPageToken: result.NextPageToken})
+}
+
+// writeListText prints the common prefixes of result followed by its
+// objects, one per line. With long, objects are printed as tab separated
+// size, last modification time, ETag, storage class and name, and values the
+// backend does not report are printed as "-", as are all the values of
+// common prefixes.
+func (sty *CommandExecuter) writeListText(result ListResult, long bool) error {
+	for _, prefix := range result.Prefixes {
+		if long {
+			prefix = "-\t-\t-\t-\t" + prefix
+		}
+		if _, err := fmt.Fprintln(sty.stdout(), prefix); err != nil {
+			return err
+		}
+	}
+	for _, object := range result.Objects {
+		if !long {
+			if _, err := fmt.Fprintln(sty.stdout(), object.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		lastModified := "-"
+		if !object.LastModified.IsZero() {
+			lastModified = object.LastModified.UTC().Format(time.RFC3339)
+		}
+		if _, err := fmt.Fprintf(sty.stdout(), "%d\t%s\t%s\t%s\t%s\n",
+			object.Size, lastModified, orDash(object.ETag), orDash(object.StorageClass), object.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("list", func() {
+	var (
+		fakeStorager *FakeStorager
+		pages        map[string]ListResult
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		pages = map[string]ListResult{
+			"":       {Objects: []ObjectInfo{{Name: "a"}, {Name: "b"}}, NextPageToken: "token1"},
+			"token1": {Objects: []ObjectInfo{{Name: "c"}}, Prefixes: []string{"d/"}},
+		}
+		fakeStorager.ListWithOptionsStub = func(_ context.Context, opts ListOptions) (ListResult, error) {
+			return pages[opts.PageToken], nil
+		}
+	})
+
+	Context("ListPages", func() {
+		It("follows the page tokens", func() {
+			var names []string
+			for page, err := range ListPages(context.Background(), fakeStorager, ListOptions{Prefix: "p"}) {
+				Expect(err).ToNot(HaveOccurred())
+				names = append(names, common.ObjectNames(page.Objects)...)
+			}
+
+			Expect(names).To(Equal([]string{"a", "b", "c"}))
+			Expect(fakeStorager.ListWithOptionsCallCount()).To(Equal(2))
+			_, opts := fakeStorager.ListWithOptionsArgsForCall(1)
+			Expect(opts).To(Equal(ListOptions{Prefix: "p", MaxKeys: defaultListPageSize, PageToken: "token1"}))
+		})
+
+		It("stops at the first error", func() {
+			fakeStorager.ListWithOptionsStub = nil
+			fakeStorager.ListWithOptionsReturns(ListResult{}, errors.New("boom"))
+
+			var errs []error
+			for _, err := range ListPages(context.Background(), fakeStorager, ListOptions{}) {
+				errs = append(errs, err)
+			}
+			Expect(errs).To(ConsistOf(MatchError("boom")))
+		})
+
+		It("stops when the caller breaks", func() {
+			for range ListPages(context.Background(), fakeStorager, ListOptions{}) {
+				break
+			}
+			Expect(fakeStorager.ListWithOptionsCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("command", func() {
+		var (
+			commandExecuter *CommandExecuter
+			out, errOut     *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			out, errOut = &bytes.Buffer{}, &bytes.Buffer{}
+			commandExecuter = &CommandExecuter{str: fakeStorager, out: out, errOut: errOut}
+		})
+
+		It("prints a single page and the token of the next one with --max-keys", func() {
+			Expect(commandExecuter.Execute(context.Background(), "list", []string{"--max-keys", "2", "--start-after", "0", "p"})).To(Succeed())
+
+			Expect(out.String()).To(Equal("a\nb\n"))
+			Expect(errOut.String()).To(Equal("Next page token: token1\n"))
+			_, opts := fakeStorager.ListWithOptionsArgsForCall(0)
+			Expect(opts).To(Equal(ListOptions{Prefix: "p", MaxKeys: 2, StartAfter: "0"}))
+		})
+
+		It("continues from --page-token", func() {
+			Expect(commandExecuter.Execute(context.Background(), "list", []string{"--max-keys", "2", "--page-token", "token1"})).To(Succeed())
+
+			Expect(out.String()).To(Equal("d/\nc\n"))
+			Expect(errOut.String()).To(BeEmpty())
+		})
+
+		It("prints every page without --max-keys", func() {
+			Expect(commandExecuter.Execute(context.Background(), "list", []string{"--start-after", "0"})).To(Succeed())
+
+			Expect(out.String()).To(Equal("a\nb\nd/\nc\n"))
+			Expect(fakeStorager.ListWithOptionsCallCount()).To(Equal(2))
+		})
+
+		It("prints each page before fetching the next one", func() {
+			fakeStorager.ListWithOptionsStub = func(_ context.Context, opts ListOptions) (ListResult, error) {
+				if opts.PageToken == "token1" {
+					Expect(out.String()).To(Equal("a\nb\n"))
+				}
+				return pages[opts.PageToken], nil
+			}
+
+			Expect(commandExecuter.Execute(context.Background(), "list", []string{"p"})).To(Succeed())
+			Expect(out.String()).To(Equal("a\nb\nd/\nc\n"))
+			Expect(fakeStorager.ListWithOptionsCallCount()).To(Equal(2))
+			Expect(fakeStorager.ListCallCount()).To(BeZero())
+		})
+
+		It("prints each object in JSON before fetching the next page", func() {
+			Expect(commandExecuter.SetOutputFormat(OutputJSON)).To(Succeed())
+			fakeStorager.ListWithOptionsStub = func(_ context.Context, opts ListOptions) (ListResult, error) {
+				if opts.PageToken == "token1" {
+					Expect(out.String()).To(Equal("[\n{\"name\":\"a\"},\n{\"name\":\"b\"}"))
+				}
+				return pages[opts.PageToken], nil
+			}
+
+			Expect(commandExecuter.Execute(context.Background(), "list", []string{"p"})).To(Succeed())
+			Expect(out.String()).To(MatchJSON(`[{"name":"a"},{"name":"b"},{"name":"c"}]`))
+		})
+
+		It("prints the prefixes after the streamed objects with a delimiter in JSON", func() {
+			Expect(commandExecuter.SetOutputFormat(OutputJSON)).To(Succeed())
+
+			Expect(commandExecuter.Execute(context.Background(), "list", []string{"--delimiter", "/", "--long"})).To(Succeed())
+			Expect(out.String()).To(MatchJSON(`{"objects":[{"name":"a","size":0},{"name":"b","size":0},{"name":"c","size":0}],"prefixes":["d/"]}`))
+		})
+
+		It("reports the next page token in JSON", func() {
+			Expect(commandExecuter.SetOutputFormat(OutputJSON)).To(Succeed())
+
+			Expect(commandExecuter.Execute(context.Background(), "list", []string{"--max-keys", "2"})).To(Succeed())
+			Expect(out.String()).To(MatchJSON(`{"prefixes":[],"objects":[{"name":"a"},{"name":"b"}],"next_page_token":"token1"}`))
+		})
+
+		It("rejects negative page sizes", func() {
+			err := commandExecuter.Execute(context.Background(), "list", []string{"--max-keys", "-1"})
+			Expect(err).To(MatchError(ContainSubstring("--max-keys must not be negative")))
+		})
+	})
+})
@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+const (
+	// OutputText prints results the way each command always did.
+	OutputText = "text"
+	// OutputJSON prints one JSON document per command, including failures.
+	OutputJSON = "json"
+)
+
+// Error codes reported in JSON output and by the server. They are part of
+// the interface used by wrappers and must not change.
+const (
+	errorCodeNotFound           = "not_found"
+	errorCodePermissionDenied   = "permission_denied"
+	errorCodeAlreadyExists      = "already_exists"
+	errorCodePreconditionFailed = "precondition_failed"
+	errorCodeThrottled          = "throttled"
+	errorCodeTimeout            = "timeout"
+	errorCodeInvalidConfig      = "invalid_config"
+	errorCodeChecksumMismatch   = "checksum_mismatch"
+	errorCodeCanceled           = "canceled"
+	errorCodeInternal           = "internal_error"
+)
+
+var errorKindCodes = map[error]string{
+	common.ErrNotFound:           errorCodeNotFound,
+	common.ErrPermissionDenied:   errorCodePermissionDenied,
+	common.ErrAlreadyExists:      errorCodeAlreadyExists,
+	common.ErrPreconditionFailed: errorCodePreconditionFailed,
+	common.ErrThrottled:          errorCodeThrottled,
+	common.ErrTimeout:            errorCodeTimeout,
+	common.ErrInvalidConfig:      errorCodeInvalidConfig,
+	common.ErrChecksumMismatch:   errorCodeChecksumMismatch,
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type errorResult struct {
+	Error errorBody `json:"error"`
+}
+
+type listEntry struct {
+	Name string `json:"name"`
+}
+
+// objectEntry is an object listed by list --long.
+type objectEntry struct {
+	Name         string     `json:"name"`
+	Size         int64      `json:"size"`
+	ETag         string     `json:"etag,omitempty"`
+	ContentMD5   string     `json:"content_md5,omitempty"`
+	LastModified *time.Time `json:"last_modified,omitempty"`
+	StorageClass string     `json:"storage_class,omitempty"`
+}
+
+func newObjectEntry(info ObjectInfo) objectEntry {
+	entry := objectEntry{
+		Name:         info.Name,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		ContentMD5:   info.ContentMD5,
+		StorageClass: info.StorageClass,
+	}
+	if !info.LastModified.IsZero() {
+		lastModified := info.LastModified.UTC()
+		entry.LastModified = &lastModified
+	}
+	return entry
+}
+
+// propertiesResult is printed by properties, omitting the values the backend
+// does not report.
+type propertiesResult struct {
+	ETag               string            `json:"etag,omitempty"`
+	LastModified       *time.Time        `json:"last_modified,omitempty"`
+	ContentLength      int64             `json:"content_length"`
+	ContentType        string            `json:"content_type,omitempty"`
+	ContentEncoding    string            `json:"content_encoding,omitempty"`
+	CacheControl       string            `json:"cache_control,omitempty"`
+	ContentDisposition string            `json:"content_disposition,omitempty"`
+	ContentMD5         string            `json:"content_md5,omitempty"`
+	CRC32              string            `json:"crc32,omitempty"`
+	CRC32C             string            `json:"crc32c,omitempty"`
+	CRC64              string            `json:"crc64,omitempty"`
+	SHA256             string            `json:"sha256,omitempty"`
+	StorageClass       string            `json:"storage_class,omitempty"`
+	Encryption         *encryptionResult `json:"encryption,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
+
+type encryptionResult struct {
+	Algorithm         string `json:"algorithm,omitempty"`
+	KMSKeyID          string `json:"kms_key_id,omitempty"`
+	CustomerKeySHA256 string `json:"customer_key_sha256,omitempty"`
+}
+
+func newPropertiesResult(props Properties) propertiesResult {
+	result := propertiesResult{
+		ETag:               props.ETag,
+		ContentLength:      props.ContentLength,
+		ContentType:        props.ContentType,
+		ContentEncoding:    props.ContentEncoding,
+		CacheControl:       props.CacheControl,
+		ContentDisposition: props.ContentDisposition,
+		ContentMD5:         props.ContentMD5,
+		CRC32:              props.CRC32,
+		CRC32C:             props.CRC32C,
+		CRC64:              props.CRC64,
+		SHA256:             props.SHA256,
+		StorageClass:       props.StorageClass,
+		Metadata:           props.Metadata,
+	}
+	if !props.LastModified.IsZero() {
+		lastModified := props.LastModified.UTC()
+		result.LastModified = &lastModified
+	}
+	if props.Encryption != (common.Encryption{}) {
+		result.Encryption = &encryptionResult{
+			Algorithm:         props.Encryption.Algorithm,
+			KMSKeyID:          props.Encryption.KMSKeyID,
+			CustomerKeySHA256: props.Encryption.CustomerKeySHA256,
+		}
+	}
+	return result
+}
+
+// listResult is printed by list with a delimiter or a page size. Objects
+// holds either listEntry or objectEntry values.
+type listResult struct {
+	Prefixes      []string `json:"prefixes"`
+	Objects       any      `json:"objects"`
+	NextPageToken string   `json:"next_page_token,omitempty"`
+}
+
+type existsResult struct {
+	Exists bool `json:"exists"`
+}
+
+// verifyResult names the checksums verify compared, and whether the object
+// was downloaded to compute them.
+type verifyResult struct {
+	Checksums  []string `json:"checksums"`
+	Downloaded bool     `json:"downloaded"`
+}
+
+type signResult struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type transferredResult struct {
+	Bytes           int64   `json:"bytes"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+type syncResult struct {
+	DryRun  bool         `json:"dry_run"`
+	Actions []syncAction `json:"actions"`
+	Summary syncSummary  `json:"summary"`
+}
+
+// errorCode classifies err into one of the stable error codes.
+func errorCode(err error) string {
+	if code, ok := errorKindCodes[common.ErrorKind(err)]; ok {
+		return code
+	}
+	if errors.Is(err, context.Canceled) {
+		return errorCodeCanceled
+	}
+	return errorCodeInternal
+}
+
+// SetOutputFormat selects how command results and failures are printed,
+// either OutputText (the default) or OutputJSON.
+func (sty *CommandExecuter) SetOutputFormat(format string) error {
+	if format != OutputText && format != OutputJSON {
+		return fmt.Errorf("unknown output format: '%s'. Available formats are '%s' and '%s'", format, OutputText, OutputJSON)
+	}
+	sty.output = format
+	return nil
+}
+
+func (sty *CommandExecuter) jsonOutput() bool {
+	return sty.output == OutputJSON
+}
+
+// WriteError reports err on stdout when the output format is JSON. Text
+// output leaves reporting errors to the caller.
+func (sty *CommandExecuter) WriteError(err error) {
+	if err == nil || !sty.jsonOutput() {
+		return
+	}
+	sty.writeJSON(errorResult{errorBody{Code: errorCode(err), Message: err.Error()}}) //nolint:errcheck
+}
+
+func (sty *CommandExecuter) writeJSON(v any) error {
+	encoder := json.NewEncoder(sty.stdout())
+	// Signed URLs must stay readable for non-JSON-aware tooling.
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(v)
+}
+
+// jsonArrayWriter prints a JSON array one element at a time, so arrays of
+// any length are printed without holding them in memory.
+type jsonArrayWriter struct {
+	w io.Writer
+	n int
+}
+
+// newJSONArrayWriter prints the opening bracket of the array to w.
+func newJSONArrayWriter(w io.Writer) (*jsonArrayWriter, error) {
+	_, err := io.WriteString(w, "[")
+	return &jsonArrayWriter{w: w}, err
+}
+
+// write prints v as the next element of the array, on a line of its own.
+func (a *jsonArrayWriter) write(v any) error {
+	var element bytes.Buffer
+	encoder := json.NewEncoder(&element)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	separator := ",\n"
+	if a.n == 0 {
+		separator = "\n"
+	}
+	a.n++
+	_, err := fmt.Fprintf(a.w, "%s%s", separator, bytes.TrimSuffix(element.Bytes(), []byte("\n")))
+	return err
+}
+
+// close prints the closing bracket of the array, followed by end.
+func (a *jsonArrayWriter) close(end string) error {
+	closing := "]"
+	if a.n > 0 {
+		closing = "\n]"
+	}
+	_, err := io.WriteString(a.w, closing+end)
+	return err
+}
+
+// writeTransferred reports the bytes moved by put, get or copy since start.
+func (sty *CommandExecuter) writeTransferred(n int64, start time.Time) error {
+	if !sty.jsonOutput() {
+		return nil
+	}
+	return sty.writeJSON(transferredResult{Bytes: n, DurationSeconds: time.Since(start).Seconds()})
+}
+
+// writeDone reports success of commands without a result of their own.
+func (sty *CommandExecuter) writeDone() error {
+	if !sty.jsonOutput() {
+		return nil
+	}
+	return sty.writeJSON(struct{}{})
+}
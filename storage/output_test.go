@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON output", func() {
+	var (
+		fakeStorager    *FakeStorager
+		commandExecuter *CommandExecuter
+		out             *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		out = &bytes.Buffer{}
+		commandExecuter = &CommandExecuter{str: fakeStorager, out: out}
+		Expect(commandExecuter.SetOutputFormat(OutputJSON)).To(Succeed())
+	})
+
+	It("rejects unknown formats", func() {
+		Expect(commandExecuter.SetOutputFormat("yaml")).To(MatchError(ContainSubstring("unknown output format: 'yaml'")))
+	})
+
+	It("lists objects as an array", func() {
+		fakeStorager.ListWithOptionsReturns(ListResult{Objects: []ObjectInfo{{Name: "a"}, {Name: "b"}}}, nil)
+
+		Expect(commandExecuter.Execute(context.Background(), "list", nil)).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`[{"name":"a"},{"name":"b"}]`))
+	})
+
+	It("lists no objects as an empty array", func() {
+		Expect(commandExecuter.Execute(context.Background(), "list", nil)).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`[]`))
+	})
+
+	It("lists objects with their metadata", func() {
+		fakeStorager.ListWithOptionsReturns(ListResult{Objects: []ObjectInfo{
+			{Name: "a", Size: 3, ETag: "abc", ContentMD5: "abc", LastModified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), StorageClass: "STANDARD"},
+			{Name: "empty"},
+		}}, nil)
+
+		Expect(commandExecuter.Execute(context.Background(), "list", []string{"--long"})).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`[
+			{"name":"a","size":3,"etag":"abc","content_md5":"abc","last_modified":"2024-01-02T03:04:05Z","storage_class":"STANDARD"},
+			{"name":"empty","size":0}
+		]`))
+	})
+
+	It("lists common prefixes separately from objects", func() {
+		fakeStorager.ListWithOptionsReturns(ListResult{Objects: []ObjectInfo{{Name: "dir/a", Size: 3}}, Prefixes: []string{"dir/sub/"}}, nil)
+
+		Expect(commandExecuter.Execute(context.Background(), "list", []string{"--delimiter", "/", "dir/"})).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`{"prefixes":["dir/sub/"],"objects":[{"name":"dir/a"}]}`))
+	})
+
+	It("lists no common prefixes as an empty array", func() {
+		fakeStorager.ListWithOptionsReturns(ListResult{Objects: []ObjectInfo{{Name: "a", Size: 3}}}, nil)
+
+		Expect(commandExecuter.Execute(context.Background(), "list", []string{"--delimiter", "/", "--long"})).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`{"prefixes":[],"objects":[{"name":"a","size":3}]}`))
+	})
+
+	It("reports whether an object exists", func() {
+		fakeStorager.ExistsReturns(false, nil)
+
+		err := commandExecuter.Execute(context.Background(), "exists", []string{"object"})
+		Expect(err).To(BeAssignableToTypeOf(&NotExistsError{}))
+		Expect(out.String()).To(MatchJSON(`{"exists":false}`))
+	})
+
+	It("reports signed URLs with their expiry", func() {
+		fakeStorager.SignReturns("https://signed?a=1&b=2", nil)
+
+		Expect(commandExecuter.Execute(context.Background(), "sign", []string{"object", "get", "1h"})).To(Succeed())
+
+		var result signResult
+		Expect(json.Unmarshal(out.Bytes(), &result)).To(Succeed())
+		Expect(result.URL).To(Equal("https://signed?a=1&b=2"))
+		Expect(result.ExpiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), 5*time.Second))
+		Expect(out.String()).ToNot(ContainSubstring(`\u0026`))
+	})
+
+	It("reports the bytes uploaded from stdin", func() {
+		commandExecuter.in = strings.NewReader("content")
+		fakeStorager.PutStreamStub = func(_ context.Context, r io.Reader, _ string) error {
+			_, err := io.Copy(io.Discard, r)
+			return err
+		}
+
+		Expect(commandExecuter.Execute(context.Background(), "put", []string{"-", "object"})).To(Succeed())
+
+		var result transferredResult
+		Expect(json.Unmarshal(out.Bytes(), &result)).To(Succeed())
+		Expect(result.Bytes).To(BeEquivalentTo(7))
+	})
+
+	It("reports the bytes downloaded into a file", func() {
+		dest := filepath.Join(GinkgoT().TempDir(), "file")
+		fakeStorager.GetStub = func(_ context.Context, _ string, dest string) error {
+			return os.WriteFile(dest, []byte("content"), 0644)
+		}
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"object", dest})).To(Succeed())
+
+		var result transferredResult
+		Expect(json.Unmarshal(out.Bytes(), &result)).To(Succeed())
+		Expect(result.Bytes).To(BeEquivalentTo(7))
+	})
+
+	It("reports the size of copied objects", func() {
+		fakeStorager.StatReturns(ObjectInfo{Size: 42}, true, nil)
+
+		Expect(commandExecuter.Execute(context.Background(), "copy", []string{"a", "b"})).To(Succeed())
+
+		var result transferredResult
+		Expect(json.Unmarshal(out.Bytes(), &result)).To(Succeed())
+		Expect(result.Bytes).To(BeEquivalentTo(42))
+		_, name := fakeStorager.StatArgsForCall(0)
+		Expect(name).To(Equal("b"))
+	})
+
+	It("prints an empty object for commands without a result", func() {
+		Expect(commandExecuter.Execute(context.Background(), "delete", []string{"object"})).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`{}`))
+	})
+
+	DescribeTable("reports failures with a stable code",
+		func(err error, code string) {
+			fakeStorager.DeleteReturns(err)
+
+			Expect(commandExecuter.Execute(context.Background(), "delete", []string{"object"})).To(MatchError(err))
+			Expect(out.String()).To(MatchJSON(fmt.Sprintf(`{"error":{"code":%q,"message":%q}}`, code, err.Error())))
+		},
+		Entry("not found", &NotExistsError{}, "not_found"),
+		Entry("missing object", fmt.Errorf("getting: %w", common.NewError(common.ErrNotFound, errors.New("RESPONSE 404"))), "not_found"),
+		Entry("permission denied", common.NewError(common.ErrPermissionDenied, errors.New("denied")), "permission_denied"),
+		Entry("already exists", common.NewError(common.ErrAlreadyExists, errors.New("exists")), "already_exists"),
+		Entry("precondition failed", common.NewError(common.ErrPreconditionFailed, errors.New("changed")), "precondition_failed"),
+		Entry("throttled", common.NewError(common.ErrThrottled, errors.New("slow down")), "throttled"),
+		Entry("invalid config", common.NewError(common.ErrInvalidConfig, errors.New("bad")), "invalid_config"),
+		Entry("timeout", fmt.Errorf("deleting: %w", context.DeadlineExceeded), "timeout"),
+		Entry("canceled", context.Canceled, "canceled"),
+		Entry("anything else", errors.New("boom"), "internal_error"),
+	)
+
+	It("prints the transfer summary when objects failed to transfer", func() {
+		destination := &FakeStorager{}
+		destination.PutStreamReturns(errors.New("boom"))
+		commandExecuter.newStorageClient = func(string, *os.File) (Storager, error) {
+			return destination, nil
+		}
+		configPath := filepath.Join(GinkgoT().TempDir(), "destination.json")
+		Expect(os.WriteFile(configPath, []byte("{}"), 0644)).To(Succeed())
+		fakeStorager.ListObjectsReturns([]ObjectInfo{{Name: "a", Size: 1, ETag: "etag"}}, nil)
+		fakeStorager.GetStreamReturns(io.NopCloser(strings.NewReader("x")), nil)
+
+		err := commandExecuter.Execute(context.Background(), "transfer", []string{"--to-s", "gcs", "--to-c", configPath})
+		Expect(err).To(MatchError(ContainSubstring("1 of 1 objects failed to transfer")))
+		Expect(out.String()).To(MatchJSON(`{"transferred":0,"skipped":0,"failed":1,"bytes":0}`))
+	})
+
+	It("prints nothing but the object when getting to stdout", func() {
+		fakeStorager.GetStreamReturns(nil, errors.New("boom"))
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"object", "-"})).ToNot(Succeed())
+		Expect(out.String()).To(BeEmpty())
+	})
+})
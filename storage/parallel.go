@@ -0,0 +1,29 @@
+package storage
+
+import "sync"
+
+// forEachParallel calls fn for every item, running at most parallel calls
+// concurrently, and returns once all calls have finished.
+func forEachParallel[T any](items []T, parallel int, fn func(item T)) {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	work := make(chan T)
+	wg := &sync.WaitGroup{}
+	for range parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				fn(item)
+			}
+		}()
+	}
+
+	for _, item := range items {
+		work <- item
+	}
+	close(work)
+	wg.Wait()
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// metadataFlag collects the repeatable --metadata key=value flag.
+type metadataFlag map[string]string
+
+func (m metadataFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		pairs = append(pairs, key+"="+m[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m metadataFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("metadata must be given as key=value, got %q", value)
+	}
+	m[key] = val
+	return nil
+}
+
+// put uploads a file, or stdin with stdioPath as source, with the headers,
+// metadata and preconditions given by its flags. With --verify the upload is
+// compared with the checksums the backend computed, see verifyUpload.
+func (sty *CommandExecuter) put(ctx context.Context, args []string) error {
+	var opts PutOptions
+	metadata := metadataFlag{}
+	flags := flag.NewFlagSet("put", flag.ContinueOnError)
+	flags.StringVar(&opts.ContentType, "content-type", "", "media type of the object")
+	flags.StringVar(&opts.ContentEncoding, "content-encoding", "", "encoding of the object, such as gzip")
+	flags.StringVar(&opts.CacheControl, "cache-control", "", "caching directives served with the object")
+	flags.StringVar(&opts.ContentDisposition, "content-disposition", "", `presentation of the object when downloaded, such as 'attachment; filename="droplet.tgz"'`)
+	flags.Var(metadata, "metadata", "user-defined metadata as key=value, repeatable")
+	flags.StringVar(&opts.IfMatch, "if-match", "", "only replace the object if its ETag matches")
+	flags.StringVar(&opts.IfNoneMatch, "if-none-match", "", "set to '*' to only create the object if it does not exist")
+	flags.StringVar(&opts.Checkpoint, "checkpoint", "", "file recording the progress of a multipart upload, so that a rerun resumes it")
+	flags.StringVar(&opts.Compression, "compress", "", "compress the content with gzip or zstd, get decompresses it again")
+	verify := flags.Bool("verify", false, "compare the checksums of the upload with the ones the backend computed, deleting the object if they differ")
+	guessContentType := flags.Bool("guess-content-type", false, "set the content type from the extension of the object name, or of the source file, without --content-type")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	args = flags.Args()
+	if len(args) != 2 {
+		return fmt.Errorf("put method expected 2 arguments got %d", len(args))
+	}
+	sourceFilePath, dst := args[0], args[1]
+	if opts.IfNoneMatch != "" && opts.IfNoneMatch != "*" {
+		return fmt.Errorf("--if-none-match only supports '*', got %q", opts.IfNoneMatch)
+	}
+	if opts.IfMatch != "" && opts.IfNoneMatch != "" {
+		return errors.New("--if-match and --if-none-match cannot be combined")
+	}
+	if opts.Checkpoint != "" && sourceFilePath == stdioPath {
+		return errors.New("--checkpoint needs a file to upload")
+	}
+	if opts.Compression != "" && opts.Compression != common.CompressionGzip && opts.Compression != common.CompressionZstd {
+		return fmt.Errorf("--compress only supports gzip and zstd, got %q", opts.Compression)
+	}
+	if opts.Compression != "" && opts.Checkpoint != "" {
+		return errors.New("--compress and --checkpoint cannot be combined")
+	}
+	if len(metadata) > 0 {
+		opts.Metadata = metadata
+	}
+	if *guessContentType && opts.ContentType == "" {
+		opts.ContentType = mime.TypeByExtension(path.Ext(dst))
+		if opts.ContentType == "" && sourceFilePath != stdioPath {
+			opts.ContentType = mime.TypeByExtension(filepath.Ext(sourceFilePath))
+		}
+	}
+	withOptions := opts.ContentType != "" || opts.ContentEncoding != "" || opts.CacheControl != "" ||
+		opts.ContentDisposition != "" || len(opts.Metadata) > 0 || opts.IfMatch != "" || opts.IfNoneMatch != "" || opts.Checkpoint != "" ||
+		opts.Compression != ""
+
+	start := time.Now()
+	if sourceFilePath == stdioPath {
+		counter := &countingReader{r: sty.stdin()}
+		var source io.Reader = counter
+		digest := common.NewDigest()
+		if *verify {
+			source = io.TeeReader(counter, digest)
+		}
+		var err error
+		if withOptions {
+			err = sty.str.PutStreamWithOptions(ctx, source, dst, opts)
+		} else {
+			err = sty.str.PutStream(ctx, source, dst)
+		}
+		if err != nil {
+			return err
+		}
+		if *verify {
+			if err := verifyUpload(ctx, sty.str, dst, digest); err != nil {
+				return err
+			}
+		}
+		return sty.writeTransferred(counter.n, start)
+	}
+
+	info, err := os.Stat(sourceFilePath)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	var digest *common.Digest
+	if *verify {
+		if digest, err = common.DigestFile(sourceFilePath); err != nil {
+			return err
+		}
+	}
+	if withOptions {
+		err = sty.str.PutWithOptions(ctx, sourceFilePath, dst, opts)
+	} else {
+		err = sty.str.Put(ctx, sourceFilePath, dst)
+	}
+	if err != nil {
+		return err
+	}
+	if *verify {
+		if err := verifyUpload(ctx, sty.str, dst, digest); err != nil {
+			return err
+		}
+	}
+	return sty.writeTransferred(info.Size(), start)
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("put", func() {
+	var (
+		fakeStorager    *FakeStorager
+		commandExecuter *CommandExecuter
+		sourceFile      string
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		commandExecuter = &CommandExecuter{str: fakeStorager}
+		sourceFile = filepath.Join(GinkgoT().TempDir(), "droplet.tgz")
+		Expect(os.WriteFile(sourceFile, []byte("content"), 0644)).To(Succeed())
+	})
+
+	It("uploads with the given headers and metadata", func() {
+		err := commandExecuter.Execute(context.Background(), "put", []string{
+			"--content-type", "application/gzip",
+			"--cache-control", "no-cache",
+			"--content-disposition", `attachment; filename="droplet.tgz"`,
+			"--content-encoding", "identity",
+			"--metadata", "owner=cc",
+			"--metadata", "guid=a=b",
+			sourceFile, "droplets/abc",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fakeStorager.PutCallCount()).To(Equal(0))
+		_, source, dest, opts := fakeStorager.PutWithOptionsArgsForCall(0)
+		Expect(source).To(Equal(sourceFile))
+		Expect(dest).To(Equal("droplets/abc"))
+		Expect(opts).To(Equal(PutOptions{
+			ContentType:        "application/gzip",
+			ContentEncoding:    "identity",
+			CacheControl:       "no-cache",
+			ContentDisposition: `attachment; filename="droplet.tgz"`,
+			Metadata:           map[string]string{"owner": "cc", "guid": "a=b"},
+		}))
+	})
+
+	It("guesses the content type from the object name, then from the source file", func() {
+		Expect(commandExecuter.Execute(context.Background(), "put", []string{"--guess-content-type", sourceFile, "index.html"})).To(Succeed())
+		_, _, _, opts := fakeStorager.PutWithOptionsArgsForCall(0)
+		Expect(opts.ContentType).To(HavePrefix("text/html"))
+
+		jsonFile := filepath.Join(GinkgoT().TempDir(), "manifest.json")
+		Expect(os.WriteFile(jsonFile, []byte("{}"), 0644)).To(Succeed())
+		Expect(commandExecuter.Execute(context.Background(), "put", []string{"--guess-content-type", jsonFile, "droplets/abc"})).To(Succeed())
+		_, _, _, opts = fakeStorager.PutWithOptionsArgsForCall(1)
+		Expect(opts.ContentType).To(Equal("application/json"))
+	})
+
+	It("keeps an explicit content type when guessing", func() {
+		Expect(commandExecuter.Execute(context.Background(), "put", []string{"--guess-content-type", "--content-type", "text/plain", sourceFile, "index.html"})).To(Succeed())
+		_, _, _, opts := fakeStorager.PutWithOptionsArgsForCall(0)
+		Expect(opts.ContentType).To(Equal("text/plain"))
+	})
+
+	It("streams stdin with the given headers", func() {
+		commandExecuter.in = strings.NewReader("piped content")
+
+		Expect(commandExecuter.Execute(context.Background(), "put", []string{"--content-type", "text/plain", "-", "destination"})).To(Succeed())
+		Expect(fakeStorager.PutStreamCallCount()).To(Equal(0))
+		_, _, dest, opts := fakeStorager.PutStreamWithOptionsArgsForCall(0)
+		Expect(dest).To(Equal("destination"))
+		Expect(opts).To(Equal(PutOptions{ContentType: "text/plain"}))
+	})
+
+	It("passes preconditions", func() {
+		Expect(commandExecuter.Execute(context.Background(), "put", []string{"--if-none-match", "*", sourceFile, "droplets/abc"})).To(Succeed())
+		_, _, _, opts := fakeStorager.PutWithOptionsArgsForCall(0)
+		Expect(opts).To(Equal(PutOptions{IfNoneMatch: "*"}))
+
+		Expect(commandExecuter.Execute(context.Background(), "put", []string{"--if-match", "abc", sourceFile, "droplets/abc"})).To(Succeed())
+		_, _, _, opts = fakeStorager.PutWithOptionsArgsForCall(1)
+		Expect(opts).To(Equal(PutOptions{IfMatch: "abc"}))
+	})
+
+	It("only supports '*' with --if-none-match", func() {
+		err := commandExecuter.Execute(context.Background(), "put", []string{"--if-none-match", "abc", sourceFile, "droplets/abc"})
+		Expect(err).To(MatchError(ContainSubstring("--if-none-match only supports '*'")))
+
+		err = commandExecuter.Execute(context.Background(), "put", []string{"--if-none-match", "*", "--if-match", "abc", sourceFile, "droplets/abc"})
+		Expect(err).To(MatchError(ContainSubstring("cannot be combined")))
+		Expect(fakeStorager.PutWithOptionsCallCount()).To(Equal(0))
+	})
+
+	It("passes a checkpoint for file uploads only", func() {
+		Expect(commandExecuter.Execute(context.Background(), "put", []string{"--checkpoint", "droplet.checkpoint", sourceFile, "droplets/abc"})).To(Succeed())
+		_, _, _, opts := fakeStorager.PutWithOptionsArgsForCall(0)
+		Expect(opts).To(Equal(PutOptions{Checkpoint: "droplet.checkpoint"}))
+
+		err := commandExecuter.Execute(context.Background(), "put", []string{"--checkpoint", "droplet.checkpoint", "-", "droplets/abc"})
+		Expect(err).To(MatchError("--checkpoint needs a file to upload"))
+		Expect(fakeStorager.PutStreamWithOptionsCallCount()).To(Equal(0))
+	})
+
+	It("passes the compression algorithm", func() {
+		Expect(commandExecuter.Execute(context.Background(), "put", []string{"--compress", "zstd", sourceFile, "droplets/abc"})).To(Succeed())
+		_, _, _, opts := fakeStorager.PutWithOptionsArgsForCall(0)
+		Expect(opts).To(Equal(PutOptions{Compression: "zstd"}))
+
+		err := commandExecuter.Execute(context.Background(), "put", []string{"--compress", "brotli", sourceFile, "droplets/abc"})
+		Expect(err).To(MatchError(`--compress only supports gzip and zstd, got "brotli"`))
+		Expect(fakeStorager.PutWithOptionsCallCount()).To(Equal(1))
+	})
+
+	It("rejects metadata without a value", func() {
+		err := commandExecuter.Execute(context.Background(), "put", []string{"--metadata", "owner", sourceFile, "destination"})
+		Expect(err).To(MatchError(ContainSubstring("metadata must be given as key=value")))
+	})
+
+	It("does not read stdin in batches when flags precede the arguments", func() {
+		_, err := commandExecuter.executeBuffered(context.Background(), "put", []string{"--content-type", "text/plain", "-", "destination"})
+		Expect(err).To(MatchError(ContainSubstring("cannot stream from stdin")))
+	})
+})
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// resumeStateSuffix names the file next to a partial download that records
+// which object version it belongs to.
+const resumeStateSuffix = ".storage-cli-resume"
+
+type resumeState struct {
+	Source string `json:"source"`
+	ETag   string `json:"etag"`
+	Size   int64  `json:"size"`
+}
+
+// getResumable downloads src to dst, continuing a partial dst that an
+// earlier call left behind for the same version of src. The version is
+// recorded in a state file next to dst until the download completes, and a
+// partial dst of another version, or without a state file, is downloaded
+// again from the start. The remainder is fetched with a range conditional on
+// the ETag, so the object cannot change midway, and streamed in order, so
+// dst always holds a prefix of the object. It returns the number of bytes
+// downloaded by this call.
+func getResumable(ctx context.Context, str Storager, src, dst, ifMatch string) (int64, error) {
+	info, exists, err := str.Stat(ctx, src)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, common.NewError(common.ErrNotFound, fmt.Errorf("object %q does not exist", src))
+	}
+	if err := common.CheckPreconditions(info.ETag, true, ifMatch, ""); err != nil {
+		return 0, err
+	}
+	if info.ETag == "" {
+		return 0, fmt.Errorf("cannot resume the download of %q: the backend reports no ETag to detect changes", src)
+	}
+
+	state := resumeState{Source: src, ETag: info.ETag, Size: info.Size}
+	statePath := dst + resumeStateSuffix
+	offset := resumeOffset(statePath, dst, state)
+
+	// The remainder is opened before dst is touched, so objects that cannot
+	// be resumed leave it alone.
+	var content io.ReadCloser
+	if offset < info.Size {
+		opts := GetOptions{IfMatch: info.ETag}
+		if offset > 0 {
+			opts.Range = &ByteRange{Start: offset, End: -1}
+		}
+		if content, err = str.GetStreamWithOptions(ctx, src, opts); err != nil {
+			return 0, err
+		}
+		defer content.Close() //nolint:errcheck
+		if algorithm := decompressedWith(content); algorithm != "" {
+			return 0, fmt.Errorf("cannot resume the download of %q: it is compressed with %s", src, algorithm)
+		}
+	}
+
+	if err := writeResumeState(statePath, state); err != nil {
+		return 0, err
+	}
+
+	file, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close() //nolint:errcheck
+	if err := file.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	var n int64
+	if content != nil {
+		n, err = io.Copy(file, content)
+		if err != nil {
+			return n, fmt.Errorf("downloading %s at byte %d, rerun to resume: %w", src, offset+n, err)
+		}
+	}
+	if offset+n != info.Size {
+		return n, fmt.Errorf("downloading %s: got %d of %d bytes, rerun to resume", src, offset+n, info.Size)
+	}
+	if err := file.Close(); err != nil {
+		return n, err
+	}
+	return n, os.Remove(statePath)
+}
+
+// resumeOffset returns the size of the partial download at dst if the state
+// file records the same object version as state, or else 0.
+func resumeOffset(statePath, dst string, state resumeState) int64 {
+	data, err := os.ReadFile(statePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0
+	}
+	var previous resumeState
+	if err == nil {
+		err = json.Unmarshal(data, &previous)
+	}
+	if err != nil {
+		slog.Warn("Ignoring unreadable resume state", "path", statePath, "error", err)
+		return 0
+	}
+	if previous != state {
+		slog.Info("Object changed since the partial download, starting over", "object", state.Source, "etag", state.ETag, "previous_etag", previous.ETag)
+		return 0
+	}
+
+	info, err := os.Stat(dst)
+	if err != nil || info.Size() > state.Size {
+		return 0
+	}
+	slog.Info("Resuming download", "object", state.Source, "offset", info.Size(), "size", state.Size)
+	return info.Size()
+}
+
+func writeResumeState(statePath string, state resumeState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(statePath, data, 0644); err != nil {
+		return fmt.Errorf("writing resume state: %w", err)
+	}
+	return nil
+}
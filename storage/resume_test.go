@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// failingReader returns content and then err.
+type failingReader struct {
+	content io.Reader
+	err     error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.content.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+var _ = Describe("get --resume", func() {
+	var (
+		fakeStorager    *FakeStorager
+		commandExecuter *CommandExecuter
+		dst             string
+		statePath       string
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		commandExecuter = &CommandExecuter{str: fakeStorager}
+		dst = filepath.Join(GinkgoT().TempDir(), "droplet.tgz")
+		statePath = dst + resumeStateSuffix
+		fakeStorager.StatReturns(ObjectInfo{Name: "droplet", Size: 7, ETag: "abc"}, true, nil)
+	})
+
+	writeState := func(etag string) {
+		Expect(writeResumeState(statePath, resumeState{Source: "droplet", ETag: etag, Size: 7})).To(Succeed())
+	}
+
+	It("downloads the whole object conditionally and removes its state", func() {
+		fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(strings.NewReader("content")), nil)
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--resume", "droplet", dst})).To(Succeed())
+
+		_, src, opts := fakeStorager.GetStreamWithOptionsArgsForCall(0)
+		Expect(src).To(Equal("droplet"))
+		Expect(opts).To(Equal(GetOptions{IfMatch: "abc"}))
+		Expect(os.ReadFile(dst)).To(Equal([]byte("content")))
+		Expect(statePath).ToNot(BeAnExistingFile())
+	})
+
+	It("keeps the partial file and its state when the download fails", func() {
+		fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(&failingReader{strings.NewReader("con"), errors.New("connection reset")}), nil)
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--resume", "droplet", dst})
+		Expect(err).To(MatchError(ContainSubstring("at byte 3, rerun to resume")))
+		Expect(os.ReadFile(dst)).To(Equal([]byte("con")))
+		Expect(statePath).To(BeAnExistingFile())
+	})
+
+	It("fetches only the rest of an unchanged object", func() {
+		Expect(os.WriteFile(dst, []byte("cont"), 0644)).To(Succeed())
+		writeState("abc")
+		fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(strings.NewReader("ent")), nil)
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--resume", "droplet", dst})).To(Succeed())
+
+		_, _, opts := fakeStorager.GetStreamWithOptionsArgsForCall(0)
+		Expect(opts).To(Equal(GetOptions{IfMatch: "abc", Range: &ByteRange{Start: 4, End: -1}}))
+		Expect(os.ReadFile(dst)).To(Equal([]byte("content")))
+		Expect(statePath).ToNot(BeAnExistingFile())
+	})
+
+	It("starts over if the object changed", func() {
+		Expect(os.WriteFile(dst, []byte("stale"), 0644)).To(Succeed())
+		writeState("old")
+		fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(strings.NewReader("content")), nil)
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--resume", "droplet", dst})).To(Succeed())
+
+		_, _, opts := fakeStorager.GetStreamWithOptionsArgsForCall(0)
+		Expect(opts.Range).To(BeNil())
+		Expect(os.ReadFile(dst)).To(Equal([]byte("content")))
+	})
+
+	It("starts over without a state file", func() {
+		Expect(os.WriteFile(dst, []byte("unknown file"), 0644)).To(Succeed())
+		fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(strings.NewReader("content")), nil)
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--resume", "droplet", dst})).To(Succeed())
+		Expect(os.ReadFile(dst)).To(Equal([]byte("content")))
+	})
+
+	It("completes a download that only lacks the removal of its state", func() {
+		Expect(os.WriteFile(dst, []byte("content"), 0644)).To(Succeed())
+		writeState("abc")
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--resume", "droplet", dst})).To(Succeed())
+		Expect(fakeStorager.GetStreamWithOptionsCallCount()).To(Equal(0))
+		Expect(statePath).ToNot(BeAnExistingFile())
+	})
+
+	It("fails for a missing object or a failed precondition", func() {
+		fakeStorager.StatReturns(ObjectInfo{}, false, nil)
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--resume", "droplet", dst})
+		Expect(err).To(MatchError(common.ErrNotFound))
+
+		fakeStorager.StatReturns(ObjectInfo{Size: 7, ETag: "abc"}, true, nil)
+		err = commandExecuter.Execute(context.Background(), "get", []string{"--resume", "--if-match", "def", "droplet", dst})
+		Expect(err).To(MatchError(common.ErrPreconditionFailed))
+		Expect(fakeStorager.GetStreamWithOptionsCallCount()).To(Equal(0))
+	})
+
+	It("rejects stdout and byte ranges", func() {
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--resume", "droplet", "-"})
+		Expect(err).To(MatchError("--resume needs a file to download to"))
+
+		err = commandExecuter.Execute(context.Background(), "get", []string{"--resume", "--range", "0-3", "droplet", dst})
+		Expect(err).To(MatchError("--resume and --range cannot be combined"))
+	})
+})
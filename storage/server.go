@@ -0,0 +1,296 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+const (
+	defaultShutdownTimeout = 30 * time.Second
+	maxCommandRequestSize  = 1024 * 1024
+)
+
+// serverCommands are the commands exposed by POST /commands/{cmd}. Commands
+// reading or writing local files are left out, objects are streamed through
+// /objects instead.
+var serverCommands = map[string]bool{
+	"copy":                  true,
+	"delete":                true,
+	"delete-recursive":      true,
+	"ensure-storage-exists": true,
+	"exists":                true,
+	"list":                  true,
+	"move":                  true,
+	"properties":            true,
+	"sign":                  true,
+}
+
+// Server exposes the operations of a single Storager over HTTP, so callers
+// performing many operations pay for configuration parsing and credential
+// resolution only once.
+//
+//	PUT    /objects/{name}       upload the request body
+//	GET    /objects/{name}       download the object (HEAD for metadata only)
+//	DELETE /objects/{name}       delete the object
+//	GET    /objects?prefix=p     list object names as a JSON array
+//	POST   /commands/{cmd}       execute cmd with {"args": [...]} like the CLI
+//
+// Failures are reported as {"error": {"code": "...", "message": "..."}}.
+//
+// The server does not authenticate its callers: anyone who can connect acts
+// with the credentials of the Storager, including deleting objects and
+// signing URLs. Serve it on a Unix domain socket only its user can access,
+// or on a TCP address that only trusted processes can reach.
+type Server struct {
+	executer        *CommandExecuter
+	shutdownTimeout time.Duration
+}
+
+func NewServer(s Storager) *Server {
+	return &Server{executer: NewCommandExecuter(s), shutdownTimeout: defaultShutdownTimeout}
+}
+
+type commandRequest struct {
+	Args []string `json:"args"`
+}
+
+type commandResponse struct {
+	Cmd    string `json:"cmd"`
+	Output string `json:"output"`
+}
+
+// Serve handles requests on listener until ctx is done, then stops accepting
+// connections and waits for in-flight requests to finish.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+	slog.Info("Serving storage operations", "address", listener.Addr().String())
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down, waiting for in-flight requests", "timeout", s.shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down server: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /objects/{name...}", s.putObject)
+	mux.HandleFunc("GET /objects/{name...}", s.getObject)
+	mux.HandleFunc("DELETE /objects/{name...}", s.deleteObject)
+	mux.HandleFunc("GET /objects", s.listObjects)
+	mux.HandleFunc("POST /commands/{cmd}", s.command)
+	return mux
+}
+
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request) {
+	name, ok := objectName(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.executer.str.PutStream(r.Context(), r.Body, name); err != nil {
+		writeServerError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request) {
+	name, ok := objectName(w, r)
+	if !ok {
+		return
+	}
+
+	info, content, err := s.openObject(r.Context(), name, r.Method == http.MethodHead)
+	if err != nil {
+		writeServerError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	// The size of decompressed content is not known until it is read.
+	if decompressedWith(content) == "" {
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	}
+	if info.ETag != "" {
+		w.Header().Set("ETag", strconv.Quote(info.ETag))
+	}
+	if !info.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", info.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if content == nil {
+		return
+	}
+	defer content.Close() //nolint:errcheck
+
+	if _, err := io.Copy(w, content); err != nil {
+		// The status has been sent already; the short body tells the client
+		// the download failed.
+		slog.Error("Streaming object failed", "object", name, "error", err)
+	}
+}
+
+// openObjectAttempts bounds how often openObject starts over when the object
+// keeps being replaced.
+const openObjectAttempts = 3
+
+// openObject returns the metadata of name and, unless headOnly, its content.
+// The content is read with an If-Match on the ETag of the metadata, so the
+// headers sent with it describe the same version of the object; if it was
+// replaced in between, the metadata is read again.
+func (s *Server) openObject(ctx context.Context, name string, headOnly bool) (ObjectInfo, io.ReadCloser, error) {
+	for attempt := 1; ; attempt++ {
+		info, exists, err := s.executer.str.Stat(ctx, name)
+		if err != nil {
+			return ObjectInfo{}, nil, err
+		}
+		if !exists {
+			return ObjectInfo{}, nil, &NotExistsError{}
+		}
+		if headOnly {
+			return info, nil, nil
+		}
+
+		content, err := s.executer.str.GetStreamWithOptions(ctx, name, GetOptions{IfMatch: info.ETag})
+		if errors.Is(err, common.ErrPreconditionFailed) && attempt < openObjectAttempts {
+			continue
+		}
+		if err != nil {
+			return ObjectInfo{}, nil, err
+		}
+		return info, content, nil
+	}
+}
+
+func (s *Server) deleteObject(w http.ResponseWriter, r *http.Request) {
+	name, ok := objectName(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.executer.str.Delete(r.Context(), name); err != nil {
+		writeServerError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listObjects streams the names of the objects page by page. Once the first
+// page is sent, a failure can only be reported by ending the array early.
+func (s *Server) listObjects(w http.ResponseWriter, r *http.Request) {
+	var names *jsonArrayWriter
+	for page, err := range ListPages(r.Context(), s.executer.str, ListOptions{Prefix: r.URL.Query().Get("prefix")}) {
+		if err != nil {
+			if names == nil {
+				writeServerError(w, r, err)
+			} else {
+				slog.Error("Listing objects failed", "path", r.URL.Path, "error", err)
+			}
+			return
+		}
+		if names == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			if names, err = newJSONArrayWriter(w); err != nil {
+				return
+			}
+		}
+		for _, object := range page.Objects {
+			if err := names.write(object.Name); err != nil {
+				return
+			}
+		}
+	}
+	names.close("\n") //nolint:errcheck
+}
+
+func (s *Server) command(w http.ResponseWriter, r *http.Request) {
+	cmd := r.PathValue("cmd")
+	if !serverCommands[cmd] {
+		writeServerJSON(w, http.StatusNotFound, errorResult{errorBody{"unknown_command", fmt.Sprintf("unknown command: '%s'", cmd)}})
+		return
+	}
+
+	var req commandRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxCommandRequestSize)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeServerJSON(w, http.StatusBadRequest, errorResult{errorBody{"invalid_request", fmt.Sprintf("invalid request body: %v", err)}})
+		return
+	}
+
+	output, err := s.executer.executeBuffered(r.Context(), cmd, req.Args)
+	if err != nil {
+		writeServerError(w, r, err)
+		return
+	}
+	writeServerJSON(w, http.StatusOK, commandResponse{Cmd: cmd, Output: output})
+}
+
+func objectName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := r.PathValue("name")
+	if name == "" {
+		writeServerJSON(w, http.StatusBadRequest, errorResult{errorBody{"invalid_request", "missing object name"}})
+		return "", false
+	}
+	return name, true
+}
+
+// serverErrorStatus maps error codes to HTTP statuses, anything else is
+// reported as http.StatusInternalServerError.
+var serverErrorStatus = map[string]int{
+	errorCodeNotFound:           http.StatusNotFound,
+	errorCodePermissionDenied:   http.StatusForbidden,
+	errorCodeAlreadyExists:      http.StatusConflict,
+	errorCodePreconditionFailed: http.StatusPreconditionFailed,
+	errorCodeThrottled:          http.StatusTooManyRequests,
+	errorCodeTimeout:            http.StatusGatewayTimeout,
+	errorCodeChecksumMismatch:   http.StatusBadGateway,
+}
+
+func writeServerError(w http.ResponseWriter, r *http.Request, err error) {
+	code := errorCode(err)
+	status, ok := serverErrorStatus[code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	if status >= http.StatusInternalServerError {
+		slog.Error("Request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+	writeServerJSON(w, status, errorResult{errorBody{code, err.Error()}})
+}
+
+func writeServerJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("Writing response failed", "error", err)
+	}
+}
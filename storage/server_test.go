@@ -0,0 +1,253 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		fakeStorager *FakeStorager
+		server       *httptest.Server
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		server = httptest.NewServer(NewServer(fakeStorager).Handler())
+		DeferCleanup(server.Close)
+	})
+
+	do := func(method, path string, body io.Reader) (*http.Response, string) {
+		req, err := http.NewRequest(method, server.URL+path, body)
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close() //nolint:errcheck
+		content, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return resp, string(content)
+	}
+
+	Context("objects", func() {
+		It("streams uploads to the storage", func() {
+			var uploaded string
+			fakeStorager.PutStreamStub = func(_ context.Context, r io.Reader, _ string) error {
+				content, err := io.ReadAll(r)
+				uploaded = string(content)
+				return err
+			}
+
+			resp, _ := do(http.MethodPut, "/objects/dir/object", strings.NewReader("content"))
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			Expect(uploaded).To(Equal("content"))
+			_, _, name := fakeStorager.PutStreamArgsForCall(0)
+			Expect(name).To(Equal("dir/object"))
+		})
+
+		It("streams downloads with object metadata", func() {
+			modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			fakeStorager.StatReturns(ObjectInfo{Size: 7, ETag: "etag", LastModified: modified}, true, nil)
+			fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(strings.NewReader("content")), nil)
+
+			resp, body := do(http.MethodGet, "/objects/object", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(body).To(Equal("content"))
+			Expect(resp.Header.Get("ETag")).To(Equal(`"etag"`))
+			Expect(resp.Header.Get("Last-Modified")).To(Equal("Tue, 02 Jan 2024 03:04:05 GMT"))
+			_, arg, opts := fakeStorager.GetStreamWithOptionsArgsForCall(0)
+			Expect(arg).To(Equal("object"))
+			Expect(opts).To(Equal(GetOptions{IfMatch: "etag"}))
+		})
+
+		It("sends the headers of the version it streams when the object is replaced", func() {
+			fakeStorager.StatReturnsOnCall(0, ObjectInfo{Size: 3, ETag: "old"}, true, nil)
+			fakeStorager.StatReturnsOnCall(1, ObjectInfo{Size: 7, ETag: "new"}, true, nil)
+			fakeStorager.GetStreamWithOptionsReturnsOnCall(0, nil, common.NewError(common.ErrPreconditionFailed, errors.New("etag changed")))
+			fakeStorager.GetStreamWithOptionsReturnsOnCall(1, io.NopCloser(strings.NewReader("content")), nil)
+
+			resp, body := do(http.MethodGet, "/objects/object", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(body).To(Equal("content"))
+			Expect(resp.Header.Get("ETag")).To(Equal(`"new"`))
+			Expect(resp.ContentLength).To(BeEquivalentTo(7))
+		})
+
+		It("streams compressed objects decompressed, without a Content-Length", func() {
+			var (
+				stored     []byte
+				storedOpts PutOptions
+			)
+			fakeStorager.PutStreamWithOptionsStub = func(_ context.Context, r io.Reader, _ string, opts PutOptions) error {
+				var err error
+				stored, err = io.ReadAll(r)
+				storedOpts = opts
+				return err
+			}
+			// Larger than the response buffer, which would set Content-Length.
+			content := strings.Repeat("content", 10000)
+			compressing := newCompressingStorager(fakeStorager, true)
+			Expect(compressing.PutStreamWithOptions(context.Background(), strings.NewReader(content), "object", PutOptions{Compression: common.CompressionGzip})).To(Succeed())
+			fakeStorager.StatReturns(ObjectInfo{Name: "object", Size: int64(len(stored)), ETag: "etag"}, true, nil)
+			fakeStorager.PropertiesReturns(Properties{ETag: "etag", ContentLength: int64(len(stored)), ContentEncoding: storedOpts.ContentEncoding}, nil)
+			fakeStorager.GetStreamWithOptionsStub = func(context.Context, string, GetOptions) (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(stored)), nil
+			}
+			compressedServer := httptest.NewServer(NewServer(compressing).Handler())
+			DeferCleanup(compressedServer.Close)
+
+			resp, err := http.Get(compressedServer.URL + "/objects/object")
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close() //nolint:errcheck
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.ContentLength).To(BeEquivalentTo(-1))
+			Expect(string(body)).To(Equal(content))
+		})
+
+		It("answers HEAD without opening the object", func() {
+			fakeStorager.StatReturns(ObjectInfo{Size: 7}, true, nil)
+
+			resp, _ := do(http.MethodHead, "/objects/object", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.ContentLength).To(BeEquivalentTo(7))
+			Expect(fakeStorager.GetStreamCallCount()).To(Equal(0))
+		})
+
+		It("reports missing objects as not found", func() {
+			resp, body := do(http.MethodGet, "/objects/missing", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			Expect(body).To(MatchJSON(`{"error":{"code":"not_found","message":"object does not exist"}}`))
+		})
+
+		It("deletes objects", func() {
+			resp, _ := do(http.MethodDelete, "/objects/object", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+			_, arg := fakeStorager.DeleteArgsForCall(0)
+			Expect(arg).To(Equal("object"))
+		})
+
+		It("lists objects as a JSON array", func() {
+			fakeStorager.ListWithOptionsStub = func(_ context.Context, opts ListOptions) (ListResult, error) {
+				if opts.PageToken == "" {
+					return ListResult{Objects: []ObjectInfo{{Name: "p/a"}}, NextPageToken: "next"}, nil
+				}
+				return ListResult{Objects: []ObjectInfo{{Name: "p/b"}}}, nil
+			}
+
+			resp, body := do(http.MethodGet, "/objects?prefix=p/", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(`["p/a","p/b"]`))
+			_, opts := fakeStorager.ListWithOptionsArgsForCall(0)
+			Expect(opts.Prefix).To(Equal("p/"))
+			Expect(fakeStorager.ListWithOptionsCallCount()).To(Equal(2))
+		})
+
+		It("lists no objects as an empty array", func() {
+			resp, body := do(http.MethodGet, "/objects", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(`[]`))
+		})
+
+		It("reports a listing failing on the first page", func() {
+			fakeStorager.ListWithOptionsReturns(ListResult{}, common.NewError(common.ErrPermissionDenied, errors.New("denied")))
+
+			resp, _ := do(http.MethodGet, "/objects", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		})
+
+		It("reports storage failures as internal errors", func() {
+			fakeStorager.DeleteReturns(errors.New("boom"))
+
+			resp, body := do(http.MethodDelete, "/objects/object", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+			Expect(body).To(MatchJSON(`{"error":{"code":"internal_error","message":"boom"}}`))
+		})
+
+		It("maps kinds of failures onto statuses", func() {
+			fakeStorager.DeleteReturns(common.NewError(common.ErrThrottled, errors.New("slow down")))
+
+			resp, body := do(http.MethodDelete, "/objects/object", nil)
+			Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+			Expect(body).To(MatchJSON(`{"error":{"code":"throttled","message":"slow down"}}`))
+		})
+	})
+
+	Context("commands", func() {
+		It("executes commands like the CLI", func() {
+			fakeStorager.SignReturns("https://signed", nil)
+
+			resp, body := do(http.MethodPost, "/commands/sign", strings.NewReader(`{"args":["object","get","60s"]}`))
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(body).To(MatchJSON(`{"cmd":"sign","output":"https://signed"}`))
+		})
+
+		It("reports a missing object as not found", func() {
+			resp, body := do(http.MethodPost, "/commands/exists", strings.NewReader(`{"args":["object"]}`))
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			Expect(body).To(ContainSubstring(`"not_found"`))
+		})
+
+		It("rejects commands touching local files", func() {
+			resp, body := do(http.MethodPost, "/commands/get", strings.NewReader(`{"args":["object","/etc/passwd"]}`))
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			Expect(body).To(ContainSubstring(`"unknown_command"`))
+			Expect(fakeStorager.GetCallCount()).To(Equal(0))
+		})
+
+		It("rejects malformed requests", func() {
+			resp, body := do(http.MethodPost, "/commands/list", strings.NewReader(`{"args":`))
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(body).To(ContainSubstring(`"invalid_request"`))
+		})
+	})
+
+	It("finishes in-flight requests on shutdown", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+
+		started, release := make(chan struct{}), make(chan struct{})
+		fakeStorager.DeleteStub = func(context.Context, string) error {
+			close(started)
+			<-release
+			return nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		served := make(chan error, 1)
+		go func() {
+			served <- NewServer(fakeStorager).Serve(ctx, listener)
+		}()
+
+		status := make(chan int, 1)
+		go func() {
+			defer GinkgoRecover()
+			req, err := http.NewRequest(http.MethodDelete, "http://"+listener.Addr().String()+"/objects/object", nil)
+			Expect(err).ToNot(HaveOccurred())
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close() //nolint:errcheck
+			status <- resp.StatusCode
+		}()
+
+		Eventually(started).Should(BeClosed())
+		cancel()
+		Consistently(served, 100*time.Millisecond).ShouldNot(Receive())
+		close(release)
+
+		Eventually(status).Should(Receive(Equal(http.StatusNoContent)))
+		Eventually(served).Should(Receive(BeNil()))
+	})
+})
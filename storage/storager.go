@@ -1,18 +1,95 @@
 package storage
 
 import (
+	"context"
+	"io"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
+// ObjectInfo describes an object as reported by a Storager.
+type ObjectInfo = common.ObjectInfo
+
+// Properties are the properties of an object returned by Storager.Properties.
+type Properties = common.Properties
+
+// PutOptions sets the headers and metadata of objects uploaded by
+// Storager.PutWithOptions and Storager.PutStreamWithOptions.
+type PutOptions = common.PutOptions
+
+// GetOptions sets the preconditions and the byte range of
+// Storager.GetWithOptions and Storager.GetStreamWithOptions.
+type GetOptions = common.GetOptions
+
+// ByteRange selects the part of an object read with GetOptions.Range.
+type ByteRange = common.ByteRange
+
+// DeleteOptions sets the preconditions of Storager.DeleteWithOptions.
+type DeleteOptions = common.DeleteOptions
+
+// DeleteFailure reports a key Storager.DeleteMany could not delete.
+type DeleteFailure = common.DeleteFailure
+
+// ListOptions selects the objects returned by Storager.ListWithOptions.
+type ListOptions = common.ListOptions
+
+// ListResult holds the objects and common prefixes of a listing.
+type ListResult = common.ListResult
+
+// Storager is implemented by every storage backend. Operations stop when ctx
+// is cancelled or its deadline expires.
 type Storager interface {
-	Put(sourceFilePath string, dest string) error
-	Get(source string, dest string) error
-	Delete(dest string) error
-	DeleteRecursive(prefix string) error
-	Exists(dest string) (bool, error)
-	Sign(dest string, action string, expiration time.Duration) (string, error)
-	List(prefix string) ([]string, error)
-	Copy(srcBlob string, dstBlob string) error
-	Properties(dest string) error
-	EnsureStorageExists() error
+	Put(ctx context.Context, sourceFilePath string, dest string) error
+	PutStream(ctx context.Context, source io.Reader, dest string) error
+	// PutWithOptions is Put with the headers and metadata of opts set on
+	// the uploaded object. A failed opts.IfMatch or opts.IfNoneMatch
+	// returns an error matching common.ErrPreconditionFailed.
+	PutWithOptions(ctx context.Context, sourceFilePath string, dest string, opts PutOptions) error
+	// PutStreamWithOptions is PutStream with the headers and metadata of
+	// opts set on the uploaded object.
+	PutStreamWithOptions(ctx context.Context, source io.Reader, dest string, opts PutOptions) error
+	Get(ctx context.Context, source string, dest string) error
+	GetStream(ctx context.Context, source string) (io.ReadCloser, error)
+	// GetWithOptions is Get, failing with common.ErrPreconditionFailed if
+	// the object does not satisfy opts. With opts.Range only that part of
+	// the object is written to dest.
+	GetWithOptions(ctx context.Context, source string, dest string, opts GetOptions) error
+	// GetStreamWithOptions is GetStream, failing with
+	// common.ErrPreconditionFailed if the object does not satisfy opts.
+	// With opts.Range only that part of the object is read, so headers and
+	// trailers of large archives can be read without downloading them.
+	GetStreamWithOptions(ctx context.Context, source string, opts GetOptions) (io.ReadCloser, error)
+	Delete(ctx context.Context, dest string) error
+	// DeleteWithOptions is Delete, failing with
+	// common.ErrPreconditionFailed if the object does not satisfy opts.
+	DeleteWithOptions(ctx context.Context, dest string, opts DeleteOptions) error
+	// DeleteRecursive deletes the objects below prefix. A failed delete
+	// does not stop the others, and the returned error names every failure.
+	DeleteRecursive(ctx context.Context, prefix string) error
+	// DeleteMany deletes keys with the backend's batch delete where it has
+	// one, or else concurrently, and returns the keys it failed to delete.
+	// Missing keys count as deleted.
+	DeleteMany(ctx context.Context, keys []string) []DeleteFailure
+	Exists(ctx context.Context, dest string) (bool, error)
+	Stat(ctx context.Context, dest string) (ObjectInfo, bool, error)
+	Sign(ctx context.Context, dest string, action string, expiration time.Duration) (string, error)
+	List(ctx context.Context, prefix string) ([]string, error)
+	// ListObjects is List with the metadata the backend returns while
+	// listing, so no request per object is needed.
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// ListWithOptions is ListObjects with the common prefixes of the objects
+	// grouped by opts.Delimiter reported separately, using the backend's
+	// native support for delimiters. With opts.MaxKeys it lists a single
+	// page, see ListPages to iterate over all of them.
+	ListWithOptions(ctx context.Context, opts ListOptions) (ListResult, error)
+	Copy(ctx context.Context, srcBlob string, dstBlob string) error
+	// Move moves srcBlob to dstBlob, deleting srcBlob only once dstBlob is
+	// in place. Retried after a partial failure, it completes the move, and
+	// it succeeds if srcBlob is gone and dstBlob exists.
+	Move(ctx context.Context, srcBlob string, dstBlob string) error
+	// Properties returns the properties of dest, or an error matching
+	// common.ErrNotFound if it does not exist.
+	Properties(ctx context.Context, dest string) (Properties, error)
+	EnsureStorageExists(ctx context.Context) error
 }
@@ -0,0 +1,417 @@
+package storage
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type syncOptions struct {
+	// delete removes entries at the destination that do not exist at the source.
+	delete   bool
+	dryRun   bool
+	parallel int
+}
+
+type syncAction struct {
+	Op     string `json:"op"`
+	Source string `json:"source"`
+	Dest   string `json:"dest,omitempty"`
+}
+
+func (a syncAction) String() string {
+	if a.Dest == "" {
+		return fmt.Sprintf("%s %s", a.Op, a.Source)
+	}
+	return fmt.Sprintf("%s %s -> %s", a.Op, a.Source, a.Dest)
+}
+
+type syncSummary struct {
+	Transferred int `json:"transferred"`
+	Skipped     int `json:"skipped"`
+	Deleted     int `json:"deleted"`
+	Failed      int `json:"failed"`
+	// Unverified counts files and objects of the same size that could not
+	// be compared, see compareLocalFile. They are left alone.
+	Unverified int `json:"unverified"`
+}
+
+func (s syncSummary) String() string {
+	return fmt.Sprintf("transferred: %d, skipped: %d, deleted: %d, failed: %d, unverified: %d", s.Transferred, s.Skipped, s.Deleted, s.Failed, s.Unverified)
+}
+
+// syncRun collects the actions and results of the parallel workers of a sync.
+type syncRun struct {
+	opts    syncOptions
+	mu      sync.Mutex
+	actions []syncAction
+	summary syncSummary
+	errs    []error
+}
+
+func (r *syncRun) skip() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.summary.Skipped++
+}
+
+// unverified records action, which is left out because its source and
+// destination could not be compared.
+func (r *syncRun) unverified(action syncAction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	action.Op = "unverified"
+	r.actions = append(r.actions, action)
+	r.summary.Unverified++
+}
+
+// do performs action unless this is a dry run, and records the outcome.
+func (r *syncRun) do(action syncAction, perform func() error) {
+	var err error
+	if !r.opts.dryRun {
+		err = perform()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		slog.Error("Sync action failed", "action", action.Op, "source", action.Source, "dest", action.Dest, "error", err)
+		r.summary.Failed++
+		r.errs = append(r.errs, fmt.Errorf("%s: %w", action, err))
+		return
+	}
+
+	r.actions = append(r.actions, action)
+	if action.Op == "delete" {
+		r.summary.Deleted++
+	} else {
+		r.summary.Transferred++
+	}
+}
+
+func (r *syncRun) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.summary.Failed++
+	r.errs = append(r.errs, err)
+}
+
+func (r *syncRun) result() ([]syncAction, syncSummary, error) {
+	sort.Slice(r.actions, func(i, j int) bool {
+		if r.actions[i].Op != r.actions[j].Op {
+			return r.actions[i].Op > r.actions[j].Op
+		}
+		return r.actions[i].Source < r.actions[j].Source
+	})
+
+	if len(r.errs) > 0 {
+		return r.actions, r.summary, fmt.Errorf("%d sync actions failed: %w", r.summary.Failed, errors.Join(r.errs...))
+	}
+	return r.actions, r.summary, nil
+}
+
+// syncUp uploads every file below dir whose content differs from the object
+// at the corresponding name below prefix. With opts.delete, objects below
+// prefix without a local counterpart are deleted.
+func syncUp(ctx context.Context, str Storager, dir, prefix string, opts syncOptions) ([]syncAction, syncSummary, error) {
+	prefix = syncPrefix(prefix)
+
+	// A mistyped source directory must not turn into an empty source, which
+	// would delete everything below prefix with opts.delete.
+	if info, err := os.Stat(dir); err != nil {
+		return nil, syncSummary{}, fmt.Errorf("reading local directory: %w", err)
+	} else if !info.IsDir() {
+		return nil, syncSummary{}, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	localFiles, err := walkLocalFiles(dir)
+	if err != nil {
+		return nil, syncSummary{}, err
+	}
+
+	var remoteNames []string
+	if opts.delete {
+		remoteNames, err = str.List(ctx, prefix)
+		if err != nil {
+			return nil, syncSummary{}, fmt.Errorf("failed to list remote objects: %w", err)
+		}
+	}
+
+	run := &syncRun{opts: opts}
+
+	rels := make([]string, 0, len(localFiles))
+	for rel := range localFiles {
+		rels = append(rels, rel)
+	}
+	forEachParallel(rels, opts.parallel, func(rel string) {
+		localPath, name := localFiles[rel], prefix+rel
+
+		remote, exists, err := str.Stat(ctx, name)
+		if err != nil {
+			run.fail(fmt.Errorf("reading metadata of %s: %w", name, err))
+			return
+		}
+		action := syncAction{Op: "upload", Source: localPath, Dest: name}
+		if exists {
+			comparison, err := compareLocalFile(localPath, remote, true)
+			if err != nil {
+				run.fail(err)
+				return
+			}
+			switch comparison {
+			case contentMatches:
+				run.skip()
+				return
+			case contentUnverified:
+				run.unverified(action)
+				return
+			}
+		}
+
+		run.do(action, func() error {
+			return str.Put(ctx, localPath, name)
+		})
+	})
+
+	var extraneous []string
+	for _, name := range remoteNames {
+		if _, ok := localFiles[strings.TrimPrefix(name, prefix)]; !ok {
+			extraneous = append(extraneous, name)
+		}
+	}
+	forEachParallel(extraneous, opts.parallel, func(name string) {
+		run.do(syncAction{Op: "delete", Source: name}, func() error {
+			return str.Delete(ctx, name)
+		})
+	})
+
+	return run.result()
+}
+
+// syncDown downloads every object below prefix whose content differs from
+// the file at the corresponding path below dir, comparing the files with
+// the listed objects. With opts.delete, files below dir without a remote
+// counterpart are removed.
+func syncDown(ctx context.Context, str Storager, prefix, dir string, opts syncOptions) ([]syncAction, syncSummary, error) {
+	prefix = syncPrefix(prefix)
+
+	objects, err := str.ListObjects(ctx, prefix)
+	if err != nil {
+		return nil, syncSummary{}, fmt.Errorf("failed to list remote objects: %w", err)
+	}
+
+	var localFiles map[string]string
+	if opts.delete {
+		localFiles, err = walkLocalFiles(dir)
+		if err != nil {
+			return nil, syncSummary{}, err
+		}
+		// A mistyped or empty prefix must not turn into an empty source, which
+		// would remove every file below dir.
+		if len(objects) == 0 && len(localFiles) > 0 {
+			return nil, syncSummary{}, fmt.Errorf("no objects below %q, refusing to delete the files in %s", prefix, dir)
+		}
+	}
+
+	run := &syncRun{opts: opts}
+	remoteRels := make(map[string]bool, len(objects))
+
+	forEachParallel(objects, opts.parallel, func(remote ObjectInfo) {
+		name := remote.Name
+		rel := strings.TrimPrefix(name, prefix)
+		if strings.HasSuffix(rel, "/") {
+			// Directory placeholder objects have no local counterpart.
+			run.skip()
+			return
+		}
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			run.fail(fmt.Errorf("object %s cannot be mapped to a path below %s", name, dir))
+			return
+		}
+
+		run.mu.Lock()
+		remoteRels[rel] = true
+		run.mu.Unlock()
+
+		localPath := filepath.Join(dir, filepath.FromSlash(rel))
+		action := syncAction{Op: "download", Source: name, Dest: localPath}
+		if info, err := os.Stat(localPath); err == nil && info.Mode().IsRegular() {
+			// Listings without MD5s, like the ones of local storage, leave the
+			// object to be looked up only for files of the same size.
+			if remote.ContentMD5 == "" && remote.Size == info.Size() {
+				var exists bool
+				remote, exists, err = str.Stat(ctx, name)
+				if err != nil {
+					run.fail(fmt.Errorf("reading metadata of %s: %w", name, err))
+					return
+				}
+				if !exists {
+					run.skip()
+					return
+				}
+			}
+			comparison, err := compareLocalFile(localPath, remote, false)
+			if err != nil {
+				run.fail(err)
+				return
+			}
+			switch comparison {
+			case contentMatches:
+				run.skip()
+				return
+			case contentUnverified:
+				run.unverified(action)
+				return
+			}
+		}
+
+		run.do(action, func() error {
+			return downloadAtomically(ctx, str, name, localPath)
+		})
+	})
+
+	if opts.delete {
+		var extraneous []string
+		for rel, localPath := range localFiles {
+			if !remoteRels[rel] {
+				extraneous = append(extraneous, localPath)
+			}
+		}
+		forEachParallel(extraneous, opts.parallel, func(localPath string) {
+			run.do(syncAction{Op: "delete", Source: localPath}, func() error {
+				return os.Remove(localPath)
+			})
+		})
+	}
+
+	return run.result()
+}
+
+// syncPrefix treats a non-empty prefix as a directory, so "cache" and
+// "cache/" both map dir/a to the object cache/a.
+func syncPrefix(prefix string) string {
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return prefix
+	}
+	return prefix + "/"
+}
+
+// walkLocalFiles returns the regular files below dir, keyed by their
+// slash-separated path relative to dir. A missing dir has no files.
+func walkLocalFiles(dir string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = p
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking local directory %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// syncComparison is the outcome of compareLocalFile.
+type syncComparison int
+
+const (
+	contentDiffers syncComparison = iota
+	contentMatches
+	// contentUnverified is reported for a file and an object of the same
+	// size when the object has neither an MD5 nor a modification time.
+	contentUnverified
+)
+
+// compareLocalFile compares the local file with the remote object. Files
+// of a different size differ. Otherwise the MD5 of the file is compared
+// with the object's ContentMD5; ETags are not used, as they are not MD5s
+// for multipart uploads and most WebDAV servers. Objects without an MD5
+// fall back to the modification times: with up the object matches if it
+// was modified no earlier than the file, since it was then uploaded from
+// it, and otherwise if the file was modified no earlier than the object.
+func compareLocalFile(localPath string, remote ObjectInfo, up bool) (syncComparison, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return contentDiffers, err
+	}
+	if info.Size() != remote.Size {
+		return contentDiffers, nil
+	}
+
+	if remote.ContentMD5 == "" {
+		switch {
+		case remote.LastModified.IsZero():
+			return contentUnverified, nil
+		case up && !info.ModTime().After(remote.LastModified),
+			!up && !remote.LastModified.After(info.ModTime()):
+			return contentMatches, nil
+		default:
+			return contentDiffers, nil
+		}
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return contentDiffers, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return contentDiffers, fmt.Errorf("failed to calculate md5 of %s: %w", localPath, err)
+	}
+	if !strings.EqualFold(hex.EncodeToString(hash.Sum(nil)), remote.ContentMD5) {
+		return contentDiffers, nil
+	}
+	return contentMatches, nil
+}
+
+// downloadAtomically downloads an object into a temporary file next to
+// localPath and renames it into place, so an interrupted sync never leaves a
+// truncated file behind.
+func downloadAtomically(ctx context.Context, str Storager, name, localPath string) error {
+	dir := filepath.Dir(localPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(dir, "."+filepath.Base(localPath)+".sync-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	tmpFile.Close() //nolint:errcheck
+
+	if err := str.Get(ctx, name, tmpPath); err != nil {
+		os.Remove(tmpPath) //nolint:errcheck
+		return err
+	}
+	if err := os.Rename(tmpPath, localPath); err != nil {
+		os.Remove(tmpPath) //nolint:errcheck
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	local "github.com/cloudfoundry/storage-cli/local/client"
+	localconfig "github.com/cloudfoundry/storage-cli/local/config"
+)
+
+var _ = Describe("sync", func() {
+	var (
+		remoteRoot string
+		localDir   string
+		remote     Storager
+	)
+
+	writeFile := func(path, content string) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	readFile := func(path string) string {
+		content, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	BeforeEach(func() {
+		remoteRoot = GinkgoT().TempDir()
+		localDir = GinkgoT().TempDir()
+
+		var err error
+		remote, err = local.New(localconfig.LocalConfig{RootDirectory: remoteRoot})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("up", func() {
+		BeforeEach(func() {
+			writeFile(filepath.Join(localDir, "same"), "same")
+			writeFile(filepath.Join(localDir, "nested", "changed"), "new")
+			writeFile(filepath.Join(localDir, "added"), "added")
+
+			writeFile(filepath.Join(remoteRoot, "cache", "same"), "same")
+			writeFile(filepath.Join(remoteRoot, "cache", "nested", "changed"), "old")
+			writeFile(filepath.Join(remoteRoot, "cache", "extra"), "extra")
+			writeFile(filepath.Join(remoteRoot, "other"), "other")
+		})
+
+		It("uploads new and changed files only", func() {
+			actions, summary, err := syncUp(context.Background(), remote, localDir, "cache", syncOptions{parallel: 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary).To(Equal(syncSummary{Transferred: 2, Skipped: 1}))
+			Expect(actions).To(ConsistOf(
+				syncAction{Op: "upload", Source: filepath.Join(localDir, "added"), Dest: "cache/added"},
+				syncAction{Op: "upload", Source: filepath.Join(localDir, "nested", "changed"), Dest: "cache/nested/changed"},
+			))
+
+			Expect(readFile(filepath.Join(remoteRoot, "cache", "nested", "changed"))).To(Equal("new"))
+			Expect(filepath.Join(remoteRoot, "cache", "extra")).To(BeAnExistingFile())
+		})
+
+		It("deletes extraneous objects below the prefix with delete", func() {
+			_, summary, err := syncUp(context.Background(), remote, localDir, "cache/", syncOptions{delete: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary.Deleted).To(Equal(1))
+
+			Expect(filepath.Join(remoteRoot, "cache", "extra")).ToNot(BeAnExistingFile())
+			Expect(filepath.Join(remoteRoot, "other")).To(BeAnExistingFile())
+		})
+
+		It("only reports the planned actions with dryRun", func() {
+			actions, summary, err := syncUp(context.Background(), remote, localDir, "cache", syncOptions{delete: true, dryRun: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary).To(Equal(syncSummary{Transferred: 2, Skipped: 1, Deleted: 1}))
+			Expect(actions).To(HaveLen(3))
+
+			Expect(readFile(filepath.Join(remoteRoot, "cache", "nested", "changed"))).To(Equal("old"))
+			Expect(filepath.Join(remoteRoot, "cache", "extra")).To(BeAnExistingFile())
+		})
+
+		Context("when the backend reports no MD5", func() {
+			var fake *FakeStorager
+
+			BeforeEach(func() {
+				fake = &FakeStorager{}
+				localDir = GinkgoT().TempDir()
+				writeFile(filepath.Join(localDir, "file"), "content")
+				modTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+				Expect(os.Chtimes(filepath.Join(localDir, "file"), modTime, modTime)).To(Succeed())
+			})
+
+			It("skips objects modified after the local file", func() {
+				fake.StatReturns(ObjectInfo{Size: 7, ETag: "nginx-etag", LastModified: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)}, true, nil)
+
+				_, summary, err := syncUp(context.Background(), fake, localDir, "cache", syncOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(summary).To(Equal(syncSummary{Skipped: 1}))
+				Expect(fake.PutCallCount()).To(Equal(0))
+			})
+
+			It("uploads files modified after the object", func() {
+				fake.StatReturns(ObjectInfo{Size: 7, ETag: "nginx-etag", LastModified: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)}, true, nil)
+
+				_, summary, err := syncUp(context.Background(), fake, localDir, "cache", syncOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(summary).To(Equal(syncSummary{Transferred: 1}))
+				Expect(fake.PutCallCount()).To(Equal(1))
+			})
+
+			It("reports objects it cannot compare instead of uploading them", func() {
+				// The ETag is the MD5 of the content, but ETags are no MD5s in general.
+				fake.StatReturns(ObjectInfo{Size: 7, ETag: "9a0364b9e99bb480dd25e1f0284c8555"}, true, nil)
+
+				actions, summary, err := syncUp(context.Background(), fake, localDir, "cache", syncOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(summary).To(Equal(syncSummary{Unverified: 1}))
+				Expect(actions).To(Equal([]syncAction{{Op: "unverified", Source: filepath.Join(localDir, "file"), Dest: "cache/file"}}))
+				Expect(fake.PutCallCount()).To(Equal(0))
+			})
+		})
+
+		It("refuses a missing source directory", func() {
+			_, _, err := syncUp(context.Background(), remote, filepath.Join(localDir, "missing"), "cache", syncOptions{delete: true})
+			Expect(err).To(MatchError(ContainSubstring("reading local directory")))
+			Expect(filepath.Join(remoteRoot, "cache", "extra")).To(BeAnExistingFile())
+		})
+	})
+
+	Context("down", func() {
+		BeforeEach(func() {
+			writeFile(filepath.Join(remoteRoot, "cache", "same"), "same")
+			writeFile(filepath.Join(remoteRoot, "cache", "nested", "changed"), "new")
+			writeFile(filepath.Join(remoteRoot, "cache", "added"), "added")
+
+			writeFile(filepath.Join(localDir, "same"), "same")
+			writeFile(filepath.Join(localDir, "nested", "changed"), "old")
+			writeFile(filepath.Join(localDir, "extra"), "extra")
+		})
+
+		It("downloads new and changed objects only", func() {
+			actions, summary, err := syncDown(context.Background(), remote, "cache", localDir, syncOptions{parallel: 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary).To(Equal(syncSummary{Transferred: 2, Skipped: 1}))
+			Expect(actions).To(HaveLen(2))
+
+			Expect(readFile(filepath.Join(localDir, "nested", "changed"))).To(Equal("new"))
+			Expect(readFile(filepath.Join(localDir, "added"))).To(Equal("added"))
+			Expect(filepath.Join(localDir, "extra")).To(BeAnExistingFile())
+		})
+
+		It("removes extraneous local files with delete", func() {
+			actions, summary, err := syncDown(context.Background(), remote, "cache", localDir, syncOptions{delete: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary.Deleted).To(Equal(1))
+			Expect(actions).To(ContainElement(syncAction{Op: "delete", Source: filepath.Join(localDir, "extra")}))
+
+			Expect(filepath.Join(localDir, "extra")).ToNot(BeAnExistingFile())
+		})
+
+		It("leaves no partial file behind if a download fails", func() {
+			fake := &FakeStorager{}
+			fake.ListObjectsReturns([]ObjectInfo{{Name: "cache/broken", Size: 7}}, nil)
+			fake.GetStub = func(_ context.Context, _ string, dest string) error {
+				Expect(os.WriteFile(dest, []byte("partial"), 0644)).To(Succeed())
+				return errors.New("connection reset")
+			}
+
+			_, summary, err := syncDown(context.Background(), fake, "cache", localDir, syncOptions{})
+			Expect(err).To(MatchError(ContainSubstring("connection reset")))
+			Expect(summary.Failed).To(Equal(1))
+
+			entries, err := os.ReadDir(localDir)
+			Expect(err).ToNot(HaveOccurred())
+			for _, entry := range entries {
+				Expect(entry.Name()).ToNot(ContainSubstring("broken"))
+			}
+		})
+
+		It("skips files written after objects without an MD5", func() {
+			fake := &FakeStorager{}
+			fake.ListObjectsReturns([]ObjectInfo{{Name: "cache/same", Size: 4, ETag: "nginx-etag", LastModified: time.Now().Add(-time.Hour)}}, nil)
+			fake.StatReturns(ObjectInfo{Name: "cache/same", Size: 4, ETag: "nginx-etag", LastModified: time.Now().Add(-time.Hour)}, true, nil)
+
+			_, summary, err := syncDown(context.Background(), fake, "cache", localDir, syncOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary).To(Equal(syncSummary{Skipped: 1}))
+			Expect(fake.GetCallCount()).To(Equal(0))
+		})
+
+		It("compares files with the listed MD5s", func() {
+			fake := &FakeStorager{}
+			fake.ListObjectsReturns([]ObjectInfo{
+				{Name: "cache/same", Size: 4, ContentMD5: "51037a4a37730f52c8732586d3aaa316"},
+				{Name: "cache/nested/changed", Size: 3, ContentMD5: "acbd18db4cc2f85cedef654fccc4a4d8"},
+			}, nil)
+
+			_, summary, err := syncDown(context.Background(), fake, "cache", localDir, syncOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(summary).To(Equal(syncSummary{Transferred: 1, Skipped: 1}))
+			Expect(fake.StatCallCount()).To(Equal(0))
+			_, name, _ := fake.GetArgsForCall(0)
+			Expect(name).To(Equal("cache/nested/changed"))
+		})
+
+		It("refuses to delete local files when no objects are below the prefix", func() {
+			_, _, err := syncDown(context.Background(), remote, "mistyped", localDir, syncOptions{delete: true})
+			Expect(err).To(MatchError(ContainSubstring(`no objects below "mistyped/", refusing to delete the files in`)))
+			Expect(filepath.Join(localDir, "extra")).To(BeAnExistingFile())
+		})
+
+		It("rejects object names escaping the local directory", func() {
+			fake := &FakeStorager{}
+			fake.ListObjectsReturns([]ObjectInfo{{Name: "cache/../../escape"}}, nil)
+
+			_, _, err := syncDown(context.Background(), fake, "cache", localDir, syncOptions{})
+			Expect(err).To(MatchError(ContainSubstring("cannot be mapped to a path below")))
+			Expect(fake.GetCallCount()).To(Equal(0))
+		})
+	})
+})
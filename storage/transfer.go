@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+const defaultTransferParallelism = 5
+
+type transferOptions struct {
+	prefix   string
+	parallel int
+	// sizeOnly skips objects whose destination size matches, without
+	// comparing ETags or checksums.
+	sizeOnly bool
+}
+
+type transferSummary struct {
+	Transferred int   `json:"transferred"`
+	Skipped     int   `json:"skipped"`
+	Failed      int   `json:"failed"`
+	Bytes       int64 `json:"bytes"`
+}
+
+func (s transferSummary) String() string {
+	return fmt.Sprintf("transferred: %d, skipped: %d, failed: %d, bytes: %d", s.Transferred, s.Skipped, s.Failed, s.Bytes)
+}
+
+// transferFailuresError reports the objects that failed to transfer, after
+// the summary was printed.
+type transferFailuresError struct {
+	total int
+	errs  []error
+}
+
+func (e *transferFailuresError) Error() string {
+	return fmt.Sprintf("%d of %d objects failed to transfer: %v", len(e.errs), e.total, errors.Join(e.errs...))
+}
+
+func (e *transferFailuresError) Unwrap() []error {
+	return e.errs
+}
+
+// transfer streams every object below opts.prefix from src to dst, keeping
+// object names. Objects already present at the destination with the same
+// content are skipped. Failures of single objects do not stop the transfer;
+// they are logged and reported in the returned *transferFailuresError.
+func transfer(ctx context.Context, src, dst Storager, opts transferOptions) (transferSummary, error) {
+	objects, err := src.ListObjects(ctx, opts.prefix)
+	if err != nil {
+		return transferSummary{}, fmt.Errorf("failed to list source objects: %w", err)
+	}
+
+	parallel := opts.parallel
+	if parallel < 1 {
+		parallel = defaultTransferParallelism
+	}
+
+	var (
+		summary transferSummary
+		errs    []error
+		mu      sync.Mutex
+	)
+
+	forEachParallel(objects, parallel, func(srcInfo ObjectInfo) {
+		name := srcInfo.Name
+		skipped, n, err := transferObject(ctx, src, dst, srcInfo, opts.sizeOnly)
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err != nil:
+			slog.Error("Failed to transfer object", "object", name, "error", err)
+			summary.Failed++
+			errs = append(errs, fmt.Errorf("transferring %s: %w", name, err))
+		case skipped:
+			summary.Skipped++
+		default:
+			summary.Transferred++
+			summary.Bytes += n
+		}
+	})
+
+	if len(errs) > 0 {
+		return summary, &transferFailuresError{total: len(objects), errs: errs}
+	}
+	return summary, nil
+}
+
+// transferObject copies the listed object srcInfo unless the destination
+// already holds the same content. It returns the number of bytes streamed.
+func transferObject(ctx context.Context, src, dst Storager, srcInfo ObjectInfo, sizeOnly bool) (skipped bool, n int64, err error) {
+	name := srcInfo.Name
+	dstInfo, exists, err := dst.Stat(ctx, name)
+	if err != nil {
+		return false, 0, fmt.Errorf("reading destination metadata: %w", err)
+	}
+	// Listings without checksums or ETags, like the ones of local storage,
+	// leave the source to be read only for objects that might be skipped.
+	if exists && !sizeOnly && srcInfo.ETag == "" && srcInfo.ContentMD5 == "" && srcInfo.Size == dstInfo.Size {
+		var srcExists bool
+		srcInfo, srcExists, err = src.Stat(ctx, name)
+		if err != nil {
+			return false, 0, fmt.Errorf("reading source metadata: %w", err)
+		}
+		if !srcExists {
+			slog.Warn("Source object disappeared during transfer, skipping", "object", name)
+			return true, 0, nil
+		}
+	}
+	if exists && sameObject(srcInfo, dstInfo, sizeOnly) {
+		slog.Debug("Object is up to date at destination, skipping", "object", name)
+		return true, 0, nil
+	}
+
+	content, err := src.GetStream(ctx, name)
+	if err != nil {
+		return false, 0, fmt.Errorf("opening source object: %w", err)
+	}
+	defer content.Close() //nolint:errcheck
+
+	counter := &countingReader{r: content}
+	if err := dst.PutStream(ctx, counter, name); err != nil {
+		return false, counter.n, fmt.Errorf("writing destination object: %w", err)
+	}
+
+	slog.Info("Transferred object", "object", name, "bytes", counter.n)
+	return false, counter.n, nil
+}
+
+func sameObject(src, dst ObjectInfo, sizeOnly bool) bool {
+	if sizeOnly {
+		return src.Size == dst.Size
+	}
+	return src.SameContent(dst)
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("transfer", func() {
+	var (
+		source      *FakeStorager
+		destination *FakeStorager
+		objects     map[string]ObjectInfo
+	)
+
+	BeforeEach(func() {
+		source = &FakeStorager{}
+		destination = &FakeStorager{}
+
+		objects = map[string]ObjectInfo{
+			"a": {Name: "a", Size: 3, ContentMD5: "acbd18db4cc2f85cedef654fccc4a4d8"},
+			"b": {Name: "b", Size: 5, ETag: "etag-b"},
+		}
+		source.ListObjectsReturns([]ObjectInfo{objects["a"], objects["b"]}, nil)
+		source.StatStub = func(_ context.Context, name string) (ObjectInfo, bool, error) {
+			info, ok := objects[name]
+			return info, ok, nil
+		}
+		source.GetStreamStub = func(_ context.Context, name string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(strings.Repeat("x", int(objects[name].Size)))), nil
+		}
+		destination.PutStreamStub = func(_ context.Context, r io.Reader, _ string) error {
+			_, err := io.Copy(io.Discard, r)
+			return err
+		}
+	})
+
+	It("streams every listed object to the destination", func() {
+		summary, err := transfer(context.Background(), source, destination, transferOptions{prefix: "p", parallel: 2})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).To(Equal(transferSummary{Transferred: 2, Bytes: 8}))
+
+		_, arg := source.ListObjectsArgsForCall(0)
+
+		Expect(arg).To(Equal("p"))
+		var names []string
+		for i := range destination.PutStreamCallCount() {
+			_, _, name := destination.PutStreamArgsForCall(i)
+			names = append(names, name)
+		}
+		Expect(names).To(ConsistOf("a", "b"))
+	})
+
+	It("skips objects with matching size and checksum at the destination", func() {
+		destination.StatStub = func(_ context.Context, name string) (ObjectInfo, bool, error) {
+			if name == "a" {
+				return ObjectInfo{Size: 3, ETag: "other", ContentMD5: "ACBD18DB4CC2F85CEDEF654FCCC4A4D8"}, true, nil
+			}
+			return ObjectInfo{Size: 5, ETag: "stale"}, true, nil
+		}
+
+		summary, err := transfer(context.Background(), source, destination, transferOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).To(Equal(transferSummary{Transferred: 1, Skipped: 1, Bytes: 5}))
+
+		_, _, name := destination.PutStreamArgsForCall(0)
+		Expect(name).To(Equal("b"))
+		Expect(source.StatCallCount()).To(BeZero())
+	})
+
+	It("reads the source metadata when the listing has no checksums", func() {
+		source.ListObjectsReturns([]ObjectInfo{{Name: "a", Size: 3}, {Name: "b", Size: 5}}, nil)
+		destination.StatStub = func(_ context.Context, name string) (ObjectInfo, bool, error) {
+			if name == "a" {
+				return ObjectInfo{Size: 3, ContentMD5: "acbd18db4cc2f85cedef654fccc4a4d8"}, true, nil
+			}
+			return ObjectInfo{Size: 4}, true, nil
+		}
+
+		summary, err := transfer(context.Background(), source, destination, transferOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).To(Equal(transferSummary{Transferred: 1, Skipped: 1, Bytes: 5}))
+		Expect(source.StatCallCount()).To(Equal(1))
+		_, name := source.StatArgsForCall(0)
+		Expect(name).To(Equal("a"))
+	})
+
+	It("only compares sizes with sizeOnly", func() {
+		destination.StatReturns(ObjectInfo{Size: 5}, true, nil)
+
+		summary, err := transfer(context.Background(), source, destination, transferOptions{sizeOnly: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).To(Equal(transferSummary{Transferred: 1, Skipped: 1, Bytes: 3}))
+	})
+
+	It("continues after a failed object and reports it", func() {
+		destination.PutStreamStub = func(_ context.Context, _ io.Reader, name string) error {
+			if name == "a" {
+				return errors.New("boom")
+			}
+			return nil
+		}
+
+		summary, err := transfer(context.Background(), source, destination, transferOptions{})
+		Expect(err).To(MatchError(ContainSubstring("1 of 2 objects failed to transfer")))
+		Expect(err).To(MatchError(ContainSubstring("transferring a: writing destination object: boom")))
+		Expect(summary.Transferred).To(Equal(1))
+		Expect(summary.Failed).To(Equal(1))
+	})
+
+	It("fails if the source cannot be listed", func() {
+		source.ListObjectsReturns(nil, errors.New("denied"))
+
+		_, err := transfer(context.Background(), source, destination, transferOptions{})
+		Expect(err).To(MatchError("failed to list source objects: denied"))
+	})
+})
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// verifyUpload compares digest of the content uploaded to dst with the size
+// and checksums the backend reports for dst. An object that differs is
+// deleted, unless it was replaced meanwhile. An object the backend reports no
+// usable checksum for is only checked for its size.
+func verifyUpload(ctx context.Context, str Storager, dst string, digest *common.Digest) error {
+	props, err := str.Properties(ctx, dst)
+	if err != nil {
+		return fmt.Errorf("verifying upload of %s: %w", dst, err)
+	}
+	algorithms, err := digest.Verify(props)
+	if errors.Is(err, common.ErrNoChecksum) {
+		slog.Warn("Upload checked for size only, the backend reports no checksum", "object", dst)
+		return nil
+	}
+	if err != nil {
+		err = fmt.Errorf("verifying upload of %s: %w", dst, err)
+		if deleteErr := str.DeleteWithOptions(ctx, dst, DeleteOptions{IfMatch: props.ETag}); deleteErr != nil {
+			return errors.Join(err, fmt.Errorf("deleting corrupt object %s: %w", dst, deleteErr))
+		}
+		return err
+	}
+	slog.Info("Verified upload", "object", dst, "checksums", algorithms)
+	return nil
+}
+
+// getVerified streams src to w if it satisfies ifMatch, computing the
+// checksums of the content on the way and comparing them with the ones the
+// backend reports, and returns the number of bytes written. The download is
+// conditional on the ETag the checksums were read with, so they cannot
+// belong to another version.
+func getVerified(ctx context.Context, str Storager, src string, w io.Writer, ifMatch string) (int64, error) {
+	props, err := str.Properties(ctx, src)
+	if err != nil {
+		return 0, err
+	}
+	if err := common.CheckPreconditions(props.ETag, true, ifMatch, ""); err != nil {
+		return 0, err
+	}
+
+	content, err := str.GetStreamWithOptions(ctx, src, GetOptions{IfMatch: props.ETag})
+	if err != nil {
+		return 0, err
+	}
+	defer content.Close() //nolint:errcheck
+
+	digest := common.NewDigest()
+	n, err := io.Copy(io.MultiWriter(w, digest), content)
+	if err != nil {
+		return n, fmt.Errorf("downloading %s: %w", src, err)
+	}
+	algorithms, err := digest.Verify(props)
+	if errors.Is(err, common.ErrNoChecksum) {
+		slog.Warn("Download checked for size only, the backend reports no checksum", "object", src)
+		return n, nil
+	}
+	if err != nil {
+		return n, fmt.Errorf("verifying download of %s: %w", src, err)
+	}
+	slog.Info("Verified download", "object", src, "checksums", algorithms)
+	return n, nil
+}
+
+// verify compares an object with a local file. The size and checksums the
+// backend reports are compared with the ones of the file, and only if none
+// of the checksums is usable the object is downloaded to compute them.
+func (sty *CommandExecuter) verify(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("verify method expected 2 arguments got %d", len(args))
+	}
+	src, localPath := args[0], args[1]
+
+	local, err := common.DigestFile(localPath)
+	if err != nil {
+		return err
+	}
+	props, err := sty.str.Properties(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	downloaded := false
+	algorithms, err := local.Verify(props)
+	if errors.Is(err, common.ErrNoChecksum) {
+		slog.Info("Backend reports no checksum, downloading the object to verify it", "object", src)
+		content, err := sty.str.GetStreamWithOptions(ctx, src, GetOptions{IfMatch: props.ETag})
+		if err != nil {
+			return err
+		}
+		defer content.Close() //nolint:errcheck
+		remote := common.NewDigest()
+		if _, err := io.Copy(remote, content); err != nil {
+			return fmt.Errorf("downloading %s: %w", src, err)
+		}
+		downloaded = true
+		algorithms, err = common.VerifyChecksums(remote.Checksums(), local.Checksums())
+		if err != nil {
+			return fmt.Errorf("verifying %s against %s: %w", src, localPath, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("verifying %s against %s: %w", src, localPath, err)
+	}
+
+	if !sty.jsonOutput() {
+		return nil
+	}
+	return sty.writeJSON(verifyResult{Checksums: algorithms, Downloaded: downloaded})
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+var _ = Describe("integrity verification", func() {
+	const contentMD5 = "9a0364b9e99bb480dd25e1f0284c8555"
+
+	var (
+		fakeStorager    *FakeStorager
+		commandExecuter *CommandExecuter
+		localFile       string
+		out             *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		out = &bytes.Buffer{}
+		commandExecuter = &CommandExecuter{str: fakeStorager, out: out}
+		localFile = filepath.Join(GinkgoT().TempDir(), "droplet.tgz")
+		Expect(os.WriteFile(localFile, []byte("content"), 0644)).To(Succeed())
+	})
+
+	Describe("put --verify", func() {
+		It("compares the upload with the backend's checksums", func() {
+			fakeStorager.PropertiesReturns(Properties{ETag: "abc", ContentLength: 7, ContentMD5: contentMD5}, nil)
+
+			Expect(commandExecuter.Execute(context.Background(), "put", []string{"--verify", localFile, "droplet"})).To(Succeed())
+			Expect(fakeStorager.PutCallCount()).To(Equal(1))
+			Expect(fakeStorager.DeleteWithOptionsCallCount()).To(Equal(0))
+		})
+
+		It("deletes an object that differs from the upload", func() {
+			fakeStorager.PropertiesReturns(Properties{ETag: "abc", ContentLength: 7, ContentMD5: strings.Repeat("0", 32)}, nil)
+
+			err := commandExecuter.Execute(context.Background(), "put", []string{"--verify", localFile, "droplet"})
+			Expect(err).To(MatchError(common.ErrChecksumMismatch))
+			_, dest, opts := fakeStorager.DeleteWithOptionsArgsForCall(0)
+			Expect(dest).To(Equal("droplet"))
+			Expect(opts).To(Equal(DeleteOptions{IfMatch: "abc"}))
+		})
+
+		It("digests stdin while streaming it", func() {
+			commandExecuter.in = strings.NewReader("content")
+			fakeStorager.PutStreamStub = func(_ context.Context, source io.Reader, _ string) error {
+				_, err := io.Copy(io.Discard, source)
+				return err
+			}
+			fakeStorager.PropertiesReturns(Properties{ContentLength: 7, ContentMD5: contentMD5}, nil)
+
+			Expect(commandExecuter.Execute(context.Background(), "put", []string{"--verify", "-", "droplet"})).To(Succeed())
+		})
+	})
+
+	Describe("get --verify", func() {
+		It("downloads the version whose checksums it compares", func() {
+			fakeStorager.PropertiesReturns(Properties{ETag: "abc", ContentLength: 7, ContentMD5: contentMD5}, nil)
+			fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(strings.NewReader("content")), nil)
+			dst := filepath.Join(GinkgoT().TempDir(), "download")
+
+			Expect(commandExecuter.Execute(context.Background(), "get", []string{"--verify", "droplet", dst})).To(Succeed())
+			_, _, opts := fakeStorager.GetStreamWithOptionsArgsForCall(0)
+			Expect(opts).To(Equal(GetOptions{IfMatch: "abc"}))
+			Expect(os.ReadFile(dst)).To(Equal([]byte("content")))
+		})
+
+		It("removes a download that differs from the object", func() {
+			fakeStorager.PropertiesReturns(Properties{ETag: "abc", ContentLength: 7, ContentMD5: contentMD5}, nil)
+			fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(strings.NewReader("corrupt")), nil)
+			dst := filepath.Join(GinkgoT().TempDir(), "download")
+
+			err := commandExecuter.Execute(context.Background(), "get", []string{"--verify", "droplet", dst})
+			Expect(err).To(MatchError(common.ErrChecksumMismatch))
+			Expect(dst).NotTo(BeAnExistingFile())
+		})
+
+		It("rejects byte ranges and resumed downloads", func() {
+			err := commandExecuter.Execute(context.Background(), "get", []string{"--verify", "--range", "0-3", "droplet", localFile})
+			Expect(err).To(MatchError("--verify and --range cannot be combined"))
+
+			err = commandExecuter.Execute(context.Background(), "get", []string{"--verify", "--resume", "droplet", localFile})
+			Expect(err).To(MatchError("--resume and --verify cannot be combined"))
+		})
+	})
+
+	Describe("verify", func() {
+		BeforeEach(func() {
+			Expect(commandExecuter.SetOutputFormat(OutputJSON)).To(Succeed())
+		})
+
+		It("compares a file with the backend's checksums without downloading the object", func() {
+			fakeStorager.PropertiesReturns(Properties{ContentLength: 7, ContentMD5: contentMD5}, nil)
+
+			Expect(commandExecuter.Execute(context.Background(), "verify", []string{"droplet", localFile})).To(Succeed())
+			Expect(fakeStorager.GetStreamWithOptionsCallCount()).To(Equal(0))
+			Expect(out.String()).To(MatchJSON(`{"checksums":["md5"],"downloaded":false}`))
+		})
+
+		It("downloads the object without a checksum to compare", func() {
+			fakeStorager.PropertiesReturns(Properties{ETag: "abc", ContentLength: 7}, nil)
+			fakeStorager.GetStreamWithOptionsReturns(io.NopCloser(strings.NewReader("content")), nil)
+
+			Expect(commandExecuter.Execute(context.Background(), "verify", []string{"droplet", localFile})).To(Succeed())
+			_, _, opts := fakeStorager.GetStreamWithOptionsArgsForCall(0)
+			Expect(opts).To(Equal(GetOptions{IfMatch: "abc"}))
+			Expect(out.String()).To(MatchJSON(`{"checksums":["sha256","md5","crc64","crc32c","crc32"],"downloaded":true}`))
+		})
+
+		It("fails for a different object", func() {
+			fakeStorager.PropertiesReturns(Properties{ContentLength: 8}, nil)
+
+			err := commandExecuter.Execute(context.Background(), "verify", []string{"droplet", localFile})
+			Expect(err).To(MatchError(common.ErrChecksumMismatch))
+			Expect(out.String()).To(ContainSubstring(`"code":"checksum_mismatch"`))
+		})
+	})
+})